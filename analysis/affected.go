@@ -0,0 +1,211 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/version"
+)
+
+// Status tells whether a package version is affected by an advisory.
+type Status string
+
+const (
+	// The version is affected.
+	Affected Status = "affected"
+
+	// The version is not affected and follows a version that fixed the
+	// advisory.
+	Fixed Status = "fixed"
+
+	// The version is not affected and precedes the affected versions.
+	Unaffected Status = "unaffected"
+)
+
+// VersionStatus is the status of a package version with respect to an
+// advisory.
+type VersionStatus struct {
+	Version string
+	Status  Status
+}
+
+// AffectedVersions classifies each version of pkg as affected by adv or
+// not, in the order of pkg.Versions. The affected version ranges are taken
+// from the OSV record of the advisory or, if OSV.dev has none or it does
+// not mention pkg, from those of its aliases. It reports an error if no
+// record mentions pkg.
+func AffectedVersions(ctx context.Context, osv *OSVClient, adv *insights.Advisory, pkg *insights.Package) ([]VersionStatus, error) {
+	system := strings.ToUpper(pkg.PackageKey.System)
+	ecosystem, ok := osvEcosystems[system]
+	if !ok {
+		return nil, fmt.Errorf("no OSV ecosystem for system %s", pkg.PackageKey.System)
+	}
+	ids := append([]string{adv.AdvisoryKey.ID}, adv.Aliases...)
+	for _, id := range ids {
+		v, err := osv.Vuln(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		r := affectedRanges(v, ecosystem, pkg.PackageKey.Name)
+		if r == nil {
+			continue
+		}
+		statuses := make([]VersionStatus, len(pkg.Versions))
+		for i, pv := range pkg.Versions {
+			statuses[i] = VersionStatus{pv.VersionKey.Version, r.status(system, pv.VersionKey.Version)}
+		}
+		return statuses, nil
+	}
+	return nil, fmt.Errorf("no OSV record of %s mentions %s", adv.AdvisoryKey.ID, pkg.PackageKey.Name)
+}
+
+// event is an OSV range event.
+type event struct {
+	kind    string // introduced, fixed, last_affected, or limit
+	version string
+}
+
+// ranges holds the affected versions of a package in an OSV record.
+type ranges struct {
+	ranges   [][]event
+	versions []string
+}
+
+// affectedRanges returns the affected ranges of the named package of the
+// given ecosystem in v, or nil if v does not mention the package. Git
+// commit ranges are ignored.
+func affectedRanges(v *Vulnerability, ecosystem, name string) *ranges {
+	var r *ranges
+	for _, a := range v.Affected {
+		if a.Package.Ecosystem != ecosystem || a.Package.Name != name {
+			continue
+		}
+		if r == nil {
+			r = new(ranges)
+		}
+		r.versions = append(r.versions, a.Versions...)
+		for _, rg := range a.Ranges {
+			if rg.Type == "GIT" {
+				continue
+			}
+			var events []event
+			for _, e := range rg.Events {
+				for kind, v := range e {
+					events = append(events, event{kind, v})
+				}
+			}
+			r.ranges = append(r.ranges, events)
+		}
+	}
+	return r
+}
+
+// status returns the status of version v of a package of the given system.
+func (r *ranges) status(system, v string) Status {
+	if slices.Contains(r.versions, v) {
+		return Affected
+	}
+	cmpTo := func(e event) int {
+		if e.kind == "introduced" && e.version == "0" {
+			return 1
+		}
+		return version.Compare(system, v, e.version)
+	}
+	fixed := false
+	for _, events := range r.ranges {
+		affected := false
+		for _, e := range sortEvents(system, events) {
+			c := cmpTo(e)
+			switch e.kind {
+			case "introduced":
+				if c >= 0 {
+					affected = true
+				}
+			case "fixed", "limit":
+				if c >= 0 {
+					affected = false
+					fixed = fixed || e.kind == "fixed"
+				}
+			case "last_affected":
+				if c > 0 {
+					affected = false
+					fixed = true
+				}
+			}
+		}
+		if affected {
+			return Affected
+		}
+	}
+	if fixed {
+		return Fixed
+	}
+	return Unaffected
+}
+
+// sortEvents returns the events of a range in increasing order of version.
+func sortEvents(system string, events []event) []event {
+	events = slices.Clone(events)
+	slices.SortStableFunc(events, func(a, b event) int {
+		switch {
+		case a.kind == "introduced" && a.version == "0":
+			return -1
+		case b.kind == "introduced" && b.version == "0":
+			return 1
+		}
+		return version.Compare(system, a.version, b.version)
+	})
+	return events
+}
+
+// FirstFixed returns the lowest version in statuses that fixed the advisory
+// and is greater than current, or the empty string if there is none.
+// Pre-releases are skipped. If current is empty, the lowest fixed version
+// is returned. The versions are those of a package of the given system.
+func FirstFixed(system string, statuses []VersionStatus, current string) string {
+	var first, cur *version.Version
+	if current != "" {
+		var err error
+		if cur, err = version.Parse(system, current); err != nil {
+			return ""
+		}
+	}
+	for _, s := range statuses {
+		if s.Status != Fixed {
+			continue
+		}
+		v, err := version.Parse(system, s.Version)
+		if err != nil || v.Prerelease() || cur != nil && v.Compare(cur) <= 0 {
+			continue
+		}
+		if first == nil || v.Compare(first) < 0 {
+			first = v
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	return first.String()
+}
+
+// FirstFixedVersion returns the earliest version of pkg greater than
+// current that is not affected by adv because it fixed it, as FirstFixed
+// does with the statuses from AffectedVersions. It returns the empty string
+// if no such version was released.
+func FirstFixedVersion(ctx context.Context, osv *OSVClient, adv *insights.Advisory, pkg *insights.Package, current string) (string, error) {
+	statuses, err := AffectedVersions(ctx, osv, adv, pkg)
+	if err != nil {
+		return "", err
+	}
+	return FirstFixed(pkg.PackageKey.System, statuses, current), nil
+}
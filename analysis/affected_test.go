@@ -0,0 +1,146 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setupOSV returns an OSV client talking to a test server that serves the
+// given records by ID.
+func setupOSV(t *testing.T, records map[string]string) *OSVClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec, ok := records[r.URL.Path[len("/v1/vulns/"):]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, rec)
+	}))
+	t.Cleanup(server.Close)
+	return &OSVClient{BaseURL: server.URL + "/v1/"}
+}
+
+const osvRecord = `{
+	"id": "CVE-1",
+	"affected": [
+		{
+			"package": {"ecosystem": "npm", "name": "other"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+		},
+		{
+			"package": {"ecosystem": "npm", "name": "a"},
+			"ranges": [
+				{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.0"}]},
+				{"type": "SEMVER", "events": [{"introduced": "2.0.0"}, {"last_affected": "2.1.0"}]},
+				{"type": "GIT", "events": [{"introduced": "abc"}]}
+			],
+			"versions": ["3.0.0-beta"]
+		}
+	]
+}`
+
+func testPackage(system, name string, versions ...string) *insights.Package {
+	p := &insights.Package{PackageKey: insights.PackageKey{System: system, Name: name}}
+	for _, v := range versions {
+		p.Versions = append(p.Versions, insights.Version{VersionKey: insights.VersionKey{System: system, Name: name, Version: v}})
+	}
+	return p
+}
+
+func TestAffectedVersions(t *testing.T) {
+	// The GHSA record is unknown, so the CVE alias is used.
+	osv := setupOSV(t, map[string]string{"CVE-1": osvRecord})
+	adv := &insights.Advisory{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-1"}, Aliases: []string{"CVE-1"}}
+	pkg := testPackage("NPM", "a", "1.0.0", "1.2.0", "1.9.0", "2.0.0", "2.1.0", "2.1.1", "3.0.0-beta", "3.0.0")
+
+	got, err := AffectedVersions(context.Background(), osv, adv, pkg)
+	if err != nil {
+		t.Fatalf("AffectedVersions failed: %v", err)
+	}
+	want := []VersionStatus{
+		{"1.0.0", Affected},
+		{"1.2.0", Fixed},
+		{"1.9.0", Fixed},
+		{"2.0.0", Affected},
+		{"2.1.0", Affected},
+		{"2.1.1", Fixed},
+		{"3.0.0-beta", Affected},
+		{"3.0.0", Fixed},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AffectedVersions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAffectedVersionsUnaffected(t *testing.T) {
+	osv := setupOSV(t, map[string]string{"GHSA-2": `{
+		"id": "GHSA-2",
+		"affected": [{"package": {"ecosystem": "PyPI", "name": "b"}, "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "1.0"}, {"fixed": "1.5"}]}]}]
+	}`})
+	adv := &insights.Advisory{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-2"}}
+	got, err := AffectedVersions(context.Background(), osv, adv, testPackage("PYPI", "b", "0.9", "1.0rc1", "1.0", "1.5"))
+	if err != nil {
+		t.Fatalf("AffectedVersions failed: %v", err)
+	}
+	want := []VersionStatus{{"0.9", Unaffected}, {"1.0rc1", Unaffected}, {"1.0", Affected}, {"1.5", Fixed}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AffectedVersions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAffectedVersionsNotMentioned(t *testing.T) {
+	osv := setupOSV(t, map[string]string{"CVE-1": osvRecord})
+	adv := &insights.Advisory{AdvisoryKey: insights.AdvisoryKey{ID: "CVE-1"}}
+	if _, err := AffectedVersions(context.Background(), osv, adv, testPackage("NPM", "z", "1.0.0")); err == nil {
+		t.Error("AffectedVersions of a package the advisory does not mention succeeded")
+	}
+}
+
+func TestFirstFixed(t *testing.T) {
+	statuses := []VersionStatus{
+		{"1.0.0", Affected},
+		{"1.3.0", Fixed},
+		{"1.2.0", Fixed},
+		{"2.0.0", Affected},
+		{"2.1.0-rc.1", Fixed},
+		{"2.1.0", Fixed},
+	}
+	testCases := []struct {
+		current, want string
+	}{
+		{"", "1.2.0"},
+		{"1.0.0", "1.2.0"},
+		{"1.2.0", "1.3.0"},
+		{"2.0.0", "2.1.0"},
+		{"2.1.0", ""},
+	}
+	for _, tc := range testCases {
+		if got := FirstFixed("NPM", statuses, tc.current); got != tc.want {
+			t.Errorf("FirstFixed(%q) = %q, want %q", tc.current, got, tc.want)
+		}
+	}
+}
+
+func TestFirstFixedVersion(t *testing.T) {
+	osv := setupOSV(t, map[string]string{"CVE-1": osvRecord})
+	adv := &insights.Advisory{AdvisoryKey: insights.AdvisoryKey{ID: "CVE-1"}}
+	pkg := testPackage("NPM", "a", "1.0.0", "1.2.0", "2.0.0", "2.1.1")
+	got, err := FirstFixedVersion(context.Background(), osv, adv, pkg, "2.0.0")
+	if err != nil {
+		t.Fatalf("FirstFixedVersion failed: %v", err)
+	}
+	if got != "2.1.1" {
+		t.Errorf("FirstFixedVersion = %q, want %q", got, "2.1.1")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package analysis answers questions about the dependencies of a project
+// that take more than one deps.dev request, such as what upgrading a
+// dependency would change.
+package analysis
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/franoliveto/insights"
+)
+
+// Options specifies optional parameters to the analyses.
+type Options struct {
+	// The maximum number of API requests in flight at once. If zero or
+	// negative, requests are sent one at a time.
+	Concurrency int
+}
+
+func (o *Options) concurrency() int {
+	if o != nil && o.Concurrency > 1 {
+		return o.Concurrency
+	}
+	return 1
+}
+
+// normalize returns k with its system in upper case, as deps.dev returns
+// it, so that keys from different sources compare equal.
+func normalize(k insights.VersionKey) insights.VersionKey {
+	k.System = strings.ToUpper(k.System)
+	return k
+}
+
+// forEach calls f for each index of a slice of length n, running up to
+// opts.Concurrency calls at once, and returns the first error returned.
+func forEach(ctx context.Context, n int, opts *Options, f func(i int) error) error {
+	sem := make(chan struct{}, opts.concurrency())
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			once.Do(func() { first = ctx.Err() })
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := f(i); err != nil {
+					once.Do(func() { first = err })
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return first
+}
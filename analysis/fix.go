@@ -0,0 +1,422 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/version"
+)
+
+// FixOptions specifies optional parameters to SuggestFixes.
+type FixOptions struct {
+	Options
+
+	// The IDs of the advisories to clear. If empty, all are.
+	Advisories []string
+
+	// The maximum number of newer versions of a direct dependency tried
+	// when looking for one that clears its advisories. If zero, 25 is
+	// used.
+	MaxCandidates int
+
+	// The client used to fetch affected version ranges. If nil, the
+	// public OSV.dev API is used.
+	OSV *OSVClient
+}
+
+// Finding is an advisory affecting a package version in the project.
+type Finding struct {
+	// The affected package version.
+	Package insights.VersionKey
+
+	// The direct dependency bringing the package version in.
+	Via insights.VersionKey
+
+	// The ID of the advisory.
+	Advisory string
+}
+
+// Bump is a proposed upgrade of a direct dependency.
+type Bump struct {
+	From, To insights.VersionKey
+
+	// The IDs of the advisories the upgrade clears, sorted.
+	Fixes []string
+}
+
+// Refresh is a proposed update of a package version brought in by a direct
+// dependency to one that still satisfies the requirements of its
+// dependents, such as by regenerating a lockfile.
+type Refresh struct {
+	From, To insights.VersionKey
+
+	// The IDs of the advisories the update clears, sorted.
+	Fixes []string
+}
+
+// FixPlan is the set of changes SuggestFixes recommends.
+type FixPlan struct {
+	// The advisories found, sorted by package and advisory.
+	Findings []Finding
+
+	// The direct dependencies to upgrade, sorted by name.
+	Bumps []Bump
+
+	// The indirect dependencies that can be updated without upgrading a
+	// direct dependency, sorted by name.
+	Refreshes []Refresh
+
+	// The findings no change considered clears.
+	Unfixed []Finding
+
+	// The changes to the packages of the project the plan would make, as
+	// WhatIf reports them.
+	Changes []Change
+}
+
+// SuggestFixes recommends the smallest upgrades of the direct dependencies
+// of a project that clear the advisories affecting the package versions
+// they bring in, with the project viewed as WhatIf does.
+//
+// An indirect dependency is only refreshed if OSV.dev knows a version that
+// fixed the advisory and that version satisfies the requirement of every
+// package depending on it in the graph of the direct dependency. Otherwise
+// the direct dependency is upgraded to its lowest newer release whose
+// graph has none of the advisories.
+//
+// Lookups that fail with an error other than insights.ErrNotFound do not
+// stop SuggestFixes, but the plan may then miss findings or fixes: their
+// errors are returned with it in a *insights.BatchError, by version,
+// package, or advisory ID.
+func SuggestFixes(ctx context.Context, c *insights.Client, direct []insights.VersionKey, opts *FixOptions) (*FixPlan, error) {
+	if opts == nil {
+		opts = new(FixOptions)
+	}
+	f := &fixer{
+		client: c,
+		opts:   opts,
+		osv:    opts.OSV,
+		ids:    make(map[insights.VersionKey]*advisoryLookup),
+	}
+	if f.osv == nil {
+		f.osv = new(OSVClient)
+	}
+	if len(opts.Advisories) > 0 {
+		f.wanted = make(map[string]bool)
+		for _, id := range opts.Advisories {
+			f.wanted[id] = true
+		}
+	}
+
+	graphs := make([]*insights.Dependencies, len(direct))
+	err := forEach(ctx, len(direct), &opts.Options, func(i int) error {
+		graphs[i], _ = f.graph(ctx, normalize(direct[i]))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := new(FixPlan)
+	newGraphs := slices.Clone(graphs)
+	for i, g := range graphs {
+		d := normalize(direct[i])
+		findings := f.findings(ctx, d, g)
+		if len(findings) == 0 {
+			continue
+		}
+		plan.Findings = append(plan.Findings, findings...)
+		bump, refreshes, unfixed, ng := f.fix(ctx, d, g, findings)
+		if bump != nil {
+			plan.Bumps = append(plan.Bumps, *bump)
+			newGraphs[i] = ng
+		}
+		plan.Refreshes = append(plan.Refreshes, refreshes...)
+		plan.Unfixed = append(plan.Unfixed, unfixed...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The dry run: the graphs of the upgraded direct dependencies replace
+	// the old ones, and refreshed package versions are replaced wherever
+	// they remain.
+	refreshed := make(map[insights.VersionKey]insights.VersionKey)
+	for _, r := range plan.Refreshes {
+		refreshed[r.From] = r.To
+	}
+	before := make(map[insights.VersionKey]bool)
+	after := make(map[insights.VersionKey]bool)
+	for i := range graphs {
+		for _, n := range graphs[i].Nodes {
+			before[normalize(n.VersionKey)] = true
+		}
+		for _, n := range newGraphs[i].Nodes {
+			k := normalize(n.VersionKey)
+			if to, ok := refreshed[k]; ok {
+				k = to
+			}
+			after[k] = true
+		}
+	}
+	plan.Changes = diffVersions(before, after)
+
+	byFinding := func(a, b Finding) int {
+		return cmp.Or(
+			strings.Compare(a.Package.Name, b.Package.Name),
+			version.Compare(a.Package.System, a.Package.Version, b.Package.Version),
+			strings.Compare(a.Advisory, b.Advisory),
+			strings.Compare(a.Via.Name, b.Via.Name))
+	}
+	slices.SortFunc(plan.Findings, byFinding)
+	slices.SortFunc(plan.Unfixed, byFinding)
+	slices.SortFunc(plan.Bumps, func(a, b Bump) int { return strings.Compare(a.From.Name, b.From.Name) })
+	slices.SortFunc(plan.Refreshes, func(a, b Refresh) int {
+		return cmp.Or(strings.Compare(a.From.Name, b.From.Name), strings.Compare(a.From.Version, b.From.Version))
+	})
+	return plan, f.batch.Err()
+}
+
+// fixer holds the state of SuggestFixes.
+type fixer struct {
+	client *insights.Client
+	opts   *FixOptions
+	osv    *OSVClient
+	wanted map[string]bool // nil for all advisories
+
+	// The failed lookups, but those of what deps.dev does not know.
+	batch insights.Batch
+
+	mu  sync.Mutex
+	ids map[insights.VersionKey]*advisoryLookup // advisories by package version
+}
+
+// fail records that the lookup of key failed with err, unless deps.dev
+// does not know key, and reports whether it failed.
+func (f *fixer) fail(key any, err error) bool {
+	if err != nil && !errors.Is(err, insights.ErrNotFound) {
+		f.batch.Fail(key, err)
+	}
+	return err != nil
+}
+
+// graph returns the resolved dependency graph of k or, if deps.dev has
+// none, a graph with k alone. It returns an error if the lookup failed
+// otherwise, with the graph of k alone.
+func (f *fixer) graph(ctx context.Context, k insights.VersionKey) (*insights.Dependencies, error) {
+	d, _, err := f.client.GetDependencies(ctx, k.System, k.Name, k.Version)
+	if f.fail(k, err) || len(d.Nodes) == 0 {
+		if errors.Is(err, insights.ErrNotFound) {
+			err = nil
+		}
+		return &insights.Dependencies{Nodes: []insights.Node{{VersionKey: k, Relation: "SELF"}}}, err
+	}
+	return d, nil
+}
+
+// advisoryIDs returns the IDs of the advisories to clear that affect k. It
+// reports whether they are known: false if the lookup of k failed for
+// another reason than deps.dev not knowing it.
+func (f *fixer) advisoryIDs(ctx context.Context, k insights.VersionKey) ([]string, bool) {
+	f.mu.Lock()
+	m, ok := f.ids[k]
+	if !ok {
+		m = new(advisoryLookup)
+		f.ids[k] = m
+	}
+	f.mu.Unlock()
+	m.once.Do(func() {
+		v, _, err := f.client.GetVersion(ctx, k.System, k.Name, k.Version)
+		if f.fail(k, err) {
+			m.known = errors.Is(err, insights.ErrNotFound)
+			return
+		}
+		m.known = true
+		for _, ak := range v.AdvisoryKeys {
+			if f.wanted == nil || f.wanted[ak.ID] {
+				m.ids = append(m.ids, ak.ID)
+			}
+		}
+	})
+	return m.ids, m.known
+}
+
+// advisoryLookup is the lookup of the advisories of a package version,
+// made once however many goroutines need it.
+type advisoryLookup struct {
+	once  sync.Once
+	ids   []string
+	known bool
+}
+
+// findings returns the advisories affecting the nodes of g, the graph of
+// the direct dependency d.
+func (f *fixer) findings(ctx context.Context, d insights.VersionKey, g *insights.Dependencies) []Finding {
+	ids := make([][]string, len(g.Nodes))
+	forEach(ctx, len(g.Nodes), &f.opts.Options, func(i int) error {
+		ids[i], _ = f.advisoryIDs(ctx, normalize(g.Nodes[i].VersionKey))
+		return nil
+	})
+	var findings []Finding
+	for i, n := range g.Nodes {
+		for _, id := range ids[i] {
+			findings = append(findings, Finding{Package: normalize(n.VersionKey), Via: d, Advisory: id})
+		}
+	}
+	return findings
+}
+
+// fix returns the changes clearing the findings in g, the graph of the
+// direct dependency d: an upgrade of d, if needed, and the graph of the
+// version it upgrades to, the refreshes of indirect dependencies, and the
+// findings that remain.
+func (f *fixer) fix(ctx context.Context, d insights.VersionKey, g *insights.Dependencies, findings []Finding) (*Bump, []Refresh, []Finding, *insights.Dependencies) {
+	var (
+		refreshes []Refresh
+		rest      []Finding
+	)
+	refreshed := make(map[insights.VersionKey]int)
+	for _, fd := range findings {
+		if fd.Package == d {
+			rest = append(rest, fd)
+			continue
+		}
+		to := f.refresh(ctx, g, fd)
+		if to == "" {
+			rest = append(rest, fd)
+			continue
+		}
+		if i, ok := refreshed[fd.Package]; ok && refreshes[i].To.Version == to {
+			refreshes[i].Fixes = append(refreshes[i].Fixes, fd.Advisory)
+			continue
+		} else if ok {
+			// Another advisory needs a different version; upgrade d instead.
+			rest = append(rest, fd)
+			continue
+		}
+		refreshed[fd.Package] = len(refreshes)
+		k := fd.Package
+		k.Version = to
+		refreshes = append(refreshes, Refresh{From: fd.Package, To: k, Fixes: []string{fd.Advisory}})
+	}
+	for i := range refreshes {
+		slices.Sort(refreshes[i].Fixes)
+	}
+	if len(rest) == 0 {
+		return nil, refreshes, nil, g
+	}
+
+	// Some findings need an upgrade of d, which replaces its whole graph,
+	// refreshes included.
+	pending := make(map[string]bool)
+	for _, fd := range findings {
+		pending[fd.Advisory] = true
+	}
+	for _, v := range f.candidates(ctx, d) {
+		k := d
+		k.Version = v
+		ng, err := f.graph(ctx, k)
+		if err == nil && f.clears(ctx, ng, pending) {
+			b := &Bump{From: d, To: k}
+			for id := range pending {
+				b.Fixes = append(b.Fixes, id)
+			}
+			slices.Sort(b.Fixes)
+			return b, nil, nil, ng
+		}
+	}
+	return nil, refreshes, rest, g
+}
+
+// candidates returns the releases of the package of d newer than d, in
+// increasing order and at most opts.MaxCandidates of them.
+func (f *fixer) candidates(ctx context.Context, d insights.VersionKey) []string {
+	p, _, err := f.client.GetPackage(ctx, d.System, d.Name)
+	if f.fail(insights.PackageKey{System: d.System, Name: d.Name}, err) {
+		return nil
+	}
+	cur, err := version.Parse(d.System, d.Version)
+	if err != nil {
+		return nil
+	}
+	var vs []*version.Version
+	for _, pv := range p.Versions {
+		v, err := version.Parse(d.System, pv.VersionKey.Version)
+		if err == nil && !v.Prerelease() && v.Compare(cur) > 0 {
+			vs = append(vs, v)
+		}
+	}
+	slices.SortFunc(vs, (*version.Version).Compare)
+	limit := f.opts.MaxCandidates
+	if limit <= 0 {
+		limit = 25
+	}
+	var out []string
+	for i := 0; i < len(vs) && i < limit; i++ {
+		out = append(out, vs[i].String())
+	}
+	return out
+}
+
+// clears reports whether no node of g is affected by the advisories in ids.
+// Nodes whose advisories are unknown are taken as affected.
+func (f *fixer) clears(ctx context.Context, g *insights.Dependencies, ids map[string]bool) bool {
+	found := make([]bool, len(g.Nodes))
+	forEach(ctx, len(g.Nodes), &f.opts.Options, func(i int) error {
+		nids, ok := f.advisoryIDs(ctx, normalize(g.Nodes[i].VersionKey))
+		found[i] = !ok
+		for _, id := range nids {
+			found[i] = found[i] || ids[id]
+		}
+		return nil
+	})
+	return !slices.Contains(found, true) && ctx.Err() == nil
+}
+
+// refresh returns the version the package of the finding fd, an indirect
+// dependency in the graph g, can be updated to to clear the advisory, or
+// the empty string if there is no such version.
+func (f *fixer) refresh(ctx context.Context, g *insights.Dependencies, fd Finding) string {
+	k := fd.Package
+	adv, _, err := f.client.GetAdvisory(ctx, fd.Advisory)
+	if f.fail(fd.Advisory, err) {
+		return ""
+	}
+	p, _, err := f.client.GetPackage(ctx, k.System, k.Name)
+	if f.fail(insights.PackageKey{System: k.System, Name: k.Name}, err) {
+		return ""
+	}
+	to, err := FirstFixedVersion(ctx, f.osv, adv, p, k.Version)
+	if err != nil || to == "" {
+		return ""
+	}
+	v, err := version.Parse(k.System, to)
+	if err != nil {
+		return ""
+	}
+	// Every dependent must accept the fixed version.
+	matched := false
+	for _, e := range g.Edges {
+		if e.ToNode < 0 || e.ToNode >= len(g.Nodes) || normalize(g.Nodes[e.ToNode].VersionKey) != k {
+			continue
+		}
+		c, err := version.ParseConstraint(k.System, e.Requirement)
+		if err != nil || !c.Match(v) {
+			return ""
+		}
+		matched = true
+	}
+	if !matched {
+		return ""
+	}
+	return to
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+// handlePackage serves the npm package name with the given versions.
+func handlePackage(mux *http.ServeMux, name string, versions ...string) {
+	var js []string
+	for _, v := range versions {
+		js = append(js, fmt.Sprintf(`{"versionKey":{"system":"NPM","name":%q,"version":%q}}`, name, v))
+	}
+	mux.HandleFunc("/systems/NPM/packages/"+name, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"packageKey":{"system":"NPM","name":%q},"versions":[%s]}`, name, strings.Join(js, ","))
+	})
+}
+
+func TestSuggestFixes(t *testing.T) {
+	client, mux := setup(t)
+
+	// a@1.0.0 brings in c@1.0.0, which has a fix within its requirement.
+	mux.HandleFunc("/systems/NPM/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"nodes":[
+			{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"}},
+			{"versionKey":{"system":"NPM","name":"c","version":"1.0.0"}}
+		],"edges":[{"fromNode":0,"toNode":1,"requirement":"^1.0.0"}]}`)
+	})
+	handleVersion(mux, "a@1.0.0")
+	handleVersion(mux, "c@1.0.0", "GHSA-C")
+	handlePackage(mux, "c", "1.0.0", "1.0.1")
+
+	// b@1.0.0 is affected itself and only fixed in 1.2.0.
+	handleGraph(mux, "b@1.0.0", "d@1.0.0")
+	handleGraph(mux, "b@1.1.0", "d@1.0.0")
+	handleGraph(mux, "b@1.2.0", "d@2.0.0")
+	handleVersion(mux, "b@1.0.0", "GHSA-B")
+	mux.HandleFunc("/systems/NPM/packages/b/versions/1.1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"b","version":"1.1.0"},"advisoryKeys":[{"id":"GHSA-B"}]}`)
+	})
+	handleVersion(mux, "b@1.2.0")
+	handleVersion(mux, "d@1.0.0")
+	handleVersion(mux, "d@2.0.0")
+	handlePackage(mux, "b", "1.0.0", "1.1.0", "1.2.0", "2.0.0", "3.0.0-rc.1")
+
+	osv := setupOSV(t, map[string]string{"GHSA-C": `{
+		"id": "GHSA-C",
+		"affected": [{"package": {"ecosystem": "npm", "name": "c"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.0.1"}]}]}]
+	}`})
+
+	direct := []insights.VersionKey{
+		{System: "NPM", Name: "a", Version: "1.0.0"},
+		{System: "NPM", Name: "b", Version: "1.0.0"},
+	}
+	got, err := SuggestFixes(context.Background(), client, direct, &FixOptions{Options: Options{Concurrency: 4}, OSV: osv})
+	if err != nil {
+		t.Fatalf("SuggestFixes failed: %v", err)
+	}
+
+	key := func(name, v string) insights.VersionKey {
+		return insights.VersionKey{System: "NPM", Name: name, Version: v}
+	}
+	want := &FixPlan{
+		Findings: []Finding{
+			{Package: key("b", "1.0.0"), Via: key("b", "1.0.0"), Advisory: "GHSA-B"},
+			{Package: key("c", "1.0.0"), Via: key("a", "1.0.0"), Advisory: "GHSA-C"},
+		},
+		Bumps:     []Bump{{From: key("b", "1.0.0"), To: key("b", "1.2.0"), Fixes: []string{"GHSA-B"}}},
+		Refreshes: []Refresh{{From: key("c", "1.0.0"), To: key("c", "1.0.1"), Fixes: []string{"GHSA-C"}}},
+		Changes: []Change{
+			{System: "NPM", Name: "b", Before: []string{"1.0.0"}, After: []string{"1.2.0"}},
+			{System: "NPM", Name: "c", Before: []string{"1.0.0"}, After: []string{"1.0.1"}},
+			{System: "NPM", Name: "d", Before: []string{"1.0.0"}, After: []string{"2.0.0"}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SuggestFixes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSuggestFixesSelected(t *testing.T) {
+	client, mux := setup(t)
+	handleGraph(mux, "a@1.0.0")
+	handleVersion(mux, "a@1.0.0", "GHSA-A")
+
+	direct := []insights.VersionKey{{System: "NPM", Name: "a", Version: "1.0.0"}}
+	got, err := SuggestFixes(context.Background(), client, direct, &FixOptions{Advisories: []string{"GHSA-OTHER"}})
+	if err != nil {
+		t.Fatalf("SuggestFixes failed: %v", err)
+	}
+	if diff := cmp.Diff(&FixPlan{}, got); diff != "" {
+		t.Errorf("SuggestFixes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSuggestFixesErrors(t *testing.T) {
+	client, mux := setup(t)
+	fail := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	}
+	// The graph of a cannot be fetched, nor the version of c in the graph
+	// of b; d is unknown to deps.dev, which is not an error.
+	mux.HandleFunc("/systems/NPM/packages/a/versions/1.0.0:dependencies", fail)
+	handleVersion(mux, "a@1.0.0")
+	handleGraph(mux, "b@1.0.0", "c@1.0.0")
+	handleVersion(mux, "b@1.0.0")
+	mux.HandleFunc("/systems/NPM/packages/c/versions/1.0.0", fail)
+
+	direct := []insights.VersionKey{
+		{System: "NPM", Name: "a", Version: "1.0.0"},
+		{System: "NPM", Name: "b", Version: "1.0.0"},
+		{System: "NPM", Name: "d", Version: "1.0.0"},
+	}
+	got, err := SuggestFixes(context.Background(), client, direct, nil)
+	var be *insights.BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("SuggestFixes returned error %v; want a *BatchError", err)
+	}
+	var keys []any
+	for _, ke := range be.Errors {
+		keys = append(keys, ke.Key)
+	}
+	slices.SortFunc(keys, func(a, b any) int { return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)) })
+	want := []any{
+		insights.VersionKey{System: "NPM", Name: "a", Version: "1.0.0"},
+		insights.VersionKey{System: "NPM", Name: "c", Version: "1.0.0"},
+	}
+	if diff := cmp.Diff(want, keys); diff != "" {
+		t.Errorf("failed keys mismatch (-want +got):\n%s", diff)
+	}
+	if got == nil || len(got.Findings) != 0 {
+		t.Errorf("SuggestFixes returned plan %+v; want one without findings", got)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// nonStandard is the license deps.dev reports for license texts it could not
+// match to an SPDX identifier.
+const nonStandard = "non-standard"
+
+// LicenseItem is a package version whose license needs a human to look at.
+type LicenseItem struct {
+	Package insights.VersionKey
+
+	// The licenses deps.dev reports for the package version, if any.
+	Licenses []string
+
+	// The links of the package version, such as to its source repository
+	// and homepage, where the license may be found.
+	Links []insights.Link
+
+	// The source repository of the package version and the license
+	// deps.dev found in it, if known.
+	SourceRepository string
+	ProjectLicense   string
+
+	// Describes why information about the package version could not be
+	// obtained.
+	Error string
+}
+
+// LicenseWorklist returns the package versions among keys whose license is
+// unknown or non-standard, in the order of keys, with the information that
+// can help to find their actual license. The items of the package versions
+// that could not be looked up have an Error, and their errors are also
+// returned with the worklist in a *insights.BatchError, by version.
+func LicenseWorklist(ctx context.Context, c *insights.Client, keys []insights.VersionKey, opts *Options) ([]LicenseItem, error) {
+	items := make([]*LicenseItem, len(keys))
+	var batch insights.Batch
+	err := forEach(ctx, len(keys), opts, func(i int) error {
+		items[i] = licenseItem(ctx, c, keys[i], &batch)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var list []LicenseItem
+	for _, it := range items {
+		if it != nil {
+			list = append(list, *it)
+		}
+	}
+	return list, batch.Err()
+}
+
+// licenseItem returns the worklist item for k, or nil if its license is
+// known. The error of the lookup of k is recorded in batch.
+func licenseItem(ctx context.Context, c *insights.Client, k insights.VersionKey, batch *insights.Batch) *LicenseItem {
+	v, _, err := c.GetVersion(ctx, k.System, k.Name, k.Version)
+	if err != nil {
+		batch.Fail(k, err)
+		return &LicenseItem{Package: k, Error: err.Error()}
+	}
+	if !unclearLicense(v.Licenses) {
+		return nil
+	}
+	it := &LicenseItem{Package: k, Licenses: v.Licenses, Links: v.Links}
+	for _, rp := range v.RelatedProjects {
+		if rp.RelationType != "SOURCE_REPO" {
+			continue
+		}
+		it.SourceRepository = rp.ProjectKey.ID
+		// A missing project is not worth failing the item for.
+		if p, _, err := c.GetProject(ctx, rp.ProjectKey.ID); err == nil {
+			it.ProjectLicense = p.License
+		}
+		break
+	}
+	return it
+}
+
+// unclearLicense reports whether licenses, as reported by deps.dev, leave
+// the license of a package version unknown.
+func unclearLicense(licenses []string) bool {
+	if len(licenses) == 0 {
+		return true
+	}
+	for _, l := range licenses {
+		if strings.EqualFold(l, nonStandard) {
+			return true
+		}
+	}
+	return false
+}
+
+// LicenseGroup is the package versions under a license.
+type LicenseGroup struct {
+	// The license, as an SPDX expression, "non-standard", or empty if the
+	// package versions report none.
+	License string
+
+	// Whether the license leaves the actual license of the package
+	// versions unknown, as for non-standard or missing licenses.
+	Unclear bool
+
+	// The package versions, sorted.
+	Packages []insights.VersionKey
+}
+
+// LicenseInventory is an inventory of the licenses of a set of package
+// versions.
+type LicenseInventory struct {
+	// The licenses, by their number of package versions, most first, and
+	// then by license. A package version reporting several licenses is
+	// under each of them.
+	Groups []LicenseGroup
+
+	// The package versions whose licenses could not be obtained, with the
+	// reason.
+	Failed []LicenseItem
+}
+
+// Licenses returns an inventory of the licenses of the package versions
+// among keys. The package versions that could not be looked up are listed
+// in Failed, and their errors are also returned with the inventory in a
+// *insights.BatchError, by version.
+func Licenses(ctx context.Context, c *insights.Client, keys []insights.VersionKey, opts *Options) (*LicenseInventory, error) {
+	// A package version may be found in more than one file.
+	seen := make(map[insights.VersionKey]bool)
+	var unique []insights.VersionKey
+	for _, k := range keys {
+		if k = normalize(k); !seen[k] {
+			seen[k] = true
+			unique = append(unique, k)
+		}
+	}
+	licenses := make([][]string, len(unique))
+	errs := make([]error, len(unique))
+	err := forEach(ctx, len(unique), opts, func(i int) error {
+		k := unique[i]
+		v, _, err := c.GetVersion(ctx, k.System, k.Name, k.Version)
+		if err != nil {
+			errs[i] = err
+			return nil
+		}
+		licenses[i] = v.Licenses
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inv := new(LicenseInventory)
+	var batch insights.Batch
+	groups := make(map[string]*LicenseGroup)
+	add := func(license string, k insights.VersionKey) {
+		g, ok := groups[license]
+		if !ok {
+			g = &LicenseGroup{License: license, Unclear: license == "" || strings.EqualFold(license, nonStandard)}
+			groups[license] = g
+		}
+		g.Packages = append(g.Packages, k)
+	}
+	for i, k := range unique {
+		if errs[i] != nil {
+			inv.Failed = append(inv.Failed, LicenseItem{Package: k, Error: errs[i].Error()})
+			batch.Fail(k, errs[i])
+			continue
+		}
+		if len(licenses[i]) == 0 {
+			add("", k)
+		}
+		for _, l := range licenses[i] {
+			add(l, k)
+		}
+	}
+	for _, g := range groups {
+		slices.SortFunc(g.Packages, compareKeys)
+		inv.Groups = append(inv.Groups, *g)
+	}
+	slices.SortFunc(inv.Groups, func(a, b LicenseGroup) int {
+		return cmp.Or(cmp.Compare(len(b.Packages), len(a.Packages)), cmp.Compare(a.License, b.License))
+	})
+	return inv, batch.Err()
+}
+
+// compareKeys orders version keys by system, name, and version.
+func compareKeys(a, b insights.VersionKey) int {
+	return cmp.Or(cmp.Compare(a.System, b.System), cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+}
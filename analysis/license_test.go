@@ -0,0 +1,98 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLicenseWorklist(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/NPM/packages/a/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"licenses":["MIT"]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/b/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"licenses":["non-standard"],
+			"links":[{"label":"SOURCE_REPO","url":"https://github.com/x/b"}],
+			"relatedProjects":[{"projectKey":{"id":"github.com/x/b"},"relationType":"SOURCE_REPO"}]
+		}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fx%2Fb", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"projectKey":{"id":"github.com/x/b"},"license":"Apache-2.0"}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/c/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/d/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "version not found", http.StatusNotFound)
+	})
+
+	key := func(name string) insights.VersionKey {
+		return insights.VersionKey{System: "NPM", Name: name, Version: "1.0.0"}
+	}
+	keys := []insights.VersionKey{key("a"), key("b"), key("c"), key("d")}
+	got, err := LicenseWorklist(context.Background(), client, keys, &Options{Concurrency: 2})
+	if diff := cmp.Diff([]any{key("d")}, failedKeys(t, err)); diff != "" {
+		t.Errorf("LicenseWorklist failed keys mismatch (-want +got):\n%s", diff)
+	}
+	want := []LicenseItem{
+		{
+			Package:          key("b"),
+			Licenses:         []string{"non-standard"},
+			Links:            []insights.Link{{Label: "SOURCE_REPO", URL: "https://github.com/x/b"}},
+			SourceRepository: "github.com/x/b",
+			ProjectLicense:   "Apache-2.0",
+		},
+		{Package: key("c")},
+		{Package: key("d"), Error: "404 version not found\n"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LicenseWorklist mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLicenses(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/NPM/packages/a/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"licenses":["MIT"]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/b/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"licenses":["MIT","non-standard"]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/c/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/d/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "version not found", http.StatusNotFound)
+	})
+
+	key := func(name string) insights.VersionKey {
+		return insights.VersionKey{System: "NPM", Name: name, Version: "1.0.0"}
+	}
+	// a is found twice, once with its system in lower case.
+	keys := []insights.VersionKey{key("b"), key("a"), {System: "npm", Name: "a", Version: "1.0.0"}, key("c"), key("d")}
+	got, err := Licenses(context.Background(), client, keys, &Options{Concurrency: 2})
+	if diff := cmp.Diff([]any{key("d")}, failedKeys(t, err)); diff != "" {
+		t.Errorf("Licenses failed keys mismatch (-want +got):\n%s", diff)
+	}
+	want := &LicenseInventory{
+		Groups: []LicenseGroup{
+			{License: "MIT", Packages: []insights.VersionKey{key("a"), key("b")}},
+			{License: "", Unclear: true, Packages: []insights.VersionKey{key("c")}},
+			{License: "non-standard", Unclear: true, Packages: []insights.VersionKey{key("b")}},
+		},
+		Failed: []LicenseItem{{Package: key("d"), Error: "404 version not found\n"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Licenses mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+
+	"github.com/franoliveto/insights"
+)
+
+// Systems are the package management systems known to deps.dev.
+var Systems = []string{"GO", "RUBYGEMS", "NPM", "CARGO", "MAVEN", "PYPI", "NUGET"}
+
+// SystemPackage is a package with a given name in one system.
+type SystemPackage struct {
+	System string
+
+	// The name of the package, as deps.dev knows it.
+	Name string
+
+	// The number of versions of the package and its default version, if
+	// any.
+	Versions       int
+	DefaultVersion string
+
+	// The number of package versions depending on the default version, as
+	// a measure of its popularity.
+	Dependents int
+
+	// Describes why the package, or its dependents, could not be
+	// obtained.
+	Error string
+}
+
+// LookupAll looks up the package with the given name in each of Systems
+// and returns those that have one, in the order of Systems. Systems that
+// failed to answer are included with an Error, and their errors are also
+// returned with the packages in a *insights.BatchError, by package. It
+// helps to tell which ecosystem a name refers to, or to spot the same
+// project published to several, or a name squatted in another.
+func LookupAll(ctx context.Context, c *insights.Client, name string, opts *Options) ([]SystemPackage, error) {
+	found := make([]*SystemPackage, len(Systems))
+	var batch insights.Batch
+	err := forEach(ctx, len(Systems), opts, func(i int) error {
+		var err error
+		if found[i], err = lookup(ctx, c, Systems[i], name); err != nil {
+			batch.Fail(insights.PackageKey{System: Systems[i], Name: name}, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []SystemPackage
+	for _, p := range found {
+		if p != nil {
+			pkgs = append(pkgs, *p)
+		}
+	}
+	return pkgs, batch.Err()
+}
+
+// Lookup returns the package with the given name in system, or nil if
+// there is none. Failing to get it, or its dependents, is recorded in its
+// Error.
+func Lookup(ctx context.Context, c *insights.Client, system, name string) *SystemPackage {
+	sp, _ := lookup(ctx, c, system, name)
+	return sp
+}
+
+// lookup is like Lookup, but also returns the error recorded in the Error
+// of the package.
+func lookup(ctx context.Context, c *insights.Client, system, name string) (*SystemPackage, error) {
+	p, _, err := c.GetPackage(ctx, system, name)
+	if errors.Is(err, insights.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return &SystemPackage{System: system, Name: name, Error: err.Error()}, err
+	}
+	sp := &SystemPackage{System: system, Name: p.PackageKey.Name, Versions: len(p.Versions)}
+	if sp.Name == "" {
+		sp.Name = name
+	}
+	for _, v := range p.Versions {
+		if v.IsDefault {
+			sp.DefaultVersion = v.VersionKey.Version
+		}
+	}
+	if sp.DefaultVersion == "" {
+		return sp, nil
+	}
+	// Dependents deps.dev does not know of are left at zero.
+	d, _, err := c.GetDependents(ctx, system, sp.Name, sp.DefaultVersion)
+	switch {
+	case err == nil:
+		sp.Dependents = d.DependentCount
+	case !errors.Is(err, insights.ErrNotFound):
+		sp.Error = err.Error()
+		return sp, err
+	}
+	return sp, nil
+}
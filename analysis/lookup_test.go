@@ -0,0 +1,44 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLookupAll(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/NPM/packages/left-pad", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packageKey":{"system":"NPM","name":"left-pad"},"versions":[{"versionKey":{"version":"1.2.0"}},{"versionKey":{"version":"1.3.0"},"isDefault":true}]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/left-pad/versions/1.3.0:dependents", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"dependentCount":4000}`)
+	})
+	mux.HandleFunc("/systems/PYPI/packages/left-pad", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packageKey":{"system":"PYPI","name":"left-pad"},"versions":[{"versionKey":{"version":"0.1"},"isDefault":true}]}`)
+	})
+	mux.HandleFunc("/systems/NUGET/packages/left-pad", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	got, err := LookupAll(context.Background(), client, "left-pad", &Options{Concurrency: 3})
+	if diff := cmp.Diff([]any{insights.PackageKey{System: "NUGET", Name: "left-pad"}}, failedKeys(t, err)); diff != "" {
+		t.Errorf("LookupAll failed keys mismatch (-want +got):\n%s", diff)
+	}
+	want := []SystemPackage{
+		{System: "NPM", Name: "left-pad", Versions: 2, DefaultVersion: "1.3.0", Dependents: 4000},
+		{System: "PYPI", Name: "left-pad", Versions: 1, DefaultVersion: "0.1"},
+		{System: "NUGET", Name: "left-pad", Error: "503 unavailable\n"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LookupAll mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/franoliveto/insights"
+)
+
+// ObscureOptions specifies optional parameters to Obscure.
+type ObscureOptions struct {
+	Options
+
+	// The depth from which dependencies are considered; direct dependencies
+	// have depth 1. If zero, 2 is used.
+	MinDepth int
+
+	// The number of dependents, per level of depth, below which a
+	// dependency is obscure. If zero, 10 is used.
+	MinDependents int
+
+	// The number of stars of the source repository below which a
+	// dependency is obscure. If zero, 10 is used.
+	MinStars int
+}
+
+// ObscureDependency is a deep dependency with little use.
+type ObscureDependency struct {
+	Package insights.VersionKey
+
+	// The length of the shortest path from the root of the graph.
+	Depth int
+
+	// The number of package versions depending on the package version.
+	Dependents int
+
+	// The source repository of the package version and its number of
+	// stars, if known.
+	SourceRepository string
+	Stars            int
+}
+
+// Obscure returns the dependencies in the graph g that are at least
+// opts.MinDepth deep and little used: those with fewer dependents than
+// opts.MinDependents times their depth, and whose source repository has
+// fewer than opts.MinStars stars or is unknown. Obscure, deeply nested
+// packages draw little scrutiny, which makes them a common vector of
+// supply chain attacks. The result is sorted from the deepest and least
+// used. Nodes whose dependents deps.dev does not know are skipped.
+//
+// Lookups that fail for another reason do not stop Obscure; the nodes they
+// are for are skipped, or returned with what is known about them, and
+// their errors are returned with the result in a *insights.BatchError, by
+// version or project ID.
+func Obscure(ctx context.Context, c *insights.Client, g *insights.Dependencies, opts *ObscureOptions) ([]ObscureDependency, error) {
+	if opts == nil {
+		opts = new(ObscureOptions)
+	}
+	minDepth := cmp.Or(opts.MinDepth, 2)
+	minDependents := cmp.Or(opts.MinDependents, 10)
+	minStars := cmp.Or(opts.MinStars, 10)
+
+	depth := g.Depths()
+	var candidates []int
+	seen := make(map[insights.VersionKey]bool)
+	for i, n := range g.Nodes {
+		if depth[i] >= minDepth && !seen[n.VersionKey] {
+			seen[n.VersionKey] = true
+			candidates = append(candidates, i)
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		out   []ObscureDependency
+		batch insights.Batch
+	)
+	// fail records the error of the lookup of key, unless deps.dev does not
+	// know key, and reports whether it failed.
+	fail := func(key any, err error) bool {
+		if err != nil && !errors.Is(err, insights.ErrNotFound) {
+			batch.Fail(key, err)
+		}
+		return err != nil
+	}
+	err := forEach(ctx, len(candidates), &opts.Options, func(j int) error {
+		i := candidates[j]
+		k := g.Nodes[i].VersionKey
+		d, _, err := c.GetDependents(ctx, k.System, k.Name, k.Version)
+		if fail(k, err) || d.DependentCount >= minDependents*depth[i] {
+			return nil
+		}
+		o := ObscureDependency{Package: k, Depth: depth[i], Dependents: d.DependentCount}
+		if v, _, err := c.GetVersion(ctx, k.System, k.Name, k.Version); !fail(k, err) {
+			for _, rp := range v.RelatedProjects {
+				if rp.RelationType != "SOURCE_REPO" {
+					continue
+				}
+				o.SourceRepository = rp.ProjectKey.ID
+				if p, _, err := c.GetProject(ctx, rp.ProjectKey.ID); !fail(rp.ProjectKey.ID, err) {
+					o.Stars = p.StarsCount
+				}
+				break
+			}
+		}
+		if o.Stars >= minStars {
+			return nil
+		}
+		mu.Lock()
+		out = append(out, o)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(out, func(a, b ObscureDependency) int {
+		return cmp.Or(
+			cmp.Compare(b.Depth, a.Depth),
+			cmp.Compare(a.Dependents, b.Dependents),
+			cmp.Compare(a.Package.Name, b.Package.Name),
+			cmp.Compare(a.Package.Version, b.Package.Version))
+	})
+	return out, batch.Err()
+}
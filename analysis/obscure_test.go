@@ -0,0 +1,59 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestObscure(t *testing.T) {
+	client, mux := setup(t)
+	dependents := map[string]int{"b": 3, "c": 15, "d": 5, "e": 1}
+	for name, n := range dependents {
+		mux.HandleFunc(fmt.Sprintf("/systems/NPM/packages/%s/versions/1.0.0:dependents", name), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"dependentCount":%d}`, n)
+		})
+		mux.HandleFunc(fmt.Sprintf("/systems/NPM/packages/%s/versions/1.0.0", name), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"relatedProjects":[{"projectKey":{"id":"github.com/x/%s"},"relationType":"SOURCE_REPO"}]}`, name)
+		})
+	}
+	mux.HandleFunc("/projects/github.com%2Fx%2Fc", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"starsCount":2}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fx%2Fd", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"starsCount":500}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fx%2Fe", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"starsCount":1}`)
+	})
+
+	key := func(name string) insights.VersionKey {
+		return insights.VersionKey{System: "NPM", Name: name, Version: "1.0.0"}
+	}
+	// a -> b -> c -> e, a -> d
+	g := &insights.Dependencies{
+		Nodes: []insights.Node{{VersionKey: key("a")}, {VersionKey: key("b")}, {VersionKey: key("c")}, {VersionKey: key("d")}, {VersionKey: key("e")}},
+		Edges: []insights.Edge{{FromNode: 0, ToNode: 1}, {FromNode: 1, ToNode: 2}, {FromNode: 0, ToNode: 3}, {FromNode: 2, ToNode: 4}},
+	}
+	got, err := Obscure(context.Background(), client, g, &ObscureOptions{Options: Options{Concurrency: 2}})
+	if err != nil {
+		t.Fatalf("Obscure failed: %v", err)
+	}
+	// b is a direct dependency and d is starred; c has 15 dependents, fewer
+	// than 10 per level at depth 2.
+	want := []ObscureDependency{
+		{Package: key("e"), Depth: 3, Dependents: 1, SourceRepository: "github.com/x/e", Stars: 1},
+		{Package: key("c"), Depth: 2, Dependents: 15, SourceRepository: "github.com/x/c", Stars: 2},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Obscure mismatch (-want +got):\n%s", diff)
+	}
+}
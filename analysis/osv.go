@@ -0,0 +1,107 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+const defaultOSVURL = "https://api.osv.dev/v1/"
+
+// OSVClient fetches vulnerability records from OSV.dev, which has the
+// affected version ranges deps.dev does not expose. The zero value is
+// ready to use.
+type OSVClient struct {
+	// The HTTP client used to send requests. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+
+	// Retry, if not nil, tells how to retry requests that failed because
+	// of a network error, a rate limit, or a transient server error.
+	Retry *insights.RetryPolicy
+
+	// The base URL of the OSV API. If empty, the public API is used.
+	BaseURL string
+}
+
+// Vulnerability is an OSV vulnerability record, reduced to what is needed
+// to tell which versions of a package it affects.
+type Vulnerability struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			// One of SEMVER, ECOSYSTEM, or GIT.
+			Type string `json:"type"`
+
+			// Each event has one key, one of "introduced", "fixed",
+			// "last_affected", or "limit", whose value is a version.
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+
+		// Versions affected besides those in Ranges.
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+}
+
+// Vuln returns the vulnerability record with the given ID or alias, or nil
+// if OSV.dev has none.
+func (o *OSVClient) Vuln(ctx context.Context, id string) (*Vulnerability, error) {
+	base := o.BaseURL
+	if base == "" {
+		base = defaultOSVURL
+	}
+	u := strings.TrimSuffix(base, "/") + "/vulns/" + url.PathEscape(id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	hc := o.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := o.Retry.Do(hc, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		io.Copy(io.Discard, resp.Body)
+		return nil, nil
+	default:
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	v := new(Vulnerability)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// osvEcosystems maps deps.dev systems to OSV ecosystems.
+var osvEcosystems = map[string]string{
+	"GO":       "Go",
+	"NPM":      "npm",
+	"CARGO":    "crates.io",
+	"MAVEN":    "Maven",
+	"PYPI":     "PyPI",
+	"NUGET":    "NuGet",
+	"RUBYGEMS": "RubyGems",
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/version"
+)
+
+// ProjectPackage is a package published from a project.
+type ProjectPackage struct {
+	System string
+	Name   string
+
+	// Versions of the package built from the project, newest first.
+	Versions []ProjectVersion
+}
+
+// ProjectVersion is a version of a package published from a project.
+type ProjectVersion struct {
+	Version string
+
+	// The IDs of the advisories affecting the version.
+	Advisories []string
+
+	// Describes why the advisories of the version could not be obtained.
+	Error string
+}
+
+// ProjectPackages returns the packages published from the project with the
+// given ID, such as a repository shipping both an npm and a PyPI package,
+// sorted by system and name, so that their versions and advisories can be
+// compared side by side. Only the latest versions of each package are
+// included, at most latest of them, or all if latest is zero. The versions
+// whose advisories could not be looked up have an Error, and their errors
+// are also returned with the packages in a *insights.BatchError, by
+// version.
+func ProjectPackages(ctx context.Context, c *insights.Client, id string, latest int, opts *Options) ([]ProjectPackage, error) {
+	pv, _, err := c.GetProjectPackageVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	type pkg struct{ system, name string }
+	byPkg := make(map[pkg]*ProjectPackage)
+	for _, v := range pv.Versions {
+		k := normalize(v.VersionKey)
+		p := byPkg[pkg{k.System, k.Name}]
+		if p == nil {
+			p = &ProjectPackage{System: k.System, Name: k.Name}
+			byPkg[pkg{k.System, k.Name}] = p
+		}
+		if !slices.ContainsFunc(p.Versions, func(pv ProjectVersion) bool { return pv.Version == k.Version }) {
+			p.Versions = append(p.Versions, ProjectVersion{Version: k.Version})
+		}
+	}
+
+	var pkgs []ProjectPackage
+	var versions []*ProjectVersion // all versions, to fetch their advisories
+	for _, p := range byPkg {
+		slices.SortFunc(p.Versions, func(a, b ProjectVersion) int { return version.Compare(p.System, b.Version, a.Version) })
+		if latest > 0 && len(p.Versions) > latest {
+			p.Versions = p.Versions[:latest]
+		}
+		pkgs = append(pkgs, *p)
+	}
+	slices.SortFunc(pkgs, func(a, b ProjectPackage) int {
+		return cmp.Or(strings.Compare(a.System, b.System), strings.Compare(a.Name, b.Name))
+	})
+	var keys []insights.VersionKey
+	for i := range pkgs {
+		for j := range pkgs[i].Versions {
+			versions = append(versions, &pkgs[i].Versions[j])
+			keys = append(keys, insights.VersionKey{System: pkgs[i].System, Name: pkgs[i].Name, Version: pkgs[i].Versions[j].Version})
+		}
+	}
+
+	var batch insights.Batch
+	err = forEach(ctx, len(keys), opts, func(i int) error {
+		k := keys[i]
+		v, _, err := c.GetVersion(ctx, k.System, k.Name, k.Version)
+		if err != nil {
+			versions[i].Error = err.Error()
+			batch.Fail(k, err)
+			return nil
+		}
+		for _, ak := range v.AdvisoryKeys {
+			versions[i].Advisories = append(versions[i].Advisories, ak.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pkgs, batch.Err()
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProjectPackages(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/projects/github.com%2Fx%2Fy:packageversions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versions":[
+			{"versionKey":{"system":"PYPI","name":"y","version":"1.0"}},
+			{"versionKey":{"system":"NPM","name":"y","version":"1.0.0"}},
+			{"versionKey":{"system":"NPM","name":"y","version":"1.10.0"}},
+			{"versionKey":{"system":"NPM","name":"y","version":"1.2.0"}},
+			{"versionKey":{"system":"NPM","name":"y","version":"1.2.0"}}
+		]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/y/versions/1.10.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/y/versions/1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"advisoryKeys":[{"id":"GHSA-1"}]}`)
+	})
+	mux.HandleFunc("/systems/PYPI/packages/y/versions/1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"advisoryKeys":[{"id":"PYSEC-1"}]}`)
+	})
+
+	got, err := ProjectPackages(context.Background(), client, "github.com/x/y", 2, &Options{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("ProjectPackages failed: %v", err)
+	}
+	want := []ProjectPackage{
+		{System: "NPM", Name: "y", Versions: []ProjectVersion{{Version: "1.10.0"}, {Version: "1.2.0", Advisories: []string{"GHSA-1"}}}},
+		{System: "PYPI", Name: "y", Versions: []ProjectVersion{{Version: "1.0", Advisories: []string{"PYSEC-1"}}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProjectPackages mismatch (-want +got):\n%s", diff)
+	}
+}
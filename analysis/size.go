@@ -0,0 +1,129 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+
+	"github.com/franoliveto/insights"
+)
+
+// A Sizer estimates the installed size of package versions, in bytes, or
+// returns 0 if it cannot. registry.Client is a Sizer, using the metadata
+// of the package registries.
+type Sizer interface {
+	Size(ctx context.Context, system, name, version string) (int64, error)
+}
+
+// NodeSize is the estimated installed size of a node of a dependency
+// graph.
+type NodeSize struct {
+	// The index of the node in the graph, and its package version.
+	Node       int
+	VersionKey insights.VersionKey
+
+	// The size of the package version, or 0 if unknown. Bundled
+	// dependencies are part of the package bundling them, and have none.
+	Size int64
+
+	// The size of the package version and of every package version it
+	// depends on, directly or indirectly, counting each package version
+	// once.
+	Total int64
+
+	// Describes why the size of the package version could not be obtained.
+	Error string
+}
+
+// InstallSize is the estimated installed size of a dependency graph.
+type InstallSize struct {
+	// The sizes of the nodes of the graph, in graph order.
+	Nodes []NodeSize
+
+	// The size of the whole graph: the Total of its root.
+	Total int64
+
+	// The number of package versions whose size is unknown, which the
+	// totals leave out.
+	Unknown int
+}
+
+// EstimateInstallSize estimates the installed size of the dependency graph
+// g, looking up the size of each of its package versions with s. The nodes
+// whose size could not be looked up have an Error, and their errors are
+// also returned with the estimate in a *insights.BatchError, by version.
+func EstimateInstallSize(ctx context.Context, s Sizer, g *insights.Dependencies, opts *Options) (*InstallSize, error) {
+	// Package versions, once each, that are not bundled.
+	var keys []insights.VersionKey
+	seen := make(map[insights.VersionKey]bool)
+	for _, n := range g.Nodes {
+		if !n.Bundled && !seen[n.VersionKey] {
+			seen[n.VersionKey] = true
+			keys = append(keys, n.VersionKey)
+		}
+	}
+	sizes := make([]int64, len(keys))
+	errs := make([]error, len(keys))
+	err := forEach(ctx, len(keys), opts, func(i int) error {
+		k := keys[i]
+		sizes[i], errs[i] = s.Size(ctx, k.System, k.Name, k.Version)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	size := make(map[insights.VersionKey]int64)
+	failed := make(map[insights.VersionKey]string)
+	is := new(InstallSize)
+	var batch insights.Batch
+	for i, k := range keys {
+		switch {
+		case errs[i] != nil:
+			failed[k] = errs[i].Error()
+			batch.Fail(k, errs[i])
+			is.Unknown++
+		case sizes[i] == 0:
+			is.Unknown++
+		}
+		size[k] = sizes[i]
+	}
+
+	adj := make([][]int, len(g.Nodes))
+	for _, e := range g.Edges {
+		if e.FromNode >= 0 && e.FromNode < len(g.Nodes) && e.ToNode >= 0 && e.ToNode < len(g.Nodes) {
+			adj[e.FromNode] = append(adj[e.FromNode], e.ToNode)
+		}
+	}
+	for i, n := range g.Nodes {
+		ns := NodeSize{Node: i, VersionKey: n.VersionKey, Error: failed[n.VersionKey]}
+		if !n.Bundled {
+			ns.Size = size[n.VersionKey]
+		}
+		// The package versions reachable from the node.
+		counted := make(map[insights.VersionKey]bool)
+		visited := make([]bool, len(g.Nodes))
+		visited[i] = true
+		stack := []int{i}
+		for len(stack) > 0 {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if nm := g.Nodes[m]; !nm.Bundled && !counted[nm.VersionKey] {
+				counted[nm.VersionKey] = true
+				ns.Total += size[nm.VersionKey]
+			}
+			for _, next := range adj[m] {
+				if !visited[next] {
+					visited[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		is.Nodes = append(is.Nodes, ns)
+	}
+	if len(is.Nodes) > 0 {
+		is.Total = is.Nodes[0].Total
+	}
+	return is, batch.Err()
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeSizer returns the sizes of package versions by name.
+type fakeSizer map[string]int64
+
+func (s fakeSizer) Size(ctx context.Context, system, name, version string) (int64, error) {
+	size, ok := s[name]
+	if !ok {
+		return 0, errors.New("not found")
+	}
+	return size, nil
+}
+
+func TestEstimateInstallSize(t *testing.T) {
+	key := func(name string) insights.VersionKey {
+		return insights.VersionKey{System: "NPM", Name: name, Version: "1.0.0"}
+	}
+	// root -> a -> c, root -> b -> c, b bundles d, and e is unknown.
+	g := &insights.Dependencies{
+		Nodes: []insights.Node{
+			{VersionKey: key("root"), Relation: "SELF"},
+			{VersionKey: key("a"), Relation: "DIRECT"},
+			{VersionKey: key("b"), Relation: "DIRECT"},
+			{VersionKey: key("c"), Relation: "INDIRECT"},
+			{VersionKey: key("b>1.0.0>d"), Relation: "INDIRECT", Bundled: true},
+			{VersionKey: key("e"), Relation: "DIRECT"},
+		},
+		Edges: []insights.Edge{
+			{FromNode: 0, ToNode: 1}, {FromNode: 0, ToNode: 2}, {FromNode: 0, ToNode: 5},
+			{FromNode: 1, ToNode: 3}, {FromNode: 2, ToNode: 3}, {FromNode: 2, ToNode: 4},
+		},
+	}
+	s := fakeSizer{"root": 1, "a": 10, "b": 100, "c": 1000}
+	got, err := EstimateInstallSize(context.Background(), s, g, &Options{Concurrency: 2})
+	if diff := cmp.Diff([]any{key("e")}, failedKeys(t, err)); diff != "" {
+		t.Errorf("EstimateInstallSize failed keys mismatch (-want +got):\n%s", diff)
+	}
+	want := &InstallSize{
+		Nodes: []NodeSize{
+			{Node: 0, VersionKey: key("root"), Size: 1, Total: 1111},
+			{Node: 1, VersionKey: key("a"), Size: 10, Total: 1010},
+			{Node: 2, VersionKey: key("b"), Size: 100, Total: 1100},
+			{Node: 3, VersionKey: key("c"), Size: 1000, Total: 1000},
+			{Node: 4, VersionKey: key("b>1.0.0>d")},
+			{Node: 5, VersionKey: key("e"), Error: "not found"},
+		},
+		Total:   1111,
+		Unknown: 1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("EstimateInstallSize mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/franoliveto/insights"
+)
+
+// GraphStats summarizes a dependency graph, as a quick overview of its
+// health.
+type GraphStats struct {
+	// The number of nodes and edges of the graph.
+	Nodes, Edges int
+
+	// The length of the longest of the shortest paths from the root to
+	// the nodes of the graph.
+	Depth int
+
+	// The number of distinct package versions the root depends on directly
+	// and indirectly.
+	Direct, Indirect int
+
+	// The packages present in more than one version.
+	Duplicates []Duplicate
+
+	// The number of package versions under each license. Package versions
+	// without a known license are counted under "unknown".
+	Licenses map[string]int
+
+	// The number of distinct advisories affecting the graph, and of package
+	// versions affected by at least one.
+	Advisories, Vulnerable int
+
+	// The number of package versions whose details could not be obtained,
+	// and which are left out of the license and advisory counts.
+	Errors int
+}
+
+// Duplicate is a package present in a dependency graph in several
+// versions.
+type Duplicate struct {
+	System, Name string
+
+	// The versions in the graph, sorted as strings.
+	Versions []string
+}
+
+// Stats returns statistics about the dependency graph g. The licenses and
+// advisories of its package versions are looked up, and failing lookups
+// are counted in the Errors field; their errors are also returned with the
+// statistics in a *insights.BatchError, by version.
+func Stats(ctx context.Context, c *insights.Client, g *insights.Dependencies, opts *Options) (*GraphStats, error) {
+	s := &GraphStats{Nodes: len(g.Nodes), Edges: len(g.Edges), Licenses: make(map[string]int)}
+	for _, d := range g.Depths() {
+		s.Depth = max(s.Depth, d)
+	}
+
+	// Package versions other than the root, once each, in graph order.
+	var keys []insights.VersionKey
+	relation := make(map[insights.VersionKey]string)
+	versions := make(map[insights.PackageKey][]string)
+	for _, n := range g.Nodes {
+		if n.Relation == "SELF" {
+			continue
+		}
+		k := n.VersionKey
+		r, seen := relation[k]
+		if !seen {
+			keys = append(keys, k)
+			pk := insights.PackageKey{System: k.System, Name: k.Name}
+			versions[pk] = append(versions[pk], k.Version)
+		}
+		if r != "DIRECT" {
+			relation[k] = n.Relation
+		}
+	}
+	for _, r := range relation {
+		if r == "DIRECT" {
+			s.Direct++
+		} else {
+			s.Indirect++
+		}
+	}
+	for pk, vs := range versions {
+		if len(vs) > 1 {
+			slices.Sort(vs)
+			s.Duplicates = append(s.Duplicates, Duplicate{System: pk.System, Name: pk.Name, Versions: vs})
+		}
+	}
+	slices.SortFunc(s.Duplicates, func(a, b Duplicate) int {
+		return cmp.Or(cmp.Compare(a.System, b.System), cmp.Compare(a.Name, b.Name))
+	})
+
+	var (
+		mu         sync.Mutex
+		advisories = make(map[string]bool)
+		batch      insights.Batch
+	)
+	err := forEach(ctx, len(keys), opts, func(i int) error {
+		k := keys[i]
+		v, _, err := c.GetVersion(ctx, k.System, k.Name, k.Version)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			s.Errors++
+			batch.Fail(k, err)
+			return nil
+		}
+		if len(v.Licenses) == 0 {
+			s.Licenses["unknown"]++
+		}
+		for _, l := range v.Licenses {
+			s.Licenses[l]++
+		}
+		if len(v.AdvisoryKeys) > 0 {
+			s.Vulnerable++
+		}
+		for _, a := range v.AdvisoryKeys {
+			advisories[a.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.Advisories = len(advisories)
+	return s, batch.Err()
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStats(t *testing.T) {
+	client, mux := setup(t)
+	versions := map[string]string{
+		"b/versions/1.0.0": `{"licenses":["MIT"],"advisoryKeys":[{"id":"GHSA-1"},{"id":"GHSA-2"}]}`,
+		"c/versions/1.0.0": `{"licenses":["MIT","Apache-2.0"]}`,
+		"c/versions/2.0.0": `{"advisoryKeys":[{"id":"GHSA-1"}]}`,
+	}
+	for path, body := range versions {
+		mux.HandleFunc("/systems/NPM/packages/"+path, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+
+	key := func(name, version string) insights.VersionKey {
+		return insights.VersionKey{System: "NPM", Name: name, Version: version}
+	}
+	// a -> b -> c@1 -> d, a -> c@2, with d unknown to deps.dev.
+	g := &insights.Dependencies{
+		Nodes: []insights.Node{
+			{VersionKey: key("a", "1.0.0"), Relation: "SELF"},
+			{VersionKey: key("b", "1.0.0"), Relation: "DIRECT"},
+			{VersionKey: key("c", "1.0.0"), Relation: "INDIRECT"},
+			{VersionKey: key("c", "2.0.0"), Relation: "DIRECT"},
+			{VersionKey: key("d", "1.0.0"), Relation: "INDIRECT"},
+		},
+		Edges: []insights.Edge{{FromNode: 0, ToNode: 1}, {FromNode: 1, ToNode: 2}, {FromNode: 0, ToNode: 3}, {FromNode: 2, ToNode: 4}},
+	}
+	got, err := Stats(context.Background(), client, g, &Options{Concurrency: 2})
+	if diff := cmp.Diff([]any{key("d", "1.0.0")}, failedKeys(t, err)); diff != "" {
+		t.Errorf("Stats failed keys mismatch (-want +got):\n%s", diff)
+	}
+	want := &GraphStats{
+		Nodes:      5,
+		Edges:      4,
+		Depth:      3,
+		Direct:     2,
+		Indirect:   2,
+		Duplicates: []Duplicate{{System: "NPM", Name: "c", Versions: []string{"1.0.0", "2.0.0"}}},
+		Licenses:   map[string]int{"MIT": 2, "Apache-2.0": 1, "unknown": 1},
+		Advisories: 2,
+		Vulnerable: 2,
+		Errors:     1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Stats mismatch (-want +got):\n%s", diff)
+	}
+}
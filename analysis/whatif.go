@@ -0,0 +1,240 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/version"
+)
+
+// Impact is the estimated effect of upgrading a direct dependency of a
+// project.
+type Impact struct {
+	// The direct dependency at its current and proposed versions.
+	From, To insights.VersionKey
+
+	// The packages whose versions in the project would change, sorted by
+	// system and name.
+	Changes []Change
+
+	// The advisories affecting package versions the upgrade removes from
+	// the project but none it adds, and those affecting versions it adds
+	// but none it removes. Both are sorted by ID.
+	Fixed      []*insights.Advisory
+	Introduced []*insights.Advisory
+}
+
+// Change is a change of the versions of a package in a project.
+type Change struct {
+	System string
+	Name   string
+
+	// The versions of the package in the project before and after the
+	// change, in increasing order. Before is empty for a package that is
+	// added and After for one that is removed.
+	Before []string
+	After  []string
+}
+
+// WhatIf estimates the effect on a project whose direct dependencies are
+// direct of upgrading the one named in upgrade to the version it gives.
+// The project is viewed as the union of the resolved dependency graphs of
+// its direct dependencies, as deps.dev resolves each on its own; the
+// upgrade replaces the graph of one of them. The graphs of the other direct
+// dependencies are fetched too, so that packages they also bring in are not
+// reported as removed.
+//
+// Downgrades are simulated the same way.
+//
+// If the graph of another direct dependency, or the versions of packages
+// that change, cannot be looked up, for another reason than deps.dev not
+// knowing them, the impact is estimated without them and returned with
+// their errors in a *insights.BatchError, by version.
+func WhatIf(ctx context.Context, c *insights.Client, direct []insights.VersionKey, upgrade insights.VersionKey, opts *Options) (*Impact, error) {
+	upgrade = normalize(upgrade)
+	target := -1
+	for i, k := range direct {
+		if k = normalize(k); k.System == upgrade.System && k.Name == upgrade.Name {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("%s is not a direct dependency", upgrade.Name)
+	}
+	im := &Impact{From: normalize(direct[target]), To: upgrade}
+
+	// graphs[i] holds the package versions direct[i] brings in; the last
+	// one is for the upgrade.
+	keys := append(slices.Clone(direct), upgrade)
+	graphs := make([][]insights.VersionKey, len(keys))
+	var batch insights.Batch
+	err := forEach(ctx, len(keys), opts, func(i int) error {
+		k := normalize(keys[i])
+		d, _, err := c.GetDependencies(ctx, k.System, k.Name, k.Version)
+		if err != nil {
+			if i == target || i == len(keys)-1 {
+				return fmt.Errorf("%s@%s: %w", k.Name, k.Version, err)
+			}
+			if !errors.Is(err, insights.ErrNotFound) {
+				batch.Fail(k, err)
+			}
+			// The dependency alone is the best guess.
+			graphs[i] = []insights.VersionKey{k}
+			return nil
+		}
+		for _, n := range d.Nodes {
+			graphs[i] = append(graphs[i], normalize(n.VersionKey))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	before := make(map[insights.VersionKey]bool)
+	after := make(map[insights.VersionKey]bool)
+	for i, g := range graphs {
+		for _, k := range g {
+			if i != len(graphs)-1 {
+				before[k] = true
+			}
+			if i != target {
+				after[k] = true
+			}
+		}
+	}
+	im.Changes = diffVersions(before, after)
+
+	var removed, added []insights.VersionKey
+	for k := range before {
+		if !after[k] {
+			removed = append(removed, k)
+		}
+	}
+	for k := range after {
+		if !before[k] {
+			added = append(added, k)
+		}
+	}
+	a := &advisories{client: c, byID: make(map[string]*insights.Advisory)}
+	oldIDs, err := a.affecting(ctx, removed, opts, &batch)
+	if err != nil {
+		return nil, err
+	}
+	newIDs, err := a.affecting(ctx, added, opts, &batch)
+	if err != nil {
+		return nil, err
+	}
+	for id := range oldIDs {
+		if !newIDs[id] {
+			im.Fixed = append(im.Fixed, a.byID[id])
+		}
+	}
+	for id := range newIDs {
+		if !oldIDs[id] {
+			im.Introduced = append(im.Introduced, a.byID[id])
+		}
+	}
+	byID := func(x, y *insights.Advisory) int { return strings.Compare(x.AdvisoryKey.ID, y.AdvisoryKey.ID) }
+	slices.SortFunc(im.Fixed, byID)
+	slices.SortFunc(im.Introduced, byID)
+	return im, batch.Err()
+}
+
+// diffVersions returns the changes of the versions of each package between
+// the sets of package versions before and after.
+func diffVersions(before, after map[insights.VersionKey]bool) []Change {
+	type pkg struct{ system, name string }
+	all := make(map[pkg]*Change)
+	changed := make(map[pkg]bool)
+	for _, s := range []struct {
+		set, other map[insights.VersionKey]bool
+		before     bool
+	}{{before, after, true}, {after, before, false}} {
+		for k := range s.set {
+			p := pkg{k.System, k.Name}
+			ch := all[p]
+			if ch == nil {
+				ch = &Change{System: k.System, Name: k.Name}
+				all[p] = ch
+			}
+			if s.before {
+				ch.Before = append(ch.Before, k.Version)
+			} else {
+				ch.After = append(ch.After, k.Version)
+			}
+			if !s.other[k] {
+				changed[p] = true
+			}
+		}
+	}
+
+	var out []Change
+	for p := range changed {
+		ch := all[p]
+		byVersion := func(a, b string) int { return version.Compare(ch.System, a, b) }
+		slices.SortFunc(ch.Before, byVersion)
+		slices.SortFunc(ch.After, byVersion)
+		out = append(out, *ch)
+	}
+	slices.SortFunc(out, func(a, b Change) int {
+		return cmp.Or(strings.Compare(a.System, b.System), strings.Compare(a.Name, b.Name))
+	})
+	return out
+}
+
+// advisories fetches and remembers advisories.
+type advisories struct {
+	client *insights.Client
+
+	mu   sync.Mutex
+	byID map[string]*insights.Advisory
+}
+
+// affecting returns the IDs of the advisories affecting any of keys.
+// Versions deps.dev does not know about are ignored; the versions that
+// cannot be looked up otherwise are recorded in batch. The returned error
+// is that of ctx, if done.
+func (a *advisories) affecting(ctx context.Context, keys []insights.VersionKey, opts *Options, batch *insights.Batch) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	err := forEach(ctx, len(keys), opts, func(i int) error {
+		k := keys[i]
+		v, _, err := a.client.GetVersion(ctx, k.System, k.Name, k.Version)
+		if err != nil {
+			if !errors.Is(err, insights.ErrNotFound) {
+				batch.Fail(k, err)
+			}
+			return nil
+		}
+		for _, ak := range v.AdvisoryKeys {
+			a.mu.Lock()
+			ids[ak.ID] = true
+			_, ok := a.byID[ak.ID]
+			a.mu.Unlock()
+			if ok {
+				continue
+			}
+			adv, _, err := a.client.GetAdvisory(ctx, ak.ID)
+			if err != nil {
+				// Keep what is known about the advisory.
+				adv = &insights.Advisory{AdvisoryKey: ak}
+			}
+			a.mu.Lock()
+			a.byID[ak.ID] = adv
+			a.mu.Unlock()
+		}
+		return nil
+	})
+	return ids, err
+}
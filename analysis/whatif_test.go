@@ -0,0 +1,157 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setup returns a client talking to a test server whose API handlers are
+// registered on mux.
+func setup(t *testing.T) (*insights.Client, *http.ServeMux) {
+	t.Helper()
+	mux := http.NewServeMux()
+	apiMux := http.NewServeMux()
+	apiMux.Handle("/v3/", http.StripPrefix("/v3", mux))
+	apiMux.Handle("/v3alpha/", http.StripPrefix("/v3alpha", mux))
+	server := httptest.NewServer(apiMux)
+	t.Cleanup(server.Close)
+
+	client := insights.NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/v3/")
+	return client, mux
+}
+
+// failedKeys returns the keys of the *insights.BatchError err. It fails
+// the test if err is some other error.
+func failedKeys(t *testing.T, err error) []any {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var be *insights.BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("got error %v; want a *BatchError", err)
+	}
+	var keys []any
+	for _, e := range be.Errors {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+// handleGraph serves the dependency graph of the npm package version
+// nodes[0], made of the given "name@version" nodes.
+func handleGraph(mux *http.ServeMux, nodes ...string) {
+	name, v, _ := strings.Cut(nodes[0], "@")
+	var js []string
+	for _, n := range nodes {
+		name, v, _ := strings.Cut(n, "@")
+		js = append(js, fmt.Sprintf(`{"versionKey":{"system":"NPM","name":%q,"version":%q}}`, name, v))
+	}
+	mux.HandleFunc(fmt.Sprintf("/systems/NPM/packages/%s/versions/%s:dependencies", name, v), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"nodes":[%s]}`, strings.Join(js, ","))
+	})
+}
+
+// handleVersion serves the npm package version nv, affected by the given
+// advisories.
+func handleVersion(mux *http.ServeMux, nv string, advisories ...string) {
+	name, v, _ := strings.Cut(nv, "@")
+	var keys []string
+	for _, id := range advisories {
+		keys = append(keys, fmt.Sprintf(`{"id":%q}`, id))
+	}
+	mux.HandleFunc(fmt.Sprintf("/systems/NPM/packages/%s/versions/%s", name, v), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"versionKey":{"system":"NPM","name":%q,"version":%q},"advisoryKeys":[%s]}`, name, v, strings.Join(keys, ","))
+	})
+	for _, id := range advisories {
+		mux.HandleFunc("/advisories/"+id, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"advisoryKey":{"id":%q},"title":"bad"}`, id)
+		})
+	}
+}
+
+func TestWhatIf(t *testing.T) {
+	client, mux := setup(t)
+	handleGraph(mux, "a@1.0.0", "c@1.0.0", "d@1.0.0")
+	handleGraph(mux, "a@2.0.0", "c@2.0.0", "e@1.0.0")
+	handleGraph(mux, "b@1.0.0", "d@1.0.0")
+	handleVersion(mux, "a@1.0.0")
+	handleVersion(mux, "a@2.0.0")
+	handleVersion(mux, "c@1.0.0", "GHSA-1")
+	handleVersion(mux, "c@2.0.0")
+	handleVersion(mux, "e@1.0.0", "GHSA-2")
+
+	direct := []insights.VersionKey{
+		{System: "npm", Name: "a", Version: "1.0.0"},
+		{System: "npm", Name: "b", Version: "1.0.0"},
+	}
+	upgrade := insights.VersionKey{System: "npm", Name: "a", Version: "2.0.0"}
+	got, err := WhatIf(context.Background(), client, direct, upgrade, &Options{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("WhatIf failed: %v", err)
+	}
+	want := &Impact{
+		From: insights.VersionKey{System: "NPM", Name: "a", Version: "1.0.0"},
+		To:   insights.VersionKey{System: "NPM", Name: "a", Version: "2.0.0"},
+		Changes: []Change{
+			{System: "NPM", Name: "a", Before: []string{"1.0.0"}, After: []string{"2.0.0"}},
+			{System: "NPM", Name: "c", Before: []string{"1.0.0"}, After: []string{"2.0.0"}},
+			{System: "NPM", Name: "e", After: []string{"1.0.0"}},
+		},
+		Fixed:      []*insights.Advisory{{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-1"}, Title: "bad"}},
+		Introduced: []*insights.Advisory{{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-2"}, Title: "bad"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("WhatIf mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWhatIfNotDirect(t *testing.T) {
+	client, _ := setup(t)
+	direct := []insights.VersionKey{{System: "NPM", Name: "a", Version: "1.0.0"}}
+	_, err := WhatIf(context.Background(), client, direct, insights.VersionKey{System: "NPM", Name: "z", Version: "1.0.0"}, nil)
+	if err == nil {
+		t.Error("WhatIf of a package that is not a direct dependency succeeded")
+	}
+}
+
+func TestWhatIfLookupErrors(t *testing.T) {
+	client, mux := setup(t)
+	handleGraph(mux, "a@1.0.0", "c@1.0.0", "d@1.0.0")
+	handleGraph(mux, "a@2.0.0", "c@2.0.0")
+	handleVersion(mux, "a@1.0.0")
+	handleVersion(mux, "a@2.0.0")
+	handleVersion(mux, "c@2.0.0")
+	// c@1.0.0 cannot be looked up, and d@1.0.0 is unknown to deps.dev.
+	mux.HandleFunc("/systems/NPM/packages/c/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	})
+
+	direct := []insights.VersionKey{{System: "NPM", Name: "a", Version: "1.0.0"}}
+	upgrade := insights.VersionKey{System: "NPM", Name: "a", Version: "2.0.0"}
+	got, err := WhatIf(context.Background(), client, direct, upgrade, nil)
+	var be *insights.BatchError
+	if !errors.As(err, &be) || len(be.Errors) != 1 {
+		t.Fatalf("WhatIf returned error %v; want a *BatchError for c@1.0.0", err)
+	}
+	if k := be.Errors[0].Key; k != (insights.VersionKey{System: "NPM", Name: "c", Version: "1.0.0"}) {
+		t.Errorf("WhatIf failed for %v; want c@1.0.0", k)
+	}
+	if got == nil || len(got.Changes) != 3 {
+		t.Errorf("WhatIf returned impact %+v; want the changes despite the errors", got)
+	}
+}
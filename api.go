@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // PackageKey identifies a package by name.
@@ -108,16 +110,46 @@ type Package struct {
 	Versions []Version
 }
 
-// GetPackage returns information about a package.
+// GetPackage returns information about a package. If deps.dev does not
+// know the package, it is asked of the client's Fallback, if any.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#getpackage
-func (c *Client) GetPackage(ctx context.Context, system string, name string) (*Package, error) {
-	path := fmt.Sprintf("systems/%s/packages/%s", url.PathEscape(system), url.PathEscape(name))
+func (c *Client) GetPackage(ctx context.Context, system string, name string) (*Package, *Response, error) {
+	path := fmt.Sprintf("systems/%s/packages/%s", url.PathEscape(system), url.PathEscape(CanonicalName(system, name)))
 	p := new(Package)
-	if err := c.get(ctx, path, p); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointGetPackage, path, p, versionAttrs(system, name, "")...)
+	if err != nil {
+		return c.fallbackPackage(ctx, system, CanonicalName(system, name), resp, err)
+	}
+	return p, resp, nil
+}
+
+// SimilarlyNamedPackages holds packages whose names are similar to the name of
+// a given package.
+type SimilarlyNamedPackages struct {
+	// The packages with similar names.
+	Packages []struct {
+		// The name of the package.
+		PackageKey PackageKey
+	}
+}
+
+// GetSimilarlyNamedPackages returns packages with names that are similar to
+// the requested package. This may be useful for detecting typosquatting
+// attacks, where a malicious package is published with a name that is
+// similar to a popular package.
+//
+// This endpoint is only available in the v3alpha API.
+//
+// deps.dev API doc: https://docs.deps.dev/api/v3alpha/#getsimilarlynamedpackages
+func (c *Client) GetSimilarlyNamedPackages(ctx context.Context, system, name string) (*SimilarlyNamedPackages, *Response, error) {
+	path := fmt.Sprintf("systems/%s/packages/%s:similarlyNamedPackages", url.PathEscape(system), url.PathEscape(CanonicalName(system, name)))
+	s := new(SimilarlyNamedPackages)
+	resp, err := c.getEndpoint(ctx, EndpointGetSimilarlyNamedPackages, path, s, versionAttrs(system, name, "")...)
+	if err != nil {
+		return nil, resp, err
 	}
-	return p, nil
+	return s, resp, nil
 }
 
 // Version holds information about a package version.
@@ -135,6 +167,10 @@ type Version struct {
 	// version number, ignoring pre-release versions.
 	IsDefault bool
 
+	// If true, this version is deprecated, as marked by its publisher in
+	// the package management authority, such as with npm deprecate.
+	IsDeprecated bool
+
 	// The licenses governing the use of this package version.
 	//
 	// We identify licenses as
@@ -198,16 +234,20 @@ type Version struct {
 	}
 }
 
-// GetVersion returns information about a specific package version.
+// GetVersion returns information about a specific package version. If
+// deps.dev does not know the version, or some of its metadata, it is asked
+// of the client's Fallback, if any.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#getversion
-func (c *Client) GetVersion(ctx context.Context, system, name, version string) (*Version, error) {
-	path := fmt.Sprintf("systems/%s/packages/%s/versions/%s", url.PathEscape(system), url.PathEscape(name), url.PathEscape(version))
+func (c *Client) GetVersion(ctx context.Context, system, name, version string) (*Version, *Response, error) {
+	path := fmt.Sprintf("systems/%s/packages/%s/versions/%s", url.PathEscape(system), url.PathEscape(CanonicalName(system, name)), url.PathEscape(version))
 	v := new(Version)
-	if err := c.get(ctx, path, v); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointGetVersion, path, v, versionAttrs(system, name, version)...)
+	if err != nil {
+		return c.fallbackVersion(ctx, system, CanonicalName(system, name), version, resp, err)
 	}
-	return v, nil
+	c.completeVersion(ctx, v)
+	return v, resp, nil
 }
 
 // Node represents a node in a resolved dependency graph.
@@ -282,13 +322,54 @@ type Dependencies struct {
 // GetDependencies returns a resolved dependency graph for the given package version.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#getdependencies
-func (c *Client) GetDependencies(ctx context.Context, system, name, version string) (*Dependencies, error) {
+func (c *Client) GetDependencies(ctx context.Context, system, name, version string) (*Dependencies, *Response, error) {
+	name = CanonicalName(system, name)
 	path := fmt.Sprintf("systems/%s/packages/%s/versions/%s:dependencies", url.PathEscape(system), url.PathEscape(name), url.PathEscape(version))
+	k := VersionKey{System: system, Name: name, Version: version}
+	if c.GraphCache != nil {
+		if d, ok := c.GraphCache.Get(k); ok {
+			return d, cachedResponse(nil), nil
+		}
+	}
 	d := new(Dependencies)
-	if err := c.get(ctx, path, d); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointGetDependencies, path, d, versionAttrs(system, name, version)...)
+	if err != nil {
+		return nil, resp, err
+	}
+	if c.GraphCache != nil {
+		c.GraphCache.Set(k, d)
+	}
+	return d, resp, nil
+}
+
+// Dependents holds information about the number of package versions that
+// depend on a given package version.
+type Dependents struct {
+	// The total number of dependents.
+	DependentCount int
+
+	// The number of dependents that depend on the package version directly.
+	DirectDependentCount int
+
+	// The number of dependents that depend on the package version only
+	// indirectly, through one or more other dependencies.
+	IndirectDependentCount int
+}
+
+// GetDependents returns information about the number of distinct packages
+// known to depend on the given package version.
+//
+// This endpoint is only available in the v3alpha API.
+//
+// deps.dev API doc: https://docs.deps.dev/api/v3alpha/#getdependents
+func (c *Client) GetDependents(ctx context.Context, system, name, version string) (*Dependents, *Response, error) {
+	path := fmt.Sprintf("systems/%s/packages/%s/versions/%s:dependents", url.PathEscape(system), url.PathEscape(CanonicalName(system, name)), url.PathEscape(version))
+	d := new(Dependents)
+	resp, err := c.getEndpoint(ctx, EndpointGetDependents, path, d, versionAttrs(system, name, version)...)
+	if err != nil {
+		return nil, resp, err
 	}
-	return d, nil
+	return d, resp, nil
 }
 
 // Project holds information about a project hosted by GitHub, GitLab, or
@@ -406,13 +487,14 @@ type OSSFuzzDetails struct {
 // GetProject returns information about projects hosted by GitHub, GitLab, or BitBucket.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#getproject
-func (c *Client) GetProject(ctx context.Context, id string) (*Project, error) {
+func (c *Client) GetProject(ctx context.Context, id string) (*Project, *Response, error) {
 	path := fmt.Sprintf("projects/%s", url.PathEscape(id))
 	p := new(Project)
-	if err := c.get(ctx, path, p); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointGetProject, path, p, attrProject.String(id))
+	if err != nil {
+		return nil, resp, err
 	}
-	return p, nil
+	return p, resp, nil
 }
 
 type ProjectPackageVersions struct {
@@ -425,7 +507,7 @@ type ProjectPackageVersions struct {
 		// for more attestations (including SLSA provenance) for all systems.
 		SLSAProvenances []SLSAProvenance
 		// Attestations that link the version to the project.
-		Attestation []Attestation
+		Attestations []Attestation
 		// What the relationship between the project and the package version is.
 		// Can be one of SOURCE_REPO, ISSUE_TRACKER.
 		RelationType string
@@ -440,13 +522,14 @@ type ProjectPackageVersions struct {
 // and package versions.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#getprojectpackageversions
-func (c *Client) GetProjectPackageVersions(ctx context.Context, id string) (*ProjectPackageVersions, error) {
+func (c *Client) GetProjectPackageVersions(ctx context.Context, id string) (*ProjectPackageVersions, *Response, error) {
 	path := fmt.Sprintf("/projects/%s:packageversions", url.PathEscape(id))
 	pv := new(ProjectPackageVersions)
-	if err := c.get(ctx, path, pv); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointGetProjectPackageVersions, path, pv, attrProject.String(id))
+	if err != nil {
+		return nil, resp, err
 	}
-	return pv, nil
+	return pv, resp, nil
 }
 
 // Advisory holds information about a security advisory hosted by OSV.
@@ -474,13 +557,14 @@ type Advisory struct {
 // GetAdvisory returns information about security advisories hosted by OSV.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#getadvisory
-func (c *Client) GetAdvisory(ctx context.Context, id string) (*Advisory, error) {
+func (c *Client) GetAdvisory(ctx context.Context, id string) (*Advisory, *Response, error) {
 	path := fmt.Sprintf("/advisories/%s", url.PathEscape(id))
 	a := new(Advisory)
-	if err := c.get(ctx, path, a); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointGetAdvisory, path, a, attrAdvisory.String(id))
+	if err != nil {
+		return nil, resp, err
 	}
-	return a, nil
+	return a, resp, nil
 }
 
 type Result struct {
@@ -503,7 +587,7 @@ type QueryOptions struct {
 	HashValue string `url:"hash.value,omitempty"`
 
 	// The package management system containing the package.
-	// Can be one of GO, NPM, CARGO, MAVEN, PYPI, NUGET.
+	// Can be one of GO, RUBYGEMS, NPM, CARGO, MAVEN, PYPI, NUGET.
 	System string `url:"versionKey.system,omitempty"`
 
 	// The name of the package.
@@ -516,15 +600,54 @@ type QueryOptions struct {
 // Query returns information about multiple package versions.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#query
-func (c *Client) Query(ctx context.Context, opts *QueryOptions) (*QueryResult, error) {
+func (c *Client) Query(ctx context.Context, opts *QueryOptions) (*QueryResult, *Response, error) {
 	u := "query"
+	if opts != nil && opts.Name != "" {
+		o := *opts
+		o.Name = CanonicalName(o.System, o.Name)
+		opts = &o
+	}
 	path, err := addOptions(u, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	var attrs []attribute.KeyValue
+	if opts != nil {
+		attrs = versionAttrs(opts.System, opts.Name, opts.Version)
 	}
 	r := new(QueryResult)
-	if err := c.get(ctx, path, r); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointQuery, path, r, attrs...)
+	if err != nil {
+		return nil, resp, err
+	}
+	return r, resp, nil
+}
+
+// PurlLookupResult holds the package, or package version, identified by a
+// package URL (purl).
+type PurlLookupResult struct {
+	// The purl looked up.
+	Purl string
+
+	// The package, if the purl has no version. Only the versions of the
+	// package are listed, without their details.
+	Package *Package
+
+	// The package version, if the purl has one.
+	Version *Version
+}
+
+// PurlLookup returns information about the package, or package version,
+// identified by a package URL (purl), such as "pkg:npm/react@18.2.0" or
+// "pkg:npm/react".
+//
+// deps.dev API doc: https://docs.deps.dev/api/v3alpha/#purllookup
+func (c *Client) PurlLookup(ctx context.Context, purl string) (*PurlLookupResult, *Response, error) {
+	path := "purl/" + url.PathEscape(purl)
+	r := new(PurlLookupResult)
+	resp, err := c.getEndpoint(ctx, EndpointPurlLookup, path, r, attrPurl.String(purl))
+	if err != nil {
+		return nil, resp, err
 	}
-	return r, nil
+	return r, resp, nil
 }
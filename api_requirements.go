@@ -255,11 +255,12 @@ type Requirements struct {
 // GetRequirements returns the requirements for a given version in a system-specific format.
 //
 // deps.dev API doc: https://docs.deps.dev/api/v3/#getrequirements
-func (c *Client) GetRequirements(ctx context.Context, system, name, version string) (*Requirements, error) {
-	path := fmt.Sprintf("/systems/%s/packages/%s/versions/%s:requirements", url.PathEscape(system), url.PathEscape(name), url.PathEscape(version))
+func (c *Client) GetRequirements(ctx context.Context, system, name, version string) (*Requirements, *Response, error) {
+	path := fmt.Sprintf("/systems/%s/packages/%s/versions/%s:requirements", url.PathEscape(system), url.PathEscape(CanonicalName(system, name)), url.PathEscape(version))
 	r := new(Requirements)
-	if err := c.get(ctx, path, r); err != nil {
-		return nil, err
+	resp, err := c.getEndpoint(ctx, EndpointGetRequirements, path, r, versionAttrs(system, name, version)...)
+	if err != nil {
+		return nil, resp, err
 	}
-	return r, nil
+	return r, resp, nil
 }
@@ -19,7 +19,7 @@ func TestGetRequirements(t *testing.T) {
 
 	want := &Requirements{NPM: NPM{}}
 
-	got, err := client.GetRequirements(context.Background(), "npm", "react", "18.2.0")
+	got, _, err := client.GetRequirements(context.Background(), "npm", "react", "18.2.0")
 	if err != nil {
 		t.Errorf("GetRequirements failed: %v", err)
 	}
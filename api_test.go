@@ -6,10 +6,12 @@ package insights
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -25,6 +27,7 @@ func setup(t *testing.T) (client *Client, mux *http.ServeMux) {
 
 	apiMux := http.NewServeMux()
 	apiMux.Handle("/v3/", http.StripPrefix("/v3", mux))
+	apiMux.Handle("/v3alpha/", http.StripPrefix("/v3alpha", mux))
 
 	// server is a test HTTP server used to provide mock API responses.
 	server := httptest.NewServer(apiMux)
@@ -67,6 +70,8 @@ func TestGetPackage(t *testing.T) {
 	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
 		testMethod(t, r, "GET")
 		testHeader(t, r, "Accept", "application/json; charset=utf-8")
+		testHeader(t, r, "User-Agent", DefaultUserAgent)
+		w.Header().Set("Cache-Control", "max-age=3600")
 		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
 	})
 
@@ -74,7 +79,7 @@ func TestGetPackage(t *testing.T) {
 		PackageKey: PackageKey{System: "GO", Name: "foo"},
 	}
 
-	got, err := client.GetPackage(context.Background(), "go", "foo")
+	got, resp, err := client.GetPackage(context.Background(), "go", "foo")
 	if err != nil {
 		t.Errorf("GetPackage failed: %v", err)
 	}
@@ -82,6 +87,9 @@ func TestGetPackage(t *testing.T) {
 	if !cmp.Equal(got, want) {
 		t.Errorf("GetPackage returned %+v; want %+v", got, want)
 	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Cache-Control") != "max-age=3600" || resp.FromCache {
+		t.Errorf("GetPackage returned response %d %v, from cache %t", resp.StatusCode, resp.Header, resp.FromCache)
+	}
 }
 
 func TestGetPackageErrorNotFound(t *testing.T) {
@@ -90,9 +98,70 @@ func TestGetPackageErrorNotFound(t *testing.T) {
 		http.Error(w, "package not found", http.StatusNotFound)
 	})
 
-	_, err := client.GetPackage(context.Background(), "bar", "baz")
-	if err == nil {
-		t.Errorf("GetPackage expected error")
+	_, resp, err := client.GetPackage(context.Background(), "bar", "baz")
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetPackage returned response %v; want 404", resp)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetPackage returned %v; want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("APIError.StatusCode is %d; want %d", apiErr.StatusCode, http.StatusNotFound)
+	}
+	if !strings.HasSuffix(apiErr.URL, "/v3/systems/bar/packages/baz") {
+		t.Errorf("APIError.URL is %q", apiErr.URL)
+	}
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer) {
+		t.Errorf("GetPackage returned %v, which does not match only ErrNotFound", err)
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	testCases := []struct {
+		code int
+		want error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusServiceUnavailable, ErrServer},
+		{http.StatusForbidden, nil},
+	}
+	sentinels := []error{ErrBadRequest, ErrNotFound, ErrRateLimited, ErrServer}
+	for _, tc := range testCases {
+		err := fmt.Errorf("wrapped: %w", &APIError{StatusCode: tc.code})
+		for _, target := range sentinels {
+			if got, want := errors.Is(err, target), target == tc.want; got != want {
+				t.Errorf("errors.Is(%d, %v) = %t; want %t", tc.code, target, got, want)
+			}
+		}
+	}
+}
+
+func TestGetSimilarlyNamedPackages(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/systems/npm/packages/raect:similarlyNamedPackages", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"packages":[{"packageKey":{"system":"NPM","name":"react"}},{"packageKey":{"system":"NPM","name":"reac"}}]}`)
+	})
+
+	want := &SimilarlyNamedPackages{
+		Packages: []struct{ PackageKey PackageKey }{
+			{PackageKey{System: "NPM", Name: "react"}},
+			{PackageKey{System: "NPM", Name: "reac"}},
+		},
+	}
+
+	got, _, err := client.GetSimilarlyNamedPackages(context.Background(), "npm", "raect")
+	if err != nil {
+		t.Errorf("GetSimilarlyNamedPackages failed: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("GetSimilarlyNamedPackages returned %+v; want %+v", got, want)
 	}
 }
 
@@ -110,7 +179,7 @@ func TestGetVersion(t *testing.T) {
 		},
 	}
 
-	got, err := client.GetVersion(context.Background(), "go", "rsc.io/github", "v0.4.1")
+	got, _, err := client.GetVersion(context.Background(), "go", "rsc.io/github", "v0.4.1")
 	if err != nil {
 		t.Errorf("GetVersion failed: %v", err)
 	}
@@ -142,7 +211,7 @@ func TestGetDependencies(t *testing.T) {
 		},
 	}
 
-	got, err := client.GetDependencies(context.Background(), "npm", "react", "18.2.0")
+	got, _, err := client.GetDependencies(context.Background(), "npm", "react", "18.2.0")
 	if err != nil {
 		t.Errorf("GetDependencies failed: %v", err)
 	}
@@ -152,6 +221,30 @@ func TestGetDependencies(t *testing.T) {
 	}
 }
 
+func TestGetDependents(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/systems/npm/packages/react/versions/18.2.0:dependents", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"dependentCount":5, "directDependentCount":2, "indirectDependentCount":3}`)
+	})
+
+	want := &Dependents{
+		DependentCount:         5,
+		DirectDependentCount:   2,
+		IndirectDependentCount: 3,
+	}
+
+	got, _, err := client.GetDependents(context.Background(), "npm", "react", "18.2.0")
+	if err != nil {
+		t.Errorf("GetDependents failed: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("GetDependents returned %+v; want %+v", got, want)
+	}
+}
+
 func TestGetProject(t *testing.T) {
 	client, mux := setup(t)
 
@@ -165,7 +258,7 @@ func TestGetProject(t *testing.T) {
 		StarsCount:      978,
 	}
 
-	got, err := client.GetProject(context.Background(), "github.com/robpike/lisp")
+	got, _, err := client.GetProject(context.Background(), "github.com/robpike/lisp")
 	if err != nil {
 		t.Errorf("GetProject failed: %v", err)
 	}
@@ -179,14 +272,24 @@ func TestGetProjectPackageVersions(t *testing.T) {
 	client, mux := setup(t)
 
 	mux.HandleFunc("/projects/github.com%2Frobpike%2Flisp:packageversions", func(w http.ResponseWriter, r *http.Request) {
-		// fmt.Fprint(w, `{"versions":[{"versionKey":{"system":"GO", "name":"robpike.io/lisp", "version":"v0.0.0"}, "relationType":"SOURCE_REPO", "relationProvenance":"GO_ORIGIN"}]}`)
-		fmt.Fprint(w, `{}`)
+		fmt.Fprint(w, `{"versions":[{"versionKey":{"system":"GO", "name":"robpike.io/lisp", "version":"v0.0.0"}, "attestations":[{"type":"https://slsa.dev/provenance/v1", "verified":true}], "relationType":"SOURCE_REPO", "relationProvenance":"GO_ORIGIN"}]}`)
 	})
 
-	// TODO: add values.
 	want := &ProjectPackageVersions{}
+	want.Versions = append(want.Versions, struct {
+		VersionKey         VersionKey
+		SLSAProvenances    []SLSAProvenance
+		Attestations       []Attestation
+		RelationType       string
+		RelationProvenance string
+	}{
+		VersionKey:         VersionKey{System: "GO", Name: "robpike.io/lisp", Version: "v0.0.0"},
+		Attestations:       []Attestation{{Type: "https://slsa.dev/provenance/v1", Verified: true}},
+		RelationType:       "SOURCE_REPO",
+		RelationProvenance: "GO_ORIGIN",
+	})
 
-	got, err := client.GetProjectPackageVersions(context.Background(), "github.com/robpike/lisp")
+	got, _, err := client.GetProjectPackageVersions(context.Background(), "github.com/robpike/lisp")
 	if err != nil {
 		t.Errorf("GetProjectPackageVersions failed: %v", err)
 	}
@@ -205,7 +308,7 @@ func TestGetAdvisory(t *testing.T) {
 
 	want := &Advisory{AdvisoryKey: AdvisoryKey{ID: "GHSA-2qrg-x229-3v8q"}}
 
-	got, err := client.GetAdvisory(context.Background(), "GHSA-2qrg-x229-3v8q")
+	got, _, err := client.GetAdvisory(context.Background(), "GHSA-2qrg-x229-3v8q")
 	if err != nil {
 		t.Errorf("GetAdvisory failed: %v", err)
 	}
@@ -238,7 +341,7 @@ func TestQuery(t *testing.T) {
 	}
 
 	opts := &QueryOptions{System: "npm", Name: "react", Version: "18.2.0"}
-	got, err := client.Query(context.Background(), opts)
+	got, _, err := client.Query(context.Background(), opts)
 	if err != nil {
 		t.Errorf("Query failed: %v", err)
 	}
@@ -247,3 +350,45 @@ func TestQuery(t *testing.T) {
 		t.Errorf("Query returned %+v; want %+v", got, want)
 	}
 }
+
+func TestPurlLookup(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/purl/pkg:npm%2Freact@18.2.0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"purl":"pkg:npm/react@18.2.0", "version":{"versionKey":{"system":"NPM", "name":"react", "version":"18.2.0"}}}`)
+	})
+	mux.HandleFunc("/purl/pkg:npm%2Freact", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"purl":"pkg:npm/react", "package":{"packageKey":{"system":"NPM", "name":"react"}}}`)
+	})
+
+	tests := []struct {
+		purl string
+		want *PurlLookupResult
+	}{
+		{
+			purl: "pkg:npm/react@18.2.0",
+			want: &PurlLookupResult{
+				Purl:    "pkg:npm/react@18.2.0",
+				Version: &Version{VersionKey: VersionKey{System: "NPM", Name: "react", Version: "18.2.0"}},
+			},
+		},
+		{
+			purl: "pkg:npm/react",
+			want: &PurlLookupResult{
+				Purl:    "pkg:npm/react",
+				Package: &Package{PackageKey: PackageKey{System: "NPM", Name: "react"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, _, err := client.PurlLookup(context.Background(), tt.purl)
+		if err != nil {
+			t.Errorf("PurlLookup(%q) failed: %v", tt.purl, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("PurlLookup(%q) mismatch (-want +got):\n%s", tt.purl, diff)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package audit gathers security, license, and supply chain information from
+// deps.dev about the package versions a project depends on.
+package audit
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/criticality"
+	"github.com/franoliveto/insights/registry"
+	"github.com/franoliveto/insights/scan"
+)
+
+// Package holds what is known about one dependency of a project.
+type Package struct {
+	// The dependency as found in the project.
+	Dependency scan.Dependency
+
+	// The licenses of the package version.
+	Licenses []string
+
+	// The security advisories known to affect the package version, sorted
+	// by ID.
+	Advisories []*insights.Advisory
+
+	// The provenance of the package version.
+	Provenance Provenance
+
+	// The source code repository of the package version, if known.
+	SourceRepository string
+
+	// The OpenSSF Scorecard of the source code repository, if available.
+	Scorecard *insights.Scorecard
+
+	// The OpenSSF criticality score of the source code repository, from 0
+	// to 1, if Options.Criticality has it; zero otherwise.
+	Criticality float64
+
+	// Whether the package version is deprecated or, as only reported by
+	// registries, yanked, and the reason given, if any.
+	Deprecated       bool
+	Yanked           bool
+	DeprecatedReason string
+
+	// Describes why information about the package version could not be
+	// obtained, for example because deps.dev does not know about it.
+	Error string
+}
+
+// Provenance summarizes the attestations of a package version.
+type Provenance struct {
+	// Whether the package version has any attestations.
+	Attested bool
+
+	// Whether all the attestations were verified by deps.dev.
+	Verified bool
+
+	// The source code repository and commit the attestations claim the
+	// package version was built from.
+	SourceRepository string
+	Commit           string
+}
+
+// Result is the result of auditing a project.
+type Result struct {
+	// The dependencies of the project, in the order they were given to Run.
+	Packages []Package
+}
+
+// Vulnerable returns the packages affected by at least one advisory.
+func (r *Result) Vulnerable() []Package {
+	var pkgs []Package
+	for _, p := range r.Packages {
+		if len(p.Advisories) > 0 {
+			pkgs = append(pkgs, p)
+		}
+	}
+	return pkgs
+}
+
+// Deprecated returns the packages that are deprecated or yanked.
+func (r *Result) Deprecated() []Package {
+	var pkgs []Package
+	for _, p := range r.Packages {
+		if p.Deprecated || p.Yanked {
+			pkgs = append(pkgs, p)
+		}
+	}
+	return pkgs
+}
+
+// Options specifies optional parameters to Run.
+type Options struct {
+	// The maximum number of dependencies audited concurrently. If zero or
+	// negative, dependencies are audited one at a time.
+	Concurrency int
+
+	// Registry, if not nil, is asked for the deprecation and yank status
+	// of the package versions of the systems it supports, which deps.dev
+	// does not fully report. Failing to get it is not an error.
+	Registry *registry.Client
+
+	// Criticality, if not nil, has the criticality scores of the source
+	// code repositories, so that findings can be weighted by how critical
+	// the packages are to the open source ecosystem.
+	Criticality criticality.Scores
+}
+
+func (o *Options) registry() *registry.Client {
+	if o == nil {
+		return nil
+	}
+	return o.Registry
+}
+
+func (o *Options) criticality() criticality.Scores {
+	if o == nil {
+		return nil
+	}
+	return o.Criticality
+}
+
+// Run audits the given dependencies using c. Failing to get information
+// about a dependency is recorded in its Package and does not stop the audit.
+// The dependencies and advisories that could not be looked up are also
+// reported in a *insights.BatchError returned with the result. Run returns
+// no result if ctx is done. opts may be nil.
+func Run(ctx context.Context, c *insights.Client, deps []scan.Dependency, opts *Options) (*Result, error) {
+	n := 1
+	if opts != nil && opts.Concurrency > 1 {
+		n = opts.Concurrency
+	}
+	a := &auditor{
+		client:     c,
+		registry:   opts.registry(),
+		scores:     opts.criticality(),
+		advisories: make(map[string]*memo[*insights.Advisory]),
+		projects:   make(map[string]*memo[*insights.Project]),
+	}
+	r := &Result{Packages: make([]Package, len(deps))}
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i, d := range deps {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.Packages[i] = a.audit(ctx, d)
+				<-sem
+			}()
+		}
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r, a.batch.Err()
+}
+
+// auditor remembers the advisories and projects it has already fetched, as
+// they are often shared by many dependencies. Each is fetched once, by the
+// first worker to need it; the others wait for it.
+type auditor struct {
+	client   *insights.Client
+	batch    insights.Batch
+	registry *registry.Client
+	scores   criticality.Scores
+
+	mu         sync.Mutex
+	advisories map[string]*memo[*insights.Advisory]
+	projects   map[string]*memo[*insights.Project]
+}
+
+// memo holds a value computed once.
+type memo[T any] struct {
+	once sync.Once
+	v    T
+}
+
+// get returns the value of m for key, computing it with f unless done
+// before. mu guards m.
+func get[T any](mu *sync.Mutex, m map[string]*memo[T], key string, f func() T) T {
+	mu.Lock()
+	e, ok := m[key]
+	if !ok {
+		e = new(memo[T])
+		m[key] = e
+	}
+	mu.Unlock()
+	e.once.Do(func() { e.v = f() })
+	return e.v
+}
+
+func (a *auditor) advisory(ctx context.Context, ak insights.AdvisoryKey) *insights.Advisory {
+	return get(&a.mu, a.advisories, ak.ID, func() *insights.Advisory {
+		adv, _, err := a.client.GetAdvisory(ctx, ak.ID)
+		if err != nil {
+			// Keep what is known about the advisory.
+			a.batch.Fail(ak, err)
+			adv = &insights.Advisory{AdvisoryKey: ak}
+		}
+		return adv
+	})
+}
+
+func (a *auditor) project(ctx context.Context, id string) *insights.Project {
+	return get(&a.mu, a.projects, id, func() *insights.Project {
+		// A missing project is not worth failing the package for.
+		p, _, _ := a.client.GetProject(ctx, id)
+		return p
+	})
+}
+
+func (a *auditor) audit(ctx context.Context, d scan.Dependency) Package {
+	p := Package{Dependency: d}
+	k := d.VersionKey
+	v, _, err := a.client.GetVersion(ctx, k.System, k.Name, k.Version)
+	if err != nil {
+		a.batch.Fail(k, err)
+		p.Error = err.Error()
+		return p
+	}
+	p.Licenses = v.Licenses
+
+	for _, ak := range v.AdvisoryKeys {
+		p.Advisories = append(p.Advisories, a.advisory(ctx, ak))
+	}
+	slices.SortFunc(p.Advisories, func(a, b *insights.Advisory) int {
+		return strings.Compare(a.AdvisoryKey.ID, b.AdvisoryKey.ID)
+	})
+
+	p.Provenance = provenance(v)
+
+	p.Deprecated = v.IsDeprecated
+	if a.registry != nil {
+		if st, err := a.registry.Status(ctx, k.System, k.Name, k.Version); err == nil {
+			p.Deprecated = p.Deprecated || st.Deprecated
+			p.Yanked, p.DeprecatedReason = st.Yanked, st.Reason
+		}
+	}
+
+	for _, rp := range v.RelatedProjects {
+		if rp.RelationType != "SOURCE_REPO" {
+			continue
+		}
+		p.SourceRepository = rp.ProjectKey.ID
+		p.Criticality, _ = a.scores.Score(rp.ProjectKey.ID)
+		if proj := a.project(ctx, rp.ProjectKey.ID); proj != nil && proj.Scorecard.Date != "" {
+			p.Scorecard = &proj.Scorecard
+		}
+		break
+	}
+	return p
+}
+
+func provenance(v *insights.Version) Provenance {
+	var p Provenance
+	p.Verified = true
+	for _, a := range v.Attestations {
+		p.Attested = true
+		p.Verified = p.Verified && a.Verified
+		if p.SourceRepository == "" {
+			p.SourceRepository, p.Commit = a.SourceRepository, a.Commit
+		}
+	}
+	for _, s := range v.SLSAProvenances {
+		p.Attested = true
+		p.Verified = p.Verified && s.Verified
+		if p.SourceRepository == "" {
+			p.SourceRepository, p.Commit = s.SourceRepository, s.Commit
+		}
+	}
+	p.Verified = p.Attested && p.Verified
+	return p
+}
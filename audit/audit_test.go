@@ -0,0 +1,218 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/criticality"
+	"github.com/franoliveto/insights/registry"
+	"github.com/franoliveto/insights/scan"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setup returns a client talking to a test server whose API handlers are
+// registered on mux.
+func setup(t *testing.T) (*insights.Client, *http.ServeMux) {
+	t.Helper()
+	mux := http.NewServeMux()
+	apiMux := http.NewServeMux()
+	apiMux.Handle("/v3/", http.StripPrefix("/v3", mux))
+	server := httptest.NewServer(apiMux)
+	t.Cleanup(server.Close)
+
+	client := insights.NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/v3/")
+	return client, mux
+}
+
+func TestRun(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/systems/NPM/packages/a/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"versionKey":{"system":"NPM","name":"a","version":"1.0.0"},
+			"licenses":["MIT"],
+			"advisoryKeys":[{"id":"GHSA-1"},{"id":"GHSA-0"}],
+			"attestations":[{"type":"https://slsa.dev/provenance/v1","verified":true,"sourceRepository":"https://github.com/x/a","commit":"abc"}],
+			"relatedProjects":[{"projectKey":{"id":"github.com/x/a"},"relationType":"SOURCE_REPO"}]
+		}`)
+	})
+	mux.HandleFunc("/advisories/GHSA-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"advisoryKey":{"id":"GHSA-1"},"title":"bad","cvss3Score":9.8}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fx%2Fa", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"projectKey":{"id":"github.com/x/a"},"scorecard":{"date":"2024-01-01T00:00:00Z","overallScore":7.5}}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/b/versions/2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "version not found", http.StatusNotFound)
+	})
+
+	deps := []scan.Dependency{
+		{VersionKey: insights.VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}, Direct: true},
+		{VersionKey: insights.VersionKey{System: "NPM", Name: "b", Version: "2.0.0"}},
+	}
+	opts := &Options{Concurrency: 2, Criticality: criticality.Scores{"github.com/x/a": 0.7}}
+	got, err := Run(context.Background(), client, deps, opts)
+	var be *insights.BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("Run returned error %v; want a *BatchError", err)
+	}
+	var failed []string
+	for _, e := range be.Errors {
+		failed = append(failed, fmt.Sprint(e.Key))
+	}
+	slices.Sort(failed)
+	if diff := cmp.Diff([]string{"{GHSA-0}", "{NPM b 2.0.0}"}, failed); diff != "" {
+		t.Errorf("Run failed keys mismatch (-want +got):\n%s", diff)
+	}
+
+	want := &Result{
+		Packages: []Package{
+			{
+				Dependency: deps[0],
+				Licenses:   []string{"MIT"},
+				// Sorted, and GHSA-0 is unknown.
+				Advisories: []*insights.Advisory{
+					{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-0"}},
+					{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-1"}, Title: "bad", CVSS3Score: 9.8},
+				},
+				Provenance: Provenance{
+					Attested:         true,
+					Verified:         true,
+					SourceRepository: "https://github.com/x/a",
+					Commit:           "abc",
+				},
+				SourceRepository: "github.com/x/a",
+				Scorecard:        &insights.Scorecard{Date: "2024-01-01T00:00:00Z", OverallScore: 7.5},
+				Criticality:      0.7,
+			},
+			{
+				Dependency: deps[1],
+				Error:      "404 version not found\n",
+			},
+		},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Run returned %+v; want %+v", got, want)
+	}
+	if n := len(got.Vulnerable()); n != 1 {
+		t.Errorf("Vulnerable returned %d packages; want 1", n)
+	}
+}
+
+func TestRunShared(t *testing.T) {
+	client, mux := setup(t)
+
+	const n = 20
+	for i := range n {
+		mux.HandleFunc(fmt.Sprintf("/systems/NPM/packages/p%d/versions/1.0.0", i), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{
+				"versionKey":{"system":"NPM","name":"p%d","version":"1.0.0"},
+				"advisoryKeys":[{"id":"GHSA-1"}],
+				"relatedProjects":[{"projectKey":{"id":"github.com/x/a"},"relationType":"SOURCE_REPO"}]
+			}`, i)
+		})
+	}
+	var advisories, projects atomic.Int32
+	mux.HandleFunc("/advisories/GHSA-1", func(w http.ResponseWriter, r *http.Request) {
+		advisories.Add(1)
+		fmt.Fprint(w, `{"advisoryKey":{"id":"GHSA-1"}}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fx%2Fa", func(w http.ResponseWriter, r *http.Request) {
+		projects.Add(1)
+		fmt.Fprint(w, `{"projectKey":{"id":"github.com/x/a"}}`)
+	})
+
+	var deps []scan.Dependency
+	for i := range n {
+		deps = append(deps, scan.Dependency{VersionKey: insights.VersionKey{System: "NPM", Name: fmt.Sprintf("p%d", i), Version: "1.0.0"}})
+	}
+	got, err := Run(context.Background(), client, deps, &Options{Concurrency: n})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, p := range got.Packages {
+		if len(p.Advisories) != 1 || p.Advisories[0] != got.Packages[0].Advisories[0] {
+			t.Errorf("%s: advisories %v; want the shared GHSA-1", p.Dependency.VersionKey.Name, p.Advisories)
+		}
+	}
+	if a, p := advisories.Load(), projects.Load(); a != 1 || p != 1 {
+		t.Errorf("fetched the advisory %d times and the project %d times; want once each", a, p)
+	}
+}
+
+func TestResultBinary(t *testing.T) {
+	r := &Result{Packages: []Package{{
+		Dependency: scan.Dependency{VersionKey: insights.VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}, Direct: true, File: "package-lock.json"},
+		Licenses:   []string{"MIT"},
+		Advisories: []*insights.Advisory{{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-1"}, CVSS3Score: 9.8}},
+		Provenance: Provenance{Attested: true, Verified: true},
+		Scorecard:  &insights.Scorecard{OverallScore: 7.5},
+	}}}
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	got := new(Result)
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if diff := cmp.Diff(r, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunDeprecated(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/NPM/packages/request/versions/2.88.2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"request","version":"2.88.2"},"isDeprecated":true}`)
+	})
+	mux.HandleFunc("/systems/PYPI/packages/urllib3/versions/2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"PYPI","name":"urllib3","version":"2.0.0"}}`)
+	})
+	regMux := http.NewServeMux()
+	regMux.HandleFunc("/pypi/urllib3/2.0.0/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"info":{"yanked":true,"yanked_reason":"broken"}}`)
+	})
+	server := httptest.NewServer(regMux)
+	t.Cleanup(server.Close)
+
+	deps := []scan.Dependency{
+		{VersionKey: insights.VersionKey{System: "NPM", Name: "request", Version: "2.88.2"}},
+		{VersionKey: insights.VersionKey{System: "PYPI", Name: "urllib3", Version: "2.0.0"}},
+	}
+	ctx := context.Background()
+
+	// Without a registry, only deps.dev's deprecations are known. The npm
+	// registry is not asked, so its reason is unknown.
+	r, err := Run(ctx, client, deps, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	want := []Package{{Dependency: deps[0], Deprecated: true}}
+	if diff := cmp.Diff(want, r.Deprecated()); diff != "" {
+		t.Errorf("Deprecated mismatch (-want +got):\n%s", diff)
+	}
+
+	// The npm registry is unreachable, which is not an error.
+	opts := &Options{Registry: &registry.Client{PyPIURL: server.URL, NPMURL: server.URL + "/npm/"}}
+	if r, err = Run(ctx, client, deps, opts); err != nil {
+		t.Fatalf("Run with a registry failed: %v", err)
+	}
+	want = append(want, Package{Dependency: deps[1], Yanked: true, DeprecatedReason: "broken"})
+	if diff := cmp.Diff(want, r.Deprecated()); diff != "" {
+		t.Errorf("Deprecated with a registry mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// resultHeader starts the binary encoding of a Result. Its last byte is the
+// version of the encoding.
+const resultHeader = "audit.Result\x01"
+
+// result has the fields of Result but not its methods, so that gob does not
+// call MarshalBinary recursively.
+type result Result
+
+// MarshalBinary implements encoding.BinaryMarshaler, so that the result of
+// an audit can be persisted and reloaded without querying deps.dev again.
+// The encoding is only meant to be read back by UnmarshalBinary.
+func (r *Result) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(resultHeader)
+	if err := gob.NewEncoder(&buf).Encode((*result)(r)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (r *Result) UnmarshalBinary(data []byte) error {
+	data, ok := bytes.CutPrefix(data, []byte(resultHeader))
+	if !ok {
+		return errors.New("audit: unknown binary format")
+	}
+	*r = Result{}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode((*result)(r))
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+// Severity returns the qualitative severity rating of a CVSS v3 score:
+// "CRITICAL", "HIGH", "MEDIUM", or "LOW", or "UNKNOWN" if the score is
+// zero, as when an advisory has none.
+func Severity(score float32) string {
+	switch {
+	case score >= 9:
+		return "CRITICAL"
+	case score >= 7:
+		return "HIGH"
+	case score >= 4:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	}
+	return "UNKNOWN"
+}
+
+// Summary counts the findings of an audit, each an advisory affecting a
+// package version, so that they can be reported or checked against
+// thresholds at a glance.
+type Summary struct {
+	// The number of findings.
+	Findings int
+
+	// The findings by the severity rating of their advisories.
+	Critical, High, Medium, Low, Unknown int
+
+	// The findings in direct and in indirect dependencies.
+	Direct, Indirect int
+
+	// The findings by package management system.
+	Systems map[string]int
+
+	// The number of packages affected by at least one advisory, that are
+	// deprecated or yanked, and whose information could not be obtained.
+	Vulnerable, Deprecated, Errors int
+}
+
+// Summary returns the counts of the findings of the audit.
+func (r *Result) Summary() *Summary {
+	s := &Summary{Systems: make(map[string]int)}
+	for _, p := range r.Packages {
+		if p.Error != "" {
+			s.Errors++
+		}
+		if p.Deprecated || p.Yanked {
+			s.Deprecated++
+		}
+		if len(p.Advisories) > 0 {
+			s.Vulnerable++
+		}
+		for _, a := range p.Advisories {
+			s.Findings++
+			switch Severity(a.CVSS3Score) {
+			case "CRITICAL":
+				s.Critical++
+			case "HIGH":
+				s.High++
+			case "MEDIUM":
+				s.Medium++
+			case "LOW":
+				s.Low++
+			default:
+				s.Unknown++
+			}
+			if p.Dependency.Direct {
+				s.Direct++
+			} else {
+				s.Indirect++
+			}
+			s.Systems[p.Dependency.VersionKey.System]++
+		}
+	}
+	return s
+}
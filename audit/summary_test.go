@@ -0,0 +1,39 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/scan"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSummary(t *testing.T) {
+	dep := func(system, name string, direct bool) scan.Dependency {
+		return scan.Dependency{VersionKey: insights.VersionKey{System: system, Name: name, Version: "1.0.0"}, Direct: direct}
+	}
+	advisory := func(score float32) *insights.Advisory {
+		return &insights.Advisory{CVSS3Score: score}
+	}
+	r := &Result{Packages: []Package{
+		{Dependency: dep("NPM", "a", true), Advisories: []*insights.Advisory{advisory(9.8), advisory(5.3)}},
+		{Dependency: dep("NPM", "b", false), Advisories: []*insights.Advisory{advisory(7.5)}, Deprecated: true},
+		{Dependency: dep("PYPI", "c", false), Advisories: []*insights.Advisory{advisory(2.1), advisory(0)}},
+		{Dependency: dep("PYPI", "d", true), Yanked: true},
+		{Dependency: dep("PYPI", "e", true), Error: "not found"},
+	}}
+	want := &Summary{
+		Findings: 5,
+		Critical: 1, High: 1, Medium: 1, Low: 1, Unknown: 1,
+		Direct: 2, Indirect: 3,
+		Systems:    map[string]int{"NPM": 3, "PYPI": 2},
+		Vulnerable: 3, Deprecated: 2, Errors: 1,
+	}
+	if diff := cmp.Diff(want, r.Summary()); diff != "" {
+		t.Errorf("Summary mismatch (-want +got):\n%s", diff)
+	}
+}
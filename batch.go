@@ -0,0 +1,89 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KeyError reports the failure of a batch operation for one of its keys.
+type KeyError struct {
+	// The key the operation failed for, depending on the operation, such
+	// as a VersionKey, PackageKey, AdvisoryKey, Hash, or project ID.
+	Key any
+
+	Err error
+}
+
+func (e *KeyError) Error() string {
+	var key string
+	switch k := e.Key.(type) {
+	case VersionKey:
+		key = k.Name + "@" + k.Version
+	case PackageKey:
+		key = k.System + " " + k.Name
+	case AdvisoryKey:
+		key = k.ID
+	case Hash:
+		key = k.Type + " " + k.Value
+	default:
+		key = fmt.Sprint(k)
+	}
+	return key + ": " + e.Err.Error()
+}
+
+func (e *KeyError) Unwrap() error { return e.Err }
+
+// BatchError reports the keys a batch operation, such as Prefetch or
+// Identify, failed for. Such operations go on despite failures, so a
+// BatchError comes with the results of the other keys.
+type BatchError struct {
+	Errors []*KeyError
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, ke := range e.Errors {
+		msgs[i] = ke.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors of the keys, so that errors.Is and errors.As
+// can find them.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, ke := range e.Errors {
+		errs[i] = ke
+	}
+	return errs
+}
+
+// Batch collects the errors of a batch operation, by key. It is safe for
+// concurrent use, and its zero value is ready to use.
+type Batch struct {
+	mu   sync.Mutex
+	errs []*KeyError
+}
+
+// Fail records that the operation failed for key with err.
+func (b *Batch) Fail(key any, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errs = append(b.errs, &KeyError{Key: key, Err: err})
+}
+
+// Err returns a BatchError with the recorded errors, in the order they
+// were recorded, or nil if there are none.
+func (b *Batch) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.errs) == 0 {
+		return nil
+	}
+	return &BatchError{Errors: b.errs}
+}
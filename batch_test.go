@@ -0,0 +1,66 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	var b Batch
+	if err := b.Err(); err != nil {
+		t.Fatalf("Err of an empty Batch = %v; want nil", err)
+	}
+	notFound := &APIError{StatusCode: http.StatusNotFound, Body: "not found"}
+	b.Fail(VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}, notFound)
+	b.Fail(PackageKey{System: "NPM", Name: "b"}, context.Canceled)
+	b.Fail("github.com/x/c", errors.New("boom"))
+
+	err := b.Err()
+	want := "a@1.0.0: 404 not found\nNPM b: context canceled\ngithub.com/x/c: boom"
+	if err.Error() != want {
+		t.Errorf("Err() = %q; want %q", err, want)
+	}
+	var be *BatchError
+	if !errors.As(err, &be) || len(be.Errors) != 3 {
+		t.Fatalf("Err() = %#v; want a *BatchError with 3 errors", err)
+	}
+	if be.Errors[1].Key != (PackageKey{System: "NPM", Name: "b"}) {
+		t.Errorf("second key = %v; want package b", be.Errors[1].Key)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr != notFound {
+		t.Errorf("errors.As(APIError) did not find the error of a")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("errors.Is(context.Canceled) = false; want true")
+	}
+}
+
+func TestPrefetchBatchError(t *testing.T) {
+	client, mux := setup(t)
+	client.Cache = &memCache{m: make(map[string][]byte)}
+	mux.HandleFunc("/systems/NPM/packages/a/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"}}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/b/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	a := VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}
+	b := VersionKey{System: "NPM", Name: "b", Version: "1.0.0"}
+	err := client.Prefetch(context.Background(), []VersionKey{a, b}, nil)
+	var be *BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("Prefetch returned %v; want a *BatchError", err)
+	}
+	if len(be.Errors) != 1 || be.Errors[0].Key != b {
+		t.Errorf("Prefetch failed for %v; want only b", be.Errors)
+	}
+}
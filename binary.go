@@ -0,0 +1,46 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// dependenciesHeader starts the binary encoding of Dependencies. Its last
+// byte is the version of the encoding, so that graphs persisted by an
+// incompatible release are rejected instead of misread.
+const dependenciesHeader = "insights.Dependencies\x01"
+
+// errBinaryFormat is returned when decoding data not produced by the
+// matching MarshalBinary method.
+var errBinaryFormat = errors.New("insights: unknown binary format")
+
+// dependencies has the fields of Dependencies but not its methods, so that
+// gob does not call MarshalBinary recursively.
+type dependencies Dependencies
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is
+// compact and fast to decode, which makes it suitable for persisting graphs
+// between runs; it is not meant for exchanging graphs between programs.
+func (d *Dependencies) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(dependenciesHeader)
+	if err := gob.NewEncoder(&buf).Encode((*dependencies)(d)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *Dependencies) UnmarshalBinary(data []byte) error {
+	data, ok := bytes.CutPrefix(data, []byte(dependenciesHeader))
+	if !ok {
+		return errBinaryFormat
+	}
+	*d = Dependencies{}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode((*dependencies)(d))
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDependenciesBinary(t *testing.T) {
+	d := &Dependencies{
+		Nodes: []Node{
+			{VersionKey: VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}, Relation: "SELF"},
+			{VersionKey: VersionKey{System: "NPM", Name: "b", Version: "2.0.0"}, Relation: "DIRECT", Bundled: true},
+			{VersionKey: VersionKey{System: "NPM", Name: "c", Version: "1.0.0"}, Relation: "INDIRECT", Errors: []string{"oops"}},
+		},
+		Edges: []Edge{
+			{FromNode: 0, ToNode: 1, Requirement: "^2.0.0"},
+			{FromNode: 1, ToNode: 2, Requirement: "^1.0.0"},
+		},
+		Error: "partial graph",
+	}
+	data, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	got := &Dependencies{Error: "stale"}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if diff := cmp.Diff(d, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := got.UnmarshalBinary([]byte(`{"nodes":[]}`)); err == nil {
+		t.Error("UnmarshalBinary accepted JSON")
+	}
+}
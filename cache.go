@@ -0,0 +1,317 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Cache stores API responses by request URL.
+//
+// A Cache must be safe for concurrent use by multiple goroutines.
+type Cache interface {
+	// Get returns the response stored under key, if any.
+	Get(key string) (data []byte, ok bool)
+
+	// Set stores the response under key.
+	Set(key string, data []byte)
+}
+
+// An ExpiringCache is a Cache that can also keep responses for a given
+// time and delete them. Clients whose Cache implements it delete cached
+// responses that cannot be decoded, so that they are fetched again. The
+// caches in this module, and rediscache.Cache, implement it.
+type ExpiringCache interface {
+	Cache
+
+	// SetTTL stores the response under key for ttl, or for as long as Set
+	// does if ttl is zero.
+	SetTTL(key string, data []byte, ttl time.Duration)
+
+	// Delete removes the response stored under key, if any.
+	Delete(key string)
+}
+
+// A StaleCache is a Cache that can return responses that have expired,
+// so that clients with a MaxStale can serve them while fetching them
+// again. DiskCache and MemoryCache implement it.
+type StaleCache interface {
+	Cache
+
+	// GetStale is like Get, but also returns the response stored under
+	// key if it expired less than maxStale ago, reporting it is stale.
+	GetStale(key string, maxStale time.Duration) (data []byte, stale, ok bool)
+}
+
+// DefaultCacheMaxAge is the MaxAge of the DiskCache NewClient sets up from
+// the environment: a day, so that advisories and new versions are seen
+// soon enough.
+const DefaultCacheMaxAge = 24 * time.Hour
+
+// DiskCache is a Cache storing responses as files in a directory, so that
+// they persist across processes.
+type DiskCache struct {
+	// The directory holding the cached responses. It is created as needed.
+	Dir string
+
+	// The maximum age of the responses returned by Get. If zero, responses
+	// never expire.
+	MaxAge time.Duration
+}
+
+func (d *DiskCache) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Cache.
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	file := d.file(key)
+	if d.MaxAge > 0 {
+		fi, err := os.Stat(file)
+		if err != nil || time.Since(fi.ModTime()) > d.MaxAge {
+			return nil, false
+		}
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// GetStale implements StaleCache.
+func (d *DiskCache) GetStale(key string, maxStale time.Duration) ([]byte, bool, bool) {
+	file := d.file(key)
+	stale := false
+	if d.MaxAge > 0 {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return nil, false, false
+		}
+		age := time.Since(fi.ModTime())
+		if age > d.MaxAge+maxStale {
+			return nil, false, false
+		}
+		stale = age > d.MaxAge
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false, false
+	}
+	return data, stale, true
+}
+
+// Set implements Cache. Failing to store a response is not reported, as the
+// response can always be fetched again.
+func (d *DiskCache) Set(key string, data []byte) {
+	d.write(d.file(key), data)
+}
+
+// SetTTL implements ExpiringCache. Files expire MaxAge after they are
+// modified, so the response is kept for ttl by setting its modification
+// time as if it were stored ttl before MaxAge elapses. If MaxAge is zero,
+// responses given a ttl are not stored, as they would never expire.
+func (d *DiskCache) SetTTL(key string, data []byte, ttl time.Duration) {
+	if ttl == 0 {
+		d.Set(key, data)
+		return
+	}
+	if d.MaxAge <= 0 {
+		return
+	}
+	file := d.file(key)
+	d.write(file, data)
+	mtime := time.Now().Add(ttl - d.MaxAge)
+	os.Chtimes(file, mtime, mtime)
+}
+
+// Delete implements ExpiringCache.
+func (d *DiskCache) Delete(key string) {
+	os.Remove(d.file(key))
+}
+
+// Prune removes the expired responses from d.Dir, and any temporary files
+// left behind by processes that died while storing one, so that the cache
+// of long-lived or scheduled jobs does not grow without bound. It returns
+// the number of files removed. Responses never expire if MaxAge is zero.
+func (d *DiskCache) Prune() (int, error) {
+	entries, err := os.ReadDir(d.Dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range entries {
+		name := e.Name()
+		// Responses are named by the hex SHA-256 of their key.
+		tmp := strings.HasPrefix(name, "tmp-")
+		if !e.Type().IsRegular() || !tmp && len(name) != 2*sha256.Size {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		age := time.Since(fi.ModTime())
+		// Temporary files are renamed right after they are written.
+		if tmp && age < time.Hour || !tmp && (d.MaxAge <= 0 || age <= d.MaxAge) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(d.Dir, name)); err != nil && !os.IsNotExist(err) {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// write writes data to file, in d.Dir, ignoring errors.
+func (d *DiskCache) write(file string, data []byte) {
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil {
+		return
+	}
+	// Write to a temporary file first so that concurrent readers never see
+	// a partially written response.
+	f, err := os.CreateTemp(d.Dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), file)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// MemoryCache is a Cache keeping responses in memory, so that a process
+// making the same requests again, as batch jobs looking up the versions
+// shared by many graphs do, sends them only once. The zero value is ready
+// to use.
+type MemoryCache struct {
+	// How long responses are kept. If zero, they never expire.
+	TTL time.Duration
+
+	// The maximum number of responses kept. When the cache is full, the
+	// least recently used response is evicted to make room. If zero, the
+	// number is not limited.
+	MaxEntries int
+
+	mu      sync.Mutex
+	lru     *list.List // of *memoryEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	data    []byte
+	expires time.Time // zero if never
+}
+
+// NewMemoryCache returns a MemoryCache keeping up to maxEntries responses
+// for ttl.
+func NewMemoryCache(ttl time.Duration, maxEntries int) *MemoryCache {
+	return &MemoryCache{TTL: ttl, MaxEntries: maxEntries}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		m.remove(el)
+		return nil, false
+	}
+	m.lru.MoveToFront(el)
+	return e.data, true
+}
+
+// GetStale implements StaleCache.
+func (m *MemoryCache) GetStale(key string, maxStale time.Duration) ([]byte, bool, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false, false
+	}
+	e := el.Value.(*memoryEntry)
+	stale := !e.expires.IsZero() && time.Now().After(e.expires)
+	if stale && time.Now().After(e.expires.Add(maxStale)) {
+		m.remove(el)
+		return nil, false, false
+	}
+	m.lru.MoveToFront(el)
+	return e.data, stale, true
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, data []byte) {
+	m.SetTTL(key, data, 0)
+}
+
+// SetTTL implements ExpiringCache.
+func (m *MemoryCache) SetTTL(key string, data []byte, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = m.TTL
+	}
+	var expires time.Time
+	if ttl != 0 {
+		expires = time.Now().Add(ttl)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.lru = list.New()
+		m.entries = make(map[string]*list.Element)
+	}
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+	m.entries[key] = m.lru.PushFront(&memoryEntry{key: key, data: data, expires: expires})
+	for m.MaxEntries > 0 && m.lru.Len() > m.MaxEntries {
+		m.remove(m.lru.Back())
+	}
+}
+
+// Delete implements ExpiringCache.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+}
+
+// Len returns the number of responses in the cache, including expired
+// ones not yet evicted.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+func (m *MemoryCache) remove(el *list.Element) {
+	m.lru.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	c := &DiskCache{Dir: t.TempDir() + "/cache"}
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get on empty cache returned ok")
+	}
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	c.Set("a", []byte("3"))
+	for key, want := range map[string]string{"a": "3", "b": "2"} {
+		got, ok := c.Get(key)
+		if !ok || string(got) != want {
+			t.Errorf("Get(%q) returned %q, %v; want %q, true", key, got, ok, want)
+		}
+	}
+
+	// Age the response for a beyond the maximum age.
+	c.MaxAge = time.Hour
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(c.file("a"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get returned expired response")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Errorf("Get did not return fresh response")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache(0, 2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get on empty cache returned ok")
+	}
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	if data, ok := c.Get("a"); !ok || string(data) != "1" {
+		t.Errorf("Get(a) = %q, %t; want 1, true", data, ok)
+	}
+	// b is the least recently used.
+	c.Set("c", []byte("3"))
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(b) returned ok after b was evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Get(%s) returned !ok", key)
+		}
+	}
+	c.Set("c", []byte("4"))
+	if data, _ := c.Get("c"); string(data) != "4" || c.Len() != 2 {
+		t.Errorf("Get(c) = %q with %d entries after replacing it; want 4 with 2", data, c.Len())
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	var c MemoryCache
+	c.TTL = time.Hour
+	c.Set("a", []byte("1"))
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) returned !ok")
+	}
+	c.TTL = time.Nanosecond
+	c.Set("a", []byte("1"))
+	time.Sleep(time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(a) returned an expired response")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d after expiry, want 0", c.Len())
+	}
+}
+
+func TestMemoryCacheConcurrent(t *testing.T) {
+	c := NewMemoryCache(time.Minute, 10)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				key := fmt.Sprint((i + j) % 20)
+				c.Set(key, []byte(key))
+				c.Get(key)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := c.Len(); n > 10 {
+		t.Errorf("Len() = %d, want at most 10", n)
+	}
+}
+
+func TestExpiringCaches(t *testing.T) {
+	caches := map[string]ExpiringCache{
+		"DiskCache":   &DiskCache{Dir: t.TempDir(), MaxAge: time.Hour},
+		"MemoryCache": NewMemoryCache(time.Hour, 0),
+	}
+	for name, c := range caches {
+		c.SetTTL("a", []byte("1"), time.Minute)
+		c.SetTTL("b", []byte("2"), -time.Minute)
+		c.SetTTL("c", []byte("3"), 2*time.Hour)
+		for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+			if _, ok := c.Get(key); ok != want {
+				t.Errorf("%s: Get(%s) ok = %t, want %t", name, key, ok, want)
+			}
+		}
+		c.Delete("a")
+		c.Delete("missing")
+		if _, ok := c.Get("a"); ok {
+			t.Errorf("%s: Get(a) returned ok after Delete", name)
+		}
+	}
+
+	// Responses a DiskCache without MaxAge could not expire are not kept.
+	d := &DiskCache{Dir: t.TempDir()}
+	d.SetTTL("a", []byte("1"), time.Minute)
+	if _, ok := d.Get("a"); ok {
+		t.Error("DiskCache without MaxAge kept a response with a TTL")
+	}
+}
+
+func TestDiskCachePrune(t *testing.T) {
+	c := &DiskCache{Dir: t.TempDir(), MaxAge: time.Hour}
+	if n, err := (&DiskCache{Dir: c.Dir + "/missing"}).Prune(); n != 0 || err != nil {
+		t.Errorf("Prune of a missing directory = %d, %v", n, err)
+	}
+	c.Set("fresh", []byte("1"))
+	c.Set("stale", []byte("2"))
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(c.file("stale"), old, old)
+	for _, name := range []string{"tmp-1", "notes.txt"} {
+		file := c.Dir + "/" + name
+		os.WriteFile(file, nil, 0o644)
+		os.Chtimes(file, old, old)
+	}
+
+	n, err := c.Prune()
+	if err != nil || n != 2 {
+		t.Errorf("Prune = %d, %v; want 2 files removed", n, err)
+	}
+	if _, ok := c.Get("fresh"); !ok {
+		t.Error("Prune removed a fresh response")
+	}
+	for _, name := range []string{"stale", "tmp-1", "notes.txt"} {
+		file := c.Dir + "/" + name
+		if name == "stale" {
+			file = c.file(name)
+		}
+		_, err := os.Stat(file)
+		if exists := err == nil; exists != (name == "notes.txt") {
+			t.Errorf("%s exists = %t after Prune", name, exists)
+		}
+	}
+}
+
+func TestStaleCaches(t *testing.T) {
+	caches := map[string]StaleCache{
+		"DiskCache":   &DiskCache{Dir: t.TempDir(), MaxAge: time.Hour},
+		"MemoryCache": NewMemoryCache(time.Hour, 0),
+	}
+	for name, c := range caches {
+		ec := c.(ExpiringCache)
+		ec.SetTTL("fresh", []byte("1"), time.Minute)
+		ec.SetTTL("stale", []byte("2"), -time.Minute)
+		ec.SetTTL("gone", []byte("3"), -time.Hour)
+		for _, tc := range []struct {
+			key       string
+			stale, ok bool
+		}{
+			{"fresh", false, true},
+			{"stale", true, true},
+			{"gone", false, false},
+			{"missing", false, false},
+		} {
+			if _, stale, ok := c.GetStale(tc.key, 30*time.Minute); stale != tc.stale || ok != tc.ok {
+				t.Errorf("%s: GetStale(%s) = %t, %t; want %t, %t", name, tc.key, stale, ok, tc.stale, tc.ok)
+			}
+		}
+	}
+}
@@ -5,59 +5,522 @@
 package insights
 
 import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const basePath = "https://api.deps.dev/v3/"
 
 // Client is a client for the deps.dev API.
 type Client struct {
-	// Base URL for API requests.
+	// Base URL for API requests, that of v3 of the API. Endpoints only
+	// served by other versions, such as v3alpha, are requested from the
+	// sibling of its last path element if that names a version, and from
+	// BaseURL itself otherwise.
 	BaseURL *url.URL
+
+	// The HTTP client used to send requests. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+
+	// Retry, if not nil, is the policy requests that fail because of a
+	// network error, a rate limit, or a transient server error are retried
+	// with. If nil, requests are not retried.
+	Retry *RetryPolicy
+
+	// TracerProvider, if not nil, provides the OpenTelemetry tracer of
+	// the client, which records a span for each API call. Spans are named
+	// after the endpoint called, as in "deps.dev GetVersion", and have
+	// attributes for the package version or other resource asked for, the
+	// API version, and the status of the response.
+	TracerProvider trace.TracerProvider
+
+	// Metrics, if not nil, records each API call made by the client.
+	Metrics Metrics
+
+	// Middleware wraps the Transport of HTTPClient, the first outermost,
+	// so that requests and responses can be seen or changed.
+	Middleware []Middleware
+
+	// UserAgent identifies the application to deps.dev in the User-Agent
+	// header of requests. If empty, DefaultUserAgent is used.
+	UserAgent string
+
+	// Header holds extra headers sent with every request, such as those
+	// asked for by a proxy. The Accept and User-Agent headers are set by
+	// the client.
+	Header http.Header
+
+	// Cache, if not nil, stores successful API responses. Requests whose
+	// response is in the cache are not sent to the API. Batch lookups,
+	// such as those of PurlLookupBatch, are not cached.
+	Cache Cache
+
+	// GraphCache, if not nil, stores the graphs returned by GetDependencies,
+	// which are then served from it, even when the client is Offline,
+	// before Cache is consulted.
+	GraphCache *GraphCache
+
+	// NotFoundTTL, if positive, is how long 404 Not Found responses are
+	// cached, so that packages and versions deps.dev does not know of, such
+	// as misspelled or private ones, are not asked for again and again.
+	// They are cached only if Cache is an ExpiringCache.
+	NotFoundTTL time.Duration
+
+	// MaxStale, if positive, is how long after they expire responses in
+	// the Cache are still served, if it is a StaleCache. A stale response
+	// is returned right away, marked Stale, and fetched again in the
+	// background to refresh the cache, so that callers sensitive to
+	// latency seldom wait for the API.
+	MaxStale time.Duration
+
+	// flights holds the requests in flight, by URL, whose responses are
+	// shared with identical requests.
+	flightsMu sync.Mutex
+	flights   map[string]*flight
+
+	// revalidating holds the URLs of the stale responses being fetched
+	// again.
+	revalidating sync.Map
+
+	// If Offline is true, requests are never sent to the API and only
+	// responses in the cache are available. Other requests fail with
+	// ErrOffline.
+	Offline bool
+
+	// Logger, if not nil, receives diagnostics about the requests made by
+	// the client. Requests sent to the API are logged at Info level and
+	// responses served from the cache at Debug level.
+	Logger *slog.Logger
+
+	// Fallback, if not nil, is asked for packages and versions deps.dev
+	// does not know of, and for the publication time, licenses, and links
+	// of versions it has none for. Responses it answers are marked
+	// FromFallback.
+	Fallback Fallback
+
+	// MaxResponseBytes limits the size of the response bodies read from
+	// the API, so that a misbehaving proxy or an unexpectedly large
+	// response cannot exhaust memory. Larger responses fail with
+	// ErrResponseTooLarge. If zero, DefaultMaxResponseBytes is used; if
+	// negative, response sizes are not limited. The bodies of error
+	// responses are further truncated to maxErrorBytes.
+	MaxResponseBytes int64
+
+	// EndpointVersions overrides the versions of the API, such as "v3",
+	// "v3alpha", or "v4", that endpoints are requested from, so that they
+	// can be moved to a new version one at a time. The versions of an
+	// endpoint are tried in order of preference while they do not serve
+	// it, answering 501 Not Implemented or a 404 Not Found other than the
+	// one deps.dev gives for what does not exist, and the first to answer
+	// successfully is used from then on. Endpoints not in the map are requested from v3,
+	// or v3alpha if they are only available there.
+	EndpointVersions map[Endpoint][]string
+
+	// negotiated maps the endpoints that may be served by several versions
+	// of the API to the version that answered them.
+	negotiated sync.Map
+
+	// probed holds the endpoints whose versions have been tried on a 404
+	// Not Found from deps.dev, which is done once per endpoint.
+	probed sync.Map
+}
+
+// DefaultUserAgent is the User-Agent of clients whose UserAgent is empty.
+const DefaultUserAgent = "insights (https://github.com/franoliveto/insights)"
+
+// DefaultMaxResponseBytes is the response size limit of clients whose
+// MaxResponseBytes is zero. It is far larger than the dependency graphs of
+// the largest packages.
+const DefaultMaxResponseBytes = 256 << 20
+
+// maxErrorBytes is the size of the longest error message kept from an
+// error response.
+const maxErrorBytes = 64 << 10
+
+// APIError reports an unsuccessful response from the API. It matches the
+// sentinel error of its status code, if any, with errors.Is:
+//
+//	if errors.Is(err, insights.ErrNotFound) {
+//		// deps.dev does not know of the package.
+//	}
+type APIError struct {
+	// The HTTP status code of the response.
+	StatusCode int
+
+	// The body of the response, which describes the error.
+	Body string
+
+	// The URL requested.
+	URL string
 }
 
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s", e.StatusCode, e.Body)
+}
+
+// Is reports whether target is the sentinel error of e's status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// Sentinel errors matched by APIErrors with errors.Is.
+var (
+	// ErrBadRequest matches 400 Bad Request responses, to requests with
+	// invalid arguments, such as an unknown system or a malformed version.
+	ErrBadRequest = errors.New("insights: bad request")
+
+	// ErrNotFound matches 404 Not Found responses, to requests for
+	// packages, versions, projects, or advisories deps.dev does not know
+	// of.
+	ErrNotFound = errors.New("insights: not found")
+
+	// ErrRateLimited matches 429 Too Many Requests responses.
+	ErrRateLimited = errors.New("insights: rate limited")
+
+	// ErrServer matches 5xx responses, to requests the API failed to
+	// answer.
+	ErrServer = errors.New("insights: server error")
+)
+
+// ErrOffline is returned when a response is not in the cache and the client
+// is offline.
+var ErrOffline = errors.New("insights: response not cached and client is offline")
+
+// ErrResponseTooLarge is returned when a response is larger than the
+// client's MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("insights: response too large")
+
 // NewClient returns a new deps.dev API client.
+//
+// The defaults can be overridden with environment variables:
+// INSIGHT_BASE_URL sets the base URL of the API, and INSIGHT_CACHE_DIR
+// enables caching responses on disk in the given directory, for up to
+// DefaultCacheMaxAge, unless INSIGHT_NO_CACHE is set to a non-empty value.
+// Invalid values are ignored.
 func NewClient() *Client {
 	u, _ := url.Parse(basePath)
-	return &Client{BaseURL: u}
+	if v := os.Getenv("INSIGHT_BASE_URL"); v != "" {
+		if eu, err := parseBaseURL(v); err == nil {
+			u = eu
+		}
+	}
+	c := &Client{BaseURL: u}
+	if dir := os.Getenv("INSIGHT_CACHE_DIR"); dir != "" && os.Getenv("INSIGHT_NO_CACHE") == "" {
+		c.Cache = &DiskCache{Dir: dir, MaxAge: DefaultCacheMaxAge}
+	}
+	return c
+}
+
+// parseBaseURL parses s as the base URL of the API. Since API paths are
+// resolved relative to it, the URL is given a trailing slash.
+func parseBaseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("base URL %q is not absolute", s)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
+
+// Response wraps the HTTP response to an API request, giving access to its
+// status and headers, such as those about caching and rate limits.
+type Response struct {
+	*http.Response
+
+	// Whether the response was served from the client's Cache or
+	// GraphCache. If so, the HTTP response is reconstructed: it has a status
+	// of 200 OK and, if served from Cache, the request, but no headers.
+	FromCache bool
+
+	// Whether the response was served from the client's Cache after it
+	// expired, within MaxStale. If so, it is being fetched again in the
+	// background.
+	Stale bool
+
+	// Whether the result was supplied by the client's Fallback because
+	// deps.dev did not have it. If so, the HTTP response is that of the
+	// API, if any, which reports the failure.
+	FromFallback bool
+}
+
+// cachedResponse returns the Response of a request served from a cache.
+func cachedResponse(req *http.Request) *Response {
+	return &Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Request:    req,
+		},
+		FromCache: true,
+	}
 }
 
-func (c *Client) get(ctx context.Context, path string, v any) error {
+// newRequest returns a request for path, relative to BaseURL, with the
+// headers of the client. body, if not nil, is sent as JSON.
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
 	// path must not have a leading slash.
 	path = strings.TrimPrefix(path, "/")
 
 	u, err := c.BaseURL.Parse(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), r)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for k, vs := range c.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
 	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", cmp.Or(c.UserAgent, DefaultUserAgent))
+	req.Header.Set("Accept-Encoding", "gzip")
+	return req, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// do sends a GET request for path, relative to BaseURL, and calls decode
+// with the body of a successful response. Unless the client has a Cache,
+// the body is read by decode as it is received.
+func (c *Client) do(ctx context.Context, path string, decode func(r io.Reader) error) (*Response, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	key := req.URL.String()
 
-	if resp.StatusCode != http.StatusOK {
+	if c.Cache != nil {
+		if data, stale, ok := c.cacheGet(key); ok {
+			c.log(ctx, slog.LevelDebug, "cache hit", slog.String("url", key), slog.Bool("stale", stale))
+			err := decode(bytes.NewReader(data))
+			if ec, ok := c.Cache.(ExpiringCache); ok && err != nil {
+				// Fetched anew next time.
+				ec.Delete(key)
+			}
+			resp := cachedResponse(req)
+			if stale && err == nil {
+				resp.Stale = true
+				c.revalidate(ctx, req)
+			}
+			return resp, err
+		}
+		if data, ok := c.Cache.Get(notFoundKey(key)); ok && c.NotFoundTTL > 0 {
+			c.log(ctx, slog.LevelDebug, "cache hit", slog.String("url", key), slog.Int("status", http.StatusNotFound))
+			resp := cachedResponse(req)
+			resp.Status, resp.StatusCode = "404 Not Found", http.StatusNotFound
+			return resp, &APIError{StatusCode: http.StatusNotFound, Body: string(data), URL: key}
+		}
+	}
+	if c.Offline {
+		return nil, fmt.Errorf("%w: %s", ErrOffline, key)
+	}
+	return c.fetchShared(ctx, req, decode)
+}
+
+// post is like do, but sends a POST request with body, as JSON. Neither
+// the request nor its response are cached, nor shared with identical
+// requests.
+func (c *Client) post(ctx context.Context, path string, body []byte, decode func(r io.Reader) error) (*Response, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Offline {
+		return nil, fmt.Errorf("%w: %s", ErrOffline, req.URL)
+	}
+	return c.fetch(ctx, req, decode)
+}
+
+// fetch sends req to the API and calls decode with the body of a
+// successful response, which is then stored in the client's Cache, if
+// any and if req is a GET request.
+func (c *Client) fetch(ctx context.Context, req *http.Request, decode func(r io.Reader) error) (*Response, error) {
+	key := req.URL.String()
+	cache := c.Cache
+	if req.Method != http.MethodGet {
+		cache = nil
+	}
+	hresp, err := c.send(c.httpClient(), req)
+	if err != nil {
+		return nil, err
+	}
+	defer hresp.Body.Close()
+	resp := &Response{Response: hresp}
+	raw, err := decompress(hresp)
+	if err != nil {
+		return resp, fmt.Errorf("%s: %w", key, err)
+	}
+
+	if hresp.StatusCode != http.StatusOK {
 		// Error messages are just text/plain.
-		data, err := io.ReadAll(resp.Body)
+		data, err := io.ReadAll(io.LimitReader(raw, maxErrorBytes))
 		if err != nil {
-			return fmt.Errorf("%d %v", resp.StatusCode, err)
+			data = []byte(err.Error())
 		}
-		return fmt.Errorf("%d %s", resp.StatusCode, string(data))
+		if ec, ok := cache.(ExpiringCache); ok && c.NotFoundTTL > 0 && hresp.StatusCode == http.StatusNotFound {
+			ec.SetTTL(notFoundKey(key), data, c.NotFoundTTL)
+		}
+		return resp, &APIError{StatusCode: hresp.StatusCode, Body: string(data), URL: key}
+	}
+	limit := c.MaxResponseBytes
+	if limit == 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	body := raw
+	if limit > 0 {
+		if hresp.ContentLength > limit {
+			return resp, fmt.Errorf("%w: %s: %d bytes", ErrResponseTooLarge, key, hresp.ContentLength)
+		}
+		body = &limitedReader{r: body, n: limit}
+	}
+	if cache == nil {
+		return resp, tooLarge(decode(body), key)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return resp, tooLarge(err, key)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-		return err
+	if err := decode(bytes.NewReader(data)); err != nil {
+		return resp, err
 	}
-	return nil
+	cache.Set(key, data)
+	return resp, nil
+}
+
+// decompress returns the body of hresp, decompressed if it is encoded
+// with gzip. Compressed responses are asked for by the client itself,
+// rather than by the Transport of its HTTPClient, so that they are asked
+// for whatever the Transport, and so that MaxResponseBytes applies to the
+// decompressed body.
+func decompress(hresp *http.Response) (io.Reader, error) {
+	if !strings.EqualFold(hresp.Header.Get("Content-Encoding"), "gzip") {
+		return hresp.Body, nil
+	}
+	zr, err := gzip.NewReader(hresp.Body)
+	if err == io.EOF {
+		// An empty body.
+		return strings.NewReader(""), nil
+	}
+	return zr, err
+}
+
+// notFoundKey returns the key under which a 404 Not Found response to the
+// request for the URL key is cached.
+func notFoundKey(key string) string {
+	return "404 " + key
+}
+
+// limitedReader reads from r until n bytes are read, after which it fails
+// with ErrResponseTooLarge if r has more to read. Unlike io.LimitReader,
+// it does not silently truncate r, which could make a cut response seem
+// whole.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if l.n <= 0 {
+		// Read one byte to tell the end of r from more data.
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// tooLarge adds the URL to err if the response to it was too large.
+func tooLarge(err error, url string) error {
+	if errors.Is(err, ErrResponseTooLarge) {
+		return fmt.Errorf("%w: %s", ErrResponseTooLarge, url)
+	}
+	return err
+}
+
+// log logs a message with c.Logger, if any.
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.Logger != nil {
+		c.Logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
+// cacheGet returns the response to the request for the URL key from the
+// client's Cache, which may be stale if the client has a MaxStale.
+func (c *Client) cacheGet(key string) (data []byte, stale, ok bool) {
+	if sc, isStale := c.Cache.(StaleCache); isStale && c.MaxStale > 0 && !c.Offline {
+		return sc.GetStale(key, c.MaxStale)
+	}
+	data, ok = c.Cache.Get(key)
+	return data, false, ok
+}
+
+// revalidate fetches the stale response to req again in the background,
+// storing it in the client's Cache, unless it is already being fetched.
+func (c *Client) revalidate(ctx context.Context, req *http.Request) {
+	key := req.URL.String()
+	if _, busy := c.revalidating.LoadOrStore(key, true); busy {
+		return
+	}
+	// The refresh outlives the call that asked for it.
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer c.revalidating.Delete(key)
+		_, err := c.fetch(ctx, req.Clone(ctx), func(r io.Reader) error {
+			_, err := io.Copy(io.Discard, r)
+			return err
+		})
+		if err != nil {
+			c.log(ctx, slog.LevelInfo, "revalidation failed", slog.String("url", key), slog.Any("error", err))
+		}
+	}()
 }
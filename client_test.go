@@ -1,6 +1,19 @@
 package insights
 
-import "testing"
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
 
 func TestNewClient(t *testing.T) {
 	c := NewClient()
@@ -9,4 +22,362 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
-// TODO: add test for Client.get method.
+func TestNewClientBaseURLFromEnv(t *testing.T) {
+	testCases := []struct {
+		env  string
+		want string
+	}{
+		{"http://localhost:8080/v3", "http://localhost:8080/v3/"},
+		{"http://localhost:8080/v3/", "http://localhost:8080/v3/"},
+		{"not a url", basePath},
+		{"", basePath},
+	}
+	for _, c := range testCases {
+		t.Setenv("INSIGHT_BASE_URL", c.env)
+		if got := NewClient().BaseURL.String(); got != c.want {
+			t.Errorf("NewClient with INSIGHT_BASE_URL=%q: BaseURL is %v, want %v", c.env, got, c.want)
+		}
+	}
+}
+
+func TestNewClientCacheFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("INSIGHT_CACHE_DIR", dir)
+	t.Setenv("INSIGHT_NO_CACHE", "")
+	want := &DiskCache{Dir: dir, MaxAge: DefaultCacheMaxAge}
+	if got, ok := NewClient().Cache.(*DiskCache); !ok || *got != *want {
+		t.Errorf("NewClient with INSIGHT_CACHE_DIR: Cache is %+v, want %+v", NewClient().Cache, want)
+	}
+
+	t.Setenv("INSIGHT_NO_CACHE", "1")
+	if c := NewClient().Cache; c != nil {
+		t.Errorf("NewClient with INSIGHT_NO_CACHE: Cache is %+v, want nil", c)
+	}
+}
+
+// memCache is a Cache for tests.
+type memCache struct {
+	mu sync.Mutex
+	m  map[string][]byte
+}
+
+func (c *memCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.m[key]
+	return data, ok
+}
+
+func (c *memCache) Set(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[key] = data
+}
+
+func TestGetCache(t *testing.T) {
+	client, mux := setup(t)
+	client.Cache = &memCache{m: make(map[string][]byte)}
+
+	requests := 0
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+	mux.HandleFunc("/systems/go/packages/bar", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "package not found", http.StatusNotFound)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		p, _, err := client.GetPackage(ctx, "go", "foo")
+		if err != nil {
+			t.Fatalf("GetPackage failed: %v", err)
+		}
+		if p.PackageKey.Name != "foo" {
+			t.Errorf("GetPackage returned %+v", p)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests; want 1", requests)
+	}
+
+	// Errors are not cached.
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.GetPackage(ctx, "go", "bar"); err == nil {
+			t.Errorf("GetPackage expected error")
+		}
+	}
+	if requests != 3 {
+		t.Errorf("got %d requests; want 3", requests)
+	}
+
+	client.Offline = true
+	_, resp, err := client.GetPackage(ctx, "go", "foo")
+	if err != nil {
+		t.Errorf("GetPackage of cached package while offline failed: %v", err)
+	}
+	if !resp.FromCache || resp.StatusCode != http.StatusOK {
+		t.Errorf("GetPackage of cached package returned response %d, from cache %t", resp.StatusCode, resp.FromCache)
+	}
+	if _, _, err := client.GetPackage(ctx, "go", "bar"); !errors.Is(err, ErrOffline) {
+		t.Errorf("GetPackage of uncached package while offline returned %v; want ErrOffline", err)
+	}
+	if requests != 3 {
+		t.Errorf("got %d requests while offline; want none", requests-3)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	client, mux := setup(t)
+	client.Cache = &memCache{m: make(map[string][]byte)}
+	var buf bytes.Buffer
+	client.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.GetPackage(ctx, "go", "foo"); err != nil {
+			t.Fatalf("GetPackage failed: %v", err)
+		}
+	}
+	out := buf.String()
+	for _, want := range []string{"level=INFO msg=request", "status=200", "level=DEBUG msg=\"cache hit\""} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	client, mux := setup(t)
+	var sent int
+	client.HTTPClient = &http.Client{
+		Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Test", "yes")
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "X-Test", "yes")
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+
+	if _, _, err := client.GetPackage(context.Background(), "go", "foo"); err != nil {
+		t.Fatalf("GetPackage failed: %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("HTTPClient sent %d requests, want 1", sent)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	client, mux := setup(t)
+	client.UserAgent = "scanner/1.0"
+	client.Header = http.Header{
+		"X-Api-Key":  {"secret"},
+		"User-Agent": {"ignored"},
+	}
+
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "User-Agent", "scanner/1.0")
+		testHeader(t, r, "X-Api-Key", "secret")
+		testHeader(t, r, "Accept", "application/json; charset=utf-8")
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+
+	if _, _, err := client.GetPackage(context.Background(), "go", "foo"); err != nil {
+		t.Fatalf("GetPackage failed: %v", err)
+	}
+}
+
+func TestCorruptCacheEntryDeleted(t *testing.T) {
+	client, mux := setup(t)
+	cache := NewMemoryCache(0, 0)
+	client.Cache = cache
+
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+	u, _ := client.BaseURL.Parse("systems/go/packages/foo")
+	cache.Set(u.String(), []byte("{"))
+
+	ctx := context.Background()
+	if _, _, err := client.GetPackage(ctx, "go", "foo"); err == nil {
+		t.Fatal("GetPackage of a corrupt cached response succeeded")
+	}
+	if _, resp, err := client.GetPackage(ctx, "go", "foo"); err != nil || resp.FromCache {
+		t.Errorf("GetPackage after a corrupt cached response returned %v, from cache %t", err, resp != nil && resp.FromCache)
+	}
+}
+
+func TestNotFoundTTL(t *testing.T) {
+	client, mux := setup(t)
+	client.Cache = NewMemoryCache(0, 0)
+	client.NotFoundTTL = time.Hour
+
+	var n int
+	mux.HandleFunc("/systems/npm/packages/reacct", func(w http.ResponseWriter, r *http.Request) {
+		n++
+		http.Error(w, "package not found", http.StatusNotFound)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, resp, err := client.GetPackage(ctx, "npm", "reacct")
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetPackage returned %v, want ErrNotFound", err)
+		}
+		if resp.StatusCode != http.StatusNotFound || resp.FromCache != (i == 1) {
+			t.Errorf("GetPackage %d returned response %d, from cache %t", i, resp.StatusCode, resp.FromCache)
+		}
+	}
+	if n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+
+	// Caches that cannot expire responses do not keep them.
+	client.Cache = &memCache{m: make(map[string][]byte)}
+	n = 0
+	client.GetPackage(ctx, "npm", "reacct")
+	client.GetPackage(ctx, "npm", "reacct")
+	if n != 2 {
+		t.Errorf("sent %d requests with a Cache, want 2", n)
+	}
+}
+
+func TestMaxStale(t *testing.T) {
+	client, mux := setup(t)
+	cache := NewMemoryCache(time.Millisecond, 0)
+	client.Cache = cache
+	client.MaxStale = time.Hour
+
+	var mu sync.Mutex
+	n := 0
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n++
+		fmt.Fprintf(w, `{"packageKey":{"system":"GO","name":"foo%d"}}`, n)
+		mu.Unlock()
+	})
+
+	ctx := context.Background()
+	p, resp, err := client.GetPackage(ctx, "go", "foo")
+	if err != nil || p.PackageKey.Name != "foo1" || resp.FromCache {
+		t.Fatalf("GetPackage = %v, %v; want foo1 from the API", p, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// The expired response is served, and refreshed in the background.
+	p, resp, err = client.GetPackage(ctx, "go", "foo")
+	if err != nil || p.PackageKey.Name != "foo1" || !resp.FromCache || !resp.Stale {
+		t.Fatalf("GetPackage = %v, %+v, %v; want stale foo1 from the cache", p, resp, err)
+	}
+	u, _ := client.BaseURL.Parse("systems/go/packages/foo")
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if data, _, ok := cache.GetStale(u.String(), time.Hour); ok && strings.Contains(string(data), "foo2") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stale response was not refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// writeGzip writes body compressed with gzip to w, with status code.
+func writeGzip(w http.ResponseWriter, code int, body string) {
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(code)
+	zw := gzip.NewWriter(w)
+	io.WriteString(zw, body)
+	zw.Close()
+}
+
+func TestGzip(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "Accept-Encoding", "gzip")
+		writeGzip(w, http.StatusOK, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+	mux.HandleFunc("/systems/go/packages/bar", func(w http.ResponseWriter, r *http.Request) {
+		writeGzip(w, http.StatusNotFound, "package not found")
+	})
+	mux.HandleFunc("/systems/go/packages/big", func(w http.ResponseWriter, r *http.Request) {
+		writeGzip(w, http.StatusOK, `{"packageKey":{"system":"GO","name":"`+strings.Repeat("x", 1<<20)+`"}}`)
+	})
+
+	ctx := context.Background()
+	p, _, err := client.GetPackage(ctx, "go", "foo")
+	if err != nil || p.PackageKey.Name != "foo" {
+		t.Errorf("GetPackage = %v, %v; want foo", p, err)
+	}
+	var apiErr *APIError
+	if _, _, err := client.GetPackage(ctx, "go", "bar"); !errors.As(err, &apiErr) || apiErr.Body != "package not found" {
+		t.Errorf("GetPackage returned %v, want the decompressed error message", err)
+	}
+	// The limit applies to the decompressed response.
+	client.MaxResponseBytes = 1 << 16
+	if _, _, err := client.GetPackage(ctx, "go", "big"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("GetPackage returned %v, want ErrResponseTooLarge", err)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	client, mux := setup(t)
+	body := `{"packageKey":{"system":"NPM","name":"big"},"versions":[` + strings.Repeat(`{"versionKey":{"version":"1.0.0"}},`, 100) + `{}]}`
+	mux.HandleFunc("/systems/npm/packages/big", func(w http.ResponseWriter, r *http.Request) {
+		// Streamed, without a Content-Length.
+		w.(http.Flusher).Flush()
+		fmt.Fprint(w, body)
+	})
+	mux.HandleFunc("/systems/npm/packages/sized", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		fmt.Fprint(w, body)
+	})
+	graph := `{"nodes":[` + strings.Repeat(`{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"}},`, 100) + `{}]}`
+	mux.HandleFunc("/systems/npm/packages/big/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		fmt.Fprint(w, graph)
+	})
+	ctx := context.Background()
+
+	client.MaxResponseBytes = int64(len(body))
+	if _, _, err := client.GetPackage(ctx, "npm", "big"); err != nil {
+		t.Errorf("GetPackage of a response of the maximum size: %v", err)
+	}
+
+	client.MaxResponseBytes = 100
+	for _, name := range []string{"big", "sized"} {
+		if _, _, err := client.GetPackage(ctx, "npm", name); !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("GetPackage of %s returned %v, want ErrResponseTooLarge", name, err)
+		}
+	}
+	// Streamed graphs are cut off at the limit.
+	n := 0
+	_, err := client.StreamDependencies(ctx, "npm", "big", "1.0.0", &DependencyHandler{Node: func(int, *Node) error { n++; return nil }})
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("StreamDependencies returned %v, want ErrResponseTooLarge", err)
+	}
+	if n > 2 {
+		t.Errorf("StreamDependencies decoded %d nodes from 100 bytes", n)
+	}
+
+	// Cached responses are read whole before being decoded.
+	client.Cache = &memCache{m: make(map[string][]byte)}
+	if _, _, err := client.GetPackage(ctx, "npm", "big"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("GetPackage with a Cache returned %v, want ErrResponseTooLarge", err)
+	}
+
+	client.Cache = nil
+	client.MaxResponseBytes = -1
+	if _, _, err := client.GetPackage(ctx, "npm", "big"); err != nil {
+		t.Errorf("GetPackage without a limit: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+module github.com/franoliveto/insights/cmd/insight-mockserver
+
+go 1.25.0
+
+require github.com/franoliveto/insights/mockserver v0.0.0-00010101000000-000000000000
+
+require (
+	deps.dev/api/v3 v3.0.0-20260811035547-133c155ce203 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/franoliveto/insights v0.0.0-00010101000000-000000000000 // indirect
+	github.com/franoliveto/insights/protoconv v0.0.0-00010101000000-000000000000 // indirect
+	go.opentelemetry.io/otel v1.43.0 // indirect
+	go.opentelemetry.io/otel/trace v1.43.0 // indirect
+	golang.org/x/net v0.55.0 // indirect
+	golang.org/x/sys v0.45.0 // indirect
+	golang.org/x/text v0.37.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+	google.golang.org/grpc v1.82.1 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
+replace (
+	github.com/franoliveto/insights => ../../
+	github.com/franoliveto/insights/mockserver => ../../mockserver
+	github.com/franoliveto/insights/protoconv => ../../protoconv
+)
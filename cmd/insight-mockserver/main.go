@@ -0,0 +1,55 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Insight-mockserver serves a canned dataset over the deps.dev v3 API, so
+// that integration tests of tools built on insights can run without
+// network access.
+//
+// Usage:
+//
+//	insight-mockserver [-addr host:port] fixture...
+//
+// The fixtures are JSON files, or directories of them, in the format
+// described in package mockserver. Point clients at the server with, for
+// example, INSIGHT_BASE_URL=http://localhost:8080/v3/.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/franoliveto/insights/mockserver"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "listen on `host:port`; port 0 picks a free one")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: insight-mockserver [-addr host:port] fixture...\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+	}
+	log.SetFlags(0)
+	log.SetPrefix("insight-mockserver: ")
+
+	d, err := mockserver.Load(flag.Args()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Printed to standard output so that scripts starting the server on a
+	// free port can read where it listens.
+	fmt.Printf("http://%s/v3/\n", l.Addr())
+	log.Fatal(http.Serve(l, mockserver.New(d)))
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Insight-sigverify verifies locally the SLSA provenance of an npm package
+// version, rather than relying on deps.dev having verified it. It checks
+// the Sigstore bundles of the provenance against the trusted root of the
+// public-good instance of Sigstore, for the published tarball and a CI
+// build of the given source repository.
+//
+// Usage:
+//
+//	insight-sigverify [-trusted-root file] name version repository
+//
+// The repository is given as a project identifier such as
+// github.com/user/repo. The trusted root is fetched with TUF unless the
+// trusted_root.json file to use is given. The command is run by
+// "x verify -local", and exits with status 1 if the provenance does not
+// verify.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/franoliveto/insights/sigverify"
+)
+
+func main() {
+	trustedRoot := flag.String("trusted-root", "", "read the Sigstore trusted root from `file` instead of fetching it")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: insight-sigverify [-trusted-root file] name version repository\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+	if flag.NArg() != 3 {
+		flag.Usage()
+	}
+	log.SetFlags(0)
+	log.SetPrefix("insight-sigverify: ")
+
+	name, version, repo := flag.Arg(0), flag.Arg(1), flag.Arg(2)
+	sv, err := sigverify.PublicGood(*trustedRoot)
+	if err != nil {
+		log.Fatal(err)
+	}
+	v := &sigverify.Verifier{Sigstore: sv}
+	provs, err := v.VerifyNPM(context.Background(), name, version, repo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, p := range provs {
+		fmt.Printf("%s: signed by %s\n", p.PredicateType, p.Build)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// A flight is a request to the API in flight, whose response is shared
+// with the identical requests made while it is.
+type flight struct {
+	done    chan struct{}
+	waiters int // guarded by Client.flightsMu
+
+	// Set before done is closed. data is the body of the response if it
+	// was shared.
+	data []byte
+	resp *Response
+	err  error
+}
+
+// fetchShared is like fetch, but identical requests made concurrently are
+// sent once, as when the goroutines walking a dependency graph reach the
+// same version together: the first is sent, and the others wait for its
+// response.
+//
+// The response body is kept in memory to be shared only if other
+// requests are waiting for it when it arrives; otherwise it is decoded as
+// it is received, and the requests made after that are sent on their own.
+func (c *Client) fetchShared(ctx context.Context, req *http.Request, decode func(r io.Reader) error) (*Response, error) {
+	key := req.URL.String()
+	for {
+		c.flightsMu.Lock()
+		f, ok := c.flights[key]
+		if !ok {
+			break
+		}
+		f.waiters++
+		c.flightsMu.Unlock()
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if f.data != nil {
+			resp := *f.resp
+			return &resp, decode(bytes.NewReader(f.data))
+		}
+		// Try again if the request failed only because the caller that
+		// sent it gave up.
+		if f.err != nil && !errors.Is(f.err, context.Canceled) && !errors.Is(f.err, context.DeadlineExceeded) {
+			return f.resp, f.err
+		}
+	}
+	f := &flight{done: make(chan struct{})}
+	if c.flights == nil {
+		c.flights = make(map[string]*flight)
+	}
+	c.flights[key] = f
+	c.flightsMu.Unlock()
+
+	leave := func() {
+		c.flightsMu.Lock()
+		if c.flights[key] == f {
+			delete(c.flights, key)
+		}
+		c.flightsMu.Unlock()
+	}
+	resp, err := c.fetch(ctx, req, func(r io.Reader) error {
+		c.flightsMu.Lock()
+		shared := f.waiters > 0
+		if !shared {
+			delete(c.flights, key)
+		}
+		c.flightsMu.Unlock()
+		if !shared {
+			return decode(r)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		f.data = data
+		return decode(bytes.NewReader(data))
+	})
+	leave()
+	f.resp, f.err = resp, err
+	close(f.done)
+	return resp, err
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalesce(t *testing.T) {
+	client, mux := setup(t)
+
+	var n atomic.Int32
+	release := make(chan struct{})
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		n.Add(1)
+		<-release
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, _, err := client.GetPackage(context.Background(), "go", "foo")
+			if err == nil && p.PackageKey.Name != "foo" {
+				err = fmt.Errorf("got package %q", p.PackageKey.Name)
+			}
+			errs <- err
+		}()
+	}
+
+	// Wait for the other callers to wait for the first.
+	u, _ := client.BaseURL.Parse("systems/go/packages/foo")
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		client.flightsMu.Lock()
+		f := client.flights[u.String()]
+		waiting := f != nil && f.waiters == callers-1
+		client.flightsMu.Unlock()
+		if waiting {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("callers did not wait for the request in flight")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetPackage failed: %v", err)
+		}
+	}
+	if got := n.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+
+	// Requests made after the response arrived are sent again.
+	if _, _, err := client.GetPackage(context.Background(), "go", "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Load(); got != 2 {
+		t.Errorf("sent %d requests, want 2", got)
+	}
+}
+
+func TestCoalesceErrors(t *testing.T) {
+	client, mux := setup(t)
+
+	release := make(chan struct{})
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		http.Error(w, "package not found", http.StatusNotFound)
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.GetPackage(context.Background(), "go", "foo")
+			errs <- err
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetPackage returned %v, want ErrNotFound", err)
+		}
+	}
+}
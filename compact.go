@@ -0,0 +1,138 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package insights
+
+import (
+	"context"
+	"unique"
+)
+
+// CompactDependencies is a resolved dependency graph stored in less memory
+// than Dependencies, for analyses that hold many graphs at once. Version
+// keys, relations, and requirements are interned, so each distinct value is
+// stored once however many nodes and graphs share it, and edges are pairs
+// of 32-bit node indexes. It needs Go 1.23 or later, which interns values
+// with package unique.
+type CompactDependencies struct {
+	// The nodes of the dependency graph. The first node is the root of the
+	// graph.
+	Nodes []CompactNode
+
+	// The edges of the dependency graph.
+	Edges []CompactEdge
+
+	// Any error associated with the dependency graph that is not specific
+	// to a node.
+	Error string
+}
+
+// CompactNode is a node of a CompactDependencies graph. See Node.
+type CompactNode struct {
+	Key      unique.Handle[VersionKey]
+	Relation unique.Handle[string]
+	Bundled  bool
+	Errors   []string
+}
+
+// CompactEdge is an edge of a CompactDependencies graph. See Edge.
+type CompactEdge struct {
+	FromNode, ToNode int32
+	Requirement      unique.Handle[string]
+}
+
+// Node returns the node n as a Node.
+func (n *CompactNode) Node() Node {
+	return Node{
+		VersionKey: n.Key.Value(),
+		Bundled:    n.Bundled,
+		Relation:   n.Relation.Value(),
+		Errors:     n.Errors,
+	}
+}
+
+// Edge returns the edge e as an Edge.
+func (e *CompactEdge) Edge() Edge {
+	return Edge{
+		FromNode:    int(e.FromNode),
+		ToNode:      int(e.ToNode),
+		Requirement: e.Requirement.Value(),
+	}
+}
+
+func compactNode(n *Node) CompactNode {
+	return CompactNode{
+		Key:      unique.Make(n.VersionKey),
+		Relation: unique.Make(n.Relation),
+		Bundled:  n.Bundled,
+		Errors:   n.Errors,
+	}
+}
+
+func compactEdge(e *Edge) CompactEdge {
+	return CompactEdge{
+		FromNode:    int32(e.FromNode),
+		ToNode:      int32(e.ToNode),
+		Requirement: unique.Make(e.Requirement),
+	}
+}
+
+// Compact returns d in compact form.
+func (d *Dependencies) Compact() *CompactDependencies {
+	g := &CompactDependencies{
+		Nodes: make([]CompactNode, len(d.Nodes)),
+		Edges: make([]CompactEdge, len(d.Edges)),
+		Error: d.Error,
+	}
+	for i := range d.Nodes {
+		g.Nodes[i] = compactNode(&d.Nodes[i])
+	}
+	for i := range d.Edges {
+		g.Edges[i] = compactEdge(&d.Edges[i])
+	}
+	return g
+}
+
+// Expand returns g as a Dependencies graph.
+func (g *CompactDependencies) Expand() *Dependencies {
+	d := &Dependencies{
+		Nodes: make([]Node, len(g.Nodes)),
+		Edges: make([]Edge, len(g.Edges)),
+		Error: g.Error,
+	}
+	for i := range g.Nodes {
+		d.Nodes[i] = g.Nodes[i].Node()
+	}
+	for i := range g.Edges {
+		d.Edges[i] = g.Edges[i].Edge()
+	}
+	return d
+}
+
+// GetCompactDependencies is like GetDependencies but returns the graph in
+// compact form. The graph is built as it is decoded, so the full graph is
+// never held in memory.
+func (c *Client) GetCompactDependencies(ctx context.Context, system, name, version string) (*CompactDependencies, *Response, error) {
+	g := new(CompactDependencies)
+	resp, err := c.StreamDependencies(ctx, system, name, version, &DependencyHandler{
+		Node: func(_ int, n *Node) error {
+			g.Nodes = append(g.Nodes, compactNode(n))
+			return nil
+		},
+		Edge: func(e *Edge) error {
+			g.Edges = append(g.Edges, compactEdge(e))
+			return nil
+		},
+		Error: func(msg string) error {
+			g.Error = msg
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	return g, resp, nil
+}
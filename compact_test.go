@@ -0,0 +1,63 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompact(t *testing.T) {
+	d := &Dependencies{
+		Nodes: []Node{
+			{VersionKey: VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}, Relation: "SELF"},
+			{VersionKey: VersionKey{System: "NPM", Name: "b", Version: "2.0.0"}, Relation: "DIRECT", Bundled: true},
+			{VersionKey: VersionKey{System: "NPM", Name: "c", Version: "1.0.0"}, Relation: "INDIRECT", Errors: []string{"oops"}},
+		},
+		Edges: []Edge{
+			{FromNode: 0, ToNode: 1, Requirement: "^2.0.0"},
+			{FromNode: 1, ToNode: 2, Requirement: "^1.0.0"},
+		},
+		Error: "partial graph",
+	}
+	g := d.Compact()
+	if diff := cmp.Diff(d, g.Expand()); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+
+	// Equal values are interned once, also across graphs.
+	other := (&Dependencies{Nodes: []Node{{VersionKey: VersionKey{System: "NPM", Name: "b", Version: "2.0.0"}, Relation: "SELF"}}}).Compact()
+	if g.Nodes[1].Key != other.Nodes[0].Key {
+		t.Error("equal version keys have different handles")
+	}
+	if g.Nodes[0].Relation != other.Nodes[0].Relation {
+		t.Error("equal relations have different handles")
+	}
+}
+
+func TestGetCompactDependencies(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, streamGraph)
+	})
+
+	g, _, err := client.GetCompactDependencies(context.Background(), "npm", "a", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetCompactDependencies failed: %v", err)
+	}
+	want, _, err := client.GetDependencies(context.Background(), "npm", "a", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if diff := cmp.Diff(want, g.Expand()); diff != "" {
+		t.Errorf("GetCompactDependencies mismatch (-want +got):\n%s", diff)
+	}
+}
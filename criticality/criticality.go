@@ -0,0 +1,129 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package criticality reads the OpenSSF criticality scores of open source
+// projects, which measure how critical a project is to the open source
+// ecosystem, from 0 (least) to 1 (most). The scores are published as CSV
+// files, produced by the criticality_score tool, that can be downloaded or
+// exported from the public BigQuery dataset.
+package criticality
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Names of the CSV columns of the repository URLs and scores, in the
+// current and in the original format of the criticality_score tool.
+var (
+	urlColumns   = []string{"repo.url", "url"}
+	scoreColumns = []string{"default_score", "criticality_score"}
+)
+
+// Scores maps projects, identified as deps.dev projects such as
+// "github.com/user/repo", to their criticality scores.
+type Scores map[string]float64
+
+// Score returns the criticality score of the project with the given
+// deps.dev identifier or repository URL, reporting whether it is known.
+func (s Scores) Score(project string) (float64, bool) {
+	v, ok := s[projectID(project)]
+	return v, ok
+}
+
+// projectID reduces a repository URL such as "https://github.com/User/Repo"
+// to a deps.dev project identifier of the form "github.com/user/repo".
+func projectID(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+len("://"):]
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	return strings.ToLower(s)
+}
+
+// Parse reads scores from CSV data with a header row naming a column of
+// repository URLs and one of scores. Rows with invalid scores are skipped.
+func Parse(r io.Reader) (Scores, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("criticality: empty CSV")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("criticality: %v", err)
+	}
+	urlCol, scoreCol := column(header, urlColumns), column(header, scoreColumns)
+	if urlCol < 0 || scoreCol < 0 {
+		return nil, fmt.Errorf("criticality: CSV has no %s and %s columns", urlColumns[0], scoreColumns[0])
+	}
+	scores := make(Scores)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return scores, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("criticality: %v", err)
+		}
+		if len(rec) <= max(urlCol, scoreCol) {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(rec[scoreCol]), 64)
+		if err != nil {
+			continue
+		}
+		scores[projectID(rec[urlCol])] = v
+	}
+}
+
+// column returns the index of the first of names found in header, or -1.
+func column(header, names []string) int {
+	for _, name := range names {
+		for i, h := range header {
+			if strings.TrimSpace(h) == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Load reads scores from src, a CSV file or an http or https URL of one.
+func Load(ctx context.Context, src string) (Scores, error) {
+	return LoadWithClient(ctx, http.DefaultClient, src)
+}
+
+// LoadWithClient is like Load, but downloads src, if a URL, with hc.
+func LoadWithClient(ctx context.Context, hc *http.Client, src string) (Scores, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return Parse(f)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", src, resp.Status)
+	}
+	return Parse(resp.Body)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package criticality
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name, csv string
+		want      Scores
+	}{
+		{
+			name: "v2",
+			csv: "repo.url,repo.language,default_score\n" +
+				"https://github.com/Kubernetes/kubernetes,Go,0.99\n" +
+				"https://gitlab.com/example/lib.git,C,0.41\n" +
+				"https://github.com/example/bad,C,n/a\n",
+			want: Scores{"github.com/kubernetes/kubernetes": 0.99, "gitlab.com/example/lib": 0.41},
+		},
+		{
+			name: "v1",
+			csv:  "name,url,language,criticality_score\nnode,https://github.com/nodejs/node,JavaScript,0.9\n",
+			want: Scores{"github.com/nodejs/node": 0.9},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse(strings.NewReader(tt.csv))
+		if err != nil {
+			t.Errorf("%s: Parse failed: %v", tt.name, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("%s: Parse mismatch (-want +got):\n%s", tt.name, diff)
+		}
+	}
+
+	if _, err := Parse(strings.NewReader("name,stars\nfoo,3\n")); err == nil {
+		t.Error("Parse of a CSV without scores succeeded")
+	}
+}
+
+func TestLoadURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "repo.url,default_score\nhttps://github.com/example/foo,0.5\n")
+	}))
+	defer server.Close()
+
+	s, err := Load(context.Background(), server.URL+"/scores.csv")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v, ok := s.Score("github.com/Example/foo"); !ok || v != 0.5 {
+		t.Errorf("Score = %v, %t; want 0.5", v, ok)
+	}
+	if _, ok := s.Score("github.com/example/bar"); ok {
+		t.Error("Score of an unknown project is known")
+	}
+}
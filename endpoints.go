@@ -0,0 +1,214 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Endpoint identifies an endpoint of the deps.dev API, which may be served
+// by several versions of the API.
+type Endpoint string
+
+// The endpoints of the deps.dev API used by Client.
+const (
+	EndpointGetPackage                Endpoint = "GetPackage"
+	EndpointGetVersion                Endpoint = "GetVersion"
+	EndpointGetRequirements           Endpoint = "GetRequirements"
+	EndpointGetDependencies           Endpoint = "GetDependencies"
+	EndpointGetDependents             Endpoint = "GetDependents"
+	EndpointGetSimilarlyNamedPackages Endpoint = "GetSimilarlyNamedPackages"
+	EndpointGetProject                Endpoint = "GetProject"
+	EndpointGetProjectPackageVersions Endpoint = "GetProjectPackageVersions"
+	EndpointGetAdvisory               Endpoint = "GetAdvisory"
+	EndpointQuery                     Endpoint = "Query"
+	EndpointPurlLookup                Endpoint = "PurlLookup"
+	EndpointPurlLookupBatch           Endpoint = "PurlLookupBatch"
+)
+
+// Versions of the deps.dev API.
+const (
+	APIVersionV3      = "v3"
+	APIVersionV3Alpha = "v3alpha"
+)
+
+// defaultEndpointVersions are the versions of the API requested for the
+// endpoints that are not served by v3, the version BaseURL points at.
+var defaultEndpointVersions = map[Endpoint][]string{
+	EndpointGetDependents:             {APIVersionV3Alpha},
+	EndpointGetSimilarlyNamedPackages: {APIVersionV3Alpha},
+	EndpointPurlLookup:                {APIVersionV3Alpha},
+	EndpointPurlLookupBatch:           {APIVersionV3Alpha},
+}
+
+// endpointVersions returns the versions of the API to request endpoint e
+// from, in order of preference.
+func (c *Client) endpointVersions(e Endpoint) []string {
+	if vs := c.EndpointVersions[e]; len(vs) > 0 {
+		return vs
+	}
+	if vs := defaultEndpointVersions[e]; len(vs) > 0 {
+		return vs
+	}
+	return []string{APIVersionV3}
+}
+
+// versionPath returns path, relative to the version ver of the API, as a
+// path relative to BaseURL. The default version, v3, is the one BaseURL
+// points at. Other versions are its siblings if the last element of the
+// path of BaseURL is a version of the API, as with deps.dev; otherwise,
+// as with a proxy or mock server under some other prefix, every version
+// is served at BaseURL.
+func (c *Client) versionPath(ver, p string) string {
+	p = strings.TrimPrefix(p, "/")
+	base := path.Base(c.BaseURL.Path)
+	if ver == APIVersionV3 || ver == base || !isAPIVersion(base) {
+		return p
+	}
+	return "../" + ver + "/" + p
+}
+
+// isAPIVersion reports whether s names a version of the API, such as "v3"
+// or "v3alpha".
+func isAPIVersion(s string) bool {
+	return len(s) > 1 && s[0] == 'v' && '0' <= s[1] && s[1] <= '9'
+}
+
+// getEndpoint sends a GET request for endpoint e at path, relative to the
+// version of the API serving it, and decodes the JSON response into v. The
+// returned Response is nil if no response was received. The call is
+// traced with attrs.
+func (c *Client) getEndpoint(ctx context.Context, e Endpoint, path string, v any, attrs ...attribute.KeyValue) (*Response, error) {
+	return c.doEndpoint(ctx, e, path, nil, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(v)
+	}, attrs...)
+}
+
+// postEndpoint is like getEndpoint, but sends body, encoded as JSON, in a
+// POST request.
+func (c *Client) postEndpoint(ctx context.Context, e Endpoint, path string, body, v any, attrs ...attribute.KeyValue) (*Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return c.doEndpoint(ctx, e, path, data, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(v)
+	}, attrs...)
+}
+
+// doEndpoint is like do, but requests endpoint e at path, relative to the
+// version of the API serving it, with a POST request sending body if body
+// is not nil. If e may be served by several versions, they are tried in
+// order while they answer 501 Not Implemented, and the first to answer
+// successfully is remembered for later requests.
+//
+// A 404 Not Found with the JSON status deps.dev answers for what does not
+// exist is not taken for a missing endpoint, except the first time it is
+// answered for e: the other versions are then tried once, in case the
+// version answering it does not serve e. Otherwise it is returned as the
+// answer of the version that gave it. The call is traced with attrs.
+func (c *Client) doEndpoint(ctx context.Context, e Endpoint, path string, body []byte, decode func(r io.Reader) error, attrs ...attribute.KeyValue) (resp *Response, err error) {
+	ctx, span := c.startSpan(ctx, e, attrs)
+	start := time.Now()
+	var ver string
+	defer func() {
+		endSpan(span, ver, resp, err)
+		c.observe(e, ver, resp, err, time.Since(start))
+	}()
+
+	versions := c.endpointVersions(e)
+	negotiate := len(versions) > 1
+	if v, ok := c.negotiated.Load(e); ok && negotiate {
+		versions, negotiate = []string{v.(string)}, false
+	}
+	// The first 404 Not Found from deps.dev, with the version that
+	// answered it.
+	var (
+		notFound     error
+		notFoundResp *Response
+		notFoundVer  string
+	)
+versions:
+	for _, ver = range versions {
+		if body != nil {
+			resp, err = c.post(ctx, c.versionPath(ver, path), body, decode)
+		} else {
+			resp, err = c.do(ctx, c.versionPath(ver, path), decode)
+		}
+		if err == nil {
+			if negotiate {
+				c.negotiated.Store(e, ver)
+			}
+			return resp, nil
+		}
+		if !negotiate {
+			break
+		}
+		switch {
+		case notServed(err):
+		case errors.Is(err, ErrNotFound):
+			if notFound != nil {
+				break
+			}
+			notFound, notFoundResp, notFoundVer = err, resp, ver
+			if _, probed := c.probed.LoadOrStore(e, true); probed {
+				break versions
+			}
+		default:
+			break versions
+		}
+		c.log(ctx, slog.LevelDebug, "endpoint not served by API version", slog.String("endpoint", string(e)), slog.String("version", ver))
+	}
+	if notFound != nil && (notServed(err) || errors.Is(err, ErrNotFound)) {
+		ver = notFoundVer
+		return notFoundResp, notFound
+	}
+	return resp, err
+}
+
+// notServed reports whether err is the response of a version of the API
+// that does not serve an endpoint: a 501 Not Implemented, or a 404 Not
+// Found whose body is not the JSON status deps.dev answers for what does
+// not exist, such as the page of a server without the route.
+func notServed(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotImplemented:
+		return true
+	case http.StatusNotFound:
+		var status struct {
+			Code    *int   `json:"code"`
+			Message string `json:"message"`
+		}
+		return json.Unmarshal([]byte(apiErr.Body), &status) != nil || status.Code == nil
+	}
+	return false
+}
+
+// APIVersion returns the version of the API that requests for endpoint e
+// are sent to: of the versions it may be served by, the first to answer
+// successfully or, until one has, the one preferred.
+func (c *Client) APIVersion(e Endpoint) string {
+	versions := c.endpointVersions(e)
+	if len(versions) > 1 {
+		if ver, ok := c.negotiated.Load(e); ok {
+			return ver.(string)
+		}
+	}
+	return versions[0]
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestEndpointVersions(t *testing.T) {
+	var requests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/v3/systems/go/packages/foo", "/v3alpha/systems/go/packages/foo/versions/v1.0.0:dependents":
+			fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+		case "/v4/systems/go/packages/foo/versions/v1.0.0":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/v3/")
+	client.EndpointVersions = map[Endpoint][]string{
+		EndpointGetPackage: {"v4", APIVersionV3},
+		EndpointGetVersion: {"v4", APIVersionV3},
+	}
+	ctx := context.Background()
+
+	if got := client.APIVersion(EndpointGetPackage); got != "v4" {
+		t.Errorf("APIVersion(GetPackage) = %q before negotiation; want v4", got)
+	}
+	for range 2 {
+		if _, _, err := client.GetPackage(ctx, "go", "foo"); err != nil {
+			t.Fatalf("GetPackage failed: %v", err)
+		}
+	}
+	if got := client.APIVersion(EndpointGetPackage); got != APIVersionV3 {
+		t.Errorf("APIVersion(GetPackage) = %q; want %q", got, APIVersionV3)
+	}
+	// v4 is not asked again once v3 answered.
+	want := []string{"/v4/systems/go/packages/foo", "/v3/systems/go/packages/foo", "/v3/systems/go/packages/foo"}
+	if fmt.Sprint(requests) != fmt.Sprint(want) {
+		t.Errorf("requests = %q; want %q", requests, want)
+	}
+
+	// Other errors are not taken for a missing endpoint.
+	requests = nil
+	_, _, err := client.GetVersion(ctx, "go", "foo", "v1.0.0")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("GetVersion returned error %v; want 500", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("requests = %q; want only v4", requests)
+	}
+
+	// Endpoints only in v3alpha are requested from it by default.
+	if _, _, err := client.GetDependents(ctx, "go", "foo", "v1.0.0"); err != nil {
+		t.Errorf("GetDependents failed: %v", err)
+	}
+	if got := client.APIVersion(EndpointGetDependents); got != APIVersionV3Alpha {
+		t.Errorf("APIVersion(GetDependents) = %q; want %q", got, APIVersionV3Alpha)
+	}
+}
+
+func TestEndpointVersionsNotFound(t *testing.T) {
+	var requests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/v4/advisories/GHSA-1":
+			http.Error(w, "not implemented", http.StatusNotImplemented)
+		case "/v3/advisories/GHSA-1":
+			fmt.Fprint(w, `{"advisoryKey":{"id":"GHSA-1"}}`)
+		default:
+			// What deps.dev answers for what does not exist.
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":5,"message":"package not found","details":[]}`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/v3/")
+	client.EndpointVersions = map[Endpoint][]string{
+		EndpointGetPackage:  {"v4", APIVersionV3},
+		EndpointGetAdvisory: {"v4", APIVersionV3},
+	}
+	ctx := context.Background()
+
+	// The versions are probed on the first not found only, and the error
+	// is the one of the preferred version.
+	for i, want := range [][]string{
+		{"/v4/systems/go/packages/missing", "/v3/systems/go/packages/missing"},
+		{"/v4/systems/go/packages/missing"},
+	} {
+		requests = nil
+		_, _, err := client.GetPackage(ctx, "go", "missing")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.URL != server.URL+"/v4/systems/go/packages/missing" {
+			t.Errorf("GetPackage %d returned error %v; want the 404 of v4", i, err)
+		}
+		if fmt.Sprint(requests) != fmt.Sprint(want) {
+			t.Errorf("GetPackage %d requests = %q; want %q", i, requests, want)
+		}
+		if got := client.APIVersion(EndpointGetPackage); got != "v4" {
+			t.Errorf("APIVersion(GetPackage) = %q after a not found; want v4", got)
+		}
+	}
+
+	// 501 Not Implemented is always taken for a missing endpoint.
+	requests = nil
+	if _, _, err := client.GetAdvisory(ctx, "GHSA-1"); err != nil {
+		t.Fatalf("GetAdvisory failed: %v", err)
+	}
+	if got := client.APIVersion(EndpointGetAdvisory); got != APIVersionV3 {
+		t.Errorf("APIVersion(GetAdvisory) = %q; want %q", got, APIVersionV3)
+	}
+}
+
+func TestBaseURLPrefix(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		fmt.Fprint(w, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/depsdev/")
+	ctx := context.Background()
+	if _, _, err := client.GetPackage(ctx, "npm", "react"); err != nil {
+		t.Fatalf("GetPackage failed: %v", err)
+	}
+	if _, _, err := client.GetDependents(ctx, "npm", "react", "18.2.0"); err != nil {
+		t.Fatalf("GetDependents failed: %v", err)
+	}
+	// Versions are not siblings of a prefix that is not a version.
+	want := []string{"/depsdev/systems/npm/packages/react", "/depsdev/systems/npm/packages/react/versions/18.2.0:dependents"}
+	if fmt.Sprint(requests) != fmt.Sprint(want) {
+		t.Errorf("requests = %q; want %q", requests, want)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+)
+
+// Fallback supplies information about packages and versions that deps.dev
+// lacks, typically from the registries of the package management systems.
+// The registry package has an implementation for npm, PyPI, crates.io, and
+// Maven Central.
+type Fallback interface {
+	// Package returns the package with the given name.
+	Package(ctx context.Context, system, name string) (*Package, error)
+
+	// Version returns a version of the package with the given name.
+	Version(ctx context.Context, system, name, version string) (*Version, error)
+}
+
+// notFound reports whether err is a not found response from the API.
+func notFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// fallbackResponse returns the Response of a request answered by the
+// client's Fallback instead of the API, which had answered with resp.
+func fallbackResponse(resp *Response) *Response {
+	fr := &Response{FromFallback: true}
+	if resp != nil {
+		fr.Response = resp.Response
+	}
+	return fr
+}
+
+// fallbackPackage returns the package from the client's Fallback, if it
+// has one and the API does not know the package, as reported by err.
+// Otherwise, or if the fallback fails too, it returns err.
+func (c *Client) fallbackPackage(ctx context.Context, system, name string, resp *Response, err error) (*Package, *Response, error) {
+	if c.Fallback == nil || !notFound(err) {
+		return nil, resp, err
+	}
+	p, ferr := c.Fallback.Package(ctx, system, name)
+	if ferr != nil {
+		return nil, resp, err
+	}
+	return p, fallbackResponse(resp), nil
+}
+
+// fallbackVersion returns the version from the client's Fallback, if it
+// has one and the API does not know the version, as reported by err.
+// Otherwise, or if the fallback fails too, it returns err.
+func (c *Client) fallbackVersion(ctx context.Context, system, name, version string, resp *Response, err error) (*Version, *Response, error) {
+	if c.Fallback == nil || !notFound(err) {
+		return nil, resp, err
+	}
+	v, ferr := c.Fallback.Version(ctx, system, name, version)
+	if ferr != nil {
+		return nil, resp, err
+	}
+	return v, fallbackResponse(resp), nil
+}
+
+// completeVersion fills in the publication time and licenses of v, when
+// deps.dev does not have them, from the client's Fallback. Failures of the
+// fallback are ignored, as v is useful as it is.
+func (c *Client) completeVersion(ctx context.Context, v *Version) {
+	if c.Fallback == nil || c.Offline || (v.PublishedAt != "" && len(v.Licenses) > 0) {
+		return
+	}
+	k := v.VersionKey
+	fv, err := c.Fallback.Version(ctx, k.System, k.Name, k.Version)
+	if err != nil {
+		return
+	}
+	if v.PublishedAt == "" {
+		v.PublishedAt = fv.PublishedAt
+	}
+	if len(v.Licenses) == 0 {
+		v.Licenses = fv.Licenses
+	}
+	if len(v.Links) == 0 {
+		v.Links = fv.Links
+	}
+	v.IsDeprecated = v.IsDeprecated || fv.IsDeprecated
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeFallback knows of the package foo and its version 1.0.0.
+type fakeFallback struct {
+	calls int
+}
+
+func (f *fakeFallback) Package(ctx context.Context, system, name string) (*Package, error) {
+	f.calls++
+	if name != "foo" {
+		return nil, errors.New("no such package")
+	}
+	return &Package{
+		PackageKey: PackageKey{System: system, Name: name},
+		Versions:   []Version{{VersionKey: VersionKey{System: system, Name: name, Version: "1.0.0"}, IsDefault: true}},
+	}, nil
+}
+
+func (f *fakeFallback) Version(ctx context.Context, system, name, version string) (*Version, error) {
+	f.calls++
+	if name != "foo" || version != "1.0.0" {
+		return nil, errors.New("no such version")
+	}
+	return &Version{
+		VersionKey:  VersionKey{System: system, Name: name, Version: version},
+		PublishedAt: "2026-01-02T03:04:05Z",
+		Licenses:    []string{"MIT"},
+		Links:       []Link{{Label: "SOURCE_REPO", URL: "https://github.com/example/foo"}},
+	}, nil
+}
+
+func TestFallbackNotFound(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	f := new(fakeFallback)
+	client.Fallback = f
+	ctx := context.Background()
+
+	p, resp, err := client.GetPackage(ctx, "NPM", "foo")
+	if err != nil {
+		t.Fatalf("GetPackage failed: %v", err)
+	}
+	if !resp.FromFallback || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetPackage returned response %d, from fallback %t; want 404 from fallback", resp.StatusCode, resp.FromFallback)
+	}
+	if p.PackageKey.Name != "foo" || len(p.Versions) != 1 {
+		t.Errorf("GetPackage returned %+v", p)
+	}
+
+	v, resp, err := client.GetVersion(ctx, "NPM", "foo", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if !resp.FromFallback || v.PublishedAt == "" {
+		t.Errorf("GetVersion returned %+v, from fallback %t", v, resp.FromFallback)
+	}
+
+	// The API error is kept when the fallback fails too.
+	_, resp, err = client.GetPackage(ctx, "NPM", "bar")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetPackage of an unknown package returned %v; want 404 APIError", err)
+	}
+	if resp == nil || resp.FromFallback {
+		t.Errorf("GetPackage of an unknown package returned response %+v", resp)
+	}
+}
+
+func TestFallbackNotUsedOnOtherErrors(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oops", http.StatusInternalServerError)
+	})
+	f := new(fakeFallback)
+	client.Fallback = f
+
+	if _, _, err := client.GetPackage(context.Background(), "NPM", "foo"); err == nil {
+		t.Error("GetPackage succeeded; want the API error")
+	}
+	if f.calls != 0 {
+		t.Errorf("fallback called %d times; want 0", f.calls)
+	}
+}
+
+func TestFallbackCompletesVersion(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/NPM/packages/foo/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"foo","version":"1.0.0"},"licenses":["Apache-2.0"]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/foo/versions/2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"foo","version":"2.0.0"},"publishedAt":"2026-02-01T00:00:00Z","licenses":["MIT"]}`)
+	})
+	f := new(fakeFallback)
+	client.Fallback = f
+	ctx := context.Background()
+
+	got, resp, err := client.GetVersion(ctx, "NPM", "foo", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	want := &Version{
+		VersionKey:  VersionKey{System: "NPM", Name: "foo", Version: "1.0.0"},
+		PublishedAt: "2026-01-02T03:04:05Z",
+		Licenses:    []string{"Apache-2.0"},
+		Links:       []Link{{Label: "SOURCE_REPO", URL: "https://github.com/example/foo"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetVersion mismatch (-want +got):\n%s", diff)
+	}
+	if resp.FromFallback {
+		t.Error("GetVersion response is from fallback; want from the API")
+	}
+
+	// Complete versions are not looked up.
+	f.calls = 0
+	if _, _, err := client.GetVersion(ctx, "NPM", "foo", "2.0.0"); err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if f.calls != 0 {
+		t.Errorf("fallback called %d times for a complete version; want 0", f.calls)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package feed publishes the new versions of a set of packages, and the
+// advisories affecting them, as an Atom feed (RFC 4287), so that they can
+// be followed in a feed reader.
+package feed
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/franoliveto/insights"
+)
+
+// Entry is an entry of a feed: a new version or advisory.
+type Entry struct {
+	Kind insights.EventKind
+
+	// The version published, or the version the advisory affects.
+	VersionKey insights.VersionKey
+
+	// The advisory. Only set for NewAdvisory entries.
+	AdvisoryKey insights.AdvisoryKey
+
+	// A unique and permanent identifier of the entry, and a link to a
+	// page about it.
+	ID, Link string
+
+	Title   string
+	Summary string
+
+	// When the version was published or the advisory was first seen.
+	Updated time.Time
+}
+
+// Options specifies optional parameters to Entries.
+type Options struct {
+	// Only versions published and advisories first seen in the period
+	// before now are included. If zero, the period is 30 days.
+	Since time.Duration
+
+	// The times entries were first seen, by ID. deps.dev does not report
+	// when advisories were published, so an advisory is dated the first
+	// time it is seen, which is recorded here. If nil, advisories are
+	// dated when the entries are built.
+	Seen map[string]time.Time
+}
+
+// defaultSince is the default period of the entries.
+const defaultSince = 30 * 24 * time.Hour
+
+// Entries returns the entries of the feed of the given packages, newest
+// first: their versions published, and the advisories affecting their
+// default versions first seen, in the period of opts. opts may be nil.
+//
+// A failure to get one package does not prevent the others from being
+// included; the returned error is then a *insights.BatchError with the
+// errors of all failed packages.
+func Entries(ctx context.Context, c *insights.Client, packages []insights.PackageKey, opts *Options) ([]Entry, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+	now := time.Now().UTC()
+	start := now.Add(-cmp.Or(opts.Since, defaultSince))
+	var entries []Entry
+	var batch insights.Batch
+	for _, k := range packages {
+		pe, err := packageEntries(ctx, c, k, now, opts.Seen)
+		if err != nil {
+			batch.Fail(k, err)
+			continue
+		}
+		for _, e := range pe {
+			if !e.Updated.Before(start) {
+				entries = append(entries, e)
+			}
+		}
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Or(b.Updated.Compare(a.Updated), cmp.Compare(a.ID, b.ID))
+	})
+	return entries, batch.Err()
+}
+
+// packageEntries returns all the entries of the package k.
+func packageEntries(ctx context.Context, c *insights.Client, k insights.PackageKey, now time.Time, seen map[string]time.Time) ([]Entry, error) {
+	p, _, err := c.GetPackage(ctx, k.System, k.Name)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	var def *insights.VersionKey
+	for _, v := range p.Versions {
+		vk := v.VersionKey
+		if v.IsDefault {
+			def = &vk
+		}
+		published, err := time.Parse(time.RFC3339, v.PublishedAt)
+		if err != nil {
+			// Versions of unknown age cannot be placed in the feed.
+			continue
+		}
+		link := versionURL(vk)
+		entries = append(entries, Entry{
+			Kind:       insights.NewVersion,
+			VersionKey: vk,
+			ID:         link,
+			Link:       link,
+			Title:      fmt.Sprintf("%s %s released", vk.Name, vk.Version),
+			Summary:    fmt.Sprintf("Version %s of the %s package %s was published.", vk.Version, strings.ToLower(vk.System), vk.Name),
+			Updated:    published.UTC(),
+		})
+	}
+	if def == nil {
+		return entries, nil
+	}
+
+	v, _, err := c.GetVersion(ctx, def.System, def.Name, def.Version)
+	if err != nil {
+		return nil, err
+	}
+	for _, ak := range v.AdvisoryKeys {
+		id := versionURL(*def) + "#" + ak.ID
+		first, ok := seen[id]
+		if !ok {
+			first = now
+			if seen != nil {
+				seen[id] = now
+			}
+		}
+		e := Entry{
+			Kind:        insights.NewAdvisory,
+			VersionKey:  *def,
+			AdvisoryKey: ak,
+			ID:          id,
+			Link:        "https://osv.dev/vulnerability/" + url.PathEscape(ak.ID),
+			Title:       fmt.Sprintf("%s affects %s %s", ak.ID, def.Name, def.Version),
+			Updated:     first,
+		}
+		if a, _, err := c.GetAdvisory(ctx, ak.ID); err == nil {
+			e.Title += ": " + a.Title
+			e.Summary = fmt.Sprintf("%s (CVSS v3 %.1f)", a.Title, a.CVSS3Score)
+			if a.URL != "" {
+				e.Link = a.URL
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// versionURL returns the URL of the page of a package version on deps.dev.
+func versionURL(k insights.VersionKey) string {
+	return "https://deps.dev/" + strings.ToLower(k.System) + "/" + url.PathEscape(k.Name) + "/" + url.PathEscape(k.Version)
+}
+
+// Feed is an Atom feed.
+type Feed struct {
+	// The title of the feed, and a unique and permanent identifier of it,
+	// such as the URL it is published at.
+	Title, ID string
+
+	// The entries of the feed, as returned by Entries.
+	Entries []Entry
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Link    atomLink `xml:"link"`
+	Updated string   `xml:"updated"`
+	Summary string   `xml:"summary,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// WriteAtom writes the feed to w as an Atom document. The feed is as
+// recent as its newest entry, so that the document only changes with its
+// entries.
+func (f *Feed) WriteAtom(w io.Writer) error {
+	af := atomFeed{
+		Title:  f.Title,
+		ID:     f.ID,
+		Author: atomAuthor{Name: "insights"},
+	}
+	updated := time.Unix(0, 0).UTC()
+	for _, e := range f.Entries {
+		if e.Updated.After(updated) {
+			updated = e.Updated
+		}
+		af.Entries = append(af.Entries, atomEntry{
+			Title:   e.Title,
+			ID:      e.ID,
+			Link:    atomLink{Href: e.Link},
+			Updated: e.Updated.Format(time.RFC3339),
+			Summary: e.Summary,
+		})
+	}
+	af.Updated = updated.Format(time.RFC3339)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(af); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
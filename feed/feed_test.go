@@ -0,0 +1,123 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setup returns a client talking to a test server whose API handlers are
+// registered on mux.
+func setup(t *testing.T) (*insights.Client, *http.ServeMux) {
+	t.Helper()
+	mux := http.NewServeMux()
+	apiMux := http.NewServeMux()
+	apiMux.Handle("/v3/", http.StripPrefix("/v3", mux))
+	server := httptest.NewServer(apiMux)
+	t.Cleanup(server.Close)
+
+	client := insights.NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/v3/")
+	return client, mux
+}
+
+func TestEntries(t *testing.T) {
+	client, mux := setup(t)
+	recent := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	mux.HandleFunc("/systems/npm/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"packageKey":{"system":"NPM","name":"foo"},"versions":[
+			{"versionKey":{"system":"NPM","name":"foo","version":"1.0.0"},"publishedAt":"2020-01-01T00:00:00Z"},
+			{"versionKey":{"system":"NPM","name":"foo","version":"1.1.0"},"publishedAt":%q,"isDefault":true}]}`, recent.Format(time.RFC3339))
+	})
+	mux.HandleFunc("/systems/NPM/packages/foo/versions/1.1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"foo","version":"1.1.0"},"advisoryKeys":[{"id":"GHSA-new"},{"id":"GHSA-old"}]}`)
+	})
+	mux.HandleFunc("/advisories/GHSA-new", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"advisoryKey":{"id":"GHSA-new"},"url":"https://example.com/GHSA-new","title":"Bad thing","cvss3Score":7.5}`)
+	})
+	mux.HandleFunc("/advisories/GHSA-old", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/systems/npm/packages/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	oldID := "https://deps.dev/npm/foo/1.1.0#GHSA-old"
+	seen := map[string]time.Time{oldID: time.Now().Add(-60 * 24 * time.Hour)}
+	packages := []insights.PackageKey{{System: "npm", Name: "foo"}, {System: "npm", Name: "missing"}}
+	entries, err := Entries(context.Background(), client, packages, &Options{Seen: seen})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Entries returned error %v; want an error about the missing package", err)
+	}
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%s %s %s", e.Kind, e.ID, e.Link))
+	}
+	// The advisory first seen now comes first, followed by the version
+	// published two days ago. The old version and advisory are left out.
+	want := []string{
+		"new advisory https://deps.dev/npm/foo/1.1.0#GHSA-new https://example.com/GHSA-new",
+		"new version https://deps.dev/npm/foo/1.1.0 https://deps.dev/npm/foo/1.1.0",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Entries mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := seen["https://deps.dev/npm/foo/1.1.0#GHSA-new"]; !ok {
+		t.Errorf("Entries did not record when the new advisory was first seen")
+	}
+	if len(entries) == 2 && !entries[1].Updated.Equal(recent) {
+		t.Errorf("version entry updated at %v; want %v", entries[1].Updated, recent)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	updated := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	f := &Feed{
+		Title: "Watched packages",
+		ID:    "urn:example:feed",
+		Entries: []Entry{
+			{ID: "urn:a", Link: "https://example.com/a", Title: "a <1.0>", Updated: updated, Summary: "A"},
+			{ID: "urn:b", Link: "https://example.com/b", Title: "b", Updated: updated.Add(-time.Hour)},
+		},
+	}
+	var buf bytes.Buffer
+	if err := f.WriteAtom(&buf); err != nil {
+		t.Fatalf("WriteAtom failed: %v", err)
+	}
+
+	var got struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			Title string `xml:"title"`
+			Link  struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("WriteAtom wrote invalid XML: %v\n%s", err, buf.Bytes())
+	}
+	if got.Title != f.Title || got.Updated != "2026-03-02T10:00:00Z" {
+		t.Errorf("feed title, updated = %q, %q; want %q, %q", got.Title, got.Updated, f.Title, "2026-03-02T10:00:00Z")
+	}
+	if len(got.Entries) != 2 || got.Entries[0].Title != "a <1.0>" || got.Entries[1].Link.Href != "https://example.com/b" {
+		t.Errorf("WriteAtom wrote entries %+v", got.Entries)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import "slices"
+
+// adjacency returns the nodes each node of the graph has edges to, by
+// index. Edges with out of range nodes are ignored.
+func (d *Dependencies) adjacency() [][]int {
+	adj := make([][]int, len(d.Nodes))
+	for _, e := range d.Edges {
+		if e.FromNode < 0 || e.FromNode >= len(d.Nodes) || e.ToNode < 0 || e.ToNode >= len(d.Nodes) {
+			continue
+		}
+		adj[e.FromNode] = append(adj[e.FromNode], e.ToNode)
+	}
+	return adj
+}
+
+// Depths returns the depth of each node of the graph, by index: the length
+// of the shortest path from the root to it, or -1 if the node cannot be
+// reached from the root. The root is at depth 0 and direct dependencies at
+// depth 1.
+func (d *Dependencies) Depths() []int {
+	depth := make([]int, len(d.Nodes))
+	for i := range depth {
+		depth[i] = -1
+	}
+	if len(d.Nodes) == 0 {
+		return depth
+	}
+	depth[0] = 0
+	adj := d.adjacency()
+	for queue := []int{0}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		for _, m := range adj[n] {
+			if depth[m] == -1 {
+				depth[m] = depth[n] + 1
+				queue = append(queue, m)
+			}
+		}
+	}
+	return depth
+}
+
+// Why returns the dependency chains explaining why the package with the given
+// name is part of the graph. There is one chain for each node of that package,
+// the shortest path from the root of the graph to that node. Each chain starts
+// with the root node and ends with the node for the package. Nodes that are
+// not reachable from the root are ignored.
+func (d *Dependencies) Why(name string) [][]Node {
+	if len(d.Nodes) == 0 {
+		return nil
+	}
+
+	// Breadth-first search from the root, recording for each node the node
+	// it was first reached from.
+	adj := d.adjacency()
+	parent := make([]int, len(d.Nodes))
+	for i := range parent {
+		parent[i] = -1
+	}
+	parent[0] = 0
+	queue := []int{0}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, m := range adj[n] {
+			if parent[m] == -1 {
+				parent[m] = n
+				queue = append(queue, m)
+			}
+		}
+	}
+
+	var chains [][]Node
+	for i, n := range d.Nodes {
+		if n.VersionKey.Name != name || parent[i] == -1 {
+			continue
+		}
+		var chain []Node
+		for j := i; ; j = parent[j] {
+			chain = append(chain, d.Nodes[j])
+			if j == 0 {
+				break
+			}
+		}
+		// Reverse so that the chain starts at the root.
+		for l, r := 0, len(chain)-1; l < r; l, r = l+1, r-1 {
+			chain[l], chain[r] = chain[r], chain[l]
+		}
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// PruneOptions specifies which nodes Prune drops from a graph.
+type PruneOptions struct {
+	// The maximum depth of the nodes kept, the length of the shortest path
+	// from the root to them. Direct dependencies are at depth 1. If zero,
+	// nodes at any depth are kept.
+	MaxDepth int
+
+	// The relations of the nodes kept, such as DIRECT or INDIRECT. If
+	// empty, nodes of any relation are kept.
+	Relations []string
+
+	// Drop bundled nodes.
+	ExcludeBundled bool
+}
+
+// Prune returns a copy of the graph without the nodes opts drops, nor the
+// edges from or to them, so that large graphs can be viewed in part. The
+// root is always kept. Nodes beyond MaxDepth, or unreachable from the root
+// if MaxDepth is set, are dropped; nodes are otherwise kept even if only
+// reachable through dropped ones, so that, for example, the indirect
+// dependencies of a graph can be viewed alone. The indices of the edges
+// refer to the nodes of the copy.
+func (d *Dependencies) Prune(opts *PruneOptions) *Dependencies {
+	out := &Dependencies{Error: d.Error}
+	if len(d.Nodes) == 0 {
+		return out
+	}
+	if opts == nil {
+		opts = new(PruneOptions)
+	}
+
+	depth := d.Depths()
+	// index maps the indices of the nodes kept to those in the copy.
+	index := make([]int, len(d.Nodes))
+	for i, n := range d.Nodes {
+		index[i] = -1
+		keep := i == 0 ||
+			(opts.MaxDepth <= 0 || depth[i] >= 0 && depth[i] <= opts.MaxDepth) &&
+				(len(opts.Relations) == 0 || slices.Contains(opts.Relations, n.Relation)) &&
+				!(opts.ExcludeBundled && n.Bundled)
+		if keep {
+			index[i] = len(out.Nodes)
+			out.Nodes = append(out.Nodes, n)
+		}
+	}
+	for _, e := range d.Edges {
+		if e.FromNode < 0 || e.FromNode >= len(d.Nodes) || e.ToNode < 0 || e.ToNode >= len(d.Nodes) {
+			continue
+		}
+		if from, to := index[e.FromNode], index[e.ToNode]; from >= 0 && to >= 0 {
+			out.Edges = append(out.Edges, Edge{FromNode: from, ToNode: to, Requirement: e.Requirement})
+		}
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func node(name, version string) Node {
+	return Node{VersionKey: VersionKey{System: "NPM", Name: name, Version: version}}
+}
+
+func TestWhy(t *testing.T) {
+	// a -> b -> c -> d
+	// a -> d
+	// a -> e -> c
+	// f is not reachable.
+	d := &Dependencies{
+		Nodes: []Node{
+			node("a", "1.0.0"),
+			node("b", "1.0.0"),
+			node("c", "1.0.0"),
+			node("d", "1.0.0"),
+			node("e", "1.0.0"),
+			node("c", "2.0.0"),
+			node("f", "1.0.0"),
+		},
+		Edges: []Edge{
+			{FromNode: 0, ToNode: 1},
+			{FromNode: 1, ToNode: 2},
+			{FromNode: 2, ToNode: 3},
+			{FromNode: 0, ToNode: 3},
+			{FromNode: 0, ToNode: 4},
+			{FromNode: 4, ToNode: 5},
+			{FromNode: 6, ToNode: 2},
+		},
+	}
+
+	testCases := []struct {
+		name string
+		want [][]Node
+	}{
+		{"a", [][]Node{{d.Nodes[0]}}},
+		{"d", [][]Node{{d.Nodes[0], d.Nodes[3]}}},
+		{"c", [][]Node{
+			{d.Nodes[0], d.Nodes[1], d.Nodes[2]},
+			{d.Nodes[0], d.Nodes[4], d.Nodes[5]},
+		}},
+		{"f", nil},
+		{"z", nil},
+	}
+
+	for _, c := range testCases {
+		got := d.Why(c.name)
+		if !cmp.Equal(got, c.want) {
+			t.Errorf("Why(%q) returned %+v; want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestDepths(t *testing.T) {
+	// a -> b -> c
+	// a -> c
+	// d -> b, not reachable.
+	d := &Dependencies{
+		Nodes: []Node{node("a", "1.0.0"), node("b", "1.0.0"), node("c", "1.0.0"), node("d", "1.0.0")},
+		Edges: []Edge{
+			{FromNode: 0, ToNode: 1},
+			{FromNode: 1, ToNode: 2},
+			{FromNode: 0, ToNode: 2},
+			{FromNode: 3, ToNode: 1},
+			{FromNode: 0, ToNode: 9},
+		},
+	}
+	if diff := cmp.Diff([]int{0, 1, 1, -1}, d.Depths()); diff != "" {
+		t.Errorf("Depths mismatch (-want +got):\n%s", diff)
+	}
+	if got := new(Dependencies).Depths(); len(got) != 0 {
+		t.Errorf("Depths of an empty graph = %v; want none", got)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	rel := func(n Node, relation string, bundled bool) Node {
+		n.Relation, n.Bundled = relation, bundled
+		return n
+	}
+	// a -> b -> c -> d
+	// a -> e, bundled
+	// f is not reachable.
+	d := &Dependencies{
+		Nodes: []Node{
+			rel(node("a", "1.0.0"), "SELF", false),
+			rel(node("b", "1.0.0"), "DIRECT", false),
+			rel(node("c", "1.0.0"), "INDIRECT", false),
+			rel(node("d", "1.0.0"), "INDIRECT", false),
+			rel(node("a>1.0.0>e", "1.0.0"), "DIRECT", true),
+			rel(node("f", "1.0.0"), "INDIRECT", false),
+		},
+		Edges: []Edge{
+			{FromNode: 0, ToNode: 1, Requirement: "^1.0.0"},
+			{FromNode: 1, ToNode: 2, Requirement: "^1.0.0"},
+			{FromNode: 2, ToNode: 3, Requirement: "^1.0.0"},
+			{FromNode: 0, ToNode: 4, Requirement: "1.0.0"},
+			{FromNode: 5, ToNode: 3, Requirement: "^1.0.0"},
+		},
+		Error: "partial",
+	}
+
+	testCases := []struct {
+		name string
+		opts *PruneOptions
+		want *Dependencies
+	}{
+		{"none", nil, d},
+		{"depth", &PruneOptions{MaxDepth: 2}, &Dependencies{
+			Nodes: []Node{d.Nodes[0], d.Nodes[1], d.Nodes[2], d.Nodes[4]},
+			Edges: []Edge{d.Edges[0], d.Edges[1], {FromNode: 0, ToNode: 3, Requirement: "1.0.0"}},
+			Error: "partial",
+		}},
+		{"indirect", &PruneOptions{Relations: []string{"INDIRECT"}}, &Dependencies{
+			Nodes: []Node{d.Nodes[0], d.Nodes[2], d.Nodes[3], d.Nodes[5]},
+			Edges: []Edge{
+				{FromNode: 1, ToNode: 2, Requirement: "^1.0.0"},
+				{FromNode: 3, ToNode: 2, Requirement: "^1.0.0"},
+			},
+			Error: "partial",
+		}},
+		{"bundled", &PruneOptions{MaxDepth: 1, ExcludeBundled: true}, &Dependencies{
+			Nodes: d.Nodes[:2:2],
+			Edges: []Edge{d.Edges[0]},
+			Error: "partial",
+		}},
+	}
+	for _, tc := range testCases {
+		got := d.Prune(tc.opts)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s: Prune mismatch (-want +got):\n%s", tc.name, diff)
+		}
+	}
+
+	if got := new(Dependencies).Prune(nil); len(got.Nodes) != 0 {
+		t.Errorf("Prune of an empty graph = %+v", got)
+	}
+}
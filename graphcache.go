@@ -0,0 +1,143 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GraphCache caches resolved dependency graphs by the package version they
+// are the graph of. Graphs are the largest responses of the API and the
+// ones most often requested again, so a Client with a GraphCache keeps
+// them decoded in memory, and optionally on disk in a compact binary
+// encoding, apart from its Cache of raw responses.
+//
+// A GraphCache is safe for concurrent use by multiple goroutines. The
+// graphs it returns are shared and must not be modified.
+type GraphCache struct {
+	// The maximum age of the graphs returned by Get. If zero, graphs never
+	// expire.
+	TTL time.Duration
+
+	// The directory where graphs are also stored, so that they persist
+	// across processes. It is created as needed. If empty, graphs are only
+	// kept in memory.
+	Dir string
+
+	mu      sync.Mutex
+	entries map[VersionKey]graphEntry
+}
+
+type graphEntry struct {
+	graph  *Dependencies
+	stored time.Time
+}
+
+// graphKey returns k with its system in upper case, as the API is not
+// sensitive to its case.
+func graphKey(k VersionKey) VersionKey {
+	k.System = strings.ToUpper(k.System)
+	return k
+}
+
+func (g *GraphCache) file(k VersionKey) string {
+	sum := sha256.Sum256([]byte(k.System + "\x00" + k.Name + "\x00" + k.Version))
+	return filepath.Join(g.Dir, "graph-"+hex.EncodeToString(sum[:]))
+}
+
+func (g *GraphCache) expired(stored time.Time) bool {
+	return g.TTL > 0 && time.Since(stored) > g.TTL
+}
+
+// Get returns the graph of the package version k, if cached and not
+// expired.
+func (g *GraphCache) Get(k VersionKey) (*Dependencies, bool) {
+	k = graphKey(k)
+	g.mu.Lock()
+	e, ok := g.entries[k]
+	g.mu.Unlock()
+	if ok && !g.expired(e.stored) {
+		return e.graph, true
+	}
+	if g.Dir == "" {
+		return nil, false
+	}
+	file := g.file(k)
+	fi, err := os.Stat(file)
+	if err != nil || g.expired(fi.ModTime()) {
+		return nil, false
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	d := new(Dependencies)
+	if err := d.UnmarshalBinary(data); err != nil {
+		return nil, false
+	}
+	g.remember(k, d, fi.ModTime())
+	return d, true
+}
+
+// Set stores d as the graph of the package version k. Failing to store it
+// on disk is not reported, as the graph can always be fetched again.
+func (g *GraphCache) Set(k VersionKey, d *Dependencies) {
+	k = graphKey(k)
+	g.remember(k, d, time.Now())
+	if g.Dir == "" {
+		return
+	}
+	data, err := d.MarshalBinary()
+	if err != nil {
+		return
+	}
+	(&DiskCache{Dir: g.Dir}).write(g.file(k), data)
+}
+
+func (g *GraphCache) remember(k VersionKey, d *Dependencies, stored time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[VersionKey]graphEntry)
+	}
+	g.entries[k] = graphEntry{d, stored}
+}
+
+// Invalidate removes the graph of the package version k, if cached.
+func (g *GraphCache) Invalidate(k VersionKey) {
+	k = graphKey(k)
+	g.mu.Lock()
+	delete(g.entries, k)
+	g.mu.Unlock()
+	if g.Dir != "" {
+		os.Remove(g.file(k))
+	}
+}
+
+// Purge removes all the cached graphs.
+func (g *GraphCache) Purge() error {
+	g.mu.Lock()
+	g.entries = nil
+	g.mu.Unlock()
+	if g.Dir == "" {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(g.Dir, "graph-*"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGraphCache(t *testing.T) {
+	dir := t.TempDir()
+	k := VersionKey{System: "npm", Name: "a", Version: "1.0.0"}
+	d := &Dependencies{Nodes: []Node{{VersionKey: k, Relation: "SELF"}}}
+
+	g := &GraphCache{Dir: dir}
+	if _, ok := g.Get(k); ok {
+		t.Error("Get on empty cache returned ok")
+	}
+	g.Set(k, d)
+	if got, ok := g.Get(VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}); !ok || got != d {
+		t.Errorf("Get returned %v, %v; want the stored graph", got, ok)
+	}
+
+	// Another cache on the same directory reads the graph from disk.
+	other := &GraphCache{Dir: dir, TTL: time.Hour}
+	got, ok := other.Get(k)
+	if !ok {
+		t.Fatal("Get from disk returned not ok")
+	}
+	if diff := cmp.Diff(d, got); diff != "" {
+		t.Errorf("Get from disk mismatch (-want +got):\n%s", diff)
+	}
+
+	// Expired graphs are not returned.
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(g.file(graphKey(k)), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (&GraphCache{Dir: dir, TTL: time.Hour}).Get(k); ok {
+		t.Error("Get returned an expired graph")
+	}
+
+	g.Invalidate(k)
+	if _, ok := g.Get(k); ok {
+		t.Error("Get returned an invalidated graph")
+	}
+
+	g.Set(k, d)
+	if err := g.Purge(); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if _, ok := g.Get(k); ok {
+		t.Error("Get returned a purged graph")
+	}
+}
+
+func TestGetDependenciesGraphCache(t *testing.T) {
+	client, mux := setup(t)
+	requests := 0
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, streamGraph)
+	})
+	client.GraphCache = new(GraphCache)
+
+	want, _, err := client.GetDependencies(context.Background(), "npm", "a", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	got, resp, err := client.GetDependencies(context.Background(), "npm", "a", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if got != want || !resp.FromCache || resp.StatusCode != http.StatusOK {
+		t.Errorf("second GetDependencies was not served from the graph cache")
+	}
+	if requests != 1 {
+		t.Errorf("%d requests sent; want 1", requests)
+	}
+}
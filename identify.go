@@ -0,0 +1,111 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Hash is the digest of a file, such as a package archive found on disk.
+type Hash struct {
+	// The function used to produce the digest.
+	// Can be one of MD5, SHA1, SHA256, SHA512.
+	Type string
+
+	// The base64-encoded digest.
+	Value string
+}
+
+// IdentifyOptions specifies the optional parameters to the Identify method.
+type IdentifyOptions struct {
+	// The maximum number of queries in flight at once. If zero, 4 is used.
+	Concurrency int
+
+	// The minimum time between the start of two queries. If zero, queries
+	// are not rate limited.
+	Interval time.Duration
+}
+
+// Identify queries for the package versions whose archives have the given
+// hashes, and returns them by hash. Duplicate hashes are queried once; hash
+// types are compared case-insensitively, and the keys of the map use the
+// upper case names. A hash that matches no package version maps to an empty
+// slice.
+//
+// Like Prefetch, Identify queries as many hashes as it can: failed queries
+// do not stop it, and their errors are returned in a *BatchError, by hash,
+// along with the results of the others. It stops early only if ctx is
+// done.
+func (c *Client) Identify(ctx context.Context, hashes []Hash, opts *IdentifyOptions) (map[Hash][]VersionKey, error) {
+	if opts == nil {
+		opts = new(IdentifyOptions)
+	}
+	n := opts.Concurrency
+	if n <= 0 {
+		n = 4
+	}
+	var tick <-chan time.Time
+	if opts.Interval > 0 {
+		t := time.NewTicker(opts.Interval)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	var (
+		mu     sync.Mutex
+		batch  Batch
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, n)
+		found  = make(map[Hash][]VersionKey)
+		queued = make(map[Hash]bool)
+	)
+loop:
+	for i, h := range hashes {
+		h.Type = strings.ToUpper(h.Type)
+		if queued[h] {
+			continue
+		}
+		queued[h] = true
+		// The first query is not delayed.
+		if tick != nil && i > 0 {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				break loop
+			}
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r, _, err := c.Query(ctx, &QueryOptions{HashType: h.Type, HashValue: h.Value})
+			if err != nil {
+				batch.Fail(h, err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			keys := make([]VersionKey, 0, len(r.Results))
+			for _, res := range r.Results {
+				keys = append(keys, res.Version.VersionKey)
+			}
+			found[h] = keys
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return found, errors.Join(batch.Err(), err)
+	}
+	return found, batch.Err()
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIdentify(t *testing.T) {
+	client, mux := setup(t)
+
+	var requests atomic.Int32
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Query().Get("hash.value") {
+		case "aaa":
+			fmt.Fprint(w, `{"results":[{"version":{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"}}},{"version":{"versionKey":{"system":"NPM","name":"a-fork","version":"1.0.0"}}}]}`)
+		case "bbb":
+			fmt.Fprint(w, `{"results":[]}`)
+		default:
+			http.Error(w, "bad hash", http.StatusBadRequest)
+		}
+	})
+
+	hashes := []Hash{
+		{Type: "SHA256", Value: "aaa"},
+		{Type: "sha256", Value: "aaa"},
+		{Type: "SHA1", Value: "bbb"},
+		{Type: "SHA1", Value: "bad"},
+	}
+	start := time.Now()
+	got, err := client.Identify(context.Background(), hashes, &IdentifyOptions{Concurrency: 2, Interval: 10 * time.Millisecond})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Identify returned %v, want a bad request error", err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("Identify sent %d requests, want 3", n)
+	}
+	// Three queries are at least two intervals apart.
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("Identify took %v, want at least 20ms", d)
+	}
+	want := map[Hash][]VersionKey{
+		{Type: "SHA256", Value: "aaa"}: {
+			{System: "NPM", Name: "a", Version: "1.0.0"},
+			{System: "NPM", Name: "a-fork", Version: "1.0.0"},
+		},
+		{Type: "SHA1", Value: "bbb"}: {},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Identify mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -6,24 +6,143 @@
 package insights
 
 import (
+	"encoding"
+	"fmt"
 	"net/url"
-
-	"github.com/google/go-querystring/query"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
 // addOptions adds the parameters in opts as URL query parameters to s.
-// opts must be a struct whose fields may contain "url" tags.
+// opts must be a struct, or a pointer to one, whose fields may contain
+// "url" tags. See encodeValues.
 func addOptions(s string, opts any) (string, error) {
 	u, err := url.Parse(s)
 	if err != nil {
 		return s, err
 	}
 
-	qs, err := query.Values(opts)
-	if err != nil {
+	qs := make(url.Values)
+	v := reflect.ValueOf(opts)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return s, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return s, fmt.Errorf("insights: options must be a struct, not %s", v.Type())
+	}
+	if err := encodeValues(qs, "", v); err != nil {
 		return s, err
 	}
 
 	u.RawQuery = qs.Encode()
 	return u.String(), nil
 }
+
+// encodeValues adds the fields of the struct v to qs, each named by the
+// prefix followed by the name in its "url" tag, or the field name if it has
+// none. A tag of "-" omits the field, and the "omitempty" option omits it if
+// it has its zero value.
+//
+// Fields that are structs are encoded recursively, their fields named with
+// the field's name and a dot as prefix, as in "versionKey.system"; embedded
+// structs without a tag are encoded as if their fields were part of v.
+// Slices and arrays are encoded as a parameter repeated for each element.
+// Other fields must be of a basic type or implement encoding.TextMarshaler.
+func encodeValues(qs url.Values, prefix string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("url")
+		if tag == "-" {
+			continue
+		}
+		name, opt, _ := strings.Cut(tag, ",")
+		omitEmpty := opt == "omitempty"
+		fv := v.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		for fv.Kind() == reflect.Pointer && !isTextMarshaler(fv) {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && !isTextMarshaler(fv) {
+			p := prefix
+			if !sf.Anonymous || name != "" {
+				p += or(name, sf.Name) + "."
+			}
+			if err := encodeValues(qs, p, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		key := prefix + or(name, sf.Name)
+		if (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) && fv.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatValue(fv.Index(j))
+				if err != nil {
+					return fmt.Errorf("insights: option %s: %v", key, err)
+				}
+				qs.Add(key, s)
+			}
+			continue
+		}
+		s, err := formatValue(fv)
+		if err != nil {
+			return fmt.Errorf("insights: option %s: %v", key, err)
+		}
+		qs.Add(key, s)
+	}
+	return nil
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+func isTextMarshaler(v reflect.Value) bool {
+	return v.Type().Implements(textMarshalerType)
+}
+
+// formatValue formats a single query parameter value.
+func formatValue(v reflect.Value) (string, error) {
+	if isTextMarshaler(v) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "", nil
+		}
+		return formatValue(v.Elem())
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func or(s, def string) string {
+	if s != "" {
+		return s
+	}
+	return def
+}
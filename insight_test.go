@@ -34,3 +34,64 @@ func TestAddOptions(t *testing.T) {
 		}
 	}
 }
+
+type nestedKey struct {
+	System string `url:"system,omitempty"`
+	Name   string `url:"name,omitempty"`
+}
+
+type Paging struct {
+	PageToken string `url:"pageToken,omitempty"`
+}
+
+type nestedOptions struct {
+	VersionKey nestedKey `url:"versionKey"`
+	Hash       *struct {
+		Type  string `url:"type"`
+		Value string `url:"value"`
+	} `url:"hash,omitempty"`
+	Label  []string `url:"label,omitempty"`
+	Limit  int      `url:"limit,omitempty"`
+	Strict bool     `url:"strict,omitempty"`
+	Secret string   `url:"-"`
+	Paging
+}
+
+func TestAddOptionsNested(t *testing.T) {
+	opts := &nestedOptions{
+		VersionKey: nestedKey{System: "NPM", Name: "@a/b"},
+		Label:      []string{"x", "y"},
+		Limit:      10,
+		Secret:     "s",
+		Paging:     Paging{PageToken: "t1"},
+	}
+	opts.Hash = &struct {
+		Type  string `url:"type"`
+		Value string `url:"value"`
+	}{"SHA1", "ulXBPXrC/UTfnMgHRFVxmjPzdbk="}
+
+	got, err := addOptions("query", opts)
+	if err != nil {
+		t.Fatalf("addOptions failed: %v", err)
+	}
+	want := "query?hash.type=SHA1&hash.value=ulXBPXrC%2FUTfnMgHRFVxmjPzdbk%3D&label=x&label=y&limit=10&pageToken=t1&versionKey.name=%40a%2Fb&versionKey.system=NPM"
+	if got != want {
+		t.Errorf("addOptions returned %q; want %q", got, want)
+	}
+
+	if got, err := addOptions("query", (*nestedOptions)(nil)); err != nil || got != "query" {
+		t.Errorf("addOptions(nil) = %q, %v; want %q", got, err, "query")
+	}
+}
+
+func TestAddOptionsError(t *testing.T) {
+	type bad struct {
+		M map[string]string `url:"m"`
+	}
+	if _, err := addOptions("query", &bad{M: map[string]string{}}); err == nil {
+		t.Error("addOptions succeeded with a map field, want error")
+	}
+	if _, err := addOptions("query", "not a struct"); err == nil {
+		t.Error("addOptions succeeded with a string, want error")
+	}
+}
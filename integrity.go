@@ -0,0 +1,78 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Hash types accepted by the query endpoint, strongest first, with the
+// size of their digests in bytes and their Subresource Integrity names.
+var hashTypes = []struct {
+	name, sri string
+	size      int
+}{
+	{"SHA512", "sha512", sha512.Size},
+	{"SHA256", "sha256", sha256.Size},
+	{"SHA1", "sha1", sha1.Size},
+	{"MD5", "md5", md5.Size},
+}
+
+// IntegrityQuery returns the options to query for the package versions
+// whose archive has the digest in integrity, a Subresource Integrity string
+// such as the "sha512-<base64>" values found in npm lockfiles. If integrity
+// lists several digests, the strongest one the query endpoint accepts is
+// used.
+func IntegrityQuery(integrity string) (*QueryOptions, error) {
+	var best *QueryOptions
+	rank := len(hashTypes)
+	for _, f := range strings.Fields(integrity) {
+		// Options, which follow a "?", are reserved and ignored.
+		f, _, _ = strings.Cut(f, "?")
+		alg, value, ok := strings.Cut(f, "-")
+		if !ok {
+			return nil, fmt.Errorf("insights: malformed integrity %q", f)
+		}
+		for i, h := range hashTypes {
+			if h.sri != strings.ToLower(alg) {
+				continue
+			}
+			sum, err := base64.StdEncoding.DecodeString(value)
+			if err != nil || len(sum) != h.size {
+				return nil, fmt.Errorf("insights: malformed %s digest in integrity %q", alg, f)
+			}
+			if i < rank {
+				best, rank = &QueryOptions{HashType: h.name, HashValue: value}, i
+			}
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("insights: no supported digest in integrity %q", integrity)
+	}
+	return best, nil
+}
+
+// Integrity returns the Subresource Integrity string for the digest given
+// by the hash type and base64-encoded value of opts, the inverse of
+// IntegrityQuery.
+func (opts *QueryOptions) Integrity() (string, error) {
+	for _, h := range hashTypes {
+		if !strings.EqualFold(h.name, opts.HashType) {
+			continue
+		}
+		sum, err := base64.StdEncoding.DecodeString(opts.HashValue)
+		if err != nil || len(sum) != h.size {
+			return "", fmt.Errorf("insights: malformed %s digest %q", h.name, opts.HashValue)
+		}
+		return h.sri + "-" + opts.HashValue, nil
+	}
+	return "", fmt.Errorf("insights: unsupported hash type %q", opts.HashType)
+}
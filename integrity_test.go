@@ -0,0 +1,53 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	sha1Value   = "ulXBPXrC/UTfnMgHRFVxmjPzdbk="
+	sha512Value = "ehDdcnUYWW8IpXXb6TlEcM63+dy7/JtLoPIgyGWDbzrbqcvuITLeDrXL3ucP8Eubv2jV+SYyEkMOfbN80MpuXA=="
+)
+
+func TestIntegrityQuery(t *testing.T) {
+	testCases := []struct {
+		integrity string
+		want      *QueryOptions
+	}{
+		{"sha1-" + sha1Value, &QueryOptions{HashType: "SHA1", HashValue: sha1Value}},
+		{"sha512-" + sha512Value, &QueryOptions{HashType: "SHA512", HashValue: sha512Value}},
+		{"sha1-" + sha1Value + " sha512-" + sha512Value + "?opt", &QueryOptions{HashType: "SHA512", HashValue: sha512Value}},
+		{"sha384-abc sha1-" + sha1Value, &QueryOptions{HashType: "SHA1", HashValue: sha1Value}},
+	}
+	for _, tc := range testCases {
+		got, err := IntegrityQuery(tc.integrity)
+		if err != nil {
+			t.Errorf("IntegrityQuery(%q) failed: %v", tc.integrity, err)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("IntegrityQuery(%q) mismatch (-want +got):\n%s", tc.integrity, diff)
+		}
+		s, err := got.Integrity()
+		if err != nil {
+			t.Errorf("Integrity failed: %v", err)
+		}
+		if back, _ := IntegrityQuery(s); !cmp.Equal(back, got) {
+			t.Errorf("IntegrityQuery(%q) = %+v, want %+v", s, back, got)
+		}
+	}
+}
+
+func TestIntegrityQueryError(t *testing.T) {
+	for _, s := range []string{"", "sha512", "sha384-abc", "sha1-notbase64!", "sha512-" + sha1Value} {
+		if _, err := IntegrityQuery(s); err == nil {
+			t.Errorf("IntegrityQuery(%q) succeeded, want error", s)
+		}
+	}
+}
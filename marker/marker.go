@@ -0,0 +1,449 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package marker evaluates the environment markers of Python requirements,
+// as specified by PEP 508, such as
+//
+//	python_version < "3.11" and sys_platform == "win32"
+//
+// which restrict a requirement to the environments they hold in.
+package marker
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights/version"
+)
+
+// Environment is the Python environment markers are evaluated in.
+type Environment struct {
+	// The values of the marker variables, such as "python_version" and
+	// "sys_platform", by name. Variables not set are empty.
+	Variables map[string]string
+
+	// The extras requested of the package whose requirements are
+	// evaluated, for markers on the "extra" variable.
+	Extras []string
+}
+
+// NewEnvironment returns the environment of CPython at the given version,
+// such as "3.12" or "3.12.4", on the platform identified by its sys.platform
+// value, such as "linux", "darwin", or "win32".
+func NewEnvironment(pythonVersion, platform string) *Environment {
+	short := pythonVersion
+	if parts := strings.Split(pythonVersion, "."); len(parts) > 2 {
+		short = parts[0] + "." + parts[1]
+	}
+	vars := map[string]string{
+		"python_version":                 short,
+		"python_full_version":            pythonVersion,
+		"implementation_name":            "cpython",
+		"implementation_version":         pythonVersion,
+		"platform_python_implementation": "CPython",
+		"sys_platform":                   platform,
+		"os_name":                        "posix",
+	}
+	switch platform {
+	case "linux":
+		vars["platform_system"] = "Linux"
+	case "darwin":
+		vars["platform_system"] = "Darwin"
+	case "win32", "cygwin":
+		vars["platform_system"] = "Windows"
+		if platform == "win32" {
+			vars["os_name"] = "nt"
+		}
+	}
+	return &Environment{Variables: vars}
+}
+
+// variables are the names of the marker variables.
+var variables = []string{
+	"implementation_name",
+	"implementation_version",
+	"os_name",
+	"platform_machine",
+	"platform_python_implementation",
+	"platform_release",
+	"platform_system",
+	"platform_version",
+	"python_full_version",
+	"python_version",
+	"sys_platform",
+	"extra",
+}
+
+// legacyVariables maps the names of the variables of PEP 345, still found
+// in old packages, to those of PEP 508.
+var legacyVariables = map[string]string{
+	"os.name":                        "os_name",
+	"sys.platform":                   "sys_platform",
+	"platform.version":               "platform_version",
+	"platform.machine":               "platform_machine",
+	"platform.python_implementation": "platform_python_implementation",
+	"python_implementation":          "platform_python_implementation",
+}
+
+// Marker is a parsed environment marker.
+type Marker struct {
+	raw  string
+	expr expr
+}
+
+// Parse parses the environment marker s, the part of a requirement after
+// its ";".
+func Parse(s string) (*Marker, error) {
+	p := &parser{s: s}
+	p.next()
+	e, err := p.or()
+	if err == nil && p.tok != "" {
+		err = fmt.Errorf("unexpected %q", p.tok)
+	}
+	if err == nil {
+		err = p.err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid environment marker %q: %v", s, err)
+	}
+	return &Marker{raw: strings.TrimSpace(s), expr: e}, nil
+}
+
+// String returns the marker as it was given to Parse, without surrounding
+// spaces.
+func (m *Marker) String() string {
+	return m.raw
+}
+
+// Evaluate reports whether the marker holds in env. A nil env has no
+// variables set and no extras.
+//
+// Comparisons are between versions, following PEP 440, when both sides are
+// valid versions, and between strings otherwise, where only the operators
+// ==, !=, in, and not in hold. Comparisons on "extra" hold if they hold for
+// any of the extras of env, whose names are compared normalized.
+func (m *Marker) Evaluate(env *Environment) bool {
+	if env == nil {
+		env = new(Environment)
+	}
+	return m.expr.eval(env)
+}
+
+// Split splits a PEP 508 requirement into the requirement proper and its
+// environment marker, if any. In requirements on URLs, the marker must be
+// preceded by a space, as ";" may appear in URLs.
+func Split(req string) (requirement, marker string) {
+	i := strings.Index(req, ";")
+	if strings.Contains(req, "@") {
+		i = strings.Index(req, " ;")
+		if j := strings.Index(req, "\t;"); j >= 0 && (i < 0 || j < i) {
+			i = j
+		}
+		if i >= 0 {
+			i++
+		}
+	}
+	if i < 0 {
+		return strings.TrimSpace(req), ""
+	}
+	return strings.TrimSpace(req[:i]), strings.TrimSpace(req[i+1:])
+}
+
+// Evaluate reports whether the environment marker s holds in env. It
+// reports an error if s is not a valid marker.
+func Evaluate(s string, env *Environment) (bool, error) {
+	m, err := Parse(s)
+	if err != nil {
+		return false, err
+	}
+	return m.Evaluate(env), nil
+}
+
+type expr interface {
+	eval(env *Environment) bool
+}
+
+type and []expr
+
+func (a and) eval(env *Environment) bool {
+	for _, e := range a {
+		if !e.eval(env) {
+			return false
+		}
+	}
+	return true
+}
+
+type or []expr
+
+func (o or) eval(env *Environment) bool {
+	for _, e := range o {
+		if e.eval(env) {
+			return true
+		}
+	}
+	return false
+}
+
+// An operand of a comparison is either a variable or a string literal.
+type operand struct {
+	variable string
+	literal  string
+}
+
+func (o operand) value(env *Environment) string {
+	if o.variable != "" {
+		return env.Variables[o.variable]
+	}
+	return o.literal
+}
+
+type comparison struct {
+	left  operand
+	op    string
+	right operand
+}
+
+func (c comparison) eval(env *Environment) bool {
+	if c.left.variable == "extra" || c.right.variable == "extra" {
+		other := c.right
+		if c.right.variable == "extra" {
+			other = c.left
+		}
+		want := normalizeExtra(other.value(env))
+		extras := env.Extras
+		if len(extras) == 0 {
+			// As if the extra variable were empty.
+			extras = []string{""}
+		}
+		for _, x := range extras {
+			l, r := normalizeExtra(x), want
+			if c.right.variable == "extra" {
+				l, r = r, l
+			}
+			if compare(l, c.op, r) {
+				return true
+			}
+		}
+		return false
+	}
+	return compare(c.left.value(env), c.op, c.right.value(env))
+}
+
+// compare reports whether the comparison lhs op rhs holds.
+func compare(lhs, op, rhs string) bool {
+	switch op {
+	case "in":
+		return strings.Contains(rhs, lhs)
+	case "not in":
+		return !strings.Contains(rhs, lhs)
+	case "===":
+		return lhs == rhs
+	}
+	if l, err := version.Parse("PYPI", lhs); err == nil {
+		if op == "~=" || strings.HasSuffix(rhs, ".*") {
+			if c, err := version.ParseConstraint("PYPI", op+rhs); err == nil {
+				return c.Match(l)
+			}
+		} else if r, err := version.Parse("PYPI", rhs); err == nil {
+			n := l.Compare(r)
+			switch op {
+			case "==":
+				return n == 0
+			case "!=":
+				return n != 0
+			case "<":
+				return n < 0
+			case "<=":
+				return n <= 0
+			case ">":
+				return n > 0
+			case ">=":
+				return n >= 0
+			}
+		}
+	}
+	switch op {
+	case "==":
+		return lhs == rhs
+	case "!=":
+		return lhs != rhs
+	}
+	return false
+}
+
+var extraSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizeExtra normalizes the name of an extra as PEP 685 does.
+func normalizeExtra(s string) string {
+	return extraSeparators.ReplaceAllString(strings.ToLower(s), "-")
+}
+
+// parser is a recursive descent parser of the grammar of PEP 508:
+//
+//	or         = and { "or" and }
+//	and        = comparison { "and" comparison }
+//	comparison = operand op operand | "(" or ")"
+type parser struct {
+	s   string
+	tok string // the current token; empty at the end of s
+	err error
+}
+
+// operators are the comparison operators, longest first so that they are
+// tokenized greedily.
+var operators = []string{"===", "==", "!=", "<=", ">=", "~=", "<", ">"}
+
+// next advances to the next token.
+func (p *parser) next() {
+	p.s = strings.TrimLeft(p.s, " \t")
+	if p.s == "" {
+		p.tok = ""
+		return
+	}
+	n := 0
+	switch c := p.s[0]; {
+	case c == '(' || c == ')':
+		n = 1
+	case c == '"' || c == '\'':
+		end := strings.IndexByte(p.s[1:], c)
+		if end < 0 {
+			p.fail(fmt.Errorf("unterminated string"))
+			return
+		}
+		n = end + 2
+	case strings.IndexByte("=!<>~", c) >= 0:
+		for _, op := range operators {
+			if strings.HasPrefix(p.s, op) {
+				n = len(op)
+				break
+			}
+		}
+		if n == 0 {
+			p.fail(fmt.Errorf("invalid operator at %q", p.s))
+			return
+		}
+	default:
+		n = strings.IndexFunc(p.s, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.')
+		})
+		if n < 0 {
+			n = len(p.s)
+		}
+		if n == 0 {
+			p.fail(fmt.Errorf("unexpected %q", p.s[:1]))
+			return
+		}
+	}
+	p.tok, p.s = p.s[:n], p.s[n:]
+}
+
+// fail records err, if it is the first error, and ends the input.
+func (p *parser) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+	p.tok, p.s = "", ""
+}
+
+func (p *parser) or() (expr, error) {
+	e, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	o := or{e}
+	for p.tok == "or" {
+		p.next()
+		e, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		o = append(o, e)
+	}
+	if len(o) == 1 {
+		return o[0], nil
+	}
+	return o, nil
+}
+
+func (p *parser) and() (expr, error) {
+	e, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	a := and{e}
+	for p.tok == "and" {
+		p.next()
+		e, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, e)
+	}
+	if len(a) == 1 {
+		return a[0], nil
+	}
+	return a, nil
+}
+
+func (p *parser) comparison() (expr, error) {
+	if p.tok == "(" {
+		p.next()
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.next()
+		return e, nil
+	}
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	op := p.tok
+	switch {
+	case op == "in":
+	case op == "not":
+		p.next()
+		if p.tok != "in" {
+			return nil, fmt.Errorf("expected in after not")
+		}
+		op = "not in"
+	case slices.Contains(operators, op):
+	default:
+		return nil, fmt.Errorf("expected an operator, found %q", op)
+	}
+	p.next()
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return comparison{left, op, right}, nil
+}
+
+func (p *parser) operand() (operand, error) {
+	tok := p.tok
+	if p.err != nil {
+		return operand{}, p.err
+	}
+	if tok == "" {
+		return operand{}, fmt.Errorf("unexpected end")
+	}
+	p.next()
+	if tok[0] == '"' || tok[0] == '\'' {
+		return operand{literal: tok[1 : len(tok)-1]}, nil
+	}
+	if v, ok := legacyVariables[tok]; ok {
+		tok = v
+	}
+	if !slices.Contains(variables, tok) {
+		return operand{}, fmt.Errorf("unknown variable %q", tok)
+	}
+	return operand{variable: tok}, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package marker
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	linux := NewEnvironment("3.10.12", "linux")
+	windows := NewEnvironment("3.12.1", "win32")
+	withExtras := NewEnvironment("3.11", "darwin")
+	withExtras.Extras = []string{"Security", "socks"}
+
+	testCases := []struct {
+		marker string
+		env    *Environment
+		want   bool
+	}{
+		{`python_version < "3.11"`, linux, true},
+		{`python_version < "3.11"`, windows, false},
+		{`python_version >= "3.9" and python_version < "3.11"`, linux, true},
+		{`python_full_version >= "3.10.13"`, linux, false},
+		{`python_version == "3.10.*"`, linux, true},
+		{`python_full_version ~= "3.12.0"`, windows, true},
+		{`python_full_version ~= "3.12.0"`, linux, false},
+		// Versions compare numerically, not as strings.
+		{`python_version > "3.9"`, linux, true},
+		{`"3.9" < python_version`, linux, true},
+		{`sys_platform == "win32" or os_name == "nt"`, windows, true},
+		{`sys_platform == "win32" or os_name == "nt"`, linux, false},
+		{`platform_system != "Windows"`, linux, true},
+		{`(sys_platform == "linux" or sys_platform == "darwin") and python_version >= "3.8"`, linux, true},
+		{`"linux" in sys_platform`, linux, true},
+		{`'lin' not in sys_platform`, linux, false},
+		{`sys.platform == 'win32'`, windows, true},
+		{`extra == "security"`, withExtras, true},
+		{`extra == "SOCKS"`, withExtras, true},
+		{`extra == "tests"`, withExtras, false},
+		{`extra == "security"`, linux, false},
+		{`extra != "tests"`, linux, true},
+		{`python_version < "3.8" or extra == "socks"`, withExtras, true},
+		// Ordering strings that are not versions never holds.
+		{`platform_machine < "x86"`, linux, false},
+		{`python_version < "3.11"`, nil, false},
+	}
+	for _, c := range testCases {
+		m, err := Parse(c.marker)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", c.marker, err)
+			continue
+		}
+		if got := m.Evaluate(c.env); got != c.want {
+			t.Errorf("Evaluate(%q) = %v; want %v", c.marker, got, c.want)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`python_version`,
+		`python_version <`,
+		`python_version < "3.11`,
+		`python_version = "3.11"`,
+		`pyversion < "3.11"`,
+		`(python_version < "3.11"`,
+		`python_version < "3.11")`,
+		`python_version not "3.11"`,
+		`python_version < "3.11" and`,
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded; want error", s)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		req, requirement, marker string
+	}{
+		{`requests==2.31.0`, `requests==2.31.0`, ``},
+		{`requests[security]==2.31.0 ; python_version >= "3.8"`, `requests[security]==2.31.0`, `python_version >= "3.8"`},
+		{`tomli>=1.1.0;python_version<"3.11"`, `tomli>=1.1.0`, `python_version<"3.11"`},
+		{`pip @ https://example.com/pip.zip;v=1`, `pip @ https://example.com/pip.zip;v=1`, ``},
+		{`pip @ https://example.com/pip.zip;v=1 ; os_name == "nt"`, `pip @ https://example.com/pip.zip;v=1`, `os_name == "nt"`},
+	}
+	for _, c := range testCases {
+		requirement, marker := Split(c.req)
+		if requirement != c.requirement || marker != c.marker {
+			t.Errorf("Split(%q) = %q, %q; want %q, %q", c.req, requirement, marker, c.requirement, c.marker)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// MavenCoordinate identifies a Maven artifact, or a project or version of
+// one if some of its parts are empty.
+type MavenCoordinate struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+
+	// The classifier and type of the artifact, such as "sources" and
+	// "jar". The type defaults to "jar".
+	Classifier string
+	Type       string
+}
+
+// ParseMavenCoordinate parses a Maven coordinate in any of the forms used
+// by Maven and Gradle:
+//
+//	group:artifact
+//	group:artifact:version
+//	group:artifact:type:version
+//	group:artifact:type:classifier:version
+//	group:artifact:version:classifier
+//	group:artifact:version[:classifier]@type
+//
+// The two forms with four parts are told apart by which part is a version:
+// in Maven's, the last one; in Gradle's, the third. Versions start with a
+// digit, and types never do. The version may be empty to leave it out, as
+// in "group:artifact:jar:sources:".
+func ParseMavenCoordinate(s string) (MavenCoordinate, error) {
+	var c MavenCoordinate
+	s = strings.TrimSpace(s)
+	rest, typ, gradle := strings.Cut(s, "@")
+	parts := strings.Split(rest, ":")
+	for i, p := range parts[:min(len(parts), 2)] {
+		if p == "" {
+			return c, fmt.Errorf("invalid Maven coordinate %q: empty %s", s, [...]string{"group ID", "artifact ID"}[i])
+		}
+	}
+	c.GroupID = parts[0]
+	if len(parts) > 1 {
+		c.ArtifactID = parts[1]
+	}
+	switch n := len(parts); {
+	case n < 2 || n > 5 || gradle && n == 5 || gradle && typ == "":
+		return c, fmt.Errorf("invalid Maven coordinate %q: want group:artifact[:version]", s)
+	case gradle:
+		c.Type = typ
+		if n > 2 {
+			c.Version = parts[2]
+		}
+		if n > 3 {
+			c.Classifier = parts[3]
+		}
+	case n == 3:
+		c.Version = parts[2]
+	case n == 4 && startsWithDigit(parts[2]) && !startsWithDigit(parts[3]):
+		c.Version, c.Classifier = parts[2], parts[3]
+	case n == 4:
+		c.Type, c.Version = parts[2], parts[3]
+	case n == 5:
+		c.Type, c.Classifier, c.Version = parts[2], parts[3], parts[4]
+	}
+	return c, nil
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// Name returns the name of the package of the artifact in deps.dev,
+// "group:artifact".
+func (c MavenCoordinate) Name() string {
+	return c.GroupID + ":" + c.ArtifactID
+}
+
+// VersionKey returns the key of the package version of the artifact in
+// deps.dev. Classifiers and types are not part of it, as deps.dev knows
+// packages by group and artifact ID only.
+func (c MavenCoordinate) VersionKey() VersionKey {
+	return VersionKey{System: "MAVEN", Name: c.Name(), Version: c.Version}
+}
+
+// String returns the coordinate in Maven's form,
+// group:artifact[:type[:classifier]]:version, leaving out the type if it
+// is the default and there is no classifier, and the version if it is
+// empty and nothing precedes it but the artifact ID.
+func (c MavenCoordinate) String() string {
+	s := c.Name()
+	switch {
+	case c.Classifier != "":
+		s += ":" + cmp.Or(c.Type, "jar") + ":" + c.Classifier + ":" + c.Version
+	case c.Type != "" && c.Type != "jar":
+		s += ":" + c.Type + ":" + c.Version
+	case c.Version != "":
+		s += ":" + c.Version
+	}
+	return s
+}
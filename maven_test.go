@@ -0,0 +1,65 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import "testing"
+
+func TestParseMavenCoordinate(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want MavenCoordinate
+		str  string
+	}{
+		{"org.slf4j:slf4j-api", MavenCoordinate{GroupID: "org.slf4j", ArtifactID: "slf4j-api"}, "org.slf4j:slf4j-api"},
+		{" org.slf4j:slf4j-api:2.0.9 ", MavenCoordinate{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.9"}, "org.slf4j:slf4j-api:2.0.9"},
+		{"io.netty:netty-bom:pom:4.1.100.Final", MavenCoordinate{GroupID: "io.netty", ArtifactID: "netty-bom", Version: "4.1.100.Final", Type: "pom"}, "io.netty:netty-bom:pom:4.1.100.Final"},
+		{"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final",
+			MavenCoordinate{GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Version: "4.1.100.Final", Classifier: "linux-x86_64", Type: "jar"},
+			"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final"},
+		{"junit:junit:4.13.2:sources", MavenCoordinate{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Classifier: "sources"}, "junit:junit:jar:sources:4.13.2"},
+		{"junit:junit:4.13.2:sources@zip", MavenCoordinate{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Classifier: "sources", Type: "zip"}, "junit:junit:zip:sources:4.13.2"},
+		{"junit:junit:4.13.2@pom", MavenCoordinate{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Type: "pom"}, "junit:junit:pom:4.13.2"},
+		{"io.netty:netty-bom:pom:", MavenCoordinate{GroupID: "io.netty", ArtifactID: "netty-bom", Type: "pom"}, "io.netty:netty-bom:pom:"},
+	}
+	for _, tc := range testCases {
+		got, err := ParseMavenCoordinate(tc.s)
+		if err != nil {
+			t.Errorf("ParseMavenCoordinate(%q) failed: %v", tc.s, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseMavenCoordinate(%q) = %+v, want %+v", tc.s, got, tc.want)
+		}
+		if s := got.String(); s != tc.str {
+			t.Errorf("String of %+v = %q, want %q", got, s, tc.str)
+		}
+		// The string form parses back to the same artifact, but for the
+		// default type.
+		again, err := ParseMavenCoordinate(got.String())
+		if again.Type == "jar" {
+			again.Type = got.Type
+		}
+		if err != nil || again != got {
+			t.Errorf("ParseMavenCoordinate(%q) = %+v, %v; want %+v", got.String(), again, err, got)
+		}
+	}
+
+	for _, s := range []string{"", "junit", ":junit:4.13.2", "junit::4.13.2", "a:b:c:d:e:f", "junit:junit:4.13.2@", "a:b:jar:sources:1.0@zip"} {
+		if c, err := ParseMavenCoordinate(s); err == nil {
+			t.Errorf("ParseMavenCoordinate(%q) = %+v; want error", s, c)
+		}
+	}
+}
+
+func TestMavenCoordinateVersionKey(t *testing.T) {
+	c, err := ParseMavenCoordinate("junit:junit:4.13.2:sources")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := VersionKey{System: "MAVEN", Name: "junit:junit", Version: "4.13.2"}
+	if got := c.VersionKey(); got != want {
+		t.Errorf("VersionKey() = %v, want %v", got, want)
+	}
+}
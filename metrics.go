@@ -0,0 +1,47 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import "time"
+
+// Metrics records the calls a Client makes to the API. The prommetrics
+// module has an implementation exporting them to Prometheus.
+type Metrics interface {
+	// ObserveCall is called once each call to the API ends, after any
+	// retries. It must be safe to call concurrently.
+	ObserveCall(c *Call)
+}
+
+// Call describes a call made to the API, as observed by Metrics.
+type Call struct {
+	// The endpoint called and the version of the API that answered.
+	Endpoint   Endpoint
+	APIVersion string
+
+	// The HTTP status code of the response, or 0 if none was received.
+	StatusCode int
+
+	// Whether the response was served from the client's Cache.
+	FromCache bool
+
+	// How long the call took.
+	Duration time.Duration
+
+	// The error the call failed with, if any.
+	Err error
+}
+
+// observe records a call to endpoint e with the client's Metrics, if any.
+func (c *Client) observe(e Endpoint, ver string, resp *Response, err error, d time.Duration) {
+	if c.Metrics == nil {
+		return
+	}
+	call := &Call{Endpoint: e, APIVersion: ver, Duration: d, Err: err}
+	if resp != nil && resp.Response != nil {
+		call.StatusCode = resp.StatusCode
+		call.FromCache = resp.FromCache
+	}
+	c.Metrics.ObserveCall(call)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// callRecorder is a Metrics keeping the calls observed.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []*Call
+}
+
+func (r *callRecorder) ObserveCall(c *Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, c)
+}
+
+func TestMetrics(t *testing.T) {
+	client, mux := setup(t)
+	client.Cache = &memCache{m: make(map[string][]byte)}
+	rec := new(callRecorder)
+	client.Metrics = rec
+
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fa%2Fb", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "project not found", http.StatusNotFound)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.GetPackage(ctx, "go", "foo"); err != nil {
+			t.Fatalf("GetPackage failed: %v", err)
+		}
+	}
+	client.GetProject(ctx, "github.com/a/b")
+
+	if len(rec.calls) != 3 {
+		t.Fatalf("observed %d calls, want 3", len(rec.calls))
+	}
+	for i, want := range []struct {
+		e         Endpoint
+		code      int
+		fromCache bool
+		failed    bool
+	}{
+		{EndpointGetPackage, 200, false, false},
+		{EndpointGetPackage, 200, true, false},
+		{EndpointGetProject, 404, false, true},
+	} {
+		c := rec.calls[i]
+		if c.Endpoint != want.e || c.StatusCode != want.code || c.FromCache != want.fromCache || (c.Err != nil) != want.failed || c.APIVersion != "v3" {
+			t.Errorf("call %d is %+v, want %+v", i, c, want)
+		}
+	}
+}
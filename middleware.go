@@ -0,0 +1,56 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import "net/http"
+
+// Middleware wraps the http.RoundTripper requests to the API are sent
+// with, to see or change the requests and their responses: to add headers,
+// log, or record metrics, for example.
+//
+// A middleware sees every attempt at sending a request, including
+// retries, but not requests answered from the client's caches.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an http.RoundTripper calling itself, to write
+// middleware with:
+//
+//	client.Middleware = append(client.Middleware, func(next http.RoundTripper) http.RoundTripper {
+//		return insights.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//			req = req.Clone(req.Context())
+//			req.Header.Set("Authorization", "Bearer "+token)
+//			return next.RoundTrip(req)
+//		})
+//	})
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip returns f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// httpClient returns the HTTP client requests are sent with: the client's
+// HTTPClient, or http.DefaultClient, with its Transport wrapped in the
+// client's Middleware.
+func (c *Client) httpClient() *http.Client {
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	if len(c.Middleware) == 0 {
+		return hc
+	}
+	t := hc.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	// The first middleware is the outermost, seeing requests first.
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		t = c.Middleware[i](t)
+	}
+	wrapped := *hc
+	wrapped.Transport = t
+	return &wrapped
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	client, mux := setup(t)
+	client.Cache = &memCache{m: make(map[string][]byte)}
+	var calls []string
+	mw := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				req = req.Clone(req.Context())
+				req.Header.Add("X-Middleware", name)
+				resp, err := next.RoundTrip(req)
+				if err == nil {
+					calls = append(calls, name+" "+resp.Status)
+				}
+				return resp, err
+			})
+		}
+	}
+	client.Middleware = []Middleware{mw("outer"), mw("inner")}
+
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		if got := strings.Join(r.Header.Values("X-Middleware"), ","); got != "outer,inner" {
+			t.Errorf("X-Middleware headers are %q, want outer,inner", got)
+		}
+		fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.GetPackage(ctx, "go", "foo"); err != nil {
+			t.Fatalf("GetPackage failed: %v", err)
+		}
+	}
+	// The second request is served from the cache.
+	want := []string{"outer", "inner", "inner 200 OK", "outer 200 OK"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("middleware calls are %q, want %q", calls, want)
+	}
+}
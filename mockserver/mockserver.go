@@ -0,0 +1,479 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mockserver serves a canned dataset over the deps.dev v3 API, so
+// that tools built on insights can be tested without network access.
+//
+// The dataset is loaded from JSON fixture files with the same fields as
+// the API responses, such as
+//
+//	{
+//	  "versions": [{
+//	    "versionKey": {"system": "NPM", "name": "left-pad", "version": "1.3.0"},
+//	    "isDefault": true,
+//	    "licenses": ["WTFPL"]
+//	  }],
+//	  "graphs": [{
+//	    "versionKey": {"system": "NPM", "name": "left-pad", "version": "1.3.0"},
+//	    "nodes": [{"versionKey": {"system": "NPM", "name": "left-pad", "version": "1.3.0"}, "relation": "SELF"}]
+//	  }]
+//	}
+//
+// and served in the same form as deps.dev serves it, under /v3/ and
+// /v3alpha/. Packages, and the package versions of projects, are derived
+// from the versions of the dataset when not given.
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/protoconv"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Dataset is the data served by a mock server.
+type Dataset struct {
+	// Packages, with their versions. The packages of Versions not listed
+	// here are served with the versions of the package in Versions.
+	Packages []insights.Package
+
+	Versions     []insights.Version
+	Graphs       []Graph
+	Requirements []Requirements
+	Dependents   []Dependents
+	Projects     []insights.Project
+	Advisories   []insights.Advisory
+
+	// The hashes of the artifacts of versions, for queries by hash.
+	Hashes []Hash
+}
+
+// Graph is the resolved dependency graph of a package version.
+type Graph struct {
+	VersionKey insights.VersionKey
+	insights.Dependencies
+}
+
+// Requirements are the requirements of a package version.
+type Requirements struct {
+	VersionKey insights.VersionKey
+	insights.Requirements
+}
+
+// Dependents are the dependent counts of a package version.
+type Dependents struct {
+	VersionKey insights.VersionKey
+	insights.Dependents
+}
+
+// Hash is the hash of an artifact of a package version.
+type Hash struct {
+	VersionKey insights.VersionKey
+	insights.Hash
+}
+
+// Load reads a dataset from the named JSON fixture files, merging their
+// contents. Directories are read recursively for files ending in .json.
+func Load(paths ...string) (*Dataset, error) {
+	d := new(Dataset)
+	read := func(file string) error {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var f Dataset
+		if err := json.Unmarshal(data, &f); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		d.Packages = append(d.Packages, f.Packages...)
+		d.Versions = append(d.Versions, f.Versions...)
+		d.Graphs = append(d.Graphs, f.Graphs...)
+		d.Requirements = append(d.Requirements, f.Requirements...)
+		d.Dependents = append(d.Dependents, f.Dependents...)
+		d.Projects = append(d.Projects, f.Projects...)
+		d.Advisories = append(d.Advisories, f.Advisories...)
+		d.Hashes = append(d.Hashes, f.Hashes...)
+		return nil
+	}
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			if err := read(p); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = filepath.WalkDir(p, func(file string, e fs.DirEntry, err error) error {
+			if err != nil || e.IsDir() || filepath.Ext(file) != ".json" {
+				return err
+			}
+			return read(file)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// server serves a dataset, indexed by canonical keys.
+type server struct {
+	packages     map[insights.PackageKey]*insights.Package
+	versions     map[insights.VersionKey]*insights.Version
+	graphs       map[insights.VersionKey]*insights.Dependencies
+	requirements map[insights.VersionKey]*insights.Requirements
+	dependents   map[insights.VersionKey]*insights.Dependents
+	projects     map[string]*insights.Project // by lower case ID
+	advisories   map[string]*insights.Advisory
+	hashes       map[insights.Hash][]insights.VersionKey
+	// The package versions of each project, by lower case project ID.
+	projectVersions map[string]*insights.ProjectPackageVersions
+}
+
+// canonicalKey returns k with the system in upper case and the name in the
+// form deps.dev knows it by, as the dataset is looked up.
+func canonicalKey(k insights.VersionKey) insights.VersionKey {
+	k.System = strings.ToUpper(k.System)
+	k.Name = insights.CanonicalName(k.System, k.Name)
+	return k
+}
+
+// New returns a handler serving d over the deps.dev v3 API, at /v3/ and
+// /v3alpha/. Requests for data not in d fail with 404 Not Found, as they
+// do with deps.dev. d must not be modified afterwards.
+func New(d *Dataset) http.Handler {
+	s := &server{
+		packages:        make(map[insights.PackageKey]*insights.Package),
+		versions:        make(map[insights.VersionKey]*insights.Version),
+		graphs:          make(map[insights.VersionKey]*insights.Dependencies),
+		requirements:    make(map[insights.VersionKey]*insights.Requirements),
+		dependents:      make(map[insights.VersionKey]*insights.Dependents),
+		projects:        make(map[string]*insights.Project),
+		advisories:      make(map[string]*insights.Advisory),
+		hashes:          make(map[insights.Hash][]insights.VersionKey),
+		projectVersions: make(map[string]*insights.ProjectPackageVersions),
+	}
+	for i := range d.Packages {
+		p := &d.Packages[i]
+		k := canonicalKey(insights.VersionKey{System: p.PackageKey.System, Name: p.PackageKey.Name})
+		s.packages[insights.PackageKey{System: k.System, Name: k.Name}] = p
+	}
+	derived := make(map[insights.PackageKey]*insights.Package)
+	for i := range d.Versions {
+		v := &d.Versions[i]
+		k := canonicalKey(v.VersionKey)
+		s.versions[k] = v
+		pk := insights.PackageKey{System: k.System, Name: k.Name}
+		if s.packages[pk] == nil {
+			if derived[pk] == nil {
+				derived[pk] = &insights.Package{PackageKey: insights.PackageKey{System: v.VersionKey.System, Name: v.VersionKey.Name}}
+			}
+			derived[pk].Versions = append(derived[pk].Versions, insights.Version{
+				VersionKey:   v.VersionKey,
+				PublishedAt:  v.PublishedAt,
+				IsDefault:    v.IsDefault,
+				IsDeprecated: v.IsDeprecated,
+			})
+		}
+		for _, rp := range v.RelatedProjects {
+			id := strings.ToLower(rp.ProjectKey.ID)
+			pv := s.projectVersions[id]
+			if pv == nil {
+				pv = new(insights.ProjectPackageVersions)
+				s.projectVersions[id] = pv
+			}
+			pv.Versions = append(pv.Versions, struct {
+				VersionKey         insights.VersionKey
+				SLSAProvenances    []insights.SLSAProvenance
+				Attestations       []insights.Attestation
+				RelationType       string
+				RelationProvenance string
+			}{
+				VersionKey:         v.VersionKey,
+				SLSAProvenances:    v.SLSAProvenances,
+				Attestations:       v.Attestations,
+				RelationType:       rp.RelationType,
+				RelationProvenance: rp.RelationProvenance,
+			})
+		}
+	}
+	for pk, p := range derived {
+		s.packages[pk] = p
+	}
+	for i := range d.Graphs {
+		s.graphs[canonicalKey(d.Graphs[i].VersionKey)] = &d.Graphs[i].Dependencies
+	}
+	for i := range d.Requirements {
+		s.requirements[canonicalKey(d.Requirements[i].VersionKey)] = &d.Requirements[i].Requirements
+	}
+	for i := range d.Dependents {
+		s.dependents[canonicalKey(d.Dependents[i].VersionKey)] = &d.Dependents[i].Dependents
+	}
+	for i := range d.Projects {
+		s.projects[strings.ToLower(d.Projects[i].ProjectKey.ID)] = &d.Projects[i]
+	}
+	for i := range d.Advisories {
+		s.advisories[d.Advisories[i].AdvisoryKey.ID] = &d.Advisories[i]
+	}
+	for _, h := range d.Hashes {
+		k := insights.Hash{Type: strings.ToUpper(h.Type), Value: h.Value}
+		s.hashes[k] = append(s.hashes[k], canonicalKey(h.VersionKey))
+	}
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/v3alpha/purlbatch" {
+		s.purlBatch(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	// Names are escaped within a path segment, so the path is split before
+	// unescaping it.
+	p := r.URL.EscapedPath()
+	alpha := strings.HasPrefix(p, "/v3alpha/")
+	if !alpha && !strings.HasPrefix(p, "/v3/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	segs := strings.Split(p[strings.Index(p[1:], "/")+2:], "/")
+	for i, seg := range segs {
+		u, err := url.PathUnescape(seg)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		segs[i] = u
+	}
+
+	switch {
+	case len(segs) == 4 && segs[0] == "systems" && segs[2] == "packages":
+		name, method, _ := strings.Cut(segs[3], ":")
+		k := canonicalKey(insights.VersionKey{System: segs[1], Name: name})
+		pk := insights.PackageKey{System: k.System, Name: k.Name}
+		switch {
+		case method == "":
+			if p := s.packages[pk]; p != nil {
+				writeProto(w, protoconv.PackageToProto(p))
+				return
+			}
+		case method == "similarlyNamedPackages" && alpha:
+			writeJSON(w, s.similar(pk))
+			return
+		}
+	case len(segs) == 6 && segs[0] == "systems" && segs[2] == "packages" && segs[4] == "versions":
+		version, method, _ := strings.Cut(segs[5], ":")
+		k := canonicalKey(insights.VersionKey{System: segs[1], Name: segs[3], Version: version})
+		switch {
+		case method == "":
+			if v := s.versions[k]; v != nil {
+				writeProto(w, protoconv.VersionToProto(v))
+				return
+			}
+		case method == "dependencies":
+			if g := s.graphs[k]; g != nil {
+				writeProto(w, protoconv.DependenciesToProto(g))
+				return
+			}
+		case method == "requirements":
+			if req := s.requirements[k]; req != nil {
+				writeJSON(w, req)
+				return
+			}
+		case method == "dependents" && alpha:
+			if d := s.dependents[k]; d != nil {
+				writeJSON(w, d)
+				return
+			}
+		}
+	case len(segs) == 2 && segs[0] == "projects":
+		id, method, _ := strings.Cut(segs[1], ":")
+		id = strings.ToLower(id)
+		switch method {
+		case "":
+			if p := s.projects[id]; p != nil {
+				writeProto(w, protoconv.ProjectToProto(p))
+				return
+			}
+		case "packageversions":
+			if pv := s.projectVersions[id]; pv != nil {
+				writeProto(w, protoconv.ProjectPackageVersionsToProto(pv))
+				return
+			}
+		}
+	case len(segs) == 2 && segs[0] == "advisories":
+		if a := s.advisories[segs[1]]; a != nil {
+			writeProto(w, protoconv.AdvisoryToProto(a))
+			return
+		}
+	case len(segs) == 1 && segs[0] == "query":
+		writeProto(w, protoconv.QueryResultToProto(s.query(r.URL.Query())))
+		return
+	case len(segs) == 2 && segs[0] == "purl" && alpha:
+		k, err := insights.ParsePURL(segs[1])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		k = canonicalKey(k)
+		var m proto.Message
+		if k.Version == "" {
+			if p := s.packages[insights.PackageKey{System: k.System, Name: k.Name}]; p != nil {
+				m = protoconv.PackageToProto(p)
+			}
+		} else if v := s.versions[k]; v != nil {
+			m = protoconv.VersionToProto(v)
+		}
+		if m != nil {
+			writePurlLookup(w, segs[1], k.Version == "", m)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not found")
+}
+
+// purlBatch serves a batch of purl lookups, all in one page. Only package
+// versions are looked up, as deps.dev does; the responses for purls not in
+// the dataset have no result.
+func (s *server) purlBatch(w http.ResponseWriter, r *http.Request) {
+	type purlRequest struct {
+		Purl string `json:"purl"`
+	}
+	var body struct {
+		Requests []purlRequest `json:"requests"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	type purlResponse struct {
+		Request purlRequest     `json:"request"`
+		Result  json.RawMessage `json:"result,omitempty"`
+	}
+	resps := make([]purlResponse, len(body.Requests))
+	for i, req := range body.Requests {
+		resps[i].Request = req
+		k, err := insights.ParsePURL(req.Purl)
+		if err != nil || k.Version == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid purl %q: a version is required", req.Purl))
+			return
+		}
+		v := s.versions[canonicalKey(k)]
+		if v == nil {
+			continue
+		}
+		data, err := protojson.Marshal(protoconv.VersionToProto(v))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		resps[i].Result, _ = json.Marshal(map[string]any{"purl": req.Purl, "version": json.RawMessage(data)})
+	}
+	writeJSON(w, map[string]any{"responses": resps})
+}
+
+// similar returns the packages of the same system as k whose names are
+// like that of k, ignoring case and punctuation, as the mock server does
+// not measure edit distances as deps.dev does.
+func (s *server) similar(k insights.PackageKey) *insights.SimilarlyNamedPackages {
+	squash := func(name string) string {
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune("-_.@/:", r) {
+				return -1
+			}
+			return r
+		}, strings.ToLower(name))
+	}
+	r := new(insights.SimilarlyNamedPackages)
+	for pk := range s.packages {
+		if pk.System == k.System && pk.Name != k.Name && squash(pk.Name) == squash(k.Name) {
+			r.Packages = append(r.Packages, struct{ PackageKey insights.PackageKey }{pk})
+		}
+	}
+	return r
+}
+
+// query returns the versions matching the parameters of a query request.
+func (s *server) query(q url.Values) *insights.QueryResult {
+	var keys []insights.VersionKey
+	if h := q.Get("hash.value"); h != "" {
+		keys = s.hashes[insights.Hash{Type: strings.ToUpper(q.Get("hash.type")), Value: h}]
+	} else {
+		keys = []insights.VersionKey{canonicalKey(insights.VersionKey{
+			System:  q.Get("versionKey.system"),
+			Name:    q.Get("versionKey.name"),
+			Version: q.Get("versionKey.version"),
+		})}
+	}
+	r := new(insights.QueryResult)
+	for _, k := range keys {
+		if v := s.versions[k]; v != nil {
+			r.Results = append(r.Results, insights.Result{Version: *v})
+		}
+	}
+	return r
+}
+
+// writeProto writes m in the JSON form the deps.dev API uses.
+func writeProto(w http.ResponseWriter, m proto.Message) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// writePurlLookup writes the result of looking up purl, the package or
+// package version m, as the purl lookup endpoint does.
+func writePurlLookup(w http.ResponseWriter, purl string, isPackage bool, m proto.Message) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	field := "version"
+	if isPackage {
+		field = "package"
+	}
+	writeJSON(w, map[string]any{"purl": purl, field: json.RawMessage(data)})
+}
+
+// writeJSON writes v, of a type with no deps.dev message, as JSON.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the form deps.dev uses.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// The gRPC code of 404 Not Found is NOT_FOUND.
+	code := 2
+	if status == http.StatusNotFound {
+		code = 5
+	}
+	json.NewEncoder(w).Encode(struct {
+		Code    int      `json:"code"`
+		Message string   `json:"message"`
+		Details []string `json:"details"`
+	}{code, msg, []string{}})
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mockserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+func setup(t *testing.T) *insights.Client {
+	t.Helper()
+	d, err := Load("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(New(d))
+	t.Cleanup(server.Close)
+	client := insights.NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/v3/")
+	return client
+}
+
+func TestServer(t *testing.T) {
+	client := setup(t)
+	ctx := context.Background()
+
+	// Names are looked up canonicalized, as deps.dev does.
+	p, _, err := client.GetPackage(ctx, "npm", "@scope/Pkg")
+	if err != nil {
+		t.Fatalf("GetPackage failed: %v", err)
+	}
+	wantPackage := &insights.Package{
+		PackageKey: insights.PackageKey{System: "NPM", Name: "@Scope/Pkg"},
+		Versions: []insights.Version{
+			{VersionKey: insights.VersionKey{System: "NPM", Name: "@Scope/Pkg", Version: "1.0.0"}, PublishedAt: "2024-01-02T03:04:05Z"},
+			{VersionKey: insights.VersionKey{System: "NPM", Name: "@Scope/Pkg", Version: "1.1.0"}, PublishedAt: "2024-02-02T03:04:05Z", IsDefault: true},
+		},
+	}
+	if diff := cmp.Diff(wantPackage, p); diff != "" {
+		t.Errorf("GetPackage mismatch (-want +got):\n%s", diff)
+	}
+
+	v, _, err := client.GetVersion(ctx, "NPM", "@scope/Pkg", "1.1.0")
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if len(v.AdvisoryKeys) != 1 || !v.IsDefault {
+		t.Errorf("GetVersion = %+v; want the default version with an advisory", v)
+	}
+	a, _, err := client.GetAdvisory(ctx, v.AdvisoryKeys[0].ID)
+	if err != nil || a.CVSS3Score != 7.5 {
+		t.Errorf("GetAdvisory = %+v, %v; want a score of 7.5", a, err)
+	}
+
+	g, _, err := client.GetDependencies(ctx, "NPM", "@scope/Pkg", "1.1.0")
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 || g.Edges[0].Requirement != "^1.3.0" {
+		t.Errorf("GetDependencies = %+v", g)
+	}
+
+	dep, _, err := client.GetDependents(ctx, "NPM", "@scope/Pkg", "1.1.0")
+	if err != nil || dep.DependentCount != 3 {
+		t.Errorf("GetDependents = %+v, %v; want 3 dependents", dep, err)
+	}
+
+	pr, _, err := client.GetProject(ctx, "github.com/Scope/pkg")
+	if err != nil || pr.StarsCount != 42 {
+		t.Errorf("GetProject = %+v, %v; want 42 stars", pr, err)
+	}
+	pv, _, err := client.GetProjectPackageVersions(ctx, "github.com/scope/pkg")
+	if err != nil {
+		t.Fatalf("GetProjectPackageVersions failed: %v", err)
+	}
+	if len(pv.Versions) != 1 || pv.Versions[0].VersionKey.Version != "1.0.0" || pv.Versions[0].RelationType != "SOURCE_REPO" {
+		t.Errorf("GetProjectPackageVersions = %+v; want version 1.0.0", pv)
+	}
+
+	q, _, err := client.Query(ctx, &insights.QueryOptions{HashType: "SHA1", HashValue: "c2hhMQ=="})
+	if err != nil || len(q.Results) != 1 || q.Results[0].Version.VersionKey.Version != "1.0.0" {
+		t.Errorf("Query by hash = %+v, %v; want version 1.0.0", q, err)
+	}
+	q, _, err = client.Query(ctx, &insights.QueryOptions{System: "NPM", Name: "@scope/Pkg", Version: "1.1.0"})
+	if err != nil || len(q.Results) != 1 {
+		t.Errorf("Query by version = %+v, %v; want one result", q, err)
+	}
+
+	pl, _, err := client.PurlLookup(ctx, "pkg:npm/%40scope/Pkg@1.1.0")
+	if err != nil || pl.Version == nil || !pl.Version.IsDefault {
+		t.Errorf("PurlLookup of a version = %+v, %v; want the default version", pl, err)
+	}
+	pl, _, err = client.PurlLookup(ctx, "pkg:npm/%40scope/Pkg")
+	if err != nil || pl.Package == nil || len(pl.Package.Versions) != 2 {
+		t.Errorf("PurlLookup of a package = %+v, %v; want 2 versions", pl, err)
+	}
+	pls, err := client.PurlLookupBatch(ctx, []string{"pkg:npm/%40scope/Pkg@1.0.0", "pkg:npm/%40scope/Pkg@9.9.9"})
+	if len(pls) != 2 || pls[0] == nil || pls[0].Version.VersionKey.Version != "1.0.0" || !errors.Is(err, insights.ErrNotFound) {
+		t.Errorf("PurlLookupBatch = %+v, %v; want version 1.0.0 and a not found error", pls, err)
+	}
+}
+
+func TestServerNotFound(t *testing.T) {
+	client := setup(t)
+	ctx := context.Background()
+	for name, f := range map[string]func() error{
+		"package":  func() error { _, _, err := client.GetPackage(ctx, "NPM", "nope"); return err },
+		"version":  func() error { _, _, err := client.GetVersion(ctx, "NPM", "@scope/pkg", "9.9.9"); return err },
+		"graph":    func() error { _, _, err := client.GetDependencies(ctx, "NPM", "@scope/pkg", "1.0.0"); return err },
+		"project":  func() error { _, _, err := client.GetProject(ctx, "github.com/nope/nope"); return err },
+		"advisory": func() error { _, _, err := client.GetAdvisory(ctx, "GHSA-nope"); return err },
+	} {
+		var apiErr *insights.APIError
+		if err := f(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: got %v; want a 404 APIError", name, err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CanonicalName returns the name of a package of the given system in the
+// form deps.dev knows it by, so that names are found as users type them.
+// The client canonicalizes the names given to its methods.
+//
+//   - PyPI names are normalized as in PEP 503: lower case, with runs of
+//     hyphens, underscores, and dots replaced by a single hyphen.
+//   - npm scopes are lower case, so "@Types/node" is "@types/node". The
+//     rest of the name is kept, as old packages may have upper case names.
+//   - Go module paths in the case-encoded form of module proxies and the
+//     module cache, where "!b" stands for "B", are decoded.
+//
+// Surrounding white space is removed for all systems.
+func CanonicalName(system, name string) string {
+	name = strings.TrimSpace(name)
+	switch strings.ToUpper(system) {
+	case "PYPI":
+		return normalizePyPI(name)
+	case "NPM":
+		if scope, rest, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+			return strings.ToLower(scope) + "/" + rest
+		}
+	case "GO":
+		if p, ok := unescapeModulePath(name); ok {
+			return p
+		}
+	}
+	return name
+}
+
+// normalizePyPI normalizes a PyPI project name as in PEP 503.
+func normalizePyPI(name string) string {
+	var sb strings.Builder
+	sep := false
+	for _, r := range name {
+		if r == '-' || r == '_' || r == '.' {
+			if !sep {
+				sb.WriteByte('-')
+			}
+			sep = true
+			continue
+		}
+		sep = false
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// unescapeModulePath decodes a case-encoded Go module path, reporting
+// whether it is one: whether it has no upper case letters, and each "!"
+// is followed by a lower case letter.
+func unescapeModulePath(p string) (string, bool) {
+	if !strings.Contains(p, "!") {
+		return p, false
+	}
+	var sb strings.Builder
+	bang := false
+	for _, r := range p {
+		switch {
+		case unicode.IsUpper(r):
+			return p, false
+		case bang:
+			if r < 'a' || r > 'z' {
+				return p, false
+			}
+			sb.WriteRune(unicode.ToUpper(r))
+			bang = false
+		case r == '!':
+			bang = true
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if bang {
+		return p, false
+	}
+	return sb.String(), true
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCanonicalName(t *testing.T) {
+	testCases := []struct {
+		system, name, want string
+	}{
+		{"PYPI", "Django", "django"},
+		{"pypi", "zope.interface", "zope-interface"},
+		{"PYPI", "typing__extensions", "typing-extensions"},
+		{"PYPI", "Flask-SQLAlchemy", "flask-sqlalchemy"},
+		{"PYPI", " requests ", "requests"},
+		{"NPM", "@Types/node", "@types/node"},
+		{"NPM", "JSONStream", "JSONStream"},
+		{"npm", "react", "react"},
+		{"GO", "github.com/!burnt!sushi/toml", "github.com/BurntSushi/toml"},
+		{"GO", "github.com/BurntSushi/toml", "github.com/BurntSushi/toml"},
+		{"GO", "example.com/!", "example.com/!"},
+		{"GO", "example.com/!Foo", "example.com/!Foo"},
+		{"MAVEN", "org.apache.commons:commons-lang3", "org.apache.commons:commons-lang3"},
+		{"CARGO", "serde_json", "serde_json"},
+	}
+	for _, tc := range testCases {
+		if got := CanonicalName(tc.system, tc.name); got != tc.want {
+			t.Errorf("CanonicalName(%q, %q) = %q, want %q", tc.system, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestGetPackageCanonicalName(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/pypi/packages/zope-interface", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packageKey":{"system":"PYPI","name":"zope-interface"}}`)
+	})
+	if _, _, err := client.GetPackage(context.Background(), "pypi", "Zope.Interface"); err != nil {
+		t.Errorf("GetPackage of a non-canonical name: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package policy checks the dependencies of a project, as audited by the
+// audit package, against rules about their licenses, vulnerabilities, and
+// supply chain, so that CI pipelines can gate changes on them.
+//
+// A policy is written in YAML:
+//
+//	allow_licenses: [MIT, Apache-2.0, BSD-3-Clause, ISC]
+//	deny_licenses: [AGPL-3.0]
+//	max_cvss3: 6.9
+//	ignore_advisories: [GHSA-xxxx-xxxx-xxxx]
+//	min_scorecard: 4
+//	require_provenance: false
+//	deny_packages:
+//	  - system: npm
+//	    name: event-stream
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/scan"
+	"gopkg.in/yaml.v3"
+)
+
+// Rules, as reported in violations.
+const (
+	RuleLicense    = "license"
+	RuleAdvisory   = "advisory"
+	RuleScorecard  = "scorecard"
+	RuleProvenance = "provenance"
+	RulePackage    = "package"
+)
+
+// Policy holds the rules dependencies must follow. The zero value allows
+// everything.
+type Policy struct {
+	// The licenses allowed, as SPDX identifiers. If empty, any license not
+	// denied is allowed; otherwise, package versions of unknown license
+	// are violations too.
+	AllowLicenses []string `yaml:"allow_licenses"`
+
+	// The licenses denied, as SPDX identifiers.
+	DenyLicenses []string `yaml:"deny_licenses"`
+
+	// The greatest CVSS v3 score allowed for the advisories affecting a
+	// package version. If nil, advisories are not checked; if zero, any
+	// advisory is a violation. Advisories without a score are violations
+	// unless ignored.
+	MaxCVSS3 *float32 `yaml:"max_cvss3"`
+
+	// The IDs of advisories that are not violations, for example because
+	// they do not apply to how the project uses the package.
+	IgnoreAdvisories []string `yaml:"ignore_advisories"`
+
+	// The lowest OpenSSF Scorecard score allowed for the source repository
+	// of a package version. Package versions without a scorecard are not
+	// checked. If zero, scorecards are not checked.
+	MinScorecard float64 `yaml:"min_scorecard"`
+
+	// Whether package versions must have attestations of their
+	// provenance.
+	RequireProvenance bool `yaml:"require_provenance"`
+
+	// The packages denied, in any version.
+	DenyPackages []Package `yaml:"deny_packages"`
+}
+
+// Package names a package in a policy.
+type Package struct {
+	// The package management system, compared case-insensitively.
+	System string `yaml:"system"`
+
+	// The name of the package.
+	Name string `yaml:"name"`
+}
+
+// Violation is a dependency breaking a rule of a policy.
+type Violation struct {
+	// The dependency breaking the rule.
+	Dependency scan.Dependency
+
+	// The rule broken, one of the Rule constants.
+	Rule string
+
+	// Describes the violation.
+	Message string
+}
+
+// Parse parses a policy written in YAML. Unknown fields are errors, so that
+// a misspelled rule is not silently ignored.
+func Parse(data []byte) (*Policy, error) {
+	p := new(Policy)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	// An empty policy allows everything.
+	if err := dec.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("policy: %v", err)
+	}
+	return p, nil
+}
+
+// Load reads and parses the policy in file.
+func Load(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return p, nil
+}
+
+// Check returns the violations of the policy by the audited dependencies
+// in r, in the order of r.Packages. Dependencies that could not be audited
+// are not checked; callers must report them, as they may violate it.
+func (p *Policy) Check(r *audit.Result) []Violation {
+	var vs []Violation
+	for _, pkg := range r.Packages {
+		if pkg.Error != "" {
+			continue
+		}
+		for _, v := range p.check(pkg) {
+			vs = append(vs, Violation{Dependency: pkg.Dependency, Rule: v.rule, Message: v.msg})
+		}
+	}
+	return vs
+}
+
+type violation struct {
+	rule, msg string
+}
+
+// check returns the rules broken by pkg.
+func (p *Policy) check(pkg audit.Package) []violation {
+	var vs []violation
+	k := pkg.Dependency.VersionKey
+	for _, d := range p.DenyPackages {
+		if strings.EqualFold(d.System, k.System) && d.Name == k.Name {
+			vs = append(vs, violation{RulePackage, fmt.Sprintf("package %s is denied", k.Name)})
+		}
+	}
+
+	if len(p.AllowLicenses) > 0 && len(pkg.Licenses) == 0 {
+		vs = append(vs, violation{RuleLicense, "license is unknown"})
+	}
+	for _, l := range pkg.Licenses {
+		switch {
+		case len(p.DenyLicenses) > 0 && !satisfies(l, func(id string) bool { return !contains(p.DenyLicenses, id) }):
+			vs = append(vs, violation{RuleLicense, fmt.Sprintf("license %s is denied", l)})
+		case len(p.AllowLicenses) > 0 && !satisfies(l, func(id string) bool { return contains(p.AllowLicenses, id) }):
+			vs = append(vs, violation{RuleLicense, fmt.Sprintf("license %s is not allowed", l)})
+		}
+	}
+
+	if p.MaxCVSS3 != nil {
+		for _, a := range pkg.Advisories {
+			id := a.AdvisoryKey.ID
+			switch {
+			case contains(p.IgnoreAdvisories, id):
+			case a.CVSS3Score == 0:
+				// The severity of advisories without a score, such as
+				// those whose details could not be fetched, is unknown.
+				msg := id + " (no CVSS score)"
+				if a.Title != "" {
+					msg += ": " + a.Title
+				}
+				vs = append(vs, violation{RuleAdvisory, msg})
+			case a.CVSS3Score > *p.MaxCVSS3:
+				vs = append(vs, violation{RuleAdvisory, fmt.Sprintf("%s (CVSS %.1f): %s", id, a.CVSS3Score, a.Title)})
+			}
+		}
+	}
+
+	if sc := pkg.Scorecard; p.MinScorecard > 0 && sc != nil && sc.OverallScore < p.MinScorecard {
+		vs = append(vs, violation{RuleScorecard, fmt.Sprintf("scorecard of %s is %.1f, below %.1f", pkg.SourceRepository, sc.OverallScore, p.MinScorecard)})
+	}
+	if p.RequireProvenance && !pkg.Provenance.Attested {
+		vs = append(vs, violation{RuleProvenance, "no provenance attestation"})
+	}
+	return vs
+}
+
+// satisfies reports whether the SPDX license expression l can be satisfied
+// with licenses for which ok returns true: whether in one of its
+// alternatives, separated by OR, ok holds for all the licenses. Parentheses
+// are ignored, and exceptions introduced by WITH are taken as part of the
+// license they follow.
+func satisfies(l string, ok func(id string) bool) bool {
+	l = strings.NewReplacer("(", " ", ")", " ").Replace(l)
+	for _, alt := range splitOperator(l, "OR") {
+		all := true
+		for _, id := range splitOperator(alt, "AND") {
+			id, _, _ = strings.Cut(id, " WITH ")
+			if !ok(strings.TrimSpace(id)) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// splitOperator splits the license expression s around the operator op.
+func splitOperator(s, op string) []string {
+	var parts []string
+	var cur []string
+	for _, f := range strings.Fields(s) {
+		if strings.EqualFold(f, op) {
+			parts = append(parts, strings.Join(cur, " "))
+			cur = nil
+			continue
+		}
+		cur = append(cur, f)
+	}
+	return append(parts, strings.Join(cur, " "))
+}
+
+// contains reports whether list contains s, ignoring case.
+func contains(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(e string) bool { return strings.EqualFold(e, s) })
+}
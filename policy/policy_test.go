@@ -0,0 +1,93 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/scan"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(`
+allow_licenses: [MIT]
+max_cvss3: 6.9
+deny_packages:
+  - system: npm
+    name: event-stream
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	max := float32(6.9)
+	want := &Policy{AllowLicenses: []string{"MIT"}, MaxCVSS3: &max, DenyPackages: []Package{{System: "npm", Name: "event-stream"}}}
+	if diff := cmp.Diff(want, p); diff != "" {
+		t.Errorf("Parse mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := Parse(nil); err != nil {
+		t.Errorf("Parse of an empty policy: %v", err)
+	}
+	if _, err := Parse([]byte("allow_license: [MIT]\n")); err == nil {
+		t.Error("Parse accepted an unknown field")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dep := func(name string) scan.Dependency {
+		return scan.Dependency{VersionKey: insights.VersionKey{System: "NPM", Name: name, Version: "1.0.0"}}
+	}
+	adv := func(id string, score float32) *insights.Advisory {
+		return &insights.Advisory{AdvisoryKey: insights.AdvisoryKey{ID: id}, CVSS3Score: score, Title: "bad"}
+	}
+	r := &audit.Result{Packages: []audit.Package{
+		{Dependency: dep("ok"), Licenses: []string{"MIT"}, Provenance: audit.Provenance{Attested: true}},
+		{Dependency: dep("dual"), Licenses: []string{"(MIT OR GPL-3.0)"}, Provenance: audit.Provenance{Attested: true}},
+		{Dependency: dep("gpl"), Licenses: []string{"GPL-3.0", "MIT AND Apache-2.0"}, Provenance: audit.Provenance{Attested: true}},
+		{Dependency: dep("unknown"), Provenance: audit.Provenance{Attested: true}},
+		{Dependency: dep("vulnerable"), Licenses: []string{"MIT"}, Advisories: []*insights.Advisory{adv("GHSA-1", 9.8), adv("GHSA-2", 5), adv("GHSA-3", 9.1), adv("GHSA-4", 0), adv("GHSA-5", 0)}, Provenance: audit.Provenance{Attested: true}},
+		{Dependency: dep("unattested"), Licenses: []string{"MIT"}, Scorecard: &insights.Scorecard{OverallScore: 2.5}, SourceRepository: "github.com/x/unattested"},
+		{Dependency: dep("event-stream"), Licenses: []string{"MIT"}, Provenance: audit.Provenance{Attested: true}},
+		{Dependency: dep("missing"), Error: "not found"},
+	}}
+	max := float32(6.9)
+	p := &Policy{
+		AllowLicenses:     []string{"mit", "Apache-2.0"},
+		DenyLicenses:      []string{"GPL-3.0"},
+		MaxCVSS3:          &max,
+		IgnoreAdvisories:  []string{"GHSA-3", "GHSA-5"},
+		MinScorecard:      4,
+		RequireProvenance: true,
+		DenyPackages:      []Package{{System: "npm", Name: "event-stream"}},
+	}
+	want := []Violation{
+		{Dependency: dep("gpl"), Rule: RuleLicense, Message: "license GPL-3.0 is denied"},
+		{Dependency: dep("unknown"), Rule: RuleLicense, Message: "license is unknown"},
+		{Dependency: dep("vulnerable"), Rule: RuleAdvisory, Message: "GHSA-1 (CVSS 9.8): bad"},
+		{Dependency: dep("vulnerable"), Rule: RuleAdvisory, Message: "GHSA-4 (no CVSS score): bad"},
+		{Dependency: dep("unattested"), Rule: RuleScorecard, Message: "scorecard of github.com/x/unattested is 2.5, below 4.0"},
+		{Dependency: dep("unattested"), Rule: RuleProvenance, Message: "no provenance attestation"},
+		{Dependency: dep("event-stream"), Rule: RulePackage, Message: "package event-stream is denied"},
+	}
+	if diff := cmp.Diff(want, p.Check(r)); diff != "" {
+		t.Errorf("Check mismatch (-want +got):\n%s", diff)
+	}
+
+	// An advisory whose details could not be fetched has only its key.
+	unscored := &audit.Result{Packages: []audit.Package{
+		{Dependency: dep("a"), Advisories: []*insights.Advisory{{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-6"}}}},
+	}}
+	want = []Violation{{Dependency: dep("a"), Rule: RuleAdvisory, Message: "GHSA-6 (no CVSS score)"}}
+	if diff := cmp.Diff(want, (&Policy{MaxCVSS3: &max}).Check(unscored)); diff != "" {
+		t.Errorf("Check of an unscored advisory mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := new(Policy).Check(r); len(got) != 0 {
+		t.Errorf("the zero Policy reported violations: %v", got)
+	}
+}
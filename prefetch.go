@@ -0,0 +1,120 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// PrefetchOptions specifies what Prefetch fetches besides the versions.
+type PrefetchOptions struct {
+	// The maximum number of requests in flight at once. If zero, 4 is used.
+	Concurrency int
+
+	// Fetch the package of each version, with the list of its versions.
+	Packages bool
+
+	// Fetch the resolved dependency graph of each version.
+	Dependencies bool
+
+	// Fetch the projects related to each version, such as its source
+	// repository.
+	Projects bool
+}
+
+// Prefetch fetches the given package versions, and whatever else opts asks
+// for, so that the responses are in the client's Cache when they are
+// requested later. It lets interactive tools warm up the cache in the
+// background.
+//
+// Prefetch fetches as much as it can: failed requests do not stop it, and
+// their errors are returned in a *BatchError, by version, package, or
+// project. It stops early only if ctx is done. It reports an error if the
+// client has no Cache.
+func (c *Client) Prefetch(ctx context.Context, keys []VersionKey, opts *PrefetchOptions) error {
+	if c.Cache == nil {
+		return errors.New("insights: Prefetch requires a Cache")
+	}
+	if opts == nil {
+		opts = new(PrefetchOptions)
+	}
+	n := opts.Concurrency
+	if n <= 0 {
+		n = 4
+	}
+
+	var (
+		mu       sync.Mutex
+		batch    Batch
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, n)
+		packages = make(map[PackageKey]bool)
+		projects = make(map[string]bool)
+	)
+	// fetch runs f for key in a new goroutine once fewer than n are
+	// running.
+	fetch := func(key any, f func() error) {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := f(); err != nil {
+				batch.Fail(key, err)
+			}
+		}()
+	}
+
+	for _, k := range keys {
+		fetch(k, func() error {
+			v, _, err := c.GetVersion(ctx, k.System, k.Name, k.Version)
+			if err != nil || !opts.Projects {
+				return err
+			}
+			for _, p := range v.RelatedProjects {
+				id := p.ProjectKey.ID
+				mu.Lock()
+				seen := projects[id]
+				projects[id] = true
+				mu.Unlock()
+				if !seen {
+					// Fetched in this goroutine, as waiting for a free slot
+					// here could deadlock.
+					if _, _, err := c.GetProject(ctx, id); err != nil {
+						batch.Fail(id, err)
+					}
+				}
+			}
+			return nil
+		})
+		if pk := (PackageKey{k.System, k.Name}); opts.Packages && !packages[pk] {
+			packages[pk] = true
+			fetch(pk, func() error {
+				_, _, err := c.GetPackage(ctx, k.System, k.Name)
+				return err
+			})
+		}
+		if opts.Dependencies {
+			fetch(k, func() error {
+				if _, _, err := c.GetDependencies(ctx, k.System, k.Name, k.Version); err != nil {
+					return fmt.Errorf("dependencies: %w", err)
+				}
+				return nil
+			})
+		}
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return errors.Join(batch.Err(), err)
+	}
+	return batch.Err()
+}
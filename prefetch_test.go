@@ -0,0 +1,71 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPrefetch(t *testing.T) {
+	client, mux := setup(t)
+	client.Cache = &memCache{m: make(map[string][]byte)}
+
+	var requests atomic.Int32
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"},"relatedProjects":[{"projectKey":{"id":"github.com/x/a"}}]}`)
+	})
+	mux.HandleFunc("/systems/npm/packages/a/versions/2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"a","version":"2.0.0"},"relatedProjects":[{"projectKey":{"id":"github.com/x/a"}}]}`)
+	})
+	mux.HandleFunc("/systems/npm/packages/a", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"packageKey":{"system":"NPM","name":"a"}}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fx%2Fa", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"projectKey":{"id":"github.com/x/a"}}`)
+	})
+
+	keys := []VersionKey{
+		{System: "npm", Name: "a", Version: "1.0.0"},
+		{System: "npm", Name: "a", Version: "2.0.0"},
+		{System: "npm", Name: "missing", Version: "1.0.0"},
+	}
+	err := client.Prefetch(context.Background(), keys, &PrefetchOptions{Concurrency: 2, Packages: true, Projects: true})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Prefetch returned %v, want a not found error", err)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("Prefetch sent %d requests, want 4", got)
+	}
+
+	// Everything prefetched is now served from the cache.
+	client.Offline = true
+	ctx := context.Background()
+	if _, _, err := client.GetVersion(ctx, "npm", "a", "2.0.0"); err != nil {
+		t.Errorf("GetVersion after Prefetch: %v", err)
+	}
+	if _, _, err := client.GetPackage(ctx, "npm", "a"); err != nil {
+		t.Errorf("GetPackage after Prefetch: %v", err)
+	}
+	if _, _, err := client.GetProject(ctx, "github.com/x/a"); err != nil {
+		t.Errorf("GetProject after Prefetch: %v", err)
+	}
+}
+
+func TestPrefetchNoCache(t *testing.T) {
+	client, _ := setup(t)
+	if err := client.Prefetch(context.Background(), nil, nil); err == nil {
+		t.Error("Prefetch without a cache succeeded, want error")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package prommetrics implements insights.Metrics with Prometheus
+// collectors, so that operators of services calling deps.dev can monitor
+// how many calls they make, how long they take, and how many fail.
+//
+// The metrics, labeled by endpoint, are:
+//
+//	insights_api_calls_total{endpoint,code}      calls, by HTTP status code ("error" if none)
+//	insights_api_call_errors_total{endpoint}     failed calls
+//	insights_api_cache_hits_total{endpoint}      calls answered from the cache
+//	insights_api_call_duration_seconds{endpoint} call latencies, a histogram
+package prommetrics
+
+import (
+	"strconv"
+
+	"github.com/franoliveto/insights"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is an insights.Metrics recording calls in Prometheus collectors.
+type Metrics struct {
+	calls    *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	hits     *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// New returns Metrics whose collectors are registered with reg, or with
+// prometheus.DefaultRegisterer if reg is nil.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	m := &Metrics{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "insights",
+			Subsystem: "api",
+			Name:      "calls_total",
+			Help:      "Calls made to the deps.dev API, by endpoint and HTTP status code.",
+		}, []string{"endpoint", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "insights",
+			Subsystem: "api",
+			Name:      "call_errors_total",
+			Help:      "Calls made to the deps.dev API that failed, by endpoint.",
+		}, []string{"endpoint"}),
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "insights",
+			Subsystem: "api",
+			Name:      "cache_hits_total",
+			Help:      "Calls made to the deps.dev API answered from the cache, by endpoint.",
+		}, []string{"endpoint"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "insights",
+			Subsystem: "api",
+			Name:      "call_duration_seconds",
+			Help:      "Duration of the calls made to the deps.dev API, including retries, by endpoint.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{"endpoint"}),
+	}
+	for _, c := range []prometheus.Collector{m.calls, m.errors, m.hits, m.duration} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveCall records c.
+func (m *Metrics) ObserveCall(c *insights.Call) {
+	e := string(c.Endpoint)
+	code := "error"
+	if c.StatusCode != 0 {
+		code = strconv.Itoa(c.StatusCode)
+	}
+	m.calls.WithLabelValues(e, code).Inc()
+	if c.Err != nil {
+		m.errors.WithLabelValues(e).Inc()
+	}
+	if c.FromCache {
+		m.hits.WithLabelValues(e).Inc()
+	}
+	m.duration.WithLabelValues(e).Observe(c.Duration.Seconds())
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prommetrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/franoliveto/insights"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m, err := New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := []*insights.Call{
+		{Endpoint: insights.EndpointGetVersion, StatusCode: 200, Duration: 10 * time.Millisecond},
+		{Endpoint: insights.EndpointGetVersion, StatusCode: 200, FromCache: true},
+		{Endpoint: insights.EndpointGetVersion, StatusCode: 404, Err: errors.New("404 not found")},
+		{Endpoint: insights.EndpointQuery, Err: errors.New("connection refused")},
+	}
+	for _, c := range calls {
+		m.ObserveCall(c)
+	}
+
+	for _, tc := range []struct {
+		c    prometheus.Collector
+		want float64
+	}{
+		{m.calls.WithLabelValues("GetVersion", "200"), 2},
+		{m.calls.WithLabelValues("GetVersion", "404"), 1},
+		{m.calls.WithLabelValues("Query", "error"), 1},
+		{m.errors.WithLabelValues("GetVersion"), 1},
+		{m.errors.WithLabelValues("Query"), 1},
+		{m.hits.WithLabelValues("GetVersion"), 1},
+	} {
+		if got := testutil.ToFloat64(tc.c); got != tc.want {
+			t.Errorf("%v = %v, want %v", tc.c.(prometheus.Metric).Desc(), got, tc.want)
+		}
+	}
+	if n := testutil.CollectAndCount(m.duration); n != 2 {
+		t.Errorf("duration has %d series, want 2", n)
+	}
+	if _, err := reg.Gather(); err != nil {
+		t.Errorf("Gather failed: %v", err)
+	}
+
+	// The collectors cannot be registered twice.
+	if _, err := New(reg); err == nil {
+		t.Error("New registered the collectors twice")
+	}
+}
@@ -0,0 +1,477 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoconv converts between the types of the insights package and
+// the protocol buffer messages of the official deps.dev v3 API, so that data
+// fetched with insights can be passed to code using the gRPC API and vice
+// versa.
+//
+// Enumerations are converted by name: a string that names no value of the
+// enumeration converts to its zero value, and the zero value converts to
+// the empty string. Timestamps are converted from and to RFC 3339 strings;
+// strings that cannot be parsed convert to a nil timestamp.
+package protoconv
+
+import (
+	"strings"
+	"time"
+
+	pb "deps.dev/api/v3"
+	"github.com/franoliveto/insights"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PackageKeyToProto converts k to a deps.dev PackageKey message.
+func PackageKeyToProto(k insights.PackageKey) *pb.PackageKey {
+	return &pb.PackageKey{System: systemToProto(k.System), Name: k.Name}
+}
+
+// PackageKeyFromProto converts the deps.dev PackageKey message k.
+func PackageKeyFromProto(k *pb.PackageKey) insights.PackageKey {
+	return insights.PackageKey{System: systemFromProto(k.GetSystem()), Name: k.GetName()}
+}
+
+// VersionKeyToProto converts k to a deps.dev VersionKey message.
+func VersionKeyToProto(k insights.VersionKey) *pb.VersionKey {
+	return &pb.VersionKey{System: systemToProto(k.System), Name: k.Name, Version: k.Version}
+}
+
+// VersionKeyFromProto converts the deps.dev VersionKey message k.
+func VersionKeyFromProto(k *pb.VersionKey) insights.VersionKey {
+	return insights.VersionKey{
+		System:  systemFromProto(k.GetSystem()),
+		Name:    k.GetName(),
+		Version: k.GetVersion(),
+	}
+}
+
+// ProjectKeyToProto converts k to a deps.dev ProjectKey message.
+func ProjectKeyToProto(k insights.ProjectKey) *pb.ProjectKey {
+	return &pb.ProjectKey{Id: k.ID}
+}
+
+// ProjectKeyFromProto converts the deps.dev ProjectKey message k.
+func ProjectKeyFromProto(k *pb.ProjectKey) insights.ProjectKey {
+	return insights.ProjectKey{ID: k.GetId()}
+}
+
+// AdvisoryKeyToProto converts k to a deps.dev AdvisoryKey message.
+func AdvisoryKeyToProto(k insights.AdvisoryKey) *pb.AdvisoryKey {
+	return &pb.AdvisoryKey{Id: k.ID}
+}
+
+// AdvisoryKeyFromProto converts the deps.dev AdvisoryKey message k.
+func AdvisoryKeyFromProto(k *pb.AdvisoryKey) insights.AdvisoryKey {
+	return insights.AdvisoryKey{ID: k.GetId()}
+}
+
+// PackageToProto converts p to a deps.dev Package message. Only the key,
+// publication date, and default and deprecated flags of each version are
+// kept, as the message holds no more.
+func PackageToProto(p *insights.Package) *pb.Package {
+	m := &pb.Package{PackageKey: PackageKeyToProto(p.PackageKey)}
+	for _, v := range p.Versions {
+		m.Versions = append(m.Versions, &pb.Package_Version{
+			VersionKey:   VersionKeyToProto(v.VersionKey),
+			PublishedAt:  timeToProto(v.PublishedAt),
+			IsDefault:    v.IsDefault,
+			IsDeprecated: v.IsDeprecated,
+		})
+	}
+	return m
+}
+
+// PackageFromProto converts the deps.dev Package message m.
+func PackageFromProto(m *pb.Package) *insights.Package {
+	p := &insights.Package{PackageKey: PackageKeyFromProto(m.GetPackageKey())}
+	for _, v := range m.GetVersions() {
+		p.Versions = append(p.Versions, insights.Version{
+			VersionKey:   VersionKeyFromProto(v.GetVersionKey()),
+			PublishedAt:  timeFromProto(v.GetPublishedAt()),
+			IsDefault:    v.GetIsDefault(),
+			IsDeprecated: v.GetIsDeprecated(),
+		})
+	}
+	return p
+}
+
+// VersionToProto converts v to a deps.dev Version message.
+func VersionToProto(v *insights.Version) *pb.Version {
+	m := &pb.Version{
+		VersionKey:      VersionKeyToProto(v.VersionKey),
+		PublishedAt:     timeToProto(v.PublishedAt),
+		IsDefault:       v.IsDefault,
+		IsDeprecated:    v.IsDeprecated,
+		Licenses:        v.Licenses,
+		Registries:      v.Registries,
+		SlsaProvenances: slsaProvenancesToProto(v.SLSAProvenances),
+		Attestations:    attestationsToProto(v.Attestations),
+	}
+	for _, k := range v.AdvisoryKeys {
+		m.AdvisoryKeys = append(m.AdvisoryKeys, AdvisoryKeyToProto(k))
+	}
+	for _, l := range v.Links {
+		m.Links = append(m.Links, &pb.Link{Label: l.Label, Url: l.URL})
+	}
+	for _, p := range v.RelatedProjects {
+		m.RelatedProjects = append(m.RelatedProjects, &pb.Version_Project{
+			ProjectKey:         ProjectKeyToProto(p.ProjectKey),
+			RelationProvenance: pb.ProjectRelationProvenance(pb.ProjectRelationProvenance_value[p.RelationProvenance]),
+			RelationType:       pb.ProjectRelationType(pb.ProjectRelationType_value[p.RelationType]),
+		})
+	}
+	return m
+}
+
+// VersionFromProto converts the deps.dev Version message m.
+func VersionFromProto(m *pb.Version) *insights.Version {
+	v := &insights.Version{
+		VersionKey:      VersionKeyFromProto(m.GetVersionKey()),
+		PublishedAt:     timeFromProto(m.GetPublishedAt()),
+		IsDefault:       m.GetIsDefault(),
+		IsDeprecated:    m.GetIsDeprecated(),
+		Licenses:        m.GetLicenses(),
+		Registries:      m.GetRegistries(),
+		SLSAProvenances: slsaProvenancesFromProto(m.GetSlsaProvenances()),
+		Attestations:    attestationsFromProto(m.GetAttestations()),
+	}
+	for _, k := range m.GetAdvisoryKeys() {
+		v.AdvisoryKeys = append(v.AdvisoryKeys, AdvisoryKeyFromProto(k))
+	}
+	for _, l := range m.GetLinks() {
+		v.Links = append(v.Links, insights.Link{Label: l.GetLabel(), URL: l.GetUrl()})
+	}
+	for _, p := range m.GetRelatedProjects() {
+		v.RelatedProjects = append(v.RelatedProjects, struct {
+			ProjectKey         insights.ProjectKey
+			RelationProvenance string
+			RelationType       string
+		}{
+			ProjectKey:         ProjectKeyFromProto(p.GetProjectKey()),
+			RelationProvenance: enumFromProto(p.GetRelationProvenance(), pb.ProjectRelationProvenance_UNKNOWN_PROJECT_RELATION_PROVENANCE),
+			RelationType:       enumFromProto(p.GetRelationType(), pb.ProjectRelationType_UNKNOWN_PROJECT_RELATION_TYPE),
+		})
+	}
+	return v
+}
+
+// DependenciesToProto converts d to a deps.dev Dependencies message.
+func DependenciesToProto(d *insights.Dependencies) *pb.Dependencies {
+	m := &pb.Dependencies{Error: d.Error}
+	for _, n := range d.Nodes {
+		m.Nodes = append(m.Nodes, &pb.Dependencies_Node{
+			VersionKey: VersionKeyToProto(n.VersionKey),
+			Bundled:    n.Bundled,
+			Relation:   pb.DependencyRelation(pb.DependencyRelation_value[n.Relation]),
+			Errors:     n.Errors,
+		})
+	}
+	for _, e := range d.Edges {
+		m.Edges = append(m.Edges, &pb.Dependencies_Edge{
+			FromNode:    uint32(e.FromNode),
+			ToNode:      uint32(e.ToNode),
+			Requirement: e.Requirement,
+		})
+	}
+	return m
+}
+
+// DependenciesFromProto converts the deps.dev Dependencies message m.
+func DependenciesFromProto(m *pb.Dependencies) *insights.Dependencies {
+	d := &insights.Dependencies{Error: m.GetError()}
+	for _, n := range m.GetNodes() {
+		d.Nodes = append(d.Nodes, insights.Node{
+			VersionKey: VersionKeyFromProto(n.GetVersionKey()),
+			Bundled:    n.GetBundled(),
+			Relation:   enumFromProto(n.GetRelation(), pb.DependencyRelation_DEPENDENCY_RELATION_UNSPECIFIED),
+			Errors:     n.GetErrors(),
+		})
+	}
+	for _, e := range m.GetEdges() {
+		d.Edges = append(d.Edges, insights.Edge{
+			FromNode:    int(e.GetFromNode()),
+			ToNode:      int(e.GetToNode()),
+			Requirement: e.GetRequirement(),
+		})
+	}
+	return d
+}
+
+// ProjectToProto converts p to a deps.dev Project message.
+func ProjectToProto(p *insights.Project) *pb.Project {
+	m := &pb.Project{
+		ProjectKey:      ProjectKeyToProto(p.ProjectKey),
+		OpenIssuesCount: int32(p.OpenIssuesCount),
+		StarsCount:      int32(p.StarsCount),
+		ForksCount:      int32(p.ForksCount),
+		License:         p.License,
+		Description:     p.Description,
+		Homepage:        p.Homepage,
+	}
+	if s := p.Scorecard; s.Date != "" || len(s.Checks) > 0 {
+		sc := &pb.Project_Scorecard{
+			Date: timeToProto(s.Date),
+			Repository: &pb.Project_Scorecard_Repository{
+				Name:   s.Repository.Name,
+				Commit: s.Repository.Commit,
+			},
+			Scorecard: &pb.Project_Scorecard_ScorecardDetails{
+				Version: s.Scorecard.Version,
+				Commit:  s.Scorecard.Commit,
+			},
+			OverallScore: float32(s.OverallScore),
+			Metadata:     s.Metadata,
+		}
+		for _, c := range s.Checks {
+			sc.Checks = append(sc.Checks, &pb.Project_Scorecard_Check{
+				Name: c.Name,
+				Documentation: &pb.Project_Scorecard_Check_Documentation{
+					ShortDescription: c.Documentation.ShortDescription,
+					Url:              c.Documentation.URL,
+				},
+				Score:   int32(c.Score),
+				Reason:  c.Reason,
+				Details: c.Details,
+			})
+		}
+		m.Scorecard = sc
+	}
+	if f := p.OSSFuzz; f != (insights.OSSFuzzDetails{}) {
+		m.OssFuzz = &pb.Project_OSSFuzzDetails{
+			LineCount:      int32(f.LineCount),
+			LineCoverCount: int32(f.LineCoverCount),
+			Date:           timeToProto(f.Date),
+			ConfigUrl:      f.ConfigURL,
+		}
+	}
+	return m
+}
+
+// ProjectFromProto converts the deps.dev Project message m.
+func ProjectFromProto(m *pb.Project) *insights.Project {
+	p := &insights.Project{
+		ProjectKey:      ProjectKeyFromProto(m.GetProjectKey()),
+		OpenIssuesCount: int(m.GetOpenIssuesCount()),
+		StarsCount:      int(m.GetStarsCount()),
+		ForksCount:      int(m.GetForksCount()),
+		License:         m.GetLicense(),
+		Description:     m.GetDescription(),
+		Homepage:        m.GetHomepage(),
+	}
+	if sc := m.GetScorecard(); sc != nil {
+		s := &p.Scorecard
+		s.Date = timeFromProto(sc.GetDate())
+		s.Repository.Name = sc.GetRepository().GetName()
+		s.Repository.Commit = sc.GetRepository().GetCommit()
+		s.Scorecard.Version = sc.GetScorecard().GetVersion()
+		s.Scorecard.Commit = sc.GetScorecard().GetCommit()
+		s.OverallScore = float64(sc.GetOverallScore())
+		s.Metadata = sc.GetMetadata()
+		if len(sc.GetChecks()) > 0 {
+			s.Checks = make([]struct {
+				Name          string
+				Documentation struct {
+					ShortDescription string
+					URL              string
+				}
+				Score   int
+				Reason  string
+				Details []string
+			}, len(sc.GetChecks()))
+		}
+		for i, c := range sc.GetChecks() {
+			check := &s.Checks[i]
+			check.Name = c.GetName()
+			check.Documentation.ShortDescription = c.GetDocumentation().GetShortDescription()
+			check.Documentation.URL = c.GetDocumentation().GetUrl()
+			check.Score = int(c.GetScore())
+			check.Reason = c.GetReason()
+			check.Details = c.GetDetails()
+		}
+	}
+	if f := m.GetOssFuzz(); f != nil {
+		p.OSSFuzz = insights.OSSFuzzDetails{
+			LineCount:      int(f.GetLineCount()),
+			LineCoverCount: int(f.GetLineCoverCount()),
+			Date:           timeFromProto(f.GetDate()),
+			ConfigURL:      f.GetConfigUrl(),
+		}
+	}
+	return p
+}
+
+// AdvisoryToProto converts a to a deps.dev Advisory message.
+func AdvisoryToProto(a *insights.Advisory) *pb.Advisory {
+	return &pb.Advisory{
+		AdvisoryKey: AdvisoryKeyToProto(a.AdvisoryKey),
+		Url:         a.URL,
+		Title:       a.Title,
+		Aliases:     a.Aliases,
+		Cvss3Score:  a.CVSS3Score,
+		Cvss3Vector: a.CVSS3Vector,
+	}
+}
+
+// AdvisoryFromProto converts the deps.dev Advisory message m.
+func AdvisoryFromProto(m *pb.Advisory) *insights.Advisory {
+	return &insights.Advisory{
+		AdvisoryKey: AdvisoryKeyFromProto(m.GetAdvisoryKey()),
+		URL:         m.GetUrl(),
+		Title:       m.GetTitle(),
+		Aliases:     m.GetAliases(),
+		CVSS3Score:  m.GetCvss3Score(),
+		CVSS3Vector: m.GetCvss3Vector(),
+	}
+}
+
+// ProjectPackageVersionsToProto converts p to a deps.dev
+// ProjectPackageVersions message.
+func ProjectPackageVersionsToProto(p *insights.ProjectPackageVersions) *pb.ProjectPackageVersions {
+	m := &pb.ProjectPackageVersions{}
+	for _, v := range p.Versions {
+		m.Versions = append(m.Versions, &pb.ProjectPackageVersions_Version{
+			VersionKey:         VersionKeyToProto(v.VersionKey),
+			RelationType:       pb.ProjectRelationType(pb.ProjectRelationType_value[v.RelationType]),
+			RelationProvenance: pb.ProjectRelationProvenance(pb.ProjectRelationProvenance_value[v.RelationProvenance]),
+			SlsaProvenances:    slsaProvenancesToProto(v.SLSAProvenances),
+			Attestations:       attestationsToProto(v.Attestations),
+		})
+	}
+	return m
+}
+
+// ProjectPackageVersionsFromProto converts the deps.dev
+// ProjectPackageVersions message m.
+func ProjectPackageVersionsFromProto(m *pb.ProjectPackageVersions) *insights.ProjectPackageVersions {
+	p := &insights.ProjectPackageVersions{}
+	if len(m.GetVersions()) == 0 {
+		return p
+	}
+	p.Versions = make([]struct {
+		VersionKey         insights.VersionKey
+		SLSAProvenances    []insights.SLSAProvenance
+		Attestations       []insights.Attestation
+		RelationType       string
+		RelationProvenance string
+	}, len(m.GetVersions()))
+	for i, v := range m.GetVersions() {
+		pv := &p.Versions[i]
+		pv.VersionKey = VersionKeyFromProto(v.GetVersionKey())
+		pv.SLSAProvenances = slsaProvenancesFromProto(v.GetSlsaProvenances())
+		pv.Attestations = attestationsFromProto(v.GetAttestations())
+		pv.RelationType = enumFromProto(v.GetRelationType(), pb.ProjectRelationType_UNKNOWN_PROJECT_RELATION_TYPE)
+		pv.RelationProvenance = enumFromProto(v.GetRelationProvenance(), pb.ProjectRelationProvenance_UNKNOWN_PROJECT_RELATION_PROVENANCE)
+	}
+	return p
+}
+
+// QueryResultToProto converts r to a deps.dev QueryResult message.
+func QueryResultToProto(r *insights.QueryResult) *pb.QueryResult {
+	m := &pb.QueryResult{}
+	for _, res := range r.Results {
+		m.Results = append(m.Results, &pb.QueryResult_Result{Version: VersionToProto(&res.Version)})
+	}
+	return m
+}
+
+// QueryResultFromProto converts the deps.dev QueryResult message m.
+func QueryResultFromProto(m *pb.QueryResult) *insights.QueryResult {
+	r := &insights.QueryResult{}
+	for _, res := range m.GetResults() {
+		r.Results = append(r.Results, insights.Result{Version: *VersionFromProto(res.GetVersion())})
+	}
+	return r
+}
+
+func systemToProto(s string) pb.System {
+	return pb.System(pb.System_value[strings.ToUpper(s)])
+}
+
+func systemFromProto(s pb.System) string {
+	return enumFromProto(s, pb.System_SYSTEM_UNSPECIFIED)
+}
+
+// enumFromProto returns the name of the enumeration value e, or the empty
+// string if e is the zero value.
+func enumFromProto[E interface {
+	comparable
+	String() string
+}](e, zero E) string {
+	if e == zero {
+		return ""
+	}
+	return e.String()
+}
+
+func timeToProto(s string) *timestamppb.Timestamp {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().Format(time.RFC3339Nano)
+}
+
+func slsaProvenancesToProto(ps []insights.SLSAProvenance) []*pb.SLSAProvenance {
+	var ms []*pb.SLSAProvenance
+	for _, p := range ps {
+		ms = append(ms, &pb.SLSAProvenance{
+			SourceRepository: p.SourceRepository,
+			Commit:           p.Commit,
+			Url:              p.URL,
+			Verified:         p.Verified,
+		})
+	}
+	return ms
+}
+
+func slsaProvenancesFromProto(ms []*pb.SLSAProvenance) []insights.SLSAProvenance {
+	var ps []insights.SLSAProvenance
+	for _, m := range ms {
+		ps = append(ps, insights.SLSAProvenance{
+			SourceRepository: m.GetSourceRepository(),
+			Commit:           m.GetCommit(),
+			URL:              m.GetUrl(),
+			Verified:         m.GetVerified(),
+		})
+	}
+	return ps
+}
+
+func attestationsToProto(as []insights.Attestation) []*pb.Attestation {
+	var ms []*pb.Attestation
+	for _, a := range as {
+		ms = append(ms, &pb.Attestation{
+			Type:             a.Type,
+			Url:              a.URL,
+			Verified:         a.Verified,
+			SourceRepository: a.SourceRepository,
+			Commit:           a.Commit,
+		})
+	}
+	return ms
+}
+
+func attestationsFromProto(ms []*pb.Attestation) []insights.Attestation {
+	var as []insights.Attestation
+	for _, m := range ms {
+		as = append(as, insights.Attestation{
+			Type:             m.GetType(),
+			URL:              m.GetUrl(),
+			Verified:         m.GetVerified(),
+			SourceRepository: m.GetSourceRepository(),
+			Commit:           m.GetCommit(),
+		})
+	}
+	return as
+}
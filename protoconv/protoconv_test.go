@@ -0,0 +1,112 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoconv
+
+import (
+	"testing"
+
+	pb "deps.dev/api/v3"
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestVersion(t *testing.T) {
+	v := &insights.Version{
+		VersionKey:   insights.VersionKey{System: "NPM", Name: "lodash", Version: "4.17.21"},
+		PublishedAt:  "2021-02-20T15:42:16Z",
+		IsDefault:    true,
+		IsDeprecated: true,
+		Licenses:     []string{"MIT"},
+		AdvisoryKeys: []insights.AdvisoryKey{{ID: "GHSA-1"}},
+		Links:        []insights.Link{{Label: "HOMEPAGE", URL: "https://lodash.com/"}},
+		SLSAProvenances: []insights.SLSAProvenance{
+			{SourceRepository: "https://github.com/lodash/lodash", Commit: "abc", URL: "https://example.com/p", Verified: true},
+		},
+		Registries: []string{"https://registry.npmjs.org/"},
+	}
+	v.RelatedProjects = append(v.RelatedProjects, struct {
+		ProjectKey         insights.ProjectKey
+		RelationProvenance string
+		RelationType       string
+	}{insights.ProjectKey{ID: "github.com/lodash/lodash"}, "UNVERIFIED_METADATA", "SOURCE_REPO"})
+
+	want := &pb.Version{
+		VersionKey:      &pb.VersionKey{System: pb.System_NPM, Name: "lodash", Version: "4.17.21"},
+		PublishedAt:     &timestamppb.Timestamp{Seconds: 1613835736},
+		IsDefault:       true,
+		IsDeprecated:    true,
+		Licenses:        []string{"MIT"},
+		AdvisoryKeys:    []*pb.AdvisoryKey{{Id: "GHSA-1"}},
+		Links:           []*pb.Link{{Label: "HOMEPAGE", Url: "https://lodash.com/"}},
+		SlsaProvenances: []*pb.SLSAProvenance{{SourceRepository: "https://github.com/lodash/lodash", Commit: "abc", Url: "https://example.com/p", Verified: true}},
+		Registries:      []string{"https://registry.npmjs.org/"},
+		RelatedProjects: []*pb.Version_Project{{
+			ProjectKey:         &pb.ProjectKey{Id: "github.com/lodash/lodash"},
+			RelationProvenance: pb.ProjectRelationProvenance_UNVERIFIED_METADATA,
+			RelationType:       pb.ProjectRelationType_SOURCE_REPO,
+		}},
+	}
+	m := VersionToProto(v)
+	if diff := cmp.Diff(want, m, protocmp.Transform()); diff != "" {
+		t.Errorf("VersionToProto mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(v, VersionFromProto(m)); diff != "" {
+		t.Errorf("VersionFromProto mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	d := &insights.Dependencies{
+		Nodes: []insights.Node{
+			{VersionKey: insights.VersionKey{System: "CARGO", Name: "a", Version: "1.0.0"}, Relation: "SELF"},
+			{VersionKey: insights.VersionKey{System: "CARGO", Name: "b", Version: "2.0.0"}, Relation: "DIRECT", Errors: []string{"oops"}},
+		},
+		Edges: []insights.Edge{{FromNode: 0, ToNode: 1, Requirement: "^2"}},
+	}
+	got := DependenciesFromProto(DependenciesToProto(d))
+	if diff := cmp.Diff(d, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProject(t *testing.T) {
+	p := &insights.Project{
+		ProjectKey:  insights.ProjectKey{ID: "github.com/x/y"},
+		StarsCount:  10,
+		License:     "MIT",
+		Description: "y",
+		OSSFuzz:     insights.OSSFuzzDetails{LineCount: 100, Date: "2024-01-02T00:00:00Z"},
+	}
+	p.Scorecard.Date = "2024-01-01T00:00:00Z"
+	p.Scorecard.Repository.Name = "github.com/x/y"
+	p.Scorecard.OverallScore = 7.5
+	p.Scorecard.Checks = append(p.Scorecard.Checks, struct {
+		Name          string
+		Documentation struct {
+			ShortDescription string
+			URL              string
+		}
+		Score   int
+		Reason  string
+		Details []string
+	}{Name: "Maintained", Score: 10})
+
+	got := ProjectFromProto(ProjectToProto(p))
+	if diff := cmp.Diff(p, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnknownEnum(t *testing.T) {
+	m := VersionKeyToProto(insights.VersionKey{System: "cobol", Name: "x"})
+	if m.System != pb.System_SYSTEM_UNSPECIFIED {
+		t.Errorf("System = %v, want SYSTEM_UNSPECIFIED", m.System)
+	}
+	if got := VersionKeyFromProto(m); got.System != "" {
+		t.Errorf("System = %q, want empty", got.System)
+	}
+}
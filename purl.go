@@ -0,0 +1,152 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// purlTypes maps the package URL types of the systems known to deps.dev to
+// their names.
+var purlTypes = map[string]string{
+	"cargo":  "CARGO",
+	"gem":    "RUBYGEMS",
+	"golang": "GO",
+	"maven":  "MAVEN",
+	"npm":    "NPM",
+	"nuget":  "NUGET",
+	"pypi":   "PYPI",
+}
+
+// ParsePURL parses a package URL (purl), such as "pkg:npm/react@18.2.0",
+// into the key of the package version it identifies. The version is empty
+// if the purl has none. Qualifiers and subpaths are ignored.
+//
+// Namespaces are joined to names as deps.dev expects: npm scopes and Go
+// module paths with "/", and Maven group IDs with ":".
+func ParsePURL(s string) (VersionKey, error) {
+	var k VersionKey
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), "pkg:")
+	if !ok {
+		return k, fmt.Errorf("invalid purl %q: missing pkg: scheme", s)
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+	rest = strings.TrimLeft(rest, "/")
+	typ, path, ok := strings.Cut(rest, "/")
+	if !ok {
+		return k, fmt.Errorf("invalid purl %q: missing name", s)
+	}
+	if k.System, ok = purlTypes[strings.ToLower(typ)]; !ok {
+		return k, fmt.Errorf("invalid purl %q: unsupported type %q", s, typ)
+	}
+	// npm scopes start with "@", so only one after the last "/" starts a
+	// version.
+	if i := strings.LastIndex(path, "@"); i >= 0 && i > strings.LastIndex(path, "/") {
+		v, err := url.PathUnescape(path[i+1:])
+		if err != nil {
+			return k, fmt.Errorf("invalid purl %q: %v", s, err)
+		}
+		k.Version, path = v, path[:i]
+	}
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segs {
+		seg, err := url.PathUnescape(seg)
+		if err != nil {
+			return k, fmt.Errorf("invalid purl %q: %v", s, err)
+		}
+		if seg == "" {
+			return k, fmt.Errorf("invalid purl %q: missing name", s)
+		}
+		segs[i] = seg
+	}
+	switch {
+	case k.System == "MAVEN" && len(segs) == 2:
+		k.Name = segs[0] + ":" + segs[1]
+	case k.System == "MAVEN":
+		return k, fmt.Errorf("invalid purl %q: Maven purls need a namespace and a name", s)
+	case k.System == "NPM" && len(segs) == 2 && !strings.HasPrefix(segs[0], "@"):
+		k.Name = "@" + segs[0] + "/" + segs[1]
+	case k.System == "GO", len(segs) == 1, k.System == "NPM" && len(segs) == 2:
+		k.Name = strings.Join(segs, "/")
+	default:
+		return k, fmt.Errorf("invalid purl %q: unexpected namespace", s)
+	}
+	return k, nil
+}
+
+// PURL returns the package URL (purl) of the package version k, or of its
+// package if the version is empty. It returns "" if the system of k has
+// no purl type.
+func (k VersionKey) PURL() string {
+	var typ string
+	for t, system := range purlTypes {
+		if strings.EqualFold(system, k.System) {
+			typ = t
+		}
+	}
+	if typ == "" {
+		return ""
+	}
+	var segs []string
+	switch typ {
+	case "maven":
+		group, artifact, _ := strings.Cut(k.Name, ":")
+		segs = []string{group, artifact}
+	case "npm", "golang":
+		segs = strings.Split(k.Name, "/")
+	default:
+		segs = []string{k.Name}
+	}
+	for i, seg := range segs {
+		segs[i] = purlEscape(seg)
+	}
+	s := "pkg:" + typ + "/" + strings.Join(segs, "/")
+	if k.Version != "" {
+		s += "@" + purlEscape(k.Version)
+	}
+	return s
+}
+
+// purlEscape percent-encodes a segment of a purl, where "@" and "+" are
+// reserved as well.
+func purlEscape(s string) string {
+	return strings.NewReplacer("@", "%40", "+", "%2B").Replace(url.PathEscape(s))
+}
+
+// ParseVersionKey parses a package version given as a purl, as parsed by
+// ParsePURL, or in the shorthand form system:name@version, such as
+// "npm:react@18.2.0" or "maven:org.slf4j:slf4j-api@2.0.9". The version
+// may be omitted, as in "npm:react", to leave it empty. The system is one
+// of those known to deps.dev, in any case, and is returned in upper case.
+func ParseVersionKey(s string) (VersionKey, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "pkg:") {
+		return ParsePURL(s)
+	}
+	var k VersionKey
+	system, name, ok := strings.Cut(s, ":")
+	if !ok {
+		return k, fmt.Errorf("invalid package version %q: want system:name@version", s)
+	}
+	k.System = strings.ToUpper(system)
+	known := false
+	for _, sys := range purlTypes {
+		known = known || sys == k.System
+	}
+	if !known {
+		return k, fmt.Errorf("invalid package version %q: unknown system %q", s, system)
+	}
+	// npm scopes start with "@".
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		name, k.Version = name[:i], name[i+1:]
+	}
+	if k.Name = name; name == "" {
+		return k, fmt.Errorf("invalid package version %q: missing name", s)
+	}
+	return k, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import "testing"
+
+func TestParsePURL(t *testing.T) {
+	testCases := []struct {
+		purl string
+		want VersionKey
+		// The purl of want, if different from the one parsed.
+		canonical string
+	}{
+		{"pkg:npm/react@18.2.0", VersionKey{"NPM", "react", "18.2.0"}, ""},
+		{"pkg:npm/%40types/node@20.1.0", VersionKey{"NPM", "@types/node", "20.1.0"}, ""},
+		{"pkg:npm/@types/node", VersionKey{"NPM", "@types/node", ""}, "pkg:npm/%40types/node"},
+		{"pkg:pypi/django@4.2.7?repository_url=https://example.com#src", VersionKey{"PYPI", "django", "4.2.7"}, "pkg:pypi/django@4.2.7"},
+		{"pkg:maven/org.slf4j/slf4j-api@2.0.9?type=jar", VersionKey{"MAVEN", "org.slf4j:slf4j-api", "2.0.9"}, "pkg:maven/org.slf4j/slf4j-api@2.0.9"},
+		{"pkg:golang/github.com/google/go-cmp@v0.7.0", VersionKey{"GO", "github.com/google/go-cmp", "v0.7.0"}, ""},
+		{"pkg:golang/github.com/pkg/errors@v0.8.0%2Bincompatible", VersionKey{"GO", "github.com/pkg/errors", "v0.8.0+incompatible"}, ""},
+		{"pkg:cargo/serde@1.0.190", VersionKey{"CARGO", "serde", "1.0.190"}, ""},
+		{"pkg:gem/rails@7.1.2", VersionKey{"RUBYGEMS", "rails", "7.1.2"}, ""},
+		{"pkg:NuGet/Newtonsoft.Json@13.0.3", VersionKey{"NUGET", "Newtonsoft.Json", "13.0.3"}, "pkg:nuget/Newtonsoft.Json@13.0.3"},
+	}
+	for _, tc := range testCases {
+		got, err := ParsePURL(tc.purl)
+		if err != nil {
+			t.Errorf("ParsePURL(%q) failed: %v", tc.purl, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParsePURL(%q) = %v, want %v", tc.purl, got, tc.want)
+		}
+		want := tc.canonical
+		if want == "" {
+			want = tc.purl
+		}
+		if s := got.PURL(); s != want {
+			t.Errorf("PURL of %v = %q, want %q", got, s, want)
+		}
+	}
+
+	for _, s := range []string{"npm/react@18.2.0", "PKG:npm/react", "pkg:npm", "pkg:deb/debian/curl@7.50.3", "pkg:maven/slf4j-api@2.0.9", "pkg:pypi/a/b/c", "pkg:npm/%zz"} {
+		if k, err := ParsePURL(s); err == nil {
+			t.Errorf("ParsePURL(%q) = %v; want error", s, k)
+		}
+	}
+}
+
+func TestParseVersionKey(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want VersionKey
+	}{
+		{"npm:react@18.2.0", VersionKey{"NPM", "react", "18.2.0"}},
+		{"npm:@types/node@20.1.0", VersionKey{"NPM", "@types/node", "20.1.0"}},
+		{"npm:@types/node", VersionKey{"NPM", "@types/node", ""}},
+		{"maven:org.slf4j:slf4j-api@2.0.9", VersionKey{"MAVEN", "org.slf4j:slf4j-api", "2.0.9"}},
+		{"Go:golang.org/x/net@v0.55.0", VersionKey{"GO", "golang.org/x/net", "v0.55.0"}},
+		{"pkg:cargo/serde@1.0.190", VersionKey{"CARGO", "serde", "1.0.190"}},
+	}
+	for _, tc := range testCases {
+		got, err := ParseVersionKey(tc.s)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseVersionKey(%q) = %v, %v; want %v", tc.s, got, err, tc.want)
+		}
+	}
+	for _, s := range []string{"react@18.2.0", "debian:curl@7.50.3", "npm:"} {
+		if k, err := ParseVersionKey(s); err == nil {
+			t.Errorf("ParseVersionKey(%q) = %v; want error", s, k)
+		}
+	}
+}
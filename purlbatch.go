@@ -0,0 +1,104 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+)
+
+// maxPurlBatch is the largest number of purls deps.dev looks up in one
+// request.
+const maxPurlBatch = 5000
+
+// errPurlNoVersion is the error of the purls given to PurlLookupBatch
+// without a version.
+var errPurlNoVersion = errors.New("purl has no version")
+
+type purlBatchRequest struct {
+	Requests  []purlRequest `json:"requests"`
+	PageToken string        `json:"pageToken,omitempty"`
+}
+
+type purlRequest struct {
+	Purl string `json:"purl"`
+}
+
+type purlBatchResponse struct {
+	Responses []struct {
+		Request purlRequest
+		Result  *PurlLookupResult
+	}
+	NextPageToken string
+}
+
+// PurlLookupBatch looks up the package versions identified by purls, such
+// as "pkg:npm/react@18.2.0", and returns their results in the order of
+// purls. Unlike PurlLookup, it only looks up package versions: purls
+// without a version are not sent.
+//
+// Purls are sent in as few requests as the API allows, following the
+// pages of its responses. The results of purls that are invalid, lack a
+// version, or are unknown to deps.dev are nil, and their errors are
+// returned in a *BatchError, by purl; unknown purls fail with ErrNotFound.
+// Any other error stops the lookup and is returned alone.
+//
+// deps.dev API doc: https://docs.deps.dev/api/v3alpha/#purllookupbatch
+func (c *Client) PurlLookupBatch(ctx context.Context, purls []string) ([]*PurlLookupResult, error) {
+	var (
+		batch   Batch
+		results = make([]*PurlLookupResult, len(purls))
+		// The indexes in purls of the purls to look up, which may be
+		// repeated.
+		index = make(map[string][]int)
+		reqs  []purlRequest
+	)
+	for i, purl := range purls {
+		k, err := ParsePURL(purl)
+		if err == nil && k.Version == "" {
+			err = errPurlNoVersion
+		}
+		if err != nil {
+			batch.Fail(purl, err)
+			continue
+		}
+		if index[purl] == nil {
+			reqs = append(reqs, purlRequest{Purl: purl})
+		}
+		index[purl] = append(index[purl], i)
+	}
+
+	found := make(map[string]bool)
+	for len(reqs) > 0 {
+		n := min(len(reqs), maxPurlBatch)
+		body := purlBatchRequest{Requests: reqs[:n]}
+		for {
+			var r purlBatchResponse
+			if _, err := c.postEndpoint(ctx, EndpointPurlLookupBatch, "purlbatch", &body, &r, attrPurlCount.Int(n)); err != nil {
+				return nil, err
+			}
+			for _, resp := range r.Responses {
+				if resp.Result == nil {
+					continue
+				}
+				found[resp.Request.Purl] = true
+				for _, i := range index[resp.Request.Purl] {
+					results[i] = resp.Result
+				}
+			}
+			if r.NextPageToken == "" {
+				break
+			}
+			body.PageToken = r.NextPageToken
+		}
+		for _, req := range reqs[:n] {
+			if !found[req.Purl] {
+				batch.Fail(req.Purl, ErrNotFound)
+			}
+		}
+		reqs = reqs[n:]
+	}
+	return results, batch.Err()
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPurlLookupBatch(t *testing.T) {
+	client, mux := setup(t)
+	client.Retry = &RetryPolicy{MaxRetries: 1, MinWait: time.Millisecond}
+
+	var requests []purlBatchRequest
+	failed := false
+	mux.HandleFunc("/purlbatch", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testHeader(t, r, "Content-Type", "application/json")
+		var req purlBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		requests = append(requests, req)
+		// The body is sent again when the request is retried.
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch req.PageToken {
+		case "":
+			fmt.Fprint(w, `{"responses":[{"request":{"purl":"pkg:npm/react@18.2.0"},"result":{"purl":"pkg:npm/react@18.2.0","version":{"versionKey":{"system":"NPM","name":"react","version":"18.2.0"}}}}],"nextPageToken":"2"}`)
+		case "2":
+			fmt.Fprint(w, `{"responses":[{"request":{"purl":"pkg:npm/left-pad@1.3.0"},"result":{"purl":"pkg:npm/left-pad@1.3.0","version":{"versionKey":{"system":"NPM","name":"left-pad","version":"1.3.0"}}}},{"request":{"purl":"pkg:npm/unknown@1.0.0"}}]}`)
+		default:
+			t.Errorf("unexpected page token %q", req.PageToken)
+		}
+	})
+
+	purls := []string{
+		"pkg:npm/react@18.2.0",
+		"pkg:npm/left-pad@1.3.0",
+		"pkg:npm/react",
+		"pkg:npm/unknown@1.0.0",
+		"not a purl",
+		"pkg:npm/react@18.2.0",
+	}
+	got, err := client.PurlLookupBatch(context.Background(), purls)
+
+	react := &PurlLookupResult{
+		Purl:    "pkg:npm/react@18.2.0",
+		Version: &Version{VersionKey: VersionKey{System: "NPM", Name: "react", Version: "18.2.0"}},
+	}
+	want := []*PurlLookupResult{
+		react,
+		{
+			Purl:    "pkg:npm/left-pad@1.3.0",
+			Version: &Version{VersionKey: VersionKey{System: "NPM", Name: "left-pad", Version: "1.3.0"}},
+		},
+		nil,
+		nil,
+		nil,
+		react,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PurlLookupBatch mismatch (-want +got):\n%s", diff)
+	}
+
+	var be *BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("PurlLookupBatch returned error %v; want a *BatchError", err)
+	}
+	var failedPurls []any
+	for _, ke := range be.Errors {
+		failedPurls = append(failedPurls, ke.Key)
+	}
+	wantFailed := []any{"pkg:npm/react", "not a purl", "pkg:npm/unknown@1.0.0"}
+	if diff := cmp.Diff(wantFailed, failedPurls); diff != "" {
+		t.Errorf("failed purls mismatch (-want +got):\n%s", diff)
+	}
+	if !errors.Is(be.Errors[0].Err, errPurlNoVersion) || !errors.Is(be.Errors[2].Err, ErrNotFound) {
+		t.Errorf("PurlLookupBatch errors = %v", be.Errors)
+	}
+
+	// Duplicate and invalid purls are not sent.
+	wantRequests := []purlBatchRequest{
+		{Requests: []purlRequest{{"pkg:npm/react@18.2.0"}, {"pkg:npm/left-pad@1.3.0"}, {"pkg:npm/unknown@1.0.0"}}},
+		{Requests: []purlRequest{{"pkg:npm/react@18.2.0"}, {"pkg:npm/left-pad@1.3.0"}, {"pkg:npm/unknown@1.0.0"}}},
+		{Requests: []purlRequest{{"pkg:npm/react@18.2.0"}, {"pkg:npm/left-pad@1.3.0"}, {"pkg:npm/unknown@1.0.0"}}, PageToken: "2"},
+	}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPurlLookupBatchError(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/purlbatch", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	})
+
+	_, err := client.PurlLookupBatch(context.Background(), []string{"pkg:npm/react@18.2.0"})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("PurlLookupBatch returned error %v; want ErrBadRequest", err)
+	}
+}
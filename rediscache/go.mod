@@ -0,0 +1,18 @@
+module github.com/franoliveto/insights/rediscache
+
+go 1.25.0
+
+require (
+	github.com/franoliveto/insights v0.0.0-00010101000000-000000000000
+	github.com/redis/go-redis/v9 v9.22.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	go.opentelemetry.io/otel v1.43.0 // indirect
+	go.opentelemetry.io/otel/trace v1.43.0 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/sys v0.45.0 // indirect
+)
+
+replace github.com/franoliveto/insights => ../
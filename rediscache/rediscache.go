@@ -0,0 +1,110 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rediscache implements an insights.Cache backed by Redis, so that
+// several processes, such as the workers of a distributed scanner, can
+// share the responses fetched from deps.dev.
+package rediscache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Client is the subset of the go-redis API used by Cache. It is implemented
+// by *redis.Client, *redis.ClusterClient, *redis.Ring, and
+// redis.UniversalClient.
+type Client interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// Default values of the Cache fields.
+const (
+	DefaultPrefix  = "insights:"
+	DefaultTimeout = time.Second
+)
+
+// Cache is an insights.Cache storing responses in Redis. Responses expire
+// after TTL, so that Redis evicts them on its own.
+//
+// Errors talking to Redis are not reported: Get misses and Set does
+// nothing, and the response is fetched from the API instead.
+type Cache struct {
+	// The Redis client.
+	Client Client
+
+	// The prefix of the Redis keys holding responses. If empty,
+	// DefaultPrefix is used.
+	Prefix string
+
+	// How long responses are kept. If zero, they never expire.
+	TTL time.Duration
+
+	// The maximum duration of each Redis command. If zero, DefaultTimeout
+	// is used.
+	Timeout time.Duration
+}
+
+// New returns a Cache using client and keeping responses for ttl.
+func New(client Client, ttl time.Duration) *Cache {
+	return &Cache{Client: client, TTL: ttl}
+}
+
+// key returns the Redis key for the request URL key. URLs are hashed to
+// keep keys short.
+func (c *Cache) key(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return prefix + hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) context() (context.Context, context.CancelFunc) {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// Get implements insights.Cache.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	ctx, cancel := c.context()
+	defer cancel()
+	data, err := c.Client.Get(ctx, c.key(key)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set implements insights.Cache.
+func (c *Cache) Set(key string, data []byte) {
+	c.SetTTL(key, data, 0)
+}
+
+// SetTTL implements insights.ExpiringCache.
+func (c *Cache) SetTTL(key string, data []byte, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = c.TTL
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	c.Client.Set(ctx, c.key(key), data, ttl)
+}
+
+// Delete implements insights.ExpiringCache.
+func (c *Cache) Delete(key string) {
+	ctx, cancel := c.context()
+	defer cancel()
+	c.Client.Del(ctx, c.key(key))
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rediscache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/franoliveto/insights"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis is an in-memory Client.
+type fakeRedis struct {
+	data map[string]string
+	ttl  map[string]time.Duration
+	down bool
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: make(map[string]string), ttl: make(map[string]time.Duration)}
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	if f.down {
+		return redis.NewStringResult("", errors.New("connection refused"))
+	}
+	v, ok := f.data[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(v, nil)
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	if f.down {
+		return redis.NewStatusResult("", errors.New("connection refused"))
+	}
+	f.data[key] = string(value.([]byte))
+	f.ttl[key] = expiration
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (f *fakeRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	if f.down {
+		return redis.NewIntResult(0, errors.New("connection refused"))
+	}
+	var n int64
+	for _, key := range keys {
+		if _, ok := f.data[key]; ok {
+			delete(f.data, key)
+			delete(f.ttl, key)
+			n++
+		}
+	}
+	return redis.NewIntResult(n, nil)
+}
+
+var _ insights.ExpiringCache = (*Cache)(nil)
+
+func TestCache(t *testing.T) {
+	r := newFakeRedis()
+	c := New(r, time.Hour)
+	const url = "https://api.deps.dev/v3/systems/npm/packages/react"
+
+	if _, ok := c.Get(url); ok {
+		t.Fatal("Get hit on an empty cache")
+	}
+	c.Set(url, []byte(`{"versions":[]}`))
+	data, ok := c.Get(url)
+	if !ok || string(data) != `{"versions":[]}` {
+		t.Errorf("Get = %q, %v; want the stored response", data, ok)
+	}
+	for k, ttl := range r.ttl {
+		if !strings.HasPrefix(k, DefaultPrefix) {
+			t.Errorf("key %q does not have prefix %q", k, DefaultPrefix)
+		}
+		if ttl != time.Hour {
+			t.Errorf("TTL = %v, want 1h", ttl)
+		}
+	}
+
+	c.SetTTL(url, []byte(`{}`), time.Minute)
+	if ttl := r.ttl[c.key(url)]; ttl != time.Minute {
+		t.Errorf("TTL after SetTTL = %v, want 1m", ttl)
+	}
+	c.Delete(url)
+	if _, ok := c.Get(url); ok {
+		t.Error("Get hit after Delete")
+	}
+
+	// Caches with different prefixes do not share responses.
+	other := &Cache{Client: r, Prefix: "other:"}
+	if _, ok := other.Get(url); ok {
+		t.Error("Get hit with a different prefix")
+	}
+}
+
+func TestCacheDown(t *testing.T) {
+	r := newFakeRedis()
+	r.down = true
+	c := New(r, 0)
+	c.Set("k", []byte("v"))
+	if _, ok := c.Get("k"); ok {
+		t.Error("Get hit with Redis down")
+	}
+}
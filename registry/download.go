@@ -0,0 +1,106 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Default base URLs of the registries' artifacts.
+var defaultDownloadURLs = map[string]string{
+	"NPM":      defaultNPMURL,
+	"CARGO":    "https://static.crates.io/crates/",
+	"MAVEN":    "https://repo.maven.apache.org/maven2/",
+	"PYPI":     "https://files.pythonhosted.org/packages/source/",
+	"GO":       "https://proxy.golang.org/",
+	"NUGET":    "https://api.nuget.org/v3-flatcontainer/",
+	"RUBYGEMS": defaultRubyGemsURL + "gems/",
+}
+
+// DownloadURL returns the URL of the artifact of version of the package
+// name of the given system, as served by the registry at base, or by the
+// public registry if base is empty:
+//
+//   - npm: the package tarball.
+//   - Cargo: the .crate file.
+//   - Maven: the jar; name is "group:artifact".
+//   - PyPI: the source distribution, assuming it is a .tar.gz named after
+//     the package. Wheels cannot be named without knowing their tags.
+//   - Go: the module zip from a module proxy.
+//   - NuGet: the .nupkg file.
+//   - RubyGems: the .gem file of the platform-independent gem.
+//
+// The base URLs in Version.Registries name registries by their main URL,
+// which is not always the one serving artifacts, as for PyPI.
+func DownloadURL(system, name, version, base string) (string, error) {
+	system = strings.ToUpper(system)
+	def, ok := defaultDownloadURLs[system]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupported, system)
+	}
+	if base == "" {
+		base = def
+	}
+	base = strings.TrimSuffix(base, "/") + "/"
+
+	switch system {
+	case "NPM":
+		// Scoped packages keep their scope in the path but not in the
+		// file name: @scope/name/-/name-1.0.0.tgz.
+		file := name
+		if i := strings.LastIndexByte(name, '/'); i >= 0 {
+			file = name[i+1:]
+		}
+		return base + escapeNPM(name) + "/-/" + url.PathEscape(file+"-"+version+".tgz"), nil
+	case "CARGO":
+		return base + url.PathEscape(name) + "/" + url.PathEscape(name+"-"+version+".crate"), nil
+	case "MAVEN":
+		group, artifact, ok := strings.Cut(name, ":")
+		if !ok || group == "" || artifact == "" {
+			return "", fmt.Errorf("registry: Maven package name %q is not group:artifact", name)
+		}
+		return base + strings.ReplaceAll(group, ".", "/") + "/" + artifact + "/" + url.PathEscape(version) + "/" + url.PathEscape(artifact+"-"+version+".jar"), nil
+	case "PYPI":
+		if name == "" {
+			return "", fmt.Errorf("registry: empty PyPI package name")
+		}
+		return base + url.PathEscape(name[:1]) + "/" + url.PathEscape(name) + "/" + url.PathEscape(name+"-"+version+".tar.gz"), nil
+	case "GO":
+		return base + escapeModule(name) + "/@v/" + escapeModule(version) + ".zip", nil
+	case "RUBYGEMS":
+		return base + url.PathEscape(name+"-"+version+".gem"), nil
+	default: // NUGET
+		id, v := strings.ToLower(name), strings.ToLower(version)
+		return base + url.PathEscape(id) + "/" + url.PathEscape(v) + "/" + url.PathEscape(id+"."+v+".nupkg"), nil
+	}
+}
+
+// escapeNPM escapes an npm package name for use in a registry path. The
+// slash of a scoped name is kept, as registries expect.
+func escapeNPM(name string) string {
+	scope, pkg, ok := strings.Cut(name, "/")
+	if !ok {
+		return url.PathEscape(name)
+	}
+	return url.PathEscape(scope) + "/" + url.PathEscape(pkg)
+}
+
+// escapeModule applies the case encoding of the Go module proxy protocol,
+// which replaces each upper-case letter by an exclamation mark followed by
+// the letter in lower case.
+func escapeModule(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDownloadURL(t *testing.T) {
+	testCases := []struct {
+		system, name, version, base string
+		want                        string
+	}{
+		{"npm", "react", "18.2.0", "", "https://registry.npmjs.org/react/-/react-18.2.0.tgz"},
+		{"NPM", "@types/node", "20.1.0", "https://npm.example.com", "https://npm.example.com/@types/node/-/node-20.1.0.tgz"},
+		{"CARGO", "serde", "1.0.0", "", "https://static.crates.io/crates/serde/serde-1.0.0.crate"},
+		{"MAVEN", "org.apache.commons:commons-lang3", "3.12.0", "", "https://repo.maven.apache.org/maven2/org/apache/commons/commons-lang3/3.12.0/commons-lang3-3.12.0.jar"},
+		{"PYPI", "requests", "2.31.0", "", "https://files.pythonhosted.org/packages/source/r/requests/requests-2.31.0.tar.gz"},
+		{"GO", "github.com/BurntSushi/toml", "v1.3.2", "", "https://proxy.golang.org/github.com/!burnt!sushi/toml/@v/v1.3.2.zip"},
+		{"NUGET", "Newtonsoft.Json", "13.0.3", "", "https://api.nuget.org/v3-flatcontainer/newtonsoft.json/13.0.3/newtonsoft.json.13.0.3.nupkg"},
+		{"RUBYGEMS", "rails", "7.1.3", "", "https://rubygems.org/gems/rails-7.1.3.gem"},
+	}
+	for _, tc := range testCases {
+		got, err := DownloadURL(tc.system, tc.name, tc.version, tc.base)
+		if err != nil {
+			t.Errorf("DownloadURL(%q, %q, %q) failed: %v", tc.system, tc.name, tc.version, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("DownloadURL(%q, %q, %q) = %q, want %q", tc.system, tc.name, tc.version, got, tc.want)
+		}
+	}
+}
+
+func TestDownloadURLError(t *testing.T) {
+	if _, err := DownloadURL("CONDA", "numpy", "1.26.0", ""); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DownloadURL of an unsupported system returned %v, want ErrUnsupported", err)
+	}
+	if _, err := DownloadURL("MAVEN", "commons-lang3", "3.12.0", ""); err == nil {
+		t.Error("DownloadURL of a Maven name without a group succeeded")
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/franoliveto/insights"
+)
+
+// The Client is a fallback for the insights.Client, answering from the
+// registries what deps.dev does not know.
+var _ insights.Fallback = (*Client)(nil)
+
+// Package returns the package with the given name from the registry of its
+// system, in the form deps.dev would. npm, PyPI, Cargo (crates.io), and
+// Maven (Maven Central) are supported.
+//
+// Registries know less than deps.dev: versions have no advisories,
+// provenance, or related projects, Maven versions have no licenses, and
+// PyPI only has the licenses of the latest version.
+func (c *Client) Package(ctx context.Context, system, name string) (*insights.Package, error) {
+	var versions []insights.Version
+	var err error
+	switch system = strings.ToUpper(system); system {
+	case "NPM":
+		versions, err = c.npmVersions(ctx, name)
+	case "PYPI":
+		versions, err = c.pypiVersions(ctx, name)
+	case "CARGO":
+		versions, err = c.cratesVersions(ctx, name, "")
+	case "MAVEN":
+		versions, err = c.mavenVersions(ctx, name, "")
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, system)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// deps.dev lists versions in the order they were published.
+	slices.SortStableFunc(versions, func(a, b insights.Version) int {
+		return cmp.Compare(a.PublishedAt, b.PublishedAt)
+	})
+	return &insights.Package{
+		PackageKey: insights.PackageKey{System: system, Name: name},
+		Versions:   versions,
+	}, nil
+}
+
+// Version returns a version of the package with the given name from the
+// registry of its system, in the form deps.dev would. The same systems are
+// supported as by Package.
+func (c *Client) Version(ctx context.Context, system, name, version string) (*insights.Version, error) {
+	var versions []insights.Version
+	var err error
+	switch system = strings.ToUpper(system); system {
+	case "NPM":
+		versions, err = c.npmVersions(ctx, name)
+	case "PYPI":
+		var v *insights.Version
+		if v, err = c.pypiVersion(ctx, name, version); err == nil {
+			versions = []insights.Version{*v}
+		}
+	case "CARGO":
+		versions, err = c.cratesVersions(ctx, name, version)
+	case "MAVEN":
+		versions, err = c.mavenVersions(ctx, name, version)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, system)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.VersionKey.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("registry: %s package %s has no version %s", system, name, version)
+}
+
+func (c *Client) npmVersions(ctx context.Context, name string) ([]insights.Version, error) {
+	base := or(c.NPMURL, defaultNPMURL)
+	var resp struct {
+		DistTags map[string]string `json:"dist-tags"`
+		Time     map[string]string `json:"time"`
+		Versions map[string]struct {
+			License    json.RawMessage `json:"license"`
+			Deprecated any             `json:"deprecated"`
+			Homepage   string          `json:"homepage"`
+			Repository json.RawMessage `json:"repository"`
+			Bugs       json.RawMessage `json:"bugs"`
+		} `json:"versions"`
+	}
+	if err := c.get(ctx, base, escapeNPM(name), &resp); err != nil {
+		return nil, err
+	}
+	var versions []insights.Version
+	for num, m := range resp.Versions {
+		v := insights.Version{
+			VersionKey:  insights.VersionKey{System: "NPM", Name: name, Version: num},
+			PublishedAt: timestamp(resp.Time[num]),
+			IsDefault:   num == resp.DistTags["latest"],
+			Registries:  []string{base},
+		}
+		// Cleared deprecations are false.
+		if reason, _ := m.Deprecated.(string); reason != "" {
+			v.IsDeprecated = true
+		}
+		if l := npmField(m.License, "type"); l != "" {
+			v.Licenses = []string{l}
+		}
+		v.Links = appendLink(v.Links, "HOMEPAGE", m.Homepage)
+		v.Links = appendLink(v.Links, "SOURCE_REPO", strings.TrimPrefix(npmField(m.Repository, "url"), "git+"))
+		v.Links = appendLink(v.Links, "ISSUE_TRACKER", npmField(m.Bugs, "url"))
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// npmField returns the value of a package.json field that is either a string
+// or an object whose key field holds it, as are license, repository, and
+// bugs.
+func npmField(raw json.RawMessage, key string) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var m map[string]any
+	json.Unmarshal(raw, &m)
+	s, _ = m[key].(string)
+	return s
+}
+
+// pypiInfo is the metadata of a PyPI release.
+type pypiInfo struct {
+	Version           string            `json:"version"`
+	License           string            `json:"license"`
+	LicenseExpression string            `json:"license_expression"`
+	HomePage          string            `json:"home_page"`
+	ProjectURLs       map[string]string `json:"project_urls"`
+}
+
+// pypiFile is a file of a PyPI release.
+type pypiFile struct {
+	UploadTime string `json:"upload_time_iso_8601"`
+}
+
+func (c *Client) pypiVersions(ctx context.Context, name string) ([]insights.Version, error) {
+	var resp struct {
+		Info     pypiInfo              `json:"info"`
+		Releases map[string][]pypiFile `json:"releases"`
+	}
+	if err := c.get(ctx, or(c.PyPIURL, defaultPyPIURL), "pypi/"+url.PathEscape(name)+"/json", &resp); err != nil {
+		return nil, err
+	}
+	var versions []insights.Version
+	for num, files := range resp.Releases {
+		v := pypiRelease(c.PyPIURL, name, num, files)
+		// Only the metadata of the latest version is listed.
+		if num == resp.Info.Version {
+			v.IsDefault = true
+			v.Licenses = resp.Info.licenses()
+			v.Links = resp.Info.links()
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+func (c *Client) pypiVersion(ctx context.Context, name, version string) (*insights.Version, error) {
+	var resp struct {
+		Info pypiInfo   `json:"info"`
+		URLs []pypiFile `json:"urls"`
+	}
+	if err := c.get(ctx, or(c.PyPIURL, defaultPyPIURL), "pypi/"+url.PathEscape(name)+"/"+url.PathEscape(version)+"/json", &resp); err != nil {
+		return nil, err
+	}
+	v := pypiRelease(c.PyPIURL, name, version, resp.URLs)
+	v.Licenses = resp.Info.licenses()
+	v.Links = resp.Info.links()
+	return &v, nil
+}
+
+// pypiRelease returns the version of a PyPI release, published when its
+// first file was uploaded.
+func pypiRelease(base, name, version string, files []pypiFile) insights.Version {
+	v := insights.Version{
+		VersionKey: insights.VersionKey{System: "PYPI", Name: name, Version: version},
+		Registries: []string{or(base, defaultPyPIURL)},
+	}
+	for _, f := range files {
+		if t := timestamp(f.UploadTime); v.PublishedAt == "" || t < v.PublishedAt {
+			v.PublishedAt = t
+		}
+	}
+	return v
+}
+
+// licenses returns the license of a PyPI release. Before PEP 639, the
+// license field was free text, which is only taken as an SPDX identifier
+// if it is a single word.
+func (i *pypiInfo) licenses() []string {
+	switch l := strings.TrimSpace(i.License); {
+	case i.LicenseExpression != "":
+		return []string{i.LicenseExpression}
+	case l == "":
+		return nil
+	case strings.ContainsFunc(l, func(r rune) bool { return r == ' ' || r == '\n' }):
+		return []string{"non-standard"}
+	default:
+		return []string{l}
+	}
+}
+
+// pypiLabels maps the usual labels of PyPI project URLs to the deps.dev
+// link labels.
+var pypiLabels = map[string]string{
+	"homepage":      "HOMEPAGE",
+	"home":          "HOMEPAGE",
+	"source":        "SOURCE_REPO",
+	"source code":   "SOURCE_REPO",
+	"repository":    "SOURCE_REPO",
+	"code":          "SOURCE_REPO",
+	"issues":        "ISSUE_TRACKER",
+	"bug tracker":   "ISSUE_TRACKER",
+	"tracker":       "ISSUE_TRACKER",
+	"documentation": "DOCUMENTATION",
+	"docs":          "DOCUMENTATION",
+}
+
+func (i *pypiInfo) links() []insights.Link {
+	links := appendLink(nil, "HOMEPAGE", i.HomePage)
+	// Map iteration order is random.
+	labels := make([]string, 0, len(i.ProjectURLs))
+	for label := range i.ProjectURLs {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		if l, ok := pypiLabels[strings.ToLower(label)]; ok {
+			links = appendLink(links, l, i.ProjectURLs[label])
+		}
+	}
+	return links
+}
+
+func (c *Client) cratesVersions(ctx context.Context, name, version string) ([]insights.Version, error) {
+	type crateVersion struct {
+		Num       string `json:"num"`
+		License   string `json:"license"`
+		CreatedAt string `json:"created_at"`
+		Yanked    bool   `json:"yanked"`
+	}
+	var resp struct {
+		Crate struct {
+			MaxStableVersion string `json:"max_stable_version"`
+			MaxVersion       string `json:"max_version"`
+			Homepage         string `json:"homepage"`
+			Repository       string `json:"repository"`
+			Documentation    string `json:"documentation"`
+		} `json:"crate"`
+		Versions []crateVersion `json:"versions"`
+		Version  crateVersion   `json:"version"`
+	}
+	base := or(c.CratesURL, defaultCratesURL)
+	path := "api/v1/crates/" + url.PathEscape(name)
+	if version != "" {
+		path += "/" + url.PathEscape(version)
+	}
+	if err := c.get(ctx, base, path, &resp); err != nil {
+		return nil, err
+	}
+	if version != "" {
+		resp.Versions = []crateVersion{resp.Version}
+	}
+	cr := resp.Crate
+	def := cmp.Or(cr.MaxStableVersion, cr.MaxVersion)
+	var links []insights.Link
+	links = appendLink(links, "HOMEPAGE", cr.Homepage)
+	links = appendLink(links, "SOURCE_REPO", cr.Repository)
+	links = appendLink(links, "DOCUMENTATION", cr.Documentation)
+	var versions []insights.Version
+	for _, cv := range resp.Versions {
+		v := insights.Version{
+			VersionKey:  insights.VersionKey{System: "CARGO", Name: name, Version: cv.Num},
+			PublishedAt: timestamp(cv.CreatedAt),
+			IsDefault:   cv.Num == def,
+			Links:       links,
+			Registries:  []string{base},
+		}
+		if cv.License != "" {
+			// Old crates separate alternatives with slashes.
+			v.Licenses = []string{strings.ReplaceAll(cv.License, "/", " OR ")}
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+func (c *Client) mavenVersions(ctx context.Context, name, version string) ([]insights.Version, error) {
+	group, artifact, ok := strings.Cut(name, ":")
+	if !ok || group == "" || artifact == "" {
+		return nil, fmt.Errorf("registry: Maven package name %q is not group:artifact", name)
+	}
+	q := `g:"` + group + `" AND a:"` + artifact + `"`
+	if version != "" {
+		q += ` AND v:"` + version + `"`
+	}
+	var versions []insights.Version
+	for {
+		params := url.Values{"q": {q}, "core": {"gav"}, "rows": {strconv.Itoa(mavenPageSize)}, "start": {strconv.Itoa(len(versions))}, "wt": {"json"}}
+		var resp struct {
+			Response struct {
+				NumFound int `json:"numFound"`
+				Docs     []struct {
+					Version   string `json:"v"`
+					Timestamp int64  `json:"timestamp"`
+				} `json:"docs"`
+			} `json:"response"`
+		}
+		if err := c.get(ctx, or(c.MavenURL, defaultMavenURL), "solrsearch/select?"+params.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		for _, d := range resp.Response.Docs {
+			v := insights.Version{
+				VersionKey: insights.VersionKey{System: "MAVEN", Name: name, Version: d.Version},
+				Registries: []string{defaultDownloadURLs["MAVEN"]},
+			}
+			if d.Timestamp > 0 {
+				v.PublishedAt = time.UnixMilli(d.Timestamp).UTC().Format(time.RFC3339)
+			}
+			versions = append(versions, v)
+		}
+		r := resp.Response
+		if len(r.Docs) == 0 || len(versions) >= r.NumFound {
+			break
+		}
+	}
+	// The search API does not tell the latest release, so the default is
+	// taken to be the last published.
+	if version == "" && len(versions) > 0 {
+		latest := slices.MaxFunc(versions, func(a, b insights.Version) int {
+			return cmp.Compare(a.PublishedAt, b.PublishedAt)
+		})
+		for i := range versions {
+			versions[i].IsDefault = versions[i].VersionKey.Version == latest.VersionKey.Version
+		}
+	}
+	return versions, nil
+}
+
+// timestamp returns the time s in the form of deps.dev, RFC 3339 in UTC
+// without fractional seconds, or s if it is not an RFC 3339 time.
+func timestamp(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// appendLink appends a link with the given label to links, unless u is
+// empty.
+func appendLink(links []insights.Link, label, u string) []insights.Link {
+	if u = strings.TrimSpace(u); u == "" {
+		return links
+	}
+	return append(links, insights.Link{Label: label, URL: u})
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPackage(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/npm/left-pad", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"dist-tags": {"latest": "1.3.0"},
+			"time": {"created": "2016-03-18T00:56:07.000Z", "1.2.0": "2017-01-01T00:00:00.000Z", "1.3.0": "2018-04-09T01:52:10.876Z"},
+			"versions": {
+				"1.3.0": {"license": "WTFPL", "deprecated": "use String.prototype.padStart()", "repository": {"type": "git", "url": "git+https://github.com/stevemao/left-pad.git"}},
+				"1.2.0": {"license": {"type": "MIT"}, "homepage": "https://github.com/stevemao/left-pad", "deprecated": false}
+			}
+		}`)
+	})
+	mux.HandleFunc("/maven/solrsearch/select", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("q"), `g:"org.example" AND a:"lib"`; got != want {
+			t.Errorf("q = %q; want %q", got, want)
+		}
+		fmt.Fprint(w, `{"response":{"numFound":2,"docs":[{"v":"2.0","timestamp":1700000000000},{"v":"1.0","timestamp":1600000000000}]}}`)
+	})
+	ctx := context.Background()
+
+	got, err := client.Package(ctx, "npm", "left-pad")
+	if err != nil {
+		t.Fatalf("Package failed: %v", err)
+	}
+	npm := client.NPMURL
+	want := &insights.Package{
+		PackageKey: insights.PackageKey{System: "NPM", Name: "left-pad"},
+		Versions: []insights.Version{
+			{
+				VersionKey:  insights.VersionKey{System: "NPM", Name: "left-pad", Version: "1.2.0"},
+				PublishedAt: "2017-01-01T00:00:00Z",
+				Licenses:    []string{"MIT"},
+				Links:       []insights.Link{{Label: "HOMEPAGE", URL: "https://github.com/stevemao/left-pad"}},
+				Registries:  []string{npm},
+			},
+			{
+				VersionKey:   insights.VersionKey{System: "NPM", Name: "left-pad", Version: "1.3.0"},
+				PublishedAt:  "2018-04-09T01:52:10Z",
+				IsDefault:    true,
+				IsDeprecated: true,
+				Licenses:     []string{"WTFPL"},
+				Links:        []insights.Link{{Label: "SOURCE_REPO", URL: "https://github.com/stevemao/left-pad.git"}},
+				Registries:   []string{npm},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Package(npm) mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = client.Package(ctx, "MAVEN", "org.example:lib")
+	if err != nil {
+		t.Fatalf("Package failed: %v", err)
+	}
+	var versions []string
+	for _, v := range got.Versions {
+		versions = append(versions, fmt.Sprintf("%s %s %t", v.VersionKey.Version, v.PublishedAt, v.IsDefault))
+	}
+	wantVersions := []string{"1.0 2020-09-13T12:26:40Z false", "2.0 2023-11-14T22:13:20Z true"}
+	if diff := cmp.Diff(wantVersions, versions); diff != "" {
+		t.Errorf("Package(maven) versions mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := client.Package(ctx, "GO", "example.com/m"); err == nil {
+		t.Error("Package(go) succeeded; want ErrUnsupported")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/pypi/pypi/requests/2.31.0/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"info": {"version": "2.31.0", "license": "Apache 2.0", "home_page": "https://requests.readthedocs.io",
+				"project_urls": {"Source": "https://github.com/psf/requests", "Funding": "https://example.com"}},
+			"urls": [{"upload_time_iso_8601": "2023-05-22T15:12:44.175Z"}, {"upload_time_iso_8601": "2023-05-22T15:12:42.313790Z"}]
+		}`)
+	})
+	mux.HandleFunc("/crates/api/v1/crates/serde/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":{"num":"1.0.0","license":"MIT/Apache-2.0","created_at":"2017-04-20T01:14:43.000000+00:00"}}`)
+	})
+	ctx := context.Background()
+
+	got, err := client.Version(ctx, "PYPI", "requests", "2.31.0")
+	if err != nil {
+		t.Fatalf("Version failed: %v", err)
+	}
+	want := &insights.Version{
+		VersionKey:  insights.VersionKey{System: "PYPI", Name: "requests", Version: "2.31.0"},
+		PublishedAt: "2023-05-22T15:12:42Z",
+		Licenses:    []string{"non-standard"},
+		Links: []insights.Link{
+			{Label: "HOMEPAGE", URL: "https://requests.readthedocs.io"},
+			{Label: "SOURCE_REPO", URL: "https://github.com/psf/requests"},
+		},
+		Registries: []string{client.PyPIURL},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Version(pypi) mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = client.Version(ctx, "CARGO", "serde", "1.0.0")
+	if err != nil {
+		t.Fatalf("Version failed: %v", err)
+	}
+	if got.PublishedAt != "2017-04-20T01:14:43Z" || !cmp.Equal(got.Licenses, []string{"MIT OR Apache-2.0"}) {
+		t.Errorf("Version(cargo) = %+v", got)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page sizes of the registries' search APIs, the largest they allow.
+const (
+	npmPageSize   = 250
+	mavenPageSize = 200
+)
+
+// Namespace returns up to limit packages published under a namespace: an
+// npm scope, with or without its leading "@", or a Maven group ID. Maven
+// packages are named "group:artifact", as in deps.dev. Other systems have
+// no namespaces that can be listed. If limit is zero, all the packages are
+// returned.
+func (c *Client) Namespace(ctx context.Context, system, namespace string, limit int) ([]Package, error) {
+	switch strings.ToUpper(system) {
+	case "NPM":
+		return c.npmScope(ctx, strings.TrimPrefix(namespace, "@"), limit)
+	case "MAVEN":
+		return c.mavenGroup(ctx, namespace, limit)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, system)
+}
+
+func (c *Client) npmScope(ctx context.Context, scope string, limit int) ([]Package, error) {
+	var pkgs []Package
+	for from := 0; ; {
+		size := npmPageSize
+		if limit > 0 {
+			size = min(size, limit-len(pkgs))
+		}
+		q := url.Values{"text": {"scope:" + scope}, "size": {strconv.Itoa(size)}, "from": {strconv.Itoa(from)}}
+		var resp struct {
+			Objects []struct {
+				Package struct {
+					Name        string `json:"name"`
+					Version     string `json:"version"`
+					Description string `json:"description"`
+				} `json:"package"`
+			} `json:"objects"`
+			Total int `json:"total"`
+		}
+		if err := c.get(ctx, or(c.NPMURL, defaultNPMURL), "-/v1/search?"+q.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		from += len(resp.Objects)
+		for _, o := range resp.Objects {
+			// Search results are not guaranteed to be in the scope.
+			if p := o.Package; strings.HasPrefix(p.Name, "@"+scope+"/") {
+				pkgs = append(pkgs, Package{System: "NPM", Name: p.Name, Version: p.Version, Description: p.Description})
+			}
+		}
+		// The registry may serve smaller pages than asked for.
+		if len(resp.Objects) == 0 || from >= resp.Total || (limit > 0 && len(pkgs) >= limit) {
+			return pkgs, nil
+		}
+	}
+}
+
+func (c *Client) mavenGroup(ctx context.Context, group string, limit int) ([]Package, error) {
+	var pkgs []Package
+	for {
+		rows := mavenPageSize
+		if limit > 0 {
+			rows = min(rows, limit-len(pkgs))
+		}
+		q := url.Values{"q": {`g:"` + group + `"`}, "rows": {strconv.Itoa(rows)}, "start": {strconv.Itoa(len(pkgs))}, "wt": {"json"}}
+		var resp struct {
+			Response struct {
+				NumFound int `json:"numFound"`
+				Docs     []struct {
+					Group         string `json:"g"`
+					Artifact      string `json:"a"`
+					LatestVersion string `json:"latestVersion"`
+				} `json:"docs"`
+			} `json:"response"`
+		}
+		if err := c.get(ctx, or(c.MavenURL, defaultMavenURL), "solrsearch/select?"+q.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		for _, d := range resp.Response.Docs {
+			pkgs = append(pkgs, Package{System: "MAVEN", Name: d.Group + ":" + d.Artifact, Version: d.LatestVersion})
+		}
+		r := resp.Response
+		if len(r.Docs) == 0 || len(pkgs) >= r.NumFound || (limit > 0 && len(pkgs) >= limit) {
+			return pkgs, nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNamespace(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/npm/-/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("text"); got != "scope:babel" {
+			t.Errorf("text = %q; want %q", got, "scope:babel")
+		}
+		// Three packages, served two at a time.
+		from, _ := strconv.Atoi(q.Get("from"))
+		var objects string
+		for i := from; i < min(from+2, 3); i++ {
+			if objects != "" {
+				objects += ","
+			}
+			objects += fmt.Sprintf(`{"package":{"name":"@babel/p%d","version":"7.0.%d"}}`, i, i)
+		}
+		if q.Get("size") == "1" {
+			objects = fmt.Sprintf(`{"package":{"name":"@babel/p%d","version":"7.0.%d"}}`, from, from)
+		}
+		fmt.Fprintf(w, `{"objects":[%s],"total":3}`, objects)
+	})
+	mux.HandleFunc("/maven/solrsearch/select", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("q"); got != `g:"com.google.guava"` {
+			t.Errorf("q = %q; want %q", got, `g:"com.google.guava"`)
+		}
+		fmt.Fprint(w, `{"response":{"numFound":2,"docs":[{"g":"com.google.guava","a":"guava","latestVersion":"33.0.0-jre"},{"g":"com.google.guava","a":"failureaccess","latestVersion":"1.0.2"}]}}`)
+	})
+	ctx := context.Background()
+
+	got, err := client.Namespace(ctx, "npm", "@babel", 0)
+	if err != nil {
+		t.Fatalf("Namespace(npm) failed: %v", err)
+	}
+	want := []Package{
+		{System: "NPM", Name: "@babel/p0", Version: "7.0.0"},
+		{System: "NPM", Name: "@babel/p1", Version: "7.0.1"},
+		{System: "NPM", Name: "@babel/p2", Version: "7.0.2"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Namespace(npm) mismatch (-want +got):\n%s", diff)
+	}
+	if got, err := client.Namespace(ctx, "npm", "babel", 1); err != nil || len(got) != 1 {
+		t.Errorf("Namespace(npm) with limit 1 returned %v, %v; want 1 package", got, err)
+	}
+
+	got, err = client.Namespace(ctx, "maven", "com.google.guava", 0)
+	if err != nil {
+		t.Fatalf("Namespace(maven) failed: %v", err)
+	}
+	want = []Package{
+		{System: "MAVEN", Name: "com.google.guava:guava", Version: "33.0.0-jre"},
+		{System: "MAVEN", Name: "com.google.guava:failureaccess", Version: "1.0.2"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Namespace(maven) mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := client.Namespace(ctx, "pypi", "x", 0); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Namespace(pypi) returned %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package registry searches the registries of package management systems
+// for packages by name or keyword, and builds the URLs of the artifacts they
+// serve. deps.dev has no name search, so the packages found can then be
+// looked up with the insights package. A Client can also be the Fallback of
+// an insights.Client, answering for packages deps.dev lacks.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// Default base URLs of the registries.
+const (
+	defaultNPMURL      = "https://registry.npmjs.org/"
+	defaultCratesURL   = "https://crates.io/"
+	defaultPyPIURL     = "https://pypi.org/"
+	defaultRubyGemsURL = "https://rubygems.org/"
+	defaultMavenURL    = "https://search.maven.org/"
+)
+
+const userAgent = "insights (https://github.com/franoliveto/insights)"
+
+// ErrUnsupported is returned when searching a system that has no supported
+// registry.
+var ErrUnsupported = errors.New("registry: system not supported")
+
+// StatusError reports an unsuccessful response from a registry.
+type StatusError struct {
+	// The URL of the request.
+	URL string
+
+	// The HTTP status code of the response.
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Package is a package found in a registry.
+type Package struct {
+	// The package management system, using the deps.dev names.
+	System string
+
+	// The name of the package.
+	Name string
+
+	// The latest version of the package, if known.
+	Version string
+
+	// A short description of the package, if known.
+	Description string
+}
+
+// Client searches package registries. The zero value is ready to use.
+type Client struct {
+	// The HTTP client used to send requests. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+
+	// Retry, if not nil, tells how to retry requests that failed because
+	// of a network error, a rate limit, or a transient server error.
+	Retry *insights.RetryPolicy
+
+	// The base URLs of the npm registry, crates.io, PyPI, RubyGems.org,
+	// and the Maven Central search API. If empty, the public registries
+	// are used.
+	NPMURL      string
+	CratesURL   string
+	PyPIURL     string
+	RubyGemsURL string
+	MavenURL    string
+
+	// MaxResponseBytes limits the size of the responses read from
+	// registries, as insights.Client.MaxResponseBytes does those of
+	// deps.dev: larger responses fail with insights.ErrResponseTooLarge. If
+	// zero, insights.DefaultMaxResponseBytes is used; if negative, response
+	// sizes are not limited.
+	MaxResponseBytes int64
+}
+
+// Search returns up to limit packages of the given system that match term.
+// npm, Cargo (crates.io), and RubyGems are searched by keyword. PyPI has no
+// search API, so for PyPI only a package named term is returned, if it
+// exists. limit must be positive.
+func (c *Client) Search(ctx context.Context, system, term string, limit int) ([]Package, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("registry: search limit %d is not positive", limit)
+	}
+	switch strings.ToUpper(system) {
+	case "NPM":
+		return c.searchNPM(ctx, term, limit)
+	case "CARGO":
+		return c.searchCrates(ctx, term, limit)
+	case "PYPI":
+		return c.lookupPyPI(ctx, term)
+	case "RUBYGEMS":
+		return c.searchRubyGems(ctx, term, limit)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, system)
+}
+
+func (c *Client) searchNPM(ctx context.Context, term string, limit int) ([]Package, error) {
+	q := url.Values{"text": {term}, "size": {strconv.Itoa(limit)}}
+	var resp struct {
+		Objects []struct {
+			Package struct {
+				Name        string `json:"name"`
+				Version     string `json:"version"`
+				Description string `json:"description"`
+			} `json:"package"`
+		} `json:"objects"`
+	}
+	if err := c.get(ctx, or(c.NPMURL, defaultNPMURL), "-/v1/search?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	var pkgs []Package
+	for _, o := range resp.Objects {
+		p := o.Package
+		pkgs = append(pkgs, Package{System: "NPM", Name: p.Name, Version: p.Version, Description: p.Description})
+	}
+	return pkgs, nil
+}
+
+func (c *Client) searchCrates(ctx context.Context, term string, limit int) ([]Package, error) {
+	q := url.Values{"q": {term}, "per_page": {strconv.Itoa(limit)}}
+	var resp struct {
+		Crates []struct {
+			Name        string `json:"name"`
+			MaxVersion  string `json:"max_stable_version"`
+			Description string `json:"description"`
+		} `json:"crates"`
+	}
+	if err := c.get(ctx, or(c.CratesURL, defaultCratesURL), "api/v1/crates?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	var pkgs []Package
+	for _, cr := range resp.Crates {
+		pkgs = append(pkgs, Package{System: "CARGO", Name: cr.Name, Version: cr.MaxVersion, Description: strings.TrimSpace(cr.Description)})
+	}
+	return pkgs, nil
+}
+
+func (c *Client) lookupPyPI(ctx context.Context, name string) ([]Package, error) {
+	var resp struct {
+		Info struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Summary string `json:"summary"`
+		} `json:"info"`
+	}
+	err := c.get(ctx, or(c.PyPIURL, defaultPyPIURL), "pypi/"+url.PathEscape(name)+"/json", &resp)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	i := resp.Info
+	return []Package{{System: "PYPI", Name: i.Name, Version: i.Version, Description: i.Summary}}, nil
+}
+
+func (c *Client) searchRubyGems(ctx context.Context, term string, limit int) ([]Package, error) {
+	q := url.Values{"query": {term}}
+	var resp []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Info    string `json:"info"`
+	}
+	if err := c.get(ctx, or(c.RubyGemsURL, defaultRubyGemsURL), "api/v1/search.json?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	// The API returns pages of a fixed size.
+	if len(resp) > limit {
+		resp = resp[:limit]
+	}
+	var pkgs []Package
+	for _, g := range resp {
+		pkgs = append(pkgs, Package{System: "RUBYGEMS", Name: g.Name, Version: g.Version, Description: strings.TrimSpace(g.Info)})
+	}
+	return pkgs, nil
+}
+
+// get sends a GET request for path, relative to base, and decodes the JSON
+// response into v.
+func (c *Client) get(ctx context.Context, base, path string, v any) error {
+	u := strings.TrimSuffix(base, "/") + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := c.Retry.Do(hc, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return &StatusError{URL: u, StatusCode: resp.StatusCode}
+	}
+	limit := c.MaxResponseBytes
+	if limit == 0 {
+		limit = insights.DefaultMaxResponseBytes
+	}
+	if limit < 0 {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+	// One byte more than allowed tells a response of limit bytes from a
+	// larger one.
+	body := &io.LimitedReader{R: resp.Body, N: limit + 1}
+	err = json.NewDecoder(body).Decode(v)
+	if body.N <= 0 {
+		return fmt.Errorf("%w: %s", insights.ErrResponseTooLarge, u)
+	}
+	return err
+}
+
+func or(s, def string) string {
+	if s != "" {
+		return s
+	}
+	return def
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setup returns a client whose registries are served by a test server with
+// handlers registered on mux.
+func setup(t *testing.T) (*Client, *http.ServeMux) {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return &Client{
+		NPMURL:      server.URL + "/npm/",
+		CratesURL:   server.URL + "/crates/",
+		PyPIURL:     server.URL + "/pypi/",
+		RubyGemsURL: server.URL + "/rubygems/",
+		MavenURL:    server.URL + "/maven/",
+	}, mux
+}
+
+func TestSearch(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/npm/-/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("text"); got != "left pad" {
+			t.Errorf("text = %q; want %q", got, "left pad")
+		}
+		if got := r.URL.Query().Get("size"); got != "2" {
+			t.Errorf("size = %q; want 2", got)
+		}
+		fmt.Fprint(w, `{"objects":[{"package":{"name":"left-pad","version":"1.3.0","description":"String left pad"}}]}`)
+	})
+	mux.HandleFunc("/crates/api/v1/crates", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("crates.io request has no User-Agent")
+		}
+		fmt.Fprint(w, `{"crates":[{"name":"serde","max_stable_version":"1.0.190","description":" A serialization framework\n"}]}`)
+	})
+	mux.HandleFunc("/pypi/pypi/requests/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"info":{"name":"requests","version":"2.31.0","summary":"Python HTTP for Humans."}}`)
+	})
+	mux.HandleFunc("/pypi/pypi/nope/json", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/rubygems/api/v1/search.json", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("query"); got != "rack" {
+			t.Errorf("query = %q; want %q", got, "rack")
+		}
+		fmt.Fprint(w, `[{"name":"rack","version":"3.0.9","info":"A modular Ruby webserver interface. "},{"name":"rack-test","version":"2.1.0","info":""},{"name":"rack-cors","version":"2.0.2","info":""}]`)
+	})
+
+	testCases := []struct {
+		system, term string
+		want         []Package
+	}{
+		{"npm", "left pad", []Package{{System: "NPM", Name: "left-pad", Version: "1.3.0", Description: "String left pad"}}},
+		{"CARGO", "serde", []Package{{System: "CARGO", Name: "serde", Version: "1.0.190", Description: "A serialization framework"}}},
+		{"PYPI", "requests", []Package{{System: "PYPI", Name: "requests", Version: "2.31.0", Description: "Python HTTP for Humans."}}},
+		{"PYPI", "nope", nil},
+		{"RUBYGEMS", "rack", []Package{
+			{System: "RUBYGEMS", Name: "rack", Version: "3.0.9", Description: "A modular Ruby webserver interface."},
+			{System: "RUBYGEMS", Name: "rack-test", Version: "2.1.0"},
+		}},
+	}
+	for _, c := range testCases {
+		got, err := client.Search(context.Background(), c.system, c.term, 2)
+		if err != nil {
+			t.Errorf("Search(%q, %q) failed: %v", c.system, c.term, err)
+			continue
+		}
+		if !cmp.Equal(got, c.want) {
+			t.Errorf("Search(%q, %q) returned %+v; want %+v", c.system, c.term, got, c.want)
+		}
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	client, mux := setup(t)
+
+	mux.HandleFunc("/npm/-/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+
+	_, err := client.Search(context.Background(), "NPM", "x", 1)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Search returned error %v; want StatusError 429", err)
+	}
+
+	if _, err := client.Search(context.Background(), "GO", "x", 1); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Search returned error %v; want ErrUnsupported", err)
+	}
+
+	for _, limit := range []int{0, -1} {
+		if _, err := client.Search(context.Background(), "RUBYGEMS", "rails", limit); err == nil {
+			t.Errorf("Search with limit %d succeeded; want an error", limit)
+		}
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	client, mux := setup(t)
+	body := `{"info":{"name":"requests","version":"2.31.0","summary":"` + strings.Repeat("x", 1000) + `"}}`
+	mux.HandleFunc("/pypi/pypi/requests/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	ctx := context.Background()
+	client.MaxResponseBytes = int64(len(body))
+	if _, err := client.Search(ctx, "PYPI", "requests", 1); err != nil {
+		t.Errorf("Search with a response of MaxResponseBytes failed: %v", err)
+	}
+	client.MaxResponseBytes = 100
+	if _, err := client.Search(ctx, "PYPI", "requests", 1); !errors.Is(err, insights.ErrResponseTooLarge) {
+		t.Errorf("Search returned %v, want ErrResponseTooLarge", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Size returns an estimate, in bytes, of the disk space a package version
+// of the given system takes once installed, from the metadata of its
+// registry: the unpacked size of the package on npm, the size of its wheel
+// on PyPI, or of its .crate archive on crates.io. Wheels and crates are
+// compressed, so for PyPI and Cargo the estimate is a lower bound. For
+// PyPI, a pure Python wheel is preferred, then the largest platform wheel,
+// then the source distribution. It returns 0 if the registry does not
+// know the size.
+func (c *Client) Size(ctx context.Context, system, name, version string) (int64, error) {
+	switch strings.ToUpper(system) {
+	case "NPM":
+		var resp struct {
+			Dist struct {
+				UnpackedSize int64 `json:"unpackedSize"`
+			} `json:"dist"`
+		}
+		if err := c.get(ctx, or(c.NPMURL, defaultNPMURL), escapeNPM(name)+"/"+url.PathEscape(version), &resp); err != nil {
+			return 0, err
+		}
+		return resp.Dist.UnpackedSize, nil
+	case "PYPI":
+		var resp struct {
+			URLs []struct {
+				Filename    string `json:"filename"`
+				PackageType string `json:"packagetype"`
+				Size        int64  `json:"size"`
+			} `json:"urls"`
+		}
+		if err := c.get(ctx, or(c.PyPIURL, defaultPyPIURL), "pypi/"+url.PathEscape(name)+"/"+url.PathEscape(version)+"/json", &resp); err != nil {
+			return 0, err
+		}
+		var wheel, sdist int64
+		for _, f := range resp.URLs {
+			switch {
+			case f.PackageType == "bdist_wheel" && strings.HasSuffix(f.Filename, "-none-any.whl"):
+				return f.Size, nil
+			case f.PackageType == "bdist_wheel":
+				wheel = max(wheel, f.Size)
+			case f.PackageType == "sdist":
+				sdist = f.Size
+			}
+		}
+		if wheel > 0 {
+			return wheel, nil
+		}
+		return sdist, nil
+	case "CARGO":
+		var resp struct {
+			Version struct {
+				CrateSize int64 `json:"crate_size"`
+			} `json:"version"`
+		}
+		if err := c.get(ctx, or(c.CratesURL, defaultCratesURL), "api/v1/crates/"+url.PathEscape(name)+"/"+url.PathEscape(version), &resp); err != nil {
+			return 0, err
+		}
+		return resp.Version.CrateSize, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnsupported, system)
+}
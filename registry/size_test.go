@@ -0,0 +1,62 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestSize(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/npm/react/18.2.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"react","version":"18.2.0","dist":{"unpackedSize":316439}}`)
+	})
+	mux.HandleFunc("/pypi/pypi/requests/2.31.0/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"urls":[
+			{"filename":"requests-2.31.0.tar.gz","packagetype":"sdist","size":110794},
+			{"filename":"requests-2.31.0-py3-none-any.whl","packagetype":"bdist_wheel","size":62574}]}`)
+	})
+	mux.HandleFunc("/pypi/pypi/numpy/1.26.0/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"urls":[
+			{"filename":"numpy-1.26.0.tar.gz","packagetype":"sdist","size":15633134},
+			{"filename":"numpy-1.26.0-cp312-cp312-manylinux_2_17_x86_64.whl","packagetype":"bdist_wheel","size":17960000},
+			{"filename":"numpy-1.26.0-cp312-cp312-win_amd64.whl","packagetype":"bdist_wheel","size":15500000}]}`)
+	})
+	mux.HandleFunc("/pypi/pypi/old/0.1/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"urls":[{"filename":"old-0.1.tar.gz","packagetype":"sdist","size":1024}]}`)
+	})
+	mux.HandleFunc("/crates/api/v1/crates/serde/1.0.190", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":{"crate_size":76848}}`)
+	})
+
+	testCases := []struct {
+		system, name, version string
+		want                  int64
+	}{
+		{"npm", "react", "18.2.0", 316439},
+		{"pypi", "requests", "2.31.0", 62574},
+		{"pypi", "numpy", "1.26.0", 17960000},
+		{"pypi", "old", "0.1", 1024},
+		{"cargo", "serde", "1.0.190", 76848},
+	}
+	for _, tc := range testCases {
+		got, err := client.Size(context.Background(), tc.system, tc.name, tc.version)
+		if err != nil {
+			t.Errorf("Size(%s, %s, %s) failed: %v", tc.system, tc.name, tc.version, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Size(%s, %s, %s) = %d; want %d", tc.system, tc.name, tc.version, got, tc.want)
+		}
+	}
+
+	if _, err := client.Size(context.Background(), "go", "x", "v1.0.0"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Size(go) returned %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Status is the standing of a package version in its registry.
+type Status struct {
+	// Whether the publisher deprecated the version, as with npm
+	// deprecate.
+	Deprecated bool
+
+	// Whether the version was yanked: withdrawn from the registry, so that
+	// it is no longer installed unless pinned, as on PyPI and crates.io.
+	Yanked bool
+
+	// The reason given for the deprecation or yank, if any.
+	Reason string
+}
+
+// Status returns the status of a package version of the given system in
+// its registry: whether it is deprecated on npm, or yanked on PyPI or
+// crates.io. deps.dev reports deprecations, but not yanks, and may lag
+// behind the registries.
+func (c *Client) Status(ctx context.Context, system, name, version string) (*Status, error) {
+	switch strings.ToUpper(system) {
+	case "NPM":
+		var resp struct {
+			// false when set and then cleared by npm undeprecate.
+			Deprecated any `json:"deprecated"`
+		}
+		if err := c.get(ctx, or(c.NPMURL, defaultNPMURL), escapeNPM(name)+"/"+url.PathEscape(version), &resp); err != nil {
+			return nil, err
+		}
+		reason, _ := resp.Deprecated.(string)
+		return &Status{Deprecated: reason != "", Reason: reason}, nil
+	case "PYPI":
+		var resp struct {
+			Info struct {
+				Yanked       bool   `json:"yanked"`
+				YankedReason string `json:"yanked_reason"`
+			} `json:"info"`
+		}
+		if err := c.get(ctx, or(c.PyPIURL, defaultPyPIURL), "pypi/"+url.PathEscape(name)+"/"+url.PathEscape(version)+"/json", &resp); err != nil {
+			return nil, err
+		}
+		return &Status{Yanked: resp.Info.Yanked, Reason: resp.Info.YankedReason}, nil
+	case "CARGO":
+		var resp struct {
+			Version struct {
+				Yanked      bool   `json:"yanked"`
+				YankMessage string `json:"yank_message"`
+			} `json:"version"`
+		}
+		if err := c.get(ctx, or(c.CratesURL, defaultCratesURL), "api/v1/crates/"+url.PathEscape(name)+"/"+url.PathEscape(version), &resp); err != nil {
+			return nil, err
+		}
+		return &Status{Yanked: resp.Version.Yanked, Reason: resp.Version.YankMessage}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, system)
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStatus(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/npm/request/2.88.2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"request","version":"2.88.2","deprecated":"request has been deprecated"}`)
+	})
+	mux.HandleFunc("/npm/@scope/pkg/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"@scope/pkg","version":"1.0.0","deprecated":false}`)
+	})
+	mux.HandleFunc("/pypi/pypi/urllib3/2.0.0/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"info":{"yanked":true,"yanked_reason":"broken wheels"}}`)
+	})
+	mux.HandleFunc("/crates/api/v1/crates/serde/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":{"yanked":false}}`)
+	})
+
+	testCases := []struct {
+		system, name, version string
+		want                  *Status
+	}{
+		{"npm", "request", "2.88.2", &Status{Deprecated: true, Reason: "request has been deprecated"}},
+		{"npm", "@scope/pkg", "1.0.0", &Status{}},
+		{"pypi", "urllib3", "2.0.0", &Status{Yanked: true, Reason: "broken wheels"}},
+		{"cargo", "serde", "1.0.0", &Status{}},
+	}
+	for _, tc := range testCases {
+		got, err := client.Status(context.Background(), tc.system, tc.name, tc.version)
+		if err != nil {
+			t.Errorf("Status(%s, %s, %s) failed: %v", tc.system, tc.name, tc.version, err)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("Status(%s, %s, %s) mismatch (-want +got):\n%s", tc.system, tc.name, tc.version, diff)
+		}
+	}
+
+	if _, err := client.Status(context.Background(), "go", "x", "v1.0.0"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Status(go) returned %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/franoliveto/insights"
+)
+
+// MaxPNGPixels is the largest number of pixels of the images drawn by
+// WritePNG, which takes four bytes of memory for each.
+const MaxPNGPixels = 1 << 26
+
+// WritePNG draws g as a PNG image to w, laid out as by WriteSVG. Labels are
+// drawn with a small bitmap font of the printable ASCII characters; other
+// characters are drawn as "?". Graphs whose image would have more than
+// MaxPNGPixels pixels, as wide ones do, are not drawn: SVG or DOT suit
+// them better.
+func WritePNG(w io.Writer, g *insights.Dependencies) error {
+	l := newLayout(g)
+	if int64(l.width)*int64(l.height) > MaxPNGPixels {
+		return fmt.Errorf("render: a PNG image of the graph would be %dx%d pixels, too large; write it as SVG or DOT instead", l.width, l.height)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
+	fillRect(img, img.Bounds(), color.White)
+
+	edge := hexColor(edgeColor)
+	for _, ln := range l.lines {
+		drawLine(img, ln.x1, ln.y1, ln.x2, ln.y2, edge)
+		// The arrowhead is two short strokes back from the end.
+		angle := math.Atan2(ln.y2-ln.y1, ln.x2-ln.x1)
+		for _, side := range []float64{-0.4, 0.4} {
+			a := angle + math.Pi + side
+			drawLine(img, ln.x2, ln.y2, ln.x2+7*math.Cos(a), ln.y2+7*math.Sin(a), edge)
+		}
+	}
+	text := color.RGBA{0x11, 0x18, 0x27, 0xff}
+	for _, b := range l.boxes {
+		s := nodeStyle(b.node)
+		r := image.Rect(b.x, b.y, b.x+b.w, b.y+b.h)
+		fillRect(img, r, hexColor(s.fill))
+		strokeRect(img, r, hexColor(s.stroke))
+		drawText(img, b.x+nodePadX, b.y+(b.h-glyphHeight)/2, b.label, text)
+	}
+	return png.Encode(w, img)
+}
+
+// hexColor parses a color of the form "#rrggbb".
+func hexColor(s string) color.RGBA {
+	v, _ := strconv.ParseUint(s[1:], 16, 32)
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
+
+func strokeRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		img.Set(x, r.Min.Y, c)
+		img.Set(x, r.Max.Y-1, c)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		img.Set(r.Min.X, y, c)
+		img.Set(r.Max.X-1, y, c)
+	}
+}
+
+// drawLine draws a line one pixel wide from (x1, y1) to (x2, y2).
+func drawLine(img *image.RGBA, x1, y1, x2, y2 float64, c color.Color) {
+	steps := int(math.Ceil(max(math.Abs(x2-x1), math.Abs(y2-y1))))
+	if steps == 0 {
+		img.Set(int(math.Round(x1)), int(math.Round(y1)), c)
+		return
+	}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		img.Set(int(math.Round(x1+t*(x2-x1))), int(math.Round(y1+t*(y2-y1))), c)
+	}
+}
+
+// drawText draws s with its top left corner at (x, y).
+func drawText(img *image.RGBA, x, y int, s string, c color.Color) {
+	for _, r := range s {
+		if r < ' ' || r > '~' {
+			r = '?'
+		}
+		for col, bits := range font[r-' '] {
+			for row := range glyphHeight {
+				if bits&(1<<row) != 0 {
+					img.Set(x+col, y+row, c)
+				}
+			}
+		}
+		x += charWidth
+	}
+}
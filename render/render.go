@@ -0,0 +1,259 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package render draws resolved dependency graphs as SVG or PNG images, or
+// writes them in the DOT language of Graphviz. Graphs are laid out by the
+// package itself, so no external tools are needed to draw them.
+//
+// The output depends only on the graph, so that renderings of the same
+// graph are identical and can be compared across runs.
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/franoliveto/insights"
+)
+
+// Dimensions of the layout, in pixels. Labels are assumed to be drawn with
+// characters charWidth wide, as are those of 12px sans-serif fonts on
+// average and the bitmap font of PNG images.
+const (
+	charWidth  = 7
+	nodeHeight = 24
+	nodePadX   = 8
+	nodeGap    = 16
+	layerGap   = 56
+	margin     = 16
+)
+
+// sweeps is the number of times nodes are reordered to reduce crossings.
+const sweeps = 4
+
+// box is a node placed in the layout.
+type box struct {
+	node  *insights.Node
+	label string
+	layer int
+
+	// The position of the top left corner and the size of the box.
+	x, y, w, h int
+}
+
+func (b *box) center() (x, y float64) {
+	return float64(b.x) + float64(b.w)/2, float64(b.y) + float64(b.h)/2
+}
+
+// line is an edge placed in the layout, from one box border to another.
+type line struct {
+	edge           *insights.Edge
+	x1, y1, x2, y2 float64
+}
+
+// layout is a drawing of a dependency graph.
+type layout struct {
+	width, height int
+	boxes         []box
+	lines         []line
+}
+
+// label returns the label of the node n.
+func label(n *insights.Node) string {
+	return n.VersionKey.Name + "@" + n.VersionKey.Version
+}
+
+// newLayout lays out g in layers: the root at the top, and each node in the
+// layer below the first node from which it is reached. Within each layer,
+// nodes are ordered by the mean position of their neighbors in the layers
+// above and below, which reduces the crossings of edges.
+func newLayout(g *insights.Dependencies) *layout {
+	l := &layout{boxes: make([]box, len(g.Nodes))}
+	if len(g.Nodes) == 0 {
+		l.width, l.height = 2*margin, 2*margin
+		return l
+	}
+	out := make([][]int, len(g.Nodes))
+	in := make([][]int, len(g.Nodes))
+	for _, e := range g.Edges {
+		if e.FromNode < 0 || e.FromNode >= len(g.Nodes) || e.ToNode < 0 || e.ToNode >= len(g.Nodes) {
+			continue
+		}
+		out[e.FromNode] = append(out[e.FromNode], e.ToNode)
+		in[e.ToNode] = append(in[e.ToNode], e.FromNode)
+	}
+
+	// Assign layers breadth first from the root. Nodes not reachable from
+	// it are put in a layer of their own at the bottom.
+	maxLayer := 0
+	for i, d := range g.Depths() {
+		n := &g.Nodes[i]
+		l.boxes[i] = box{node: n, label: label(n), layer: d}
+		maxLayer = max(maxLayer, d)
+	}
+	unreached := maxLayer + 1
+	layers := make([][]int, maxLayer+1)
+	for i := range l.boxes {
+		b := &l.boxes[i]
+		if b.layer < 0 {
+			b.layer = unreached
+		}
+		for len(layers) <= b.layer {
+			layers = append(layers, nil)
+		}
+		layers[b.layer] = append(layers[b.layer], i)
+	}
+
+	// Order the layers, starting by label.
+	pos := make([]float64, len(g.Nodes))
+	for _, layer := range layers {
+		slices.SortStableFunc(layer, func(a, b int) int {
+			return cmp.Compare(l.boxes[a].label, l.boxes[b].label)
+		})
+		for p, v := range layer {
+			pos[v] = float64(p)
+		}
+	}
+	for range sweeps {
+		for i := 1; i < len(layers); i++ {
+			reorder(layers[i], in, pos)
+		}
+		for i := len(layers) - 2; i >= 0; i-- {
+			reorder(layers[i], out, pos)
+		}
+	}
+
+	// Place the boxes, centering each layer on the widest one.
+	widths := make([]int, len(layers))
+	for i, layer := range layers {
+		for _, v := range layer {
+			b := &l.boxes[v]
+			b.w = len(b.label)*charWidth + 2*nodePadX
+			b.h = nodeHeight
+			widths[i] += b.w
+		}
+		widths[i] += nodeGap * (len(layer) - 1)
+	}
+	maxWidth := slices.Max(widths)
+	for i, layer := range layers {
+		x := margin + (maxWidth-widths[i])/2
+		for _, v := range layer {
+			b := &l.boxes[v]
+			b.x, b.y = x, margin+i*(nodeHeight+layerGap)
+			x += b.w + nodeGap
+		}
+	}
+	l.width = maxWidth + 2*margin
+	l.height = len(layers)*nodeHeight + (len(layers)-1)*layerGap + 2*margin
+
+	// Draw the edges between the borders of the boxes, in a stable order.
+	edges := slices.Clone(g.Edges)
+	slices.SortStableFunc(edges, func(a, b insights.Edge) int {
+		return cmp.Or(cmp.Compare(a.FromNode, b.FromNode), cmp.Compare(a.ToNode, b.ToNode))
+	})
+	for i := range edges {
+		e := &edges[i]
+		if e.FromNode < 0 || e.FromNode >= len(g.Nodes) || e.ToNode < 0 || e.ToNode >= len(g.Nodes) || e.FromNode == e.ToNode {
+			continue
+		}
+		from, to := &l.boxes[e.FromNode], &l.boxes[e.ToNode]
+		fx, fy := from.center()
+		tx, ty := to.center()
+		x1, y1 := clip(from, tx, ty)
+		x2, y2 := clip(to, fx, fy)
+		l.lines = append(l.lines, line{edge: e, x1: x1, y1: y1, x2: x2, y2: y2})
+	}
+	return l
+}
+
+// reorder sorts the nodes of a layer by the mean position of their
+// neighbors, keeping the nodes without neighbors in place, and updates pos.
+func reorder(layer []int, neighbors [][]int, pos []float64) {
+	key := make(map[int]float64, len(layer))
+	for _, v := range layer {
+		key[v] = pos[v]
+		if len(neighbors[v]) == 0 {
+			continue
+		}
+		var sum float64
+		for _, w := range neighbors[v] {
+			sum += pos[w]
+		}
+		key[v] = sum / float64(len(neighbors[v]))
+	}
+	slices.SortStableFunc(layer, func(a, b int) int {
+		return cmp.Or(cmp.Compare(key[a], key[b]), cmp.Compare(pos[a], pos[b]))
+	})
+	for p, v := range layer {
+		pos[v] = float64(p)
+	}
+}
+
+// clip returns the point where the segment from the center of b to (x, y)
+// crosses the border of b.
+func clip(b *box, x, y float64) (float64, float64) {
+	cx, cy := b.center()
+	dx, dy := x-cx, y-cy
+	if dx == 0 && dy == 0 {
+		return cx, cy
+	}
+	hw, hh := float64(b.w)/2, float64(b.h)/2
+	// Scale the direction so that it just reaches the nearer border.
+	s := 1.0
+	if dx != 0 {
+		s = min(s, hw/math.Abs(dx))
+	}
+	if dy != 0 {
+		s = min(s, hh/math.Abs(dy))
+	}
+	return cx + dx*s, cy + dy*s
+}
+
+// WriteDOT writes g to w in the DOT language, for drawing with Graphviz.
+// Nodes are written in the order of the graph and edges sorted by the
+// nodes they join.
+func WriteDOT(w io.Writer, g *insights.Dependencies) error {
+	bw := &errWriter{w: w}
+	bw.printf("digraph dependencies {\n\tnode [shape=box];\n")
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		attrs := fmt.Sprintf("label=%q", label(n))
+		switch {
+		case len(n.Errors) > 0:
+			attrs += ", color=red"
+		case n.Relation == "SELF":
+			attrs += ", style=bold"
+		}
+		bw.printf("\tn%d [%s];\n", i, attrs)
+	}
+	edges := slices.Clone(g.Edges)
+	slices.SortStableFunc(edges, func(a, b insights.Edge) int {
+		return cmp.Or(cmp.Compare(a.FromNode, b.FromNode), cmp.Compare(a.ToNode, b.ToNode))
+	})
+	for _, e := range edges {
+		if e.Requirement != "" {
+			bw.printf("\tn%d -> n%d [label=%q];\n", e.FromNode, e.ToNode, e.Requirement)
+		} else {
+			bw.printf("\tn%d -> n%d;\n", e.FromNode, e.ToNode)
+		}
+	}
+	bw.printf("}\n")
+	return bw.err
+}
+
+// errWriter writes formatted text to w until the first error, which it
+// keeps.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, args ...any) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, args...)
+	}
+}
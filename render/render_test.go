@@ -0,0 +1,151 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testGraph is a graph of a with the dependencies b and c, which both
+// depend on d.
+func testGraph() *insights.Dependencies {
+	node := func(name, relation string) insights.Node {
+		return insights.Node{VersionKey: insights.VersionKey{System: "NPM", Name: name, Version: "1.0.0"}, Relation: relation}
+	}
+	d := node("d", "INDIRECT")
+	d.Errors = []string{"could not resolve e"}
+	return &insights.Dependencies{
+		Nodes: []insights.Node{node("a", "SELF"), node("c", "DIRECT"), node("b", "DIRECT"), d},
+		Edges: []insights.Edge{
+			{FromNode: 1, ToNode: 3, Requirement: "^1.0.0"},
+			{FromNode: 0, ToNode: 1, Requirement: "^1.0.0"},
+			{FromNode: 0, ToNode: 2, Requirement: "<2"},
+			{FromNode: 2, ToNode: 3, Requirement: "1.x"},
+		},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	l := newLayout(testGraph())
+	type placed struct {
+		Label string
+		Layer int
+		X     int
+	}
+	var got []placed
+	for _, b := range l.boxes {
+		got = append(got, placed{b.label, b.layer, b.x})
+	}
+	// b and c are ordered by label, and both centered under a.
+	w := len("a@1.0.0")*charWidth + 2*nodePadX
+	want := []placed{
+		{"a@1.0.0", 0, margin + (w+nodeGap)/2},
+		{"c@1.0.0", 1, margin + w + nodeGap},
+		{"b@1.0.0", 1, margin},
+		{"d@1.0.0", 2, margin + (w+nodeGap)/2},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("layout mismatch (-want +got):\n%s", diff)
+	}
+	if len(l.lines) != 4 {
+		t.Fatalf("layout has %d lines; want 4", len(l.lines))
+	}
+	// Lines start at the bottom of a and end at the top of its dependencies.
+	a := l.boxes[0]
+	if ln := l.lines[0]; ln.y1 != float64(a.y+a.h) || ln.y2 != float64(l.boxes[1].y) {
+		t.Errorf("line from a to c is %+v; want from the bottom of a to the top of c", ln)
+	}
+}
+
+func TestLayoutEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, &insights.Dependencies{}); err != nil {
+		t.Fatalf("WriteSVG failed: %v", err)
+	}
+	if err := WritePNG(&buf, &insights.Dependencies{}); err != nil {
+		t.Fatalf("WritePNG failed: %v", err)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf, again bytes.Buffer
+	if err := WriteSVG(&buf, testGraph()); err != nil {
+		t.Fatalf("WriteSVG failed: %v", err)
+	}
+	WriteSVG(&again, testGraph())
+	if buf.String() != again.String() {
+		t.Error("WriteSVG output differs between runs")
+	}
+	svg := buf.String()
+	for _, s := range []string{"<svg ", ">a@1.0.0</text>", "<title>&lt;2</title>", "could not resolve e", errorStroke} {
+		if !strings.Contains(svg, s) {
+			t.Errorf("SVG does not contain %q:\n%s", s, svg)
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, testGraph()); err != nil {
+		t.Fatalf("WritePNG failed: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	l := newLayout(testGraph())
+	if b := img.Bounds(); b.Dx() != l.width || b.Dy() != l.height {
+		t.Errorf("PNG is %dx%d; want %dx%d", b.Dx(), b.Dy(), l.width, l.height)
+	}
+}
+
+func TestWritePNGTooLarge(t *testing.T) {
+	// A package with many direct dependencies draws a wide graph.
+	g := &insights.Dependencies{Nodes: []insights.Node{{VersionKey: insights.VersionKey{System: "NPM", Name: "root", Version: "1.0.0"}, Relation: "SELF"}}}
+	for i := range 8000 {
+		g.Nodes = append(g.Nodes, insights.Node{VersionKey: insights.VersionKey{System: "NPM", Name: fmt.Sprintf("dep-%d", i), Version: "1.0.0"}, Relation: "DIRECT"})
+		g.Edges = append(g.Edges, insights.Edge{FromNode: 0, ToNode: i + 1})
+	}
+	l := newLayout(g)
+	if l.width*l.height <= MaxPNGPixels {
+		t.Fatalf("test graph is only %dx%d pixels", l.width, l.height)
+	}
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, g); err == nil || !strings.Contains(err.Error(), "SVG or DOT") {
+		t.Errorf("WritePNG returned error %v; want one pointing to SVG or DOT", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WritePNG wrote %d bytes", buf.Len())
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, testGraph()); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	want := `digraph dependencies {
+	node [shape=box];
+	n0 [label="a@1.0.0", style=bold];
+	n1 [label="c@1.0.0"];
+	n2 [label="b@1.0.0"];
+	n3 [label="d@1.0.0", color=red];
+	n0 -> n1 [label="^1.0.0"];
+	n0 -> n2 [label="<2"];
+	n1 -> n3 [label="^1.0.0"];
+	n2 -> n3 [label="1.x"];
+}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteDOT mismatch (-want +got):\n%s", diff)
+	}
+}
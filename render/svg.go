@@ -0,0 +1,72 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// style is the colors of a node, by its relation to the root.
+type style struct {
+	fill, stroke string
+}
+
+var styles = map[string]style{
+	"SELF":     {"#dbeafe", "#1d4ed8"},
+	"DIRECT":   {"#dcfce7", "#15803d"},
+	"INDIRECT": {"#f3f4f6", "#6b7280"},
+}
+
+// errorStroke is the border color of nodes with errors.
+const errorStroke = "#dc2626"
+
+const edgeColor = "#9ca3af"
+
+// nodeStyle returns the style of the node n.
+func nodeStyle(n *insights.Node) style {
+	s, ok := styles[n.Relation]
+	if !ok {
+		s = styles["INDIRECT"]
+	}
+	if len(n.Errors) > 0 {
+		s.stroke = errorStroke
+	}
+	return s
+}
+
+// WriteSVG draws g as an SVG image to w. The root is at the top and each
+// dependency is below the first node from which it is reached. Hovering
+// over an edge shows its requirement, and over a node, its errors.
+func WriteSVG(w io.Writer, g *insights.Dependencies) error {
+	l := newLayout(g)
+	bw := &errWriter{w: w}
+	bw.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="%[2]d" viewBox="0 0 %[1]d %[2]d" font-family="Verdana,DejaVu Sans,sans-serif" font-size="12">
+<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0L10,5L0,10z" fill="%[3]s"/></marker></defs>
+<rect width="100%%" height="100%%" fill="#fff"/>
+`, l.width, l.height, edgeColor)
+	for _, ln := range l.lines {
+		bw.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" marker-end="url(#arrow)">`, ln.x1, ln.y1, ln.x2, ln.y2, edgeColor)
+		if ln.edge.Requirement != "" {
+			bw.printf("<title>%s</title>", html.EscapeString(ln.edge.Requirement))
+		}
+		bw.printf("</line>\n")
+	}
+	for _, b := range l.boxes {
+		s := nodeStyle(b.node)
+		cx, cy := b.center()
+		title := b.label
+		if len(b.node.Errors) > 0 {
+			title += "\n" + strings.Join(b.node.Errors, "\n")
+		}
+		bw.printf(`<g><title>%s</title><rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" stroke="%s"/><text x="%.1f" y="%.1f" text-anchor="middle">%s</text></g>`+"\n",
+			html.EscapeString(title), b.x, b.y, b.w, b.h, s.fill, s.stroke, cx, cy+4, html.EscapeString(b.label))
+	}
+	bw.printf("</svg>\n")
+	return bw.err
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy tells a Client how to retry requests that failed because of
+// a network error, a rate limit (429 Too Many Requests), or a transient
+// server error (500, 502, 503, or 504). Its Do method retries requests to
+// other services the same way.
+type RetryPolicy struct {
+	// The maximum number of times a request is retried.
+	MaxRetries int
+
+	// The wait before the first retry and the longest wait between
+	// retries. Waits double after each retry, up to MaxWait, and are
+	// randomized between half and all of that so that clients failing
+	// together do not retry together. If zero, MinWait is 500ms and
+	// MaxWait 30s.
+	MinWait time.Duration
+	MaxWait time.Duration
+}
+
+// wait returns how long to wait before retrying a request for the nth
+// time, counting from zero, after it failed with resp, which may be nil.
+// Waits asked for by the Retry-After header of resp are honored.
+func (p *RetryPolicy) wait(n int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+	lo := p.MinWait
+	if lo <= 0 {
+		lo = 500 * time.Millisecond
+	}
+	hi := p.MaxWait
+	if hi <= 0 {
+		hi = 30 * time.Second
+	}
+	d := lo
+	for i := 0; i < n && d < hi; i++ {
+		d *= 2
+	}
+	d = min(d, hi)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// retryable reports whether a request that failed with resp or err is
+// worth sending again. Requests canceled by their context are not.
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Do sends req with hc, retrying it as p says, for the HTTP clients of
+// services other than deps.dev, such as those of package registries. A
+// retry that would not be sent before the deadline of the context of req
+// is not waited for: the last failure is returned instead. If p is nil,
+// req is sent once.
+func (p *RetryPolicy) Do(hc *http.Client, req *http.Request) (*http.Response, error) {
+	return p.send(hc, req, nil)
+}
+
+// send is Do, logging each attempt with log, if not nil.
+func (p *RetryPolicy) send(hc *http.Client, req *http.Request, log func(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)) (*http.Response, error) {
+	if log == nil {
+		log = func(context.Context, slog.Level, string, ...slog.Attr) {}
+	}
+	ctx := req.Context()
+	key := req.URL.String()
+	for n := 0; ; n++ {
+		if n > 0 && req.GetBody != nil {
+			// The body was read by the last attempt.
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		start := time.Now()
+		hresp, err := hc.Do(req)
+		if err != nil {
+			log(ctx, slog.LevelInfo, "request failed", slog.String("url", key), slog.Any("error", err))
+		} else {
+			log(ctx, slog.LevelInfo, "request", slog.String("url", key), slog.Int("status", hresp.StatusCode), slog.Duration("duration", time.Since(start)))
+		}
+		if p == nil || n >= p.MaxRetries || !retryable(ctx, hresp, err) {
+			return hresp, err
+		}
+		wait := p.wait(n, hresp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return hresp, err
+		}
+		if hresp != nil {
+			io.Copy(io.Discard, io.LimitReader(hresp.Body, maxErrorBytes))
+			hresp.Body.Close()
+		}
+		log(ctx, slog.LevelDebug, "retrying request", slog.String("url", key), slog.Int("attempt", n+1), slog.Duration("wait", wait))
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// send sends req with hc, retrying it as the client's Retry policy says,
+// and logs each attempt.
+func (c *Client) send(hc *http.Client, req *http.Request) (*http.Response, error) {
+	return c.Retry.send(hc, req, c.log)
+}
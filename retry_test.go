@@ -0,0 +1,142 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	client, mux := setup(t)
+	client.Retry = &RetryPolicy{MaxRetries: 3, MinWait: time.Millisecond, MaxWait: time.Millisecond}
+
+	var n int
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		n++
+		switch n {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `{"packageKey":{"system":"GO","name":"foo"}}`)
+		}
+	})
+	if _, _, err := client.GetPackage(context.Background(), "go", "foo"); err != nil {
+		t.Fatalf("GetPackage failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("GetPackage sent %d requests, want 3", n)
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	var n int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if n == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		policy *RetryPolicy
+		status int
+		sent   int
+	}{
+		{nil, http.StatusServiceUnavailable, 1},
+		{&RetryPolicy{MaxRetries: 1, MinWait: time.Millisecond}, http.StatusOK, 2},
+	}
+	for _, tt := range tests {
+		n = 0
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := tt.policy.Do(http.DefaultClient, req)
+		if err != nil {
+			t.Fatalf("Do failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || n != tt.sent {
+			t.Errorf("Do with policy %+v = %d after %d requests; want %d after %d", tt.policy, resp.StatusCode, n, tt.status, tt.sent)
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	client, mux := setup(t)
+	client.Retry = &RetryPolicy{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: time.Millisecond}
+
+	var n int
+	mux.HandleFunc("/systems/go/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		n++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/systems/go/packages/bar", func(w http.ResponseWriter, r *http.Request) {
+		n++
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	ctx := context.Background()
+	_, _, err := client.GetPackage(ctx, "go", "foo")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("GetPackage returned error %v, want 503", err)
+	}
+	if n != 3 {
+		t.Errorf("GetPackage sent %d requests, want 3", n)
+	}
+
+	// Client errors are not retried.
+	n = 0
+	if _, _, err := client.GetPackage(ctx, "go", "bar"); err == nil {
+		t.Error("GetPackage succeeded, want 404")
+	}
+	if n != 1 {
+		t.Errorf("GetPackage sent %d requests, want 1", n)
+	}
+
+	// Waits past the deadline are not waited for.
+	n = 0
+	mux.HandleFunc("/systems/go/packages/baz", func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.Header().Set("Retry-After", "3600")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if _, _, err := client.GetPackage(ctx, "go", "baz"); err == nil {
+		t.Error("GetPackage succeeded, want 429")
+	}
+	if n != 1 {
+		t.Errorf("GetPackage sent %d requests, want 1", n)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	p := &RetryPolicy{MinWait: time.Second, MaxWait: 4 * time.Second}
+	for n, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		if d := p.wait(n, nil); d < max/2 || d > max {
+			t.Errorf("wait(%d) = %v, want between %v and %v", n, d, max/2, max)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"7"}}}
+	if d := p.wait(0, resp); d != 7*time.Second {
+		t.Errorf("wait with Retry-After: 7 = %v, want 7s", d)
+	}
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if d := p.wait(0, resp); d < 58*time.Second || d > time.Minute {
+		t.Errorf("wait with Retry-After date a minute away = %v", d)
+	}
+}
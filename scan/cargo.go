@@ -0,0 +1,114 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+type cargoPackage struct {
+	name, version, source string
+	dependencies          []string
+}
+
+// parseCargoLock parses a Cargo.lock file. Only crates from a registry are
+// reported; the crates of the workspace itself have no source. A crate is
+// direct if a workspace crate depends on it.
+func parseCargoLock(data []byte) ([]Dependency, error) {
+	var pkgs []*cargoPackage
+	var cur *cargoPackage
+	inDeps := false
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if inDeps {
+			// Inside a multi-line dependencies array.
+			if line == "]" {
+				inDeps = false
+				continue
+			}
+			cur.dependencies = append(cur.dependencies, tomlString(strings.TrimSuffix(line, ",")))
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			cur = nil
+			if line == "[[package]]" {
+				cur = new(cargoPackage)
+				pkgs = append(pkgs, cur)
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: malformed key/value pair", n)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "name":
+			cur.name = tomlString(value)
+		case "version":
+			cur.version = tomlString(value)
+		case "source":
+			cur.source = tomlString(value)
+		case "dependencies":
+			if value == "[" {
+				inDeps = true
+				continue
+			}
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+			for _, d := range strings.Split(value, ",") {
+				if d = strings.TrimSpace(d); d != "" {
+					cur.dependencies = append(cur.dependencies, tomlString(d))
+				}
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	// Dependencies are listed as "name" or, when ambiguous, as
+	// "name version" or "name version (source)".
+	direct := make(map[string]bool)
+	for _, p := range pkgs {
+		if p.source != "" {
+			continue
+		}
+		for _, d := range p.dependencies {
+			f := strings.Fields(d)
+			direct[f[0]] = true
+			if len(f) > 1 {
+				direct[f[0]+" "+f[1]] = true
+			}
+		}
+	}
+	var deps []Dependency
+	for _, p := range pkgs {
+		if !strings.HasPrefix(p.source, "registry+") && !strings.HasPrefix(p.source, "sparse+") {
+			continue
+		}
+		deps = append(deps, Dependency{
+			VersionKey: insights.VersionKey{System: "CARGO", Name: p.name, Version: p.version},
+			Direct:     direct[p.name+" "+p.version] || direct[p.name],
+		})
+	}
+	return uniq(deps), nil
+}
+
+// tomlString returns the contents of a TOML basic string.
+func tomlString(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"`)
+}
@@ -0,0 +1,293 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/version"
+)
+
+// parseGoMod parses the require directives of a go.mod file. Requirements
+// marked with an "// indirect" comment are reported as indirect.
+func parseGoMod(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	inBlock := false
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		var comment string
+		if i := strings.Index(line, "//"); i >= 0 {
+			line, comment = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+2:])
+		}
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "require (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require"))
+		default:
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		f := strings.Fields(line)
+		if len(f) != 2 {
+			return nil, fmt.Errorf("line %d: malformed requirement", n)
+		}
+		deps = append(deps, Dependency{
+			VersionKey: insights.VersionKey{System: "GO", Name: unquote(f[0]), Version: unquote(f[1])},
+			Direct:     comment != "indirect" && !strings.HasPrefix(comment, "indirect;"),
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return uniq(deps), nil
+}
+
+// unquote removes the double quotes or backquotes go.mod allows around
+// module paths and versions.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '`') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// parseGoWork returns the directories of the modules named by the use
+// directives of a go.work file, as written in the file.
+func parseGoWork(data []byte) ([]string, error) {
+	var dirs []string
+	inBlock := false
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "use (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "use"))
+		default:
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) != 1 {
+			return nil, fmt.Errorf("line %d: malformed use directive", n)
+		}
+		dirs = append(dirs, unquote(f[0]))
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+// modulePath returns the path declared by the module directive of a go.mod
+// file, or "" if there is none.
+func modulePath(data []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+		if len(f) >= 2 && f[0] == "module" {
+			return unquote(f[1])
+		}
+	}
+	return ""
+}
+
+// parseVendorModules parses a vendor/modules.txt file, as written by go mod
+// vendor, into the module versions vendored. Modules marked "## explicit"
+// are required by go.mod, and reported as direct. A replaced module is
+// reported as its replacement, the code actually vendored, and left out if
+// replaced by a local directory.
+func parseVendorModules(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	var last *Dependency
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case strings.HasPrefix(line, "## "):
+			if last != nil {
+				for _, a := range strings.Split(strings.TrimPrefix(line, "## "), ";") {
+					if strings.TrimSpace(a) == "explicit" {
+						last.Direct = true
+					}
+				}
+			}
+			continue
+		case strings.HasPrefix(line, "# "):
+		default:
+			// A package of the last module.
+			continue
+		}
+
+		last = nil
+		f := strings.Fields(strings.TrimPrefix(line, "# "))
+		if i := slices.Index(f, "=>"); i >= 0 {
+			// Replacements of every version of a module have no version on
+			// the left, and local ones none on the right.
+			if i+3 != len(f) {
+				continue
+			}
+			f = f[i+1:]
+		}
+		if len(f) != 2 {
+			return nil, fmt.Errorf("line %d: malformed module line", n)
+		}
+		deps = append(deps, Dependency{
+			VersionKey: insights.VersionKey{System: "GO", Name: f[0], Version: f[1]},
+		})
+		last = &deps[len(deps)-1]
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return uniq(deps), nil
+}
+
+// vendorModules returns the module versions vendored in the vendor
+// directory next to the named go.mod or go.work file, and the
+// vendor/modules.txt file listing them, if there is one. Vendored code may
+// drift from the versions go.mod requires, so both are worth looking up.
+func vendorModules(file string) ([]Dependency, string, error) {
+	txt := filepath.Join(filepath.Dir(file), "vendor", "modules.txt")
+	data, err := os.ReadFile(txt)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	deps, err := parseVendorModules(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %v", txt, err)
+	}
+	for i := range deps {
+		deps[i].File = txt
+	}
+	return deps, txt, nil
+}
+
+// goModule returns the dependencies of the Go module defined by the named
+// go.mod file: its requirements and, if it has a vendor directory, the
+// module versions vendored in it.
+func goModule(file string) ([]Dependency, []string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	deps, err := Parse(file, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	vendored, txt, err := vendorModules(file)
+	if err != nil || txt == "" {
+		return deps, []string{file}, err
+	}
+	return uniq(append(deps, vendored...)), []string{file, txt}, nil
+}
+
+// goWorkspace returns the dependencies of the modules of the Go workspace
+// defined by the named go.work file. Like the go command in workspace mode,
+// it selects the greatest version required of each module, which is direct
+// if any workspace module requires it directly, and leaves out the
+// workspace modules themselves. Each dependency records the go.mod file and
+// directory, as its Subproject, of the first workspace module requiring the
+// selected version. The module versions vendored in the vendor directory of
+// the workspace, if any, are included too.
+func goWorkspace(file string) ([]Dependency, []string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	dirs, err := parseGoWork(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", file, err)
+	}
+	local := make(map[string]bool)
+	var mods []string
+	var all []Dependency
+	for _, dir := range dirs {
+		mod := filepath.Join(filepath.Dir(file), filepath.FromSlash(dir), "go.mod")
+		data, err := os.ReadFile(mod)
+		if err != nil {
+			return nil, nil, err
+		}
+		local[modulePath(data)] = true
+		mods = append(mods, mod)
+		deps, err := Parse(mod, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sub := path.Clean(dir); sub != "." {
+			for i := range deps {
+				deps[i].Subproject = sub
+			}
+		}
+		all = append(all, deps...)
+	}
+
+	selected := make(map[string]int)
+	direct := make(map[string]bool)
+	var deps []Dependency
+	for _, d := range all {
+		name := d.VersionKey.Name
+		if local[name] {
+			continue
+		}
+		direct[name] = direct[name] || d.Direct
+		i, ok := selected[name]
+		switch {
+		case !ok:
+			selected[name] = len(deps)
+			deps = append(deps, d)
+		case version.Compare("GO", d.VersionKey.Version, deps[i].VersionKey.Version) > 0:
+			deps[i] = d
+		}
+	}
+	for i := range deps {
+		deps[i].Direct = direct[deps[i].VersionKey.Name]
+	}
+	vendored, txt, err := vendorModules(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if txt != "" {
+		deps = append(deps, vendored...)
+		mods = append(mods, txt)
+	}
+	return uniq(deps), mods, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// parseGradleLock parses a Gradle dependency lockfile: either the
+// gradle.lockfile holding the locks of all the configurations of a project,
+// or one of the per-configuration lockfiles of older Gradle versions, such
+// as gradle/dependency-locks/compileClasspath.lockfile. Lockfiles do not
+// record which modules the project depends on directly, so all of them are
+// reported as indirect.
+func parseGradleLock(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// The configurations locking the module follow "=" in
+		// gradle.lockfile, where "empty=..." lists those locking none.
+		line, _, _ = strings.Cut(line, "=")
+		if line == "empty" {
+			continue
+		}
+		f := strings.Split(line, ":")
+		if len(f) != 3 {
+			return nil, fmt.Errorf("line %d: malformed module coordinates", n)
+		}
+		deps = append(deps, Dependency{
+			VersionKey: insights.VersionKey{System: "MAVEN", Name: f[0] + ":" + f[1], Version: f[2]},
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return uniq(deps), nil
+}
@@ -0,0 +1,283 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// pom is the part of a Maven POM relevant to finding its dependencies.
+type pom struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID      string  `xml:"groupId"`
+		ArtifactID   string  `xml:"artifactId"`
+		Version      string  `xml:"version"`
+		RelativePath *string `xml:"relativePath"`
+	} `xml:"parent"`
+	Properties           pomProperties `xml:"properties"`
+	Modules              []string      `xml:"modules>module"`
+	DependencyManagement []pomDep      `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []pomDep      `xml:"dependencies>dependency"`
+}
+
+type pomDep struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
+
+// pomProperties holds the properties of a POM, which are elements named
+// after the properties.
+type pomProperties map[string]string
+
+func (p *pomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*p = make(pomProperties)
+	for _, e := range v.Entries {
+		(*p)[e.XMLName.Local] = strings.TrimSpace(e.Value)
+	}
+	return nil
+}
+
+// groupID returns the group ID of p, which may be inherited from its parent.
+func (p *pom) groupID() string {
+	if p.GroupID != "" {
+		return p.GroupID
+	}
+	return p.Parent.GroupID
+}
+
+// version returns the version of p, which may be inherited from its parent.
+func (p *pom) version() string {
+	if p.Version != "" {
+		return p.Version
+	}
+	return p.Parent.Version
+}
+
+// mavenProject is a POM along with the POMs it inherits from that could be
+// found locally, nearest first.
+type mavenProject struct {
+	file    string
+	pom     *pom
+	parents []*pom
+}
+
+// properties returns the properties of the project for interpolation,
+// including the inherited ones and the built-in project properties.
+func (m *mavenProject) properties() map[string]string {
+	props := make(map[string]string)
+	for i := len(m.parents) - 1; i >= 0; i-- {
+		for k, v := range m.parents[i].Properties {
+			props[k] = v
+		}
+	}
+	for k, v := range m.pom.Properties {
+		props[k] = v
+	}
+	props["project.groupId"] = m.pom.groupID()
+	props["project.artifactId"] = m.pom.ArtifactID
+	props["project.version"] = m.pom.version()
+	props["project.parent.groupId"] = m.pom.Parent.GroupID
+	props["project.parent.version"] = m.pom.Parent.Version
+	props["pom.version"] = m.pom.version()
+	props["version"] = m.pom.version()
+	return props
+}
+
+// interpolate replaces the ${name} references to properties in s. References
+// to unknown properties are left as they are.
+func interpolate(s string, props map[string]string) string {
+	// Bounded, in case properties refer to each other in a cycle.
+	for range 10 {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			return s
+		}
+		j := strings.Index(s[i:], "}")
+		if j < 0 {
+			return s
+		}
+		v, ok := props[s[i+2:i+j]]
+		if !ok {
+			return s
+		}
+		s = s[:i] + v + s[i+j+1:]
+	}
+	return s
+}
+
+// dependencies returns the dependencies of the project, leaving out the
+// artifacts of the reactor, given by "groupId:artifactId", and those whose
+// exact version cannot be determined locally: version ranges and versions
+// managed by a parent POM or BOM that is not available.
+func (m *mavenProject) dependencies(reactor map[string]bool) []Dependency {
+	props := m.properties()
+	managed := make(map[string]string)
+	for i := len(m.parents) - 1; i >= -1; i-- {
+		p := m.pom
+		if i >= 0 {
+			p = m.parents[i]
+		}
+		for _, d := range p.DependencyManagement {
+			if d.Scope != "import" {
+				managed[interpolate(d.GroupID, props)+":"+interpolate(d.ArtifactID, props)] = d.Version
+			}
+		}
+	}
+
+	var deps []Dependency
+	for _, d := range m.pom.Dependencies {
+		name := interpolate(d.GroupID, props) + ":" + interpolate(d.ArtifactID, props)
+		if reactor[name] || d.Scope == "system" {
+			continue
+		}
+		v := d.Version
+		if v == "" {
+			v = managed[name]
+		}
+		v = interpolate(v, props)
+		if v == "" || strings.Contains(v, "${") || strings.ContainsAny(v, "[(,") {
+			continue
+		}
+		deps = append(deps, Dependency{
+			VersionKey: insights.VersionKey{System: "MAVEN", Name: name, Version: v},
+			Direct:     true,
+		})
+	}
+	return deps
+}
+
+// parsePOM parses a Maven pom.xml file on its own, without its parent POM.
+func parsePOM(data []byte) ([]Dependency, error) {
+	var p pom
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	m := &mavenProject{pom: &p}
+	return uniq(m.dependencies(nil)), nil
+}
+
+// readPOM reads and parses the named POM.
+func readPOM(file string) (*pom, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := new(pom)
+	if err := xml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return p, nil
+}
+
+// mavenReactor returns the dependencies of the Maven project defined by the
+// named pom.xml file and, if it is an aggregator, of the modules of its
+// reactor, recursively. Properties and managed versions are inherited from
+// the parent POMs found locally. Dependencies on artifacts of the reactor
+// are resolved locally and left out. Each dependency records the POM
+// declaring it and the directory of its module as its Subproject.
+func mavenReactor(file string) ([]Dependency, []string, error) {
+	root := filepath.Dir(file)
+	var projects []*mavenProject
+	seen := make(map[string]bool)
+	var load func(file string) error
+	load = func(file string) error {
+		file = filepath.Clean(file)
+		if seen[file] {
+			return nil
+		}
+		seen[file] = true
+		p, err := readPOM(file)
+		if err != nil {
+			return err
+		}
+		m := &mavenProject{file: file, pom: p}
+		m.parents = localParents(file, p)
+		projects = append(projects, m)
+		for _, mod := range p.Modules {
+			f := filepath.Join(filepath.Dir(file), filepath.FromSlash(mod))
+			if fi, err := os.Stat(f); err == nil && fi.IsDir() {
+				f = filepath.Join(f, "pom.xml")
+			}
+			if err := load(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := load(file); err != nil {
+		return nil, nil, err
+	}
+
+	reactor := make(map[string]bool)
+	for _, m := range projects {
+		reactor[m.pom.groupID()+":"+m.pom.ArtifactID] = true
+	}
+	var deps []Dependency
+	var files []string
+	for _, m := range projects {
+		files = append(files, m.file)
+		rel, err := filepath.Rel(root, filepath.Dir(m.file))
+		if err != nil {
+			return nil, nil, err
+		}
+		sub := path.Clean(filepath.ToSlash(rel))
+		if sub == "." {
+			sub = ""
+		}
+		for _, d := range m.dependencies(reactor) {
+			d.File, d.Subproject = m.file, sub
+			deps = append(deps, d)
+		}
+	}
+	return uniq(deps), files, nil
+}
+
+// localParents returns the chain of parent POMs of p, the POM in the named
+// file, that are found at their relative paths, nearest first. The chain
+// stops at the first parent that is not found or does not match.
+func localParents(file string, p *pom) []*pom {
+	var parents []*pom
+	for range 10 {
+		rel := "../pom.xml"
+		if p.Parent.RelativePath != nil {
+			rel = *p.Parent.RelativePath
+		}
+		if p.Parent.ArtifactID == "" || rel == "" {
+			break
+		}
+		f := filepath.Join(filepath.Dir(file), filepath.FromSlash(rel))
+		if fi, err := os.Stat(f); err == nil && fi.IsDir() {
+			f = filepath.Join(f, "pom.xml")
+		}
+		parent, err := readPOM(f)
+		if err != nil || parent.ArtifactID != p.Parent.ArtifactID || parent.groupID() != p.Parent.GroupID {
+			break
+		}
+		parents = append(parents, parent)
+		file, p = f, parent
+	}
+	return parents
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+type packageLock struct {
+	LockfileVersion int
+
+	// Lockfile versions 2 and 3 list every package by its location in the
+	// installed tree: "" is the project itself and "node_modules/a" is the
+	// package a.
+	Packages map[string]struct {
+		Version              string
+		Link                 bool
+		Dependencies         map[string]string
+		DevDependencies      map[string]string
+		OptionalDependencies map[string]string
+		PeerDependencies     map[string]string
+	}
+
+	// Lockfile version 1 nests the packages instead.
+	Dependencies map[string]packageLockV1Dep
+}
+
+type packageLockV1Dep struct {
+	Version      string
+	Bundled      bool
+	Dependencies map[string]packageLockV1Dep
+}
+
+// parsePackageLock parses an npm package-lock.json file. Version 1 lockfiles
+// do not record which packages the project depends on directly, so all of
+// their dependencies are reported as indirect.
+//
+// In an npm workspace, the dependencies declared by a workspace package are
+// direct and attributed to it, by recording its directory as their
+// Subproject. Workspace packages themselves are linked rather than installed
+// from the registry, so they are left out.
+func parsePackageLock(data []byte) ([]Dependency, error) {
+	var lock packageLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	if lock.Packages != nil {
+		// The names of the dependencies declared by the project, under "",
+		// and by each workspace package, under its directory.
+		declared := make(map[string]map[string]bool)
+		var workspaces []string
+		for path, p := range lock.Packages {
+			if strings.Contains(path, "node_modules/") || p.Link {
+				continue
+			}
+			if path != "" {
+				workspaces = append(workspaces, path)
+			}
+			declared[path] = make(map[string]bool)
+			for _, m := range []map[string]string{p.Dependencies, p.DevDependencies, p.OptionalDependencies, p.PeerDependencies} {
+				for name := range m {
+					declared[path][name] = true
+				}
+			}
+		}
+		sort.Strings(workspaces)
+
+		for path, p := range lock.Packages {
+			i := strings.LastIndex(path, "node_modules/")
+			if i < 0 || p.Link || p.Version == "" {
+				continue
+			}
+			name := path[i+len("node_modules/"):]
+			d := Dependency{VersionKey: insights.VersionKey{System: "NPM", Name: name, Version: p.Version}}
+			// The workspace package the dependency is installed for, or ""
+			// for the project or packages hoisted to its node_modules.
+			owner := strings.TrimSuffix(path[:strings.Index(path, "node_modules/")], "/")
+			if path == strings.TrimPrefix(owner+"/node_modules/"+name, "/") {
+				switch {
+				case declared[owner][name]:
+					d.Direct, d.Subproject = true, owner
+				case owner == "":
+					// Hoisted for the first workspace package declaring
+					// it without installing its own version.
+					for _, ws := range workspaces {
+						if _, own := lock.Packages[ws+"/node_modules/"+name]; declared[ws][name] && !own {
+							d.Direct, d.Subproject = true, ws
+							break
+						}
+					}
+				}
+			}
+			deps = append(deps, d)
+		}
+	} else {
+		var walk func(m map[string]packageLockV1Dep)
+		walk = func(m map[string]packageLockV1Dep) {
+			for name, d := range m {
+				// Versions of linked or git dependencies are not
+				// registry versions.
+				if d.Version != "" && !strings.Contains(d.Version, ":") {
+					deps = append(deps, Dependency{
+						VersionKey: insights.VersionKey{System: "NPM", Name: name, Version: d.Version},
+					})
+				}
+				walk(d.Dependencies)
+			}
+		}
+		walk(lock.Dependencies)
+	}
+	return uniq(deps), nil
+}
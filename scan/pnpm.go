@@ -0,0 +1,107 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"gopkg.in/yaml.v3"
+)
+
+// pnpmDeps maps the names of the dependencies of a pnpm project to their
+// resolved versions.
+type pnpmDeps map[string]struct {
+	Version string `yaml:"version"`
+}
+
+// pnpmImporter holds the dependencies of a pnpm project.
+type pnpmImporter struct {
+	Dependencies         pnpmDeps `yaml:"dependencies"`
+	DevDependencies      pnpmDeps `yaml:"devDependencies"`
+	OptionalDependencies pnpmDeps `yaml:"optionalDependencies"`
+}
+
+type pnpmLock struct {
+	LockfileVersion string `yaml:"lockfileVersion"`
+
+	// The projects of the workspace, by directory; "." is the root. A
+	// lockfile without workspace has the importer of the root inline.
+	Importers    map[string]pnpmImporter `yaml:"importers"`
+	pnpmImporter `yaml:",inline"`
+
+	// Every installed package, keyed by "name@version", with a leading
+	// slash before version 9.
+	Packages map[string]yaml.Node `yaml:"packages"`
+}
+
+// parsePNPMLock parses a pnpm-lock.yaml file, version 6 or later. The
+// dependencies declared by each project of a pnpm workspace, as listed in
+// pnpm-workspace.yaml, are direct and attributed to it, by recording its
+// directory as their Subproject. Dependencies on other projects of the
+// workspace are links, not registry versions, and are left out.
+func parsePNPMLock(data []byte) ([]Dependency, error) {
+	var lock pnpmLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	if lock.Importers == nil {
+		lock.Importers = map[string]pnpmImporter{".": lock.pnpmImporter}
+	}
+
+	var deps []Dependency
+	for dir, imp := range lock.Importers {
+		if dir == "." {
+			dir = ""
+		}
+		for _, m := range []pnpmDeps{imp.Dependencies, imp.DevDependencies, imp.OptionalDependencies} {
+			for name, d := range m {
+				if strings.HasPrefix(d.Version, "link:") || strings.HasPrefix(d.Version, "file:") {
+					continue
+				}
+				deps = append(deps, Dependency{
+					VersionKey: insights.VersionKey{System: "NPM", Name: name, Version: pnpmVersion(d.Version)},
+					Direct:     true,
+					Subproject: dir,
+				})
+			}
+		}
+	}
+	for key := range lock.Packages {
+		name, version, err := pnpmPackage(key)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(version, ":") {
+			continue
+		}
+		deps = append(deps, Dependency{
+			VersionKey: insights.VersionKey{System: "NPM", Name: name, Version: version},
+		})
+	}
+	return uniq(deps), nil
+}
+
+// pnpmVersion removes the peer dependencies pnpm appends to versions, as in
+// "1.0.0(react@18.2.0)".
+func pnpmVersion(v string) string {
+	if i := strings.Index(v, "("); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
+
+// pnpmPackage splits the key of a package in a pnpm lockfile, such as
+// "/@scope/a@1.0.0(react@18.2.0)", into its name and version.
+func pnpmPackage(key string) (name, version string, err error) {
+	key = pnpmVersion(strings.TrimPrefix(key, "/"))
+	// The name of a scoped package starts with "@".
+	i := strings.LastIndex(key, "@")
+	if i <= 0 {
+		return "", "", fmt.Errorf("malformed package key %q", key)
+	}
+	return key[:i], key[i+1:], nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/marker"
+)
+
+// parseRequirements parses a pip requirements file. Only requirements pinned
+// to an exact version with "==" are reported, as they are the only ones that
+// identify a single package version. Options, file references, and URLs are
+// ignored. Environment markers are kept in the dependencies, to be
+// evaluated by Select.
+func parseRequirements(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line, m := marker.Split(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		name, version, ok := strings.Cut(line, "==")
+		if !ok || strings.ContainsAny(version, "<>!=~,*") {
+			continue
+		}
+		// Extras, as in "requests[security]==2.31.0".
+		if i := strings.Index(name, "["); i >= 0 {
+			name = name[:i]
+		}
+		deps = append(deps, Dependency{
+			VersionKey: insights.VersionKey{System: "PYPI", Name: strings.TrimSpace(name), Version: strings.TrimSpace(version)},
+			Direct:     true,
+			Marker:     m,
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return uniq(deps), nil
+}
+
+// Select returns the dependencies of deps that apply to the Python
+// environment env: those without an environment marker, and those whose
+// marker holds in env. Dependencies with invalid markers are kept, as they
+// may apply.
+func Select(deps []Dependency, env *marker.Environment) []Dependency {
+	var out []Dependency
+	for _, d := range deps {
+		if d.Marker != "" {
+			if m, err := marker.Parse(d.Marker); err == nil && !m.Evaluate(env) {
+				continue
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
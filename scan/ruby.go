@@ -0,0 +1,71 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// parseGemfileLock parses a Bundler Gemfile.lock file. Only the gems of GEM
+// sections, installed from a gem server, are reported; gems from git
+// repositories or local paths are not registry versions. The gems listed
+// under DEPENDENCIES are direct.
+func parseGemfileLock(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	direct := make(map[string]bool)
+	var section string
+	inSpecs := false
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			section, inSpecs = strings.TrimSpace(line), false
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		line = strings.TrimSpace(line)
+		switch section {
+		case "GEM":
+			if indent == 2 {
+				inSpecs = line == "specs:"
+				continue
+			}
+			// Gems are indented by four spaces, their own dependencies
+			// by six.
+			if !inSpecs || indent != 4 {
+				continue
+			}
+			name, version, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			version = strings.Trim(version, "()")
+			// Platform-specific gems, as in "nokogiri (1.16.0-x86_64-linux)".
+			version, _, _ = strings.Cut(version, "-")
+			deps = append(deps, Dependency{
+				VersionKey: insights.VersionKey{System: "RUBYGEMS", Name: name, Version: version},
+			})
+		case "DEPENDENCIES":
+			// As in "rails (~> 7.0)", or "rails!" for a gem from another
+			// source.
+			name, _, _ := strings.Cut(line, " ")
+			direct[strings.TrimSuffix(name, "!")] = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	for i := range deps {
+		deps[i].Direct = direct[deps[i].VersionKey.Name]
+	}
+	return uniq(deps), nil
+}
@@ -0,0 +1,281 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scan finds the package versions a project depends on by reading
+// its manifests and lockfiles.
+package scan
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// Dependency is a package version found in a manifest or lockfile.
+type Dependency struct {
+	// The package version.
+	VersionKey insights.VersionKey
+
+	// Whether the project depends on the package version directly, as
+	// opposed to through another dependency.
+	Direct bool
+
+	// The file the dependency was found in.
+	File string
+
+	// The directory of the subproject declaring the dependency, relative to
+	// the root given to Tree, or to the directory of the file for the
+	// packages of a workspace defined in the file. It is empty for
+	// dependencies of the project at the root.
+	Subproject string
+
+	// The environment marker restricting the dependency to some Python
+	// environments, such as `python_version < "3.11"`, if any.
+	Marker string
+}
+
+// A parser extracts the dependencies declared in the contents of a file.
+type parser func(data []byte) ([]Dependency, error)
+
+// parsers maps the base names of the supported files to their parsers.
+var parsers = map[string]parser{
+	"go.mod":            parseGoMod,
+	"package-lock.json": parsePackageLock,
+	"pnpm-lock.yaml":    parsePNPMLock,
+	"pom.xml":           parsePOM,
+	"Cargo.lock":        parseCargoLock,
+	"Gemfile.lock":      parseGemfileLock,
+	"gradle.lockfile":   parseGradleLock,
+	"requirements.txt":  parseRequirements,
+}
+
+// parserFor returns the parser of the files with the given base name.
+// Besides the names in parsers, any name ending in ".lockfile" is taken
+// for a Gradle lockfile, as Gradle names them after configurations.
+func parserFor(base string) (parser, bool) {
+	if p, ok := parsers[base]; ok {
+		return p, true
+	}
+	if strings.HasSuffix(base, ".lockfile") {
+		return parseGradleLock, true
+	}
+	return nil, false
+}
+
+// A workspace defines a project made of other projects, each with its own
+// manifest or lockfile, or whose dependencies are otherwise declared in
+// more than one file, as a Go module with a vendor directory.
+type workspace struct {
+	// read returns the dependencies of the projects making up the workspace
+	// defined by the named file, and the manifests of those projects.
+	// Unlike a parser, it reads the files of those projects from disk.
+	read func(file string) (deps []Dependency, files []string, err error)
+
+	// The base name of the manifest that the workspace file takes the
+	// place of when both are in the same directory.
+	replaces string
+}
+
+// workspaces maps the base names of the supported workspace files to their
+// definitions.
+var workspaces = map[string]workspace{
+	"go.work": {read: goWorkspace, replaces: "go.mod"},
+	"go.mod":  {read: goModule},
+	"pom.xml": {read: mavenReactor},
+}
+
+// Supported reports whether the named file is a manifest, lockfile, or
+// workspace file that can be scanned.
+func Supported(file string) bool {
+	base := filepath.Base(file)
+	_, ok := parserFor(base)
+	_, isWork := workspaces[base]
+	return ok || isWork
+}
+
+// File returns the dependencies declared in the named manifest or lockfile,
+// or in the projects of the named workspace file.
+func File(file string) ([]Dependency, error) {
+	if !Supported(file) {
+		return nil, fmt.Errorf("%s: unsupported file", file)
+	}
+	if w, ok := workspaces[filepath.Base(file)]; ok {
+		deps, _, err := w.read(file)
+		return deps, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(file, data)
+}
+
+// Parse returns the dependencies declared in data, the contents of the named
+// manifest or lockfile. The kind of file is determined by its base name, so
+// data need not be read from file itself, for example when comparing it with
+// an older revision.
+func Parse(file string, data []byte) ([]Dependency, error) {
+	parse, ok := parserFor(filepath.Base(file))
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported file", file)
+	}
+	deps, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	for i := range deps {
+		deps[i].File = file
+	}
+	return deps, nil
+}
+
+// Dir returns the dependencies declared in the supported manifests and
+// lockfiles found in the directory dir. Subdirectories are not scanned,
+// except for the projects of a workspace file in dir, which is then scanned
+// instead of the manifest it replaces.
+func Dir(dir string) ([]Dependency, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	replaced := make(map[string]bool)
+	for _, e := range entries {
+		if w, ok := workspaces[e.Name()]; ok && !e.IsDir() {
+			replaced[w.replaces] = true
+		}
+	}
+	var deps []Dependency
+	for _, e := range entries {
+		if e.IsDir() || !Supported(e.Name()) || replaced[e.Name()] {
+			continue
+		}
+		d, err := File(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, d...)
+	}
+	return deps, nil
+}
+
+// skipDirs holds the names of directories Tree does not descend into, as
+// they hold dependencies rather than subprojects.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"testdata":     true,
+}
+
+// Tree returns the dependencies declared in the supported manifests and
+// lockfiles found in the directory root and its subdirectories, such as the
+// subprojects of a monorepo in different languages. Each dependency records
+// the directory of its subproject, relative to root, as its Subproject.
+// Hidden directories and those holding installed or vendored dependencies
+// are not scanned. Workspace files are scanned first, outermost first, and
+// the files of the projects they cover are not scanned again on their own.
+// The dependencies are in the order of their subprojects, by path.
+func Tree(root string) ([]Dependency, error) {
+	var files, works []string
+	err := filepath.WalkDir(root, func(file string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			if file != root && (skipDirs[e.Name()] || strings.HasPrefix(e.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := workspaces[e.Name()]; ok {
+			works = append(works, file)
+		} else if Supported(e.Name()) {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// In the same directory, the files replacing others, which cover them,
+	// come first.
+	sort.SliceStable(works, func(i, j int) bool {
+		di, dj := strings.Count(works[i], string(filepath.Separator)), strings.Count(works[j], string(filepath.Separator))
+		if di != dj {
+			return di < dj
+		}
+		return workspaces[filepath.Base(works[i])].replaces != "" && workspaces[filepath.Base(works[j])].replaces == ""
+	})
+
+	covered := make(map[string]bool)
+	var deps []Dependency
+	for _, file := range append(works, files...) {
+		if covered[filepath.Clean(file)] {
+			continue
+		}
+		covered[filepath.Clean(file)] = true
+		var d []Dependency
+		if w, ok := workspaces[filepath.Base(file)]; ok {
+			var projects []string
+			d, projects, err = w.read(file)
+			for _, f := range projects {
+				covered[filepath.Clean(f)] = true
+			}
+		} else {
+			d, err = File(file)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+		for i := range d {
+			d[i].Subproject = path.Join(filepath.ToSlash(rel), d[i].Subproject)
+		}
+		deps = append(deps, d...)
+	}
+	sort.SliceStable(deps, func(i, j int) bool { return deps[i].Subproject < deps[j].Subproject })
+	return deps, nil
+}
+
+// uniq sorts deps by name and version and merges the dependencies on the same
+// package version, which is direct if any of the merged ones is, and applies
+// to the environments any of them applies to. The merged dependency keeps
+// the subproject of the first one declaring it directly, by directory.
+func uniq(deps []Dependency) []Dependency {
+	sort.Slice(deps, func(i, j int) bool {
+		a, b := deps[i], deps[j]
+		switch {
+		case a.VersionKey.Name != b.VersionKey.Name:
+			return a.VersionKey.Name < b.VersionKey.Name
+		case a.VersionKey.Version != b.VersionKey.Version:
+			return a.VersionKey.Version < b.VersionKey.Version
+		case a.Direct != b.Direct:
+			return a.Direct
+		}
+		return a.Subproject < b.Subproject
+	})
+	out := deps[:0]
+	for _, d := range deps {
+		if n := len(out); n > 0 && out[n-1].VersionKey == d.VersionKey {
+			out[n-1].Direct = out[n-1].Direct || d.Direct
+			if m := out[n-1].Marker; m == "" || d.Marker == "" {
+				out[n-1].Marker = ""
+			} else if m != d.Marker {
+				out[n-1].Marker = "(" + m + ") or (" + d.Marker + ")"
+			}
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
@@ -0,0 +1,625 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/marker"
+	"github.com/google/go-cmp/cmp"
+)
+
+func dep(system, name, version string, direct bool) Dependency {
+	return Dependency{
+		VersionKey: insights.VersionKey{System: system, Name: name, Version: version},
+		Direct:     direct,
+	}
+}
+
+func TestParsers(t *testing.T) {
+	testCases := []struct {
+		name  string
+		parse parser
+		data  string
+		want  []Dependency
+	}{
+		{
+			"go.mod",
+			parseGoMod,
+			`module example.com/m
+
+go 1.22
+
+require rsc.io/quote v1.5.2
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+	"github.com/google/go-cmp" v0.7.0
+)
+
+replace rsc.io/quote => ../quote
+`,
+			[]Dependency{
+				dep("GO", "github.com/google/go-cmp", "v0.7.0", true),
+				dep("GO", "golang.org/x/text", "v0.14.0", false),
+				dep("GO", "rsc.io/quote", "v1.5.2", true),
+			},
+		},
+		{
+			"vendor/modules.txt",
+			parseVendorModules,
+			`# github.com/google/go-cmp v0.7.0
+## explicit; go 1.21
+github.com/google/go-cmp/cmp
+github.com/google/go-cmp/cmp/internal/diff
+# golang.org/x/text v0.14.0
+golang.org/x/text/unicode/norm
+# rsc.io/quote v1.5.2 => rsc.io/quote v1.5.3
+## explicit
+rsc.io/quote
+# example.com/local => ../local
+## explicit
+example.com/local
+`,
+			[]Dependency{
+				dep("GO", "github.com/google/go-cmp", "v0.7.0", true),
+				dep("GO", "golang.org/x/text", "v0.14.0", false),
+				dep("GO", "rsc.io/quote", "v1.5.3", true),
+			},
+		},
+		{
+			"package-lock.json v3",
+			parsePackageLock,
+			`{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"react": "^18.2.0"}},
+    "node_modules/react": {"version": "18.2.0"},
+    "node_modules/loose-envify": {"version": "1.4.0"},
+    "node_modules/a/node_modules/react": {"version": "17.0.2"},
+    "node_modules/local": {"resolved": "packages/local", "link": true}
+  }
+}`,
+			[]Dependency{
+				dep("NPM", "loose-envify", "1.4.0", false),
+				dep("NPM", "react", "17.0.2", false),
+				dep("NPM", "react", "18.2.0", true),
+			},
+		},
+		{
+			"package-lock.json workspaces",
+			parsePackageLock,
+			`{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"workspaces": ["packages/*"], "devDependencies": {"typescript": "^5.0.0"}},
+    "packages/web": {"version": "1.0.0", "dependencies": {"react": "^18.2.0", "ui": "^1.0.0"}},
+    "packages/ui": {"version": "1.0.0", "dependencies": {"react": "^17.0.0"}},
+    "packages/ui/node_modules/react": {"version": "17.0.2"},
+    "node_modules/react": {"version": "18.2.0"},
+    "node_modules/typescript": {"version": "5.4.5"},
+    "node_modules/ui": {"resolved": "packages/ui", "link": true},
+    "node_modules/web": {"resolved": "packages/web", "link": true}
+  }
+}`,
+			[]Dependency{
+				{VersionKey: insights.VersionKey{System: "NPM", Name: "react", Version: "17.0.2"}, Direct: true, Subproject: "packages/ui"},
+				{VersionKey: insights.VersionKey{System: "NPM", Name: "react", Version: "18.2.0"}, Direct: true, Subproject: "packages/web"},
+				dep("NPM", "typescript", "5.4.5", true),
+			},
+		},
+		{
+			"pnpm-lock.yaml",
+			parsePNPMLock,
+			`lockfileVersion: '9.0'
+
+importers:
+
+  .:
+    devDependencies:
+      typescript:
+        specifier: ^5.0.0
+        version: 5.4.5
+
+  packages/web:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+      ui:
+        specifier: workspace:*
+        version: link:../ui
+
+packages:
+
+  js-tokens@4.0.0:
+    resolution: {integrity: sha512-x}
+
+  react@18.2.0:
+    resolution: {integrity: sha512-y}
+
+  '@types/react@18.2.0(react@18.2.0)':
+    resolution: {integrity: sha512-z}
+
+  typescript@5.4.5:
+    resolution: {integrity: sha512-w}
+`,
+			[]Dependency{
+				dep("NPM", "@types/react", "18.2.0", false),
+				dep("NPM", "js-tokens", "4.0.0", false),
+				{VersionKey: insights.VersionKey{System: "NPM", Name: "react", Version: "18.2.0"}, Direct: true, Subproject: "packages/web"},
+				dep("NPM", "typescript", "5.4.5", true),
+			},
+		},
+		{
+			"pnpm-lock.yaml v6 without workspace",
+			parsePNPMLock,
+			`lockfileVersion: '6.0'
+
+dependencies:
+  react:
+    specifier: ^18.2.0
+    version: 18.2.0
+
+packages:
+
+  /react@18.2.0:
+    resolution: {integrity: sha512-y}
+`,
+			[]Dependency{dep("NPM", "react", "18.2.0", true)},
+		},
+		{
+			"pom.xml",
+			parsePOM,
+			`<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0.0</version>
+  <properties>
+    <guava.version>33.0.0-jre</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>junit</groupId>
+        <artifactId>junit</artifactId>
+        <version>4.13.2</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>${guava.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>[2.0,3.0)</version>
+    </dependency>
+    <dependency>
+      <groupId>org.unknown</groupId>
+      <artifactId>managed-elsewhere</artifactId>
+    </dependency>
+  </dependencies>
+</project>`,
+			[]Dependency{
+				dep("MAVEN", "com.google.guava:guava", "33.0.0-jre", true),
+				dep("MAVEN", "junit:junit", "4.13.2", true),
+			},
+		},
+		{
+			"gradle.lockfile",
+			parseGradleLock,
+			`# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:failureaccess:1.0.1=compileClasspath,runtimeClasspath
+com.google.guava:guava:31.1-jre=compileClasspath,runtimeClasspath
+junit:junit:4.13.2=testCompileClasspath
+empty=annotationProcessor
+`,
+			[]Dependency{
+				dep("MAVEN", "com.google.guava:failureaccess", "1.0.1", false),
+				dep("MAVEN", "com.google.guava:guava", "31.1-jre", false),
+				dep("MAVEN", "junit:junit", "4.13.2", false),
+			},
+		},
+		{
+			"Gemfile.lock",
+			parseGemfileLock,
+			`GIT
+  remote: https://github.com/rails/rails.git
+  revision: abc
+  specs:
+    rails (7.2.0.alpha)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.16.0-x86_64-linux)
+      racc (~> 1.4)
+    puma (6.4.2)
+      nio4r (~> 2.0)
+    racc (1.7.3)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  nokogiri
+  puma (>= 5.0)
+  rails!
+
+BUNDLED WITH
+   2.5.3
+`,
+			[]Dependency{
+				dep("RUBYGEMS", "nokogiri", "1.16.0", true),
+				dep("RUBYGEMS", "puma", "6.4.2", true),
+				dep("RUBYGEMS", "racc", "1.7.3", false),
+			},
+		},
+		{
+			"package-lock.json v1",
+			parsePackageLock,
+			`{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "a": {"version": "1.0.0", "dependencies": {"b": {"version": "2.0.0"}}},
+    "c": {"version": "file:../c"}
+  }
+}`,
+			[]Dependency{
+				dep("NPM", "a", "1.0.0", false),
+				dep("NPM", "b", "2.0.0", false),
+			},
+		},
+		{
+			"Cargo.lock",
+			parseCargoLock,
+			`# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "serde",
+ "rand 0.8.5",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.190"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand"
+version = "0.8.5"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = ["libc"]
+
+[[package]]
+name = "libc"
+version = "0.2.150"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`,
+			[]Dependency{
+				dep("CARGO", "libc", "0.2.150", false),
+				dep("CARGO", "rand", "0.8.5", true),
+				dep("CARGO", "serde", "1.0.190", true),
+			},
+		},
+		{
+			"requirements.txt",
+			parseRequirements,
+			`# comment
+-r other.txt
+requests[security]==2.31.0 ; python_version >= "3.8"
+flask>=2.0
+Django == 4.2.7
+`,
+			[]Dependency{
+				dep("PYPI", "Django", "4.2.7", true),
+				{
+					VersionKey: insights.VersionKey{System: "PYPI", Name: "requests", Version: "2.31.0"},
+					Direct:     true,
+					Marker:     `python_version >= "3.8"`,
+				},
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		got, err := c.parse([]byte(c.data))
+		if err != nil {
+			t.Errorf("%s: parse failed: %v", c.name, err)
+			continue
+		}
+		if !cmp.Equal(got, c.want) {
+			t.Errorf("%s: parse returned %+v; want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+// writeFiles creates the given files, by slash-separated path relative to
+// dir, and their parent directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":     "module m\n\nrequire rsc.io/quote v1.5.2\n",
+		"README.md":  "# m\n",
+		"sub/go.mod": "module m/sub\n\nrequire rsc.io/sampler v1.3.0\n",
+	})
+
+	got, err := Dir(dir)
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	want := []Dependency{dep("GO", "rsc.io/quote", "v1.5.2", true)}
+	want[0].File = filepath.Join(dir, "go.mod")
+	if !cmp.Equal(got, want) {
+		t.Errorf("Dir returned %+v; want %+v", got, want)
+	}
+}
+
+func TestGoWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.work":      "go 1.22\n\nuse (\n\t.\n\t./tools // linters\n)\n",
+		"go.mod":       "module m\n\nrequire (\n\tm/tools v0.0.0\n\trsc.io/quote v1.5.2\n\tgolang.org/x/text v0.3.0 // indirect\n)\n",
+		"tools/go.mod": "module m/tools\n\nrequire (\n\trsc.io/quote v1.5.1\n\tgolang.org/x/text v0.14.0\n)\n",
+		"other/go.mod": "module other\n\nrequire rsc.io/sampler v1.3.0\n",
+	})
+
+	got, err := Dir(dir)
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	in := func(file string, d Dependency) Dependency {
+		d.File = filepath.Join(dir, filepath.FromSlash(file))
+		return d
+	}
+	// The greatest version of each module, direct if any module requires it
+	// directly, and not the workspace modules.
+	text := in("tools/go.mod", dep("GO", "golang.org/x/text", "v0.14.0", true))
+	text.Subproject = "tools"
+	want := []Dependency{text, in("go.mod", dep("GO", "rsc.io/quote", "v1.5.2", true))}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Dir mismatch (-want +got):\n%s", diff)
+	}
+
+	// Tree scans the workspace once, and the module outside it on its own.
+	got, err = Tree(dir)
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	sampler := in("other/go.mod", dep("GO", "rsc.io/sampler", "v1.3.0", true))
+	sampler.Subproject = "other"
+	quote := want[1]
+	quote.Subproject = "."
+	want = []Dependency{quote, sampler, text}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Tree mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGoVendor(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":             "module m\n\nrequire (\n\trsc.io/quote v1.5.2\n\trsc.io/sampler v1.3.0\n)\n",
+		"vendor/modules.txt": "# rsc.io/quote v1.5.2\n## explicit\nrsc.io/quote\n# rsc.io/sampler v1.3.1\n## explicit\nrsc.io/sampler\n",
+	})
+
+	got, err := Dir(dir)
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	in := func(file string, d Dependency) Dependency {
+		d.File = filepath.Join(dir, filepath.FromSlash(file))
+		return d
+	}
+	// The vendored version of sampler drifted from the one required.
+	want := []Dependency{
+		in("go.mod", dep("GO", "rsc.io/quote", "v1.5.2", true)),
+		in("go.mod", dep("GO", "rsc.io/sampler", "v1.3.0", true)),
+		in("vendor/modules.txt", dep("GO", "rsc.io/sampler", "v1.3.1", true)),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Dir mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMavenReactor(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>2.0.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>core</module>
+    <module>app</module>
+  </modules>
+  <properties>
+    <jackson.version>2.17.0</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+		"core/pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0.0</version>
+  </parent>
+  <artifactId>core</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+  </dependencies>
+</project>`,
+		"app/pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>2.0.13</version>
+    </dependency>
+  </dependencies>
+</project>`,
+	})
+
+	// The module dependency of app on core is resolved locally.
+	in := func(sub string, d Dependency) Dependency {
+		d.File, d.Subproject = filepath.Join(dir, sub, "pom.xml"), sub
+		return d
+	}
+	want := []Dependency{
+		in("core", dep("MAVEN", "com.fasterxml.jackson.core:jackson-databind", "2.17.0", true)),
+		in("app", dep("MAVEN", "org.slf4j:slf4j-api", "2.0.13", true)),
+	}
+	got, err := Dir(dir)
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Dir mismatch (-want +got):\n%s", diff)
+	}
+
+	// Tree does not scan the modules again on their own.
+	got, err = Tree(dir)
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	want[0], want[1] = want[1], want[0]
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Tree mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTree(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                       "module m\n\nrequire rsc.io/quote v1.5.2\n",
+		"web/package-lock.json":        `{"lockfileVersion":3,"packages":{"":{"dependencies":{"a":"^1.0.0"}},"node_modules/a":{"version":"1.0.0"}}}`,
+		"web/node_modules/a/go.mod":    "module a\n\nrequire rsc.io/sampler v1.3.0\n",
+		"tools/py/requirements.txt":    "requests==2.31.0\n",
+		".git/go.mod":                  "module hidden\n\nrequire rsc.io/sampler v1.3.0\n",
+		"tools/py/testdata/Cargo.lock": "",
+	})
+
+	got, err := Tree(dir)
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	in := func(sub, file string, d Dependency) Dependency {
+		d.Subproject, d.File = sub, filepath.Join(dir, filepath.FromSlash(file))
+		return d
+	}
+	want := []Dependency{
+		in(".", "go.mod", dep("GO", "rsc.io/quote", "v1.5.2", true)),
+		in("tools/py", "tools/py/requirements.txt", dep("PYPI", "requests", "2.31.0", true)),
+		in("web", "web/package-lock.json", dep("NPM", "a", "1.0.0", true)),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Tree mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse(t *testing.T) {
+	got, err := Parse("old/go.mod", []byte("module m\n\nrequire rsc.io/quote v1.5.2\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []Dependency{dep("GO", "rsc.io/quote", "v1.5.2", true)}
+	want[0].File = "old/go.mod"
+	if !cmp.Equal(got, want) {
+		t.Errorf("Parse returned %+v; want %+v", got, want)
+	}
+
+	if _, err := Parse("go.sum", nil); err == nil {
+		t.Error("Parse of an unsupported file succeeded")
+	}
+
+	// Per-configuration Gradle lockfiles are named after the configuration.
+	got, err = Parse("gradle/dependency-locks/compileClasspath.lockfile", []byte("junit:junit:4.13.2\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want = []Dependency{dep("MAVEN", "junit:junit", "4.13.2", false)}
+	want[0].File = "gradle/dependency-locks/compileClasspath.lockfile"
+	if !cmp.Equal(got, want) {
+		t.Errorf("Parse returned %+v; want %+v", got, want)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	deps, err := parseRequirements([]byte(`numpy==1.24.4 ; python_version < "3.9"
+numpy==1.26.4 ; python_version >= "3.9"
+pywin32==306 ; sys_platform == "win32"
+tomli==2.0.1 ; python_version < "3.11"
+tomli==2.0.1 ; sys_platform == "win32"
+requests==2.31.0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Select(deps, marker.NewEnvironment("3.10.12", "linux"))
+	want := []Dependency{
+		dep("PYPI", "numpy", "1.26.4", true),
+		dep("PYPI", "requests", "2.31.0", true),
+		dep("PYPI", "tomli", "2.0.1", true),
+	}
+	want[0].Marker = `python_version >= "3.9"`
+	want[2].Marker = `(python_version < "3.11") or (sys_platform == "win32")`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Select mismatch (-want +got):\n%s", diff)
+	}
+}
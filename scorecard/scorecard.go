@@ -0,0 +1,195 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scorecard gets OpenSSF Scorecard results of source repositories
+// from the Scorecard API, for the commits they were produced from, so that
+// the history of the score of a project can be told, not only the latest
+// scorecard deps.dev reports. Results are returned as insights.Scorecard
+// values, as deps.dev returns them.
+//
+// The API keeps the results of the commits scanned by the weekly Scorecard
+// runs; other commits have none.
+package scorecard
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+const defaultBaseURL = "https://api.scorecard.dev/"
+
+// ErrNotFound is returned when the API has no result for a repository or
+// commit.
+var ErrNotFound = errors.New("scorecard: no result")
+
+// Client gets results from the Scorecard API. The zero value is ready to
+// use.
+type Client struct {
+	// The HTTP client used to send requests. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+
+	// Retry, if not nil, tells how to retry requests that failed because
+	// of a network error, a rate limit, or a transient server error.
+	Retry *insights.RetryPolicy
+
+	// The base URL of the API. If empty, the public API is used.
+	BaseURL string
+}
+
+// result is a result as returned by the API.
+type result struct {
+	Date string `json:"date"`
+	Repo struct {
+		Name   string `json:"name"`
+		Commit string `json:"commit"`
+	} `json:"repo"`
+	Scorecard struct {
+		Version string `json:"version"`
+		Commit  string `json:"commit"`
+	} `json:"scorecard"`
+	Score  float64 `json:"score"`
+	Checks []struct {
+		Name          string   `json:"name"`
+		Score         int      `json:"score"`
+		Reason        string   `json:"reason"`
+		Details       []string `json:"details"`
+		Documentation struct {
+			Short string `json:"short"`
+			URL   string `json:"url"`
+		} `json:"documentation"`
+	} `json:"checks"`
+	Metadata []string `json:"metadata"`
+}
+
+func (r *result) scorecard() *insights.Scorecard {
+	s := &insights.Scorecard{Date: r.Date, OverallScore: r.Score, Metadata: r.Metadata}
+	s.Repository.Name = r.Repo.Name
+	s.Repository.Commit = r.Repo.Commit
+	s.Scorecard.Version = r.Scorecard.Version
+	s.Scorecard.Commit = r.Scorecard.Commit
+	// The checks are of an unnamed type.
+	s.Checks = slices.Grow(s.Checks, len(r.Checks))[:len(r.Checks)]
+	for i, c := range r.Checks {
+		sc := &s.Checks[i]
+		sc.Name, sc.Score, sc.Reason, sc.Details = c.Name, c.Score, c.Reason, c.Details
+		sc.Documentation.ShortDescription = c.Documentation.Short
+		sc.Documentation.URL = c.Documentation.URL
+	}
+	return s
+}
+
+// Get returns the result of the repository, identified as a deps.dev
+// project such as "github.com/ossf/scorecard", for the given commit. If
+// commit is empty, the latest result is returned.
+func (c *Client) Get(ctx context.Context, repo, commit string) (*insights.Scorecard, error) {
+	u := strings.TrimSuffix(cmp.Or(c.BaseURL, defaultBaseURL), "/") + "/projects/" + repo
+	if commit != "" {
+		u += "?" + url.Values{"commit": {commit}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := c.Retry.Do(hc, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, u)
+	default:
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	var r result
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("%s: %v", u, err)
+	}
+	return r.scorecard(), nil
+}
+
+// History returns the results of the repository for the given commits,
+// and its latest result, oldest first. Commits the API has no result for
+// are left out. Failing to get the result of a commit does not prevent the
+// others from being returned; the returned error is then a
+// *insights.BatchError, by commit, or "latest" for the latest result.
+func (c *Client) History(ctx context.Context, repo string, commits []string) ([]*insights.Scorecard, error) {
+	var results []*insights.Scorecard
+	var batch insights.Batch
+	seen := make(map[string]bool)
+	for _, commit := range append([]string{""}, commits...) {
+		s, err := c.Get(ctx, repo, commit)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			batch.Fail(cmp.Or(commit, "latest"), err)
+			continue
+		}
+		// The latest result may be that of one of the commits.
+		if seen[s.Repository.Commit] {
+			continue
+		}
+		seen[s.Repository.Commit] = true
+		results = append(results, s)
+	}
+	slices.SortStableFunc(results, func(a, b *insights.Scorecard) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+	return results, batch.Err()
+}
+
+// Change is the change in the score of a check between two results.
+type Change struct {
+	// The name of the check.
+	Check string
+
+	// The scores of the check in the older and newer result. A score is
+	// negative if the check did not run successfully, or is missing from
+	// a result.
+	From, To int
+}
+
+// Compare returns the checks whose scores differ between the results from
+// and to, sorted by name.
+func Compare(from, to *insights.Scorecard) []Change {
+	scores := make(map[string]*Change)
+	for _, c := range from.Checks {
+		scores[c.Name] = &Change{Check: c.Name, From: c.Score, To: -1}
+	}
+	for _, c := range to.Checks {
+		if ch, ok := scores[c.Name]; ok {
+			ch.To = c.Score
+		} else {
+			scores[c.Name] = &Change{Check: c.Name, From: -1, To: c.Score}
+		}
+	}
+	var changes []Change
+	for _, ch := range scores {
+		if ch.From != ch.To {
+			changes = append(changes, *ch)
+		}
+	}
+	slices.SortFunc(changes, func(a, b Change) int { return cmp.Compare(a.Check, b.Check) })
+	return changes
+}
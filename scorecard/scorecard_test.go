@@ -0,0 +1,75 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scorecard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// setup returns a client of a test server with handlers registered on mux.
+func setup(t *testing.T) (*Client, *http.ServeMux) {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return &Client{BaseURL: server.URL}, mux
+}
+
+func TestHistory(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/projects/github.com/example/foo", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("commit") {
+		case "":
+			fmt.Fprint(w, `{"date":"2026-03-02T00:00:00Z","repo":{"name":"github.com/example/foo","commit":"bbb"},"score":6.5,
+				"checks":[{"name":"Code-Review","score":8,"documentation":{"short":"Reviews"}},{"name":"Fuzzing","score":10}]}`)
+		case "aaa":
+			fmt.Fprint(w, `{"date":"2026-02-23T00:00:00Z","repo":{"name":"github.com/example/foo","commit":"aaa"},"score":5.1,
+				"checks":[{"name":"Code-Review","score":4},{"name":"Maintained","score":10}]}`)
+		case "bbb":
+			// The same as the latest.
+			fmt.Fprint(w, `{"date":"2026-03-02T00:00:00Z","repo":{"name":"github.com/example/foo","commit":"bbb"},"score":6.5}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	got, err := client.History(context.Background(), "github.com/example/foo", []string{"aaa", "bbb", "ccc"})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	var commits []string
+	for _, s := range got {
+		commits = append(commits, fmt.Sprintf("%s %.1f", s.Repository.Commit, s.OverallScore))
+	}
+	if diff := cmp.Diff([]string{"aaa 5.1", "bbb 6.5"}, commits); diff != "" {
+		t.Fatalf("History mismatch (-want +got):\n%s", diff)
+	}
+	if c := got[1].Checks[0]; c.Name != "Code-Review" || c.Score != 8 || c.Documentation.ShortDescription != "Reviews" {
+		t.Errorf("latest check = %+v", c)
+	}
+
+	want := []Change{
+		{Check: "Code-Review", From: 4, To: 8},
+		{Check: "Fuzzing", From: -1, To: 10},
+		{Check: "Maintained", From: 10, To: -1},
+	}
+	if diff := cmp.Diff(want, Compare(got[0], got[1])); diff != "" {
+		t.Errorf("Compare mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetNotFound(t *testing.T) {
+	client, _ := setup(t)
+	if _, err := client.Get(context.Background(), "github.com/example/none", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of an unknown repository returned %v; want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sigverify verifies the SLSA provenance of npm package versions
+// locally, rather than relying on deps.dev having verified it. The Sigstore
+// bundles of the provenance are fetched from the npm registry and checked
+// to be signed, for the published tarball, by a CI build of the source
+// repository the version claims to be built from.
+package sigverify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+// DefaultRegistryURL is the npm registry used if Verifier.RegistryURL is
+// empty.
+const DefaultRegistryURL = "https://registry.npmjs.org/"
+
+// ErrNoProvenance is returned by Verifier.VerifyNPM for versions published
+// without SLSA provenance.
+var ErrNoProvenance = errors.New("no SLSA provenance")
+
+// Verifier verifies the provenance of npm package versions.
+type Verifier struct {
+	// HTTPClient is used to talk to the registry. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// RegistryURL is the base URL of the npm registry, with a trailing
+	// slash. If empty, DefaultRegistryURL is used.
+	RegistryURL string
+
+	// Sigstore verifies the bundles, such as the one returned by
+	// PublicGood.
+	Sigstore *verify.Verifier
+}
+
+// PublicGood returns a verifier of the bundles signed by the public-good
+// instance of Sigstore, as npm provenance is. Its trusted root is read from
+// the trusted_root.json file trustedRoot or, if empty, fetched with TUF or
+// read from the TUF cache.
+func PublicGood(trustedRoot string) (*verify.Verifier, error) {
+	var trusted *root.TrustedRoot
+	var err error
+	if trustedRoot != "" {
+		trusted, err = root.NewTrustedRootFromPath(trustedRoot)
+	} else {
+		trusted, err = root.FetchTrustedRoot()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading the Sigstore trusted root: %w", err)
+	}
+	return verify.NewVerifier(trusted,
+		verify.WithSignedCertificateTimestamps(1),
+		verify.WithTransparencyLog(1),
+		verify.WithObserverTimestamps(1))
+}
+
+// Provenance is a verified SLSA provenance.
+type Provenance struct {
+	// The SLSA predicate type, such as https://slsa.dev/provenance/v1.
+	PredicateType string
+
+	// The identity of the build that signed the provenance, such as the
+	// URL of a GitHub Actions workflow.
+	Build string
+}
+
+// VerifyNPM verifies the SLSA provenance of the npm package version
+// name@version, and that it was signed by a CI build of repo, a source
+// repository such as "github.com/user/repo". It returns the verified
+// provenance, or an error if there is none or any fails to verify.
+func (v *Verifier) VerifyNPM(ctx context.Context, name, version, repo string) ([]Provenance, error) {
+	var meta struct {
+		Dist struct {
+			Integrity    string
+			Attestations struct {
+				URL string
+			}
+		}
+	}
+	if err := v.get(ctx, v.registryURL()+escapeName(name)+"/"+url.PathEscape(version), &meta); err != nil {
+		return nil, err
+	}
+	if meta.Dist.Attestations.URL == "" {
+		return nil, fmt.Errorf("%s@%s: %w", name, version, ErrNoProvenance)
+	}
+	digest, err := sha512Integrity(meta.Dist.Integrity)
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s: %w", name, version, err)
+	}
+	id, err := identity(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var atts struct {
+		Attestations []struct {
+			PredicateType string
+			Bundle        json.RawMessage
+		}
+	}
+	if err := v.get(ctx, meta.Dist.Attestations.URL, &atts); err != nil {
+		return nil, err
+	}
+	var provs []Provenance
+	for _, a := range atts.Attestations {
+		// The publish attestation is signed by the registry with a key of
+		// its own, not by the build.
+		if !strings.HasPrefix(a.PredicateType, "https://slsa.dev/provenance/") {
+			continue
+		}
+		var b bundle.Bundle
+		if err := b.UnmarshalJSON(a.Bundle); err != nil {
+			return nil, fmt.Errorf("%s@%s: %s: %w", name, version, a.PredicateType, err)
+		}
+		build, err := verifyEntity(v.Sigstore, &b, digest, id)
+		if err != nil {
+			return nil, fmt.Errorf("%s@%s: %s: %w", name, version, a.PredicateType, err)
+		}
+		provs = append(provs, Provenance{PredicateType: a.PredicateType, Build: build})
+	}
+	if len(provs) == 0 {
+		return nil, fmt.Errorf("%s@%s: %w", name, version, ErrNoProvenance)
+	}
+	return provs, nil
+}
+
+// verifyEntity verifies e with sv, for an artifact with the given SHA-512
+// digest and signed by id, and returns the identity of the signer.
+func verifyEntity(sv *verify.Verifier, e verify.SignedEntity, digest []byte, id verify.CertificateIdentity) (string, error) {
+	res, err := sv.Verify(e, verify.NewPolicy(verify.WithArtifactDigest("sha512", digest), verify.WithCertificateIdentity(id)))
+	if err != nil {
+		return "", err
+	}
+	if res.Signature == nil || res.Signature.Certificate == nil {
+		return "", nil
+	}
+	return res.Signature.Certificate.SubjectAlternativeName, nil
+}
+
+// issuers maps the hosts of source repositories to the OIDC issuers of
+// their CI builds.
+var issuers = map[string]string{
+	"github.com": "https://token.actions.githubusercontent.com",
+	"gitlab.com": "https://gitlab.com",
+}
+
+// identity returns the certificate identity of the CI builds of repo.
+func identity(repo string) (verify.CertificateIdentity, error) {
+	host, _, _ := strings.Cut(repo, "/")
+	issuer, ok := issuers[strings.ToLower(host)]
+	if !ok {
+		return verify.CertificateIdentity{}, fmt.Errorf("%s: unsupported source repository host", repo)
+	}
+	// The builds are identified by the URL of their workflow or pipeline
+	// file in the repository, whose owner and name keep their case.
+	san := "(?i)^https://" + regexp.QuoteMeta(repo) + "/"
+	return verify.NewShortCertificateIdentity(issuer, "", "", san)
+}
+
+// sha512Integrity returns the digest of a Subresource Integrity string
+// such as "sha512-...", as npm gives for tarballs.
+func sha512Integrity(s string) ([]byte, error) {
+	b64, ok := strings.CutPrefix(s, "sha512-")
+	if !ok {
+		return nil, fmt.Errorf("no SHA-512 integrity for the tarball: %q", s)
+	}
+	digest, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tarball integrity %q: %w", s, err)
+	}
+	return digest, nil
+}
+
+// escapeName escapes an npm package name for the registry, which expects
+// the slash of a scoped name as is.
+func escapeName(name string) string {
+	if scope, pkg, ok := strings.Cut(name, "/"); ok {
+		return url.PathEscape(scope) + "/" + url.PathEscape(pkg)
+	}
+	return url.PathEscape(name)
+}
+
+func (v *Verifier) registryURL() string {
+	if v.RegistryURL == "" {
+		return DefaultRegistryURL
+	}
+	return v.RegistryURL
+}
+
+// get fetches the JSON document at u into dst.
+func (v *Verifier) get(ctx context.Context, u string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	hc := v.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sigverify
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sigstore/sigstore-go/pkg/testing/ca"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+const githubIssuer = "https://token.actions.githubusercontent.com"
+
+// statement returns an in-toto statement of SLSA provenance for an npm
+// tarball with the given SHA-512 digest.
+func statement(digest []byte) []byte {
+	return []byte(fmt.Sprintf(`{
+		"_type": "https://in-toto.io/Statement/v1",
+		"subject": [{"name": "pkg:npm/a@1.0.0", "digest": {"sha512": %q}}],
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"predicate": {}
+	}`, hex.EncodeToString(digest)))
+}
+
+func TestVerifyEntity(t *testing.T) {
+	vs, err := ca.NewVirtualSigstore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := verify.NewVerifier(vs, verify.WithTransparencyLog(1), verify.WithObserverTimestamps(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarball := sha512.Sum512([]byte("tarball"))
+	other := sha512.Sum512([]byte("other tarball"))
+	const build = "https://github.com/User/Repo/.github/workflows/publish.yml@refs/heads/main"
+
+	tests := []struct {
+		name    string
+		signer  string
+		issuer  string
+		digest  []byte
+		repo    string
+		wantErr bool
+	}{
+		{name: "verified", signer: build, issuer: githubIssuer, digest: tarball[:], repo: "github.com/user/repo"},
+		{name: "other repository", signer: build, issuer: githubIssuer, digest: tarball[:], repo: "github.com/user/other", wantErr: true},
+		{name: "repository prefix", signer: build, issuer: githubIssuer, digest: tarball[:], repo: "github.com/user/rep", wantErr: true},
+		{name: "other issuer", signer: build, issuer: "https://accounts.google.com", digest: tarball[:], repo: "github.com/user/repo", wantErr: true},
+		{name: "other tarball", signer: build, issuer: githubIssuer, digest: other[:], repo: "github.com/user/repo", wantErr: true},
+	}
+	for _, tt := range tests {
+		e, err := vs.Attest(tt.signer, tt.issuer, statement(tarball[:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := identity(tt.repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := verifyEntity(sv, e, tt.digest, id)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: verifyEntity succeeded; want an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: verifyEntity failed: %v", tt.name, err)
+		} else if got != build {
+			t.Errorf("%s: verifyEntity = %q; want %q", tt.name, got, build)
+		}
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	for _, repo := range []string{"github.com/user/repo", "gitlab.com/group/project"} {
+		if _, err := identity(repo); err != nil {
+			t.Errorf("identity(%q) failed: %v", repo, err)
+		}
+	}
+	if _, err := identity("bitbucket.org/user/repo"); err == nil {
+		t.Error("identity of a Bitbucket repository succeeded; want an error")
+	}
+}
+
+func TestSHA512Integrity(t *testing.T) {
+	sum := sha512.Sum512([]byte("tarball"))
+	got, err := sha512Integrity("sha512-" + base64.StdEncoding.EncodeToString(sum[:]))
+	if err != nil || string(got) != string(sum[:]) {
+		t.Errorf("sha512Integrity = %x, %v; want %x", got, err, sum)
+	}
+	for _, s := range []string{"", "sha1-abc=", "sha512-not base64"} {
+		if _, err := sha512Integrity(s); err == nil {
+			t.Errorf("sha512Integrity(%q) succeeded; want an error", s)
+		}
+	}
+}
+
+func TestVerifyNPM(t *testing.T) {
+	sum := sha512.Sum512([]byte("tarball"))
+	integrity := "sha512-" + base64.StdEncoding.EncodeToString(sum[:])
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/unattested/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"dist":{"integrity":%q}}`, integrity)
+	})
+	mux.HandleFunc("/@scope/publish-only/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"dist":{"integrity":%q,"attestations":{"url":%q}}}`, integrity, server.URL+"/-/attestations/publish-only")
+	})
+	mux.HandleFunc("/-/attestations/publish-only", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"attestations":[{"predicateType":"https://github.com/npm/attestation/tree/main/specs/publish/v0.1","bundle":{}}]}`)
+	})
+	mux.HandleFunc("/bad-bundle/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"dist":{"integrity":%q,"attestations":{"url":%q}}}`, integrity, server.URL+"/-/attestations/bad-bundle")
+	})
+	mux.HandleFunc("/-/attestations/bad-bundle", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"attestations":[{"predicateType":"https://slsa.dev/provenance/v1","bundle":{"mediaType":"unknown"}}]}`)
+	})
+
+	v := &Verifier{RegistryURL: server.URL + "/"}
+	ctx := context.Background()
+	for _, name := range []string{"unattested", "@scope/publish-only"} {
+		if _, err := v.VerifyNPM(ctx, name, "1.0.0", "github.com/user/repo"); !errors.Is(err, ErrNoProvenance) {
+			t.Errorf("VerifyNPM(%q) returned error %v; want ErrNoProvenance", name, err)
+		}
+	}
+	if _, err := v.VerifyNPM(ctx, "bad-bundle", "1.0.0", "github.com/user/repo"); err == nil || errors.Is(err, ErrNoProvenance) {
+		t.Errorf("VerifyNPM of an invalid bundle returned error %v; want a bundle error", err)
+	}
+	if _, err := v.VerifyNPM(ctx, "missing", "1.0.0", "github.com/user/repo"); err == nil {
+		t.Error("VerifyNPM of a missing version succeeded")
+	}
+}
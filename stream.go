@@ -0,0 +1,170 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// DependencyHandler receives the parts of a dependency graph as it is
+// decoded by StreamDependencies. Nil fields are ignored. If a function
+// returns an error, decoding stops and the error is returned.
+type DependencyHandler struct {
+	// Node is called for each node of the graph, in order; i is the index
+	// of the node, by which edges refer to it.
+	Node func(i int, n *Node) error
+
+	// Edge is called for each edge of the graph.
+	Edge func(e *Edge) error
+
+	// Error is called with the error encountered while resolving the
+	// graph, if any.
+	Error func(msg string) error
+}
+
+// StreamDependencies is like GetDependencies but, instead of returning the
+// graph, it passes its nodes and edges to h one at a time as they are
+// decoded from the response. It lets graphs with tens of thousands of nodes
+// be processed without holding them in memory. The Node and Edge passed to
+// h are reused and must not be retained.
+//
+// Graphs in the client's GraphCache are passed to h from there, without
+// requests.
+func (c *Client) StreamDependencies(ctx context.Context, system, name, version string, h *DependencyHandler) (*Response, error) {
+	name = CanonicalName(system, name)
+	if c.GraphCache != nil {
+		if d, ok := c.GraphCache.Get(VersionKey{System: system, Name: name, Version: version}); ok {
+			return cachedResponse(nil), walkDependencies(d, h)
+		}
+	}
+	path := fmt.Sprintf("systems/%s/packages/%s/versions/%s:dependencies", url.PathEscape(system), url.PathEscape(name), url.PathEscape(version))
+	return c.doEndpoint(ctx, EndpointGetDependencies, path, nil, func(r io.Reader) error {
+		return decodeDependencies(json.NewDecoder(r), h)
+	}, versionAttrs(system, name, version)...)
+}
+
+// walkDependencies passes the parts of d to h, as decodeDependencies does.
+func walkDependencies(d *Dependencies, h *DependencyHandler) error {
+	var (
+		node Node
+		edge Edge
+	)
+	if h.Node != nil {
+		for i := range d.Nodes {
+			node = d.Nodes[i]
+			if err := h.Node(i, &node); err != nil {
+				return err
+			}
+		}
+	}
+	if h.Edge != nil {
+		for i := range d.Edges {
+			edge = d.Edges[i]
+			if err := h.Edge(&edge); err != nil {
+				return err
+			}
+		}
+	}
+	if d.Error != "" && h.Error != nil {
+		return h.Error(d.Error)
+	}
+	return nil
+}
+
+// decodeDependencies decodes a Dependencies object from dec, passing its
+// parts to h.
+func decodeDependencies(dec *json.Decoder, h *DependencyHandler) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	var (
+		node Node
+		edge Edge
+	)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		switch {
+		case strings.EqualFold(key, "nodes"):
+			i := 0
+			err = decodeArray(dec, func() error {
+				node = Node{}
+				if err := dec.Decode(&node); err != nil {
+					return err
+				}
+				i++
+				if h.Node == nil {
+					return nil
+				}
+				return h.Node(i-1, &node)
+			})
+		case strings.EqualFold(key, "edges"):
+			err = decodeArray(dec, func() error {
+				edge = Edge{}
+				if err := dec.Decode(&edge); err != nil {
+					return err
+				}
+				if h.Edge == nil {
+					return nil
+				}
+				return h.Edge(&edge)
+			})
+		case strings.EqualFold(key, "error"):
+			var msg string
+			if err = dec.Decode(&msg); err == nil && msg != "" && h.Error != nil {
+				err = h.Error(msg)
+			}
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// decodeArray decodes a JSON array from dec, calling elem to decode each of
+// its elements. A null array is treated as empty.
+func decodeArray(dec *json.Decoder, elem func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("insights: unexpected %v in JSON, want array", tok)
+	}
+	for dec.More() {
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token from dec and reports an error unless it
+// is the delimiter d.
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != d {
+		return fmt.Errorf("insights: unexpected %v in JSON, want %v", tok, d)
+	}
+	return nil
+}
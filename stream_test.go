@@ -0,0 +1,136 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const streamGraph = `{
+	"nodes": [
+		{"versionKey": {"system": "NPM", "name": "a", "version": "1.0.0"}, "relation": "SELF", "errors": []},
+		{"versionKey": {"system": "NPM", "name": "b", "version": "2.0.0"}, "relation": "DIRECT", "bundled": true}
+	],
+	"unknown": {"x": [1, 2]},
+	"edges": [{"fromNode": 0, "toNode": 1, "requirement": "^2.0.0"}],
+	"error": "partial graph"
+}`
+
+func TestStreamDependencies(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, streamGraph)
+	})
+
+	var got Dependencies
+	_, err := client.StreamDependencies(context.Background(), "npm", "a", "1.0.0", &DependencyHandler{
+		Node: func(i int, n *Node) error {
+			if i != len(got.Nodes) {
+				t.Errorf("node index = %d, want %d", i, len(got.Nodes))
+			}
+			got.Nodes = append(got.Nodes, *n)
+			return nil
+		},
+		Edge: func(e *Edge) error {
+			got.Edges = append(got.Edges, *e)
+			return nil
+		},
+		Error: func(msg string) error {
+			got.Error = msg
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("StreamDependencies failed: %v", err)
+	}
+
+	want, _, err := client.GetDependencies(context.Background(), "npm", "a", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if diff := cmp.Diff(*want, got); diff != "" {
+		t.Errorf("StreamDependencies mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStreamDependenciesGraphCache(t *testing.T) {
+	client, mux := setup(t)
+	client.GraphCache = new(GraphCache)
+	n := 0
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		n++
+		fmt.Fprint(w, streamGraph)
+	})
+
+	ctx := context.Background()
+	want, _, err := client.GetDependencies(ctx, "npm", "a", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	var got Dependencies
+	resp, err := client.StreamDependencies(ctx, "npm", "a", "1.0.0", &DependencyHandler{
+		Node: func(i int, n *Node) error {
+			got.Nodes = append(got.Nodes, *n)
+			return nil
+		},
+		Edge: func(e *Edge) error {
+			got.Edges = append(got.Edges, *e)
+			return nil
+		},
+		Error: func(msg string) error {
+			got.Error = msg
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("StreamDependencies failed: %v", err)
+	}
+	if n != 1 || !resp.FromCache {
+		t.Errorf("sent %d requests, response from cache %t; want the graph from the GraphCache", n, resp.FromCache)
+	}
+	if diff := cmp.Diff(*want, got); diff != "" {
+		t.Errorf("StreamDependencies mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStreamDependenciesStop(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, streamGraph)
+	})
+
+	stop := errors.New("stop")
+	n := 0
+	_, err := client.StreamDependencies(context.Background(), "npm", "a", "1.0.0", &DependencyHandler{
+		Node: func(i int, _ *Node) error {
+			n++
+			return stop
+		},
+	})
+	if err != stop {
+		t.Errorf("StreamDependencies returned %v, want %v", err, stop)
+	}
+	if n != 1 {
+		t.Errorf("Node called %d times, want 1", n)
+	}
+}
+
+func TestStreamDependenciesMalformed(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"nodes": {}}`)
+	})
+
+	_, err := client.StreamDependencies(context.Background(), "npm", "a", "1.0.0", &DependencyHandler{})
+	if err == nil {
+		t.Error("StreamDependencies succeeded, want error")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer of the client.
+const tracerName = "github.com/franoliveto/insights"
+
+// The attributes of the spans of API calls.
+const (
+	attrEndpoint   = attribute.Key("deps_dev.endpoint")
+	attrAPIVersion = attribute.Key("deps_dev.api_version")
+	attrSystem     = attribute.Key("deps_dev.system")
+	attrPackage    = attribute.Key("deps_dev.package")
+	attrVersion    = attribute.Key("deps_dev.version")
+	attrProject    = attribute.Key("deps_dev.project")
+	attrAdvisory   = attribute.Key("deps_dev.advisory")
+	attrPurl       = attribute.Key("deps_dev.purl")
+	attrPurlCount  = attribute.Key("deps_dev.purl_count")
+	attrFromCache  = attribute.Key("deps_dev.from_cache")
+	attrStatusCode = attribute.Key("http.response.status_code")
+)
+
+// versionAttrs returns the attributes of the package version, or package,
+// an API call is about. Empty parts are left out.
+func versionAttrs(system, name, version string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, a := range []attribute.KeyValue{attrSystem.String(system), attrPackage.String(name), attrVersion.String(version)} {
+		if a.Value.AsString() != "" {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+// startSpan starts the span of a call to endpoint e, if the client has a
+// TracerProvider. The span is nil otherwise.
+func (c *Client) startSpan(ctx context.Context, e Endpoint, attrs []attribute.KeyValue) (context.Context, trace.Span) {
+	if c.TracerProvider == nil {
+		return ctx, nil
+	}
+	attrs = append([]attribute.KeyValue{attrEndpoint.String(string(e))}, attrs...)
+	return c.TracerProvider.Tracer(tracerName).Start(ctx, "deps.dev "+string(e),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, if not nil, recording the response of the call and
+// the version of the API that answered it.
+func endSpan(span trace.Span, ver string, resp *Response, err error) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(attrAPIVersion.String(ver))
+	if resp != nil && resp.Response != nil {
+		span.SetAttributes(attrStatusCode.Int(resp.StatusCode), attrFromCache.Bool(resp.FromCache))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
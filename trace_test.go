@@ -0,0 +1,85 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	client, mux := setup(t)
+	rec := tracetest.NewSpanRecorder()
+	client.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+
+	mux.HandleFunc("/systems/go/packages/foo/versions/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"GO","name":"foo","version":"v1.0.0"}}`)
+	})
+	mux.HandleFunc("/advisories/GHSA-1", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "advisory not found", http.StatusNotFound)
+	})
+
+	ctx := context.Background()
+	if _, _, err := client.GetVersion(ctx, "go", "foo", "v1.0.0"); err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if _, _, err := client.GetAdvisory(ctx, "GHSA-1"); err == nil {
+		t.Fatal("GetAdvisory succeeded, want 404")
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	type span struct {
+		Name   string
+		Attrs  map[attribute.Key]string
+		Status codes.Code
+	}
+	var got []span
+	for _, s := range spans {
+		sp := span{Name: s.Name(), Attrs: make(map[attribute.Key]string), Status: s.Status().Code}
+		for _, a := range s.Attributes() {
+			sp.Attrs[a.Key] = a.Value.Emit()
+		}
+		got = append(got, sp)
+	}
+	want := []span{
+		{
+			Name: "deps.dev GetVersion",
+			Attrs: map[attribute.Key]string{
+				attrEndpoint:   "GetVersion",
+				attrSystem:     "go",
+				attrPackage:    "foo",
+				attrVersion:    "v1.0.0",
+				attrAPIVersion: "v3",
+				attrStatusCode: "200",
+				attrFromCache:  "false",
+			},
+		},
+		{
+			Name: "deps.dev GetAdvisory",
+			Attrs: map[attribute.Key]string{
+				attrEndpoint:   "GetAdvisory",
+				attrAdvisory:   "GHSA-1",
+				attrAPIVersion: "v3",
+				attrStatusCode: "404",
+				attrFromCache:  "false",
+			},
+			Status: codes.Error,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("spans mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// TransportOptions tunes the connections made to the API. Zero fields keep
+// the defaults of http.DefaultTransport.
+type TransportOptions struct {
+	// The maximum number of connections to a host, including those in use.
+	// Zero means no limit.
+	MaxConnsPerHost int
+
+	// The maximum number of idle connections kept open, in total and to
+	// each host. http.DefaultTransport keeps only 2 to each host, so
+	// clients sending many requests concurrently keep opening new ones.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+
+	// How long an idle connection is kept open.
+	IdleConnTimeout time.Duration
+
+	// If DisableHTTP2 is true, only HTTP/1.1 is used.
+	DisableHTTP2 bool
+
+	// HTTP/2 settings, such as the maximum number of concurrent streams
+	// and the health checks of idle connections. If nil, the defaults are
+	// used.
+	HTTP2 *HTTP2Config
+
+	// The certificates presented to servers that ask for one, as do
+	// egress gateways enforcing mutual TLS. See LoadClientCertificate.
+	Certificates []tls.Certificate
+
+	// The certificate authorities trusted to sign the certificates of
+	// servers, such as those of a gateway intercepting TLS. If nil, the
+	// system's are used. See LoadCertPool.
+	RootCAs *x509.CertPool
+}
+
+// NewTransport returns a copy of http.DefaultTransport tuned with opts,
+// which may be nil. It is used by setting the Transport of the client's
+// HTTPClient:
+//
+//	client.HTTPClient = &http.Client{
+//		Transport: insights.NewTransport(&insights.TransportOptions{
+//			MaxIdleConnsPerHost: 32,
+//		}),
+//	}
+func NewTransport(opts *TransportOptions) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if opts == nil {
+		return t
+	}
+	if opts.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = opts.MaxConnsPerHost
+	}
+	if opts.MaxIdleConns > 0 {
+		t.MaxIdleConns = opts.MaxIdleConns
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	setHTTP2(t, opts)
+	if opts.Certificates != nil || opts.RootCAs != nil {
+		cfg := new(tls.Config)
+		if t.TLSClientConfig != nil {
+			cfg = t.TLSClientConfig.Clone()
+		}
+		cfg.Certificates = opts.Certificates
+		cfg.RootCAs = opts.RootCAs
+		t.TLSClientConfig = cfg
+	}
+	return t
+}
+
+// LoadClientCertificate reads a client certificate and its private key
+// from a pair of PEM files, for TransportOptions.Certificates. The
+// certificate file may hold intermediate certificates after the leaf.
+func LoadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("insights: client certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// LoadCertPool returns the system's certificate pool with the certificates
+// in the given PEM files added, for TransportOptions.RootCAs.
+func LoadCertPool(files ...string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("insights: %s: no PEM certificates", f)
+		}
+	}
+	return pool, nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.24
+
+package insights
+
+import "net/http"
+
+// HTTP2Config holds the HTTP/2 settings of TransportOptions. It is
+// http.HTTP2Config, which is only available since Go 1.24.
+type HTTP2Config = http.HTTP2Config
+
+// setHTTP2 applies the HTTP/2 options of opts to t.
+func setHTTP2(t *http.Transport, opts *TransportOptions) {
+	if opts.DisableHTTP2 {
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetHTTP1(true)
+	}
+	if opts.HTTP2 != nil {
+		cfg := *opts.HTTP2
+		t.HTTP2 = &cfg
+	}
+}
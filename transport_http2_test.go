@@ -0,0 +1,25 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.24
+
+package insights
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewTransportHTTP2(t *testing.T) {
+	tr := NewTransport(&TransportOptions{
+		DisableHTTP2: true,
+		HTTP2:        &http.HTTP2Config{MaxConcurrentStreams: 50},
+	})
+	if tr.Protocols == nil || tr.Protocols.HTTP2() || !tr.Protocols.HTTP1() {
+		t.Errorf("Protocols = %v, want HTTP/1 only", tr.Protocols)
+	}
+	if tr.HTTP2 == nil || tr.HTTP2.MaxConcurrentStreams != 50 {
+		t.Errorf("HTTP2 = %+v, want MaxConcurrentStreams 50", tr.HTTP2)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.24
+
+package insights
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// HTTP2Config holds the HTTP/2 settings of TransportOptions. Before Go
+// 1.24, which added http.HTTP2Config, the HTTP/2 settings of a transport
+// cannot be changed, and it has none.
+type HTTP2Config struct{}
+
+// setHTTP2 applies the HTTP/2 options of opts to t.
+func setHTTP2(t *http.Transport, opts *TransportOptions) {
+	if opts.DisableHTTP2 {
+		// A non-nil map disables HTTP/2.
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	def := http.DefaultTransport.(*http.Transport)
+	tr := NewTransport(nil)
+	if tr == def {
+		t.Fatal("NewTransport returned http.DefaultTransport, want a copy")
+	}
+	if tr.MaxIdleConnsPerHost != def.MaxIdleConnsPerHost || tr.IdleConnTimeout != def.IdleConnTimeout {
+		t.Errorf("NewTransport(nil) changed the defaults")
+	}
+
+	tr = NewTransport(&TransportOptions{
+		MaxConnsPerHost:     16,
+		MaxIdleConnsPerHost: 8,
+		IdleConnTimeout:     time.Minute,
+	})
+	if tr.MaxConnsPerHost != 16 || tr.MaxIdleConnsPerHost != 8 || tr.IdleConnTimeout != time.Minute {
+		t.Errorf("got MaxConnsPerHost %d, MaxIdleConnsPerHost %d, IdleConnTimeout %v; want 16, 8, 1m0s",
+			tr.MaxConnsPerHost, tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+	if tr.MaxIdleConns != def.MaxIdleConns {
+		t.Errorf("MaxIdleConns = %d, want default %d", tr.MaxIdleConns, def.MaxIdleConns)
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and its
+// key to PEM files in dir and returns their names.
+func writeClientCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewTransportMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeClientCertificate(t, dir)
+	clientCAs, err := LoadCertPool(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	serverCA := filepath.Join(dir, "server.pem")
+	if err := os.WriteFile(serverCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCAs, err := LoadCertPool(serverCA)
+	if err != nil {
+		t.Fatalf("LoadCertPool failed: %v", err)
+	}
+	cert, err := LoadClientCertificate(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadClientCertificate failed: %v", err)
+	}
+
+	// Without a client certificate, the handshake fails.
+	hc := &http.Client{Transport: NewTransport(&TransportOptions{RootCAs: rootCAs})}
+	if resp, err := hc.Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Error("request without a client certificate succeeded")
+	}
+	hc = &http.Client{Transport: NewTransport(&TransportOptions{RootCAs: rootCAs, Certificates: []tls.Certificate{cert}})}
+	resp, err := hc.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with a client certificate failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := LoadCertPool(keyFile); err == nil {
+		t.Error("LoadCertPool accepted a file without certificates")
+	}
+}
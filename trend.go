@@ -0,0 +1,309 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sample is an observation of the adoption of a package at some time.
+type Sample struct {
+	// When the sample was taken.
+	Time time.Time
+
+	// The default version of the package at the time, whose dependents
+	// were counted.
+	Version string
+
+	// The number of package versions depending on the default version,
+	// in total and directly.
+	Dependents       int
+	DirectDependents int
+
+	// The number of stars of the source repository of the package, or
+	// zero if it is not known.
+	Stars int
+
+	// The OpenSSF Scorecard score of the source repository of the package,
+	// or zero if it has no scorecard.
+	Scorecard float64
+}
+
+// SampleAdoption returns a Sample of the current adoption of a package: the
+// dependents of its default version and the stars of its source
+// repository.
+func (c *Client) SampleAdoption(ctx context.Context, system, name string) (*Sample, error) {
+	p, _, err := c.GetPackage(ctx, system, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range p.Versions {
+		if !v.IsDefault {
+			continue
+		}
+		// The versions listed by GetPackage have no related projects.
+		k := v.VersionKey
+		full, _, err := c.GetVersion(ctx, k.System, k.Name, k.Version)
+		if err != nil {
+			return nil, err
+		}
+		return c.sampleVersion(ctx, full)
+	}
+	return nil, fmt.Errorf("%s package %s has no default version", system, name)
+}
+
+// sampleVersion returns a Sample of the adoption of the default version v,
+// as returned by GetVersion.
+func (c *Client) sampleVersion(ctx context.Context, v *Version) (*Sample, error) {
+	k := v.VersionKey
+	s := &Sample{Time: time.Now().UTC(), Version: k.Version}
+	d, _, err := c.GetDependents(ctx, k.System, k.Name, k.Version)
+	switch {
+	case err == nil:
+		s.Dependents = d.DependentCount
+		s.DirectDependents = d.DirectDependentCount
+	case !notFound(err):
+		return nil, err
+	}
+	for _, rp := range v.RelatedProjects {
+		if rp.RelationType != "SOURCE_REPO" {
+			continue
+		}
+		p, _, err := c.GetProject(ctx, rp.ProjectKey.ID)
+		switch {
+		case err == nil:
+			s.Stars = p.StarsCount
+			s.Scorecard = p.Scorecard.OverallScore
+		case !notFound(err):
+			return nil, err
+		}
+		break
+	}
+	return s, nil
+}
+
+// History records Samples of the adoption of packages over time in a file,
+// so that their Trend can be told. A History is safe for concurrent use by
+// multiple goroutines.
+type History struct {
+	// The file the history is saved to, in JSON.
+	File string
+
+	mu       sync.Mutex
+	packages map[PackageKey][]Sample
+}
+
+// historyPackage is the form in which the samples of a package are saved.
+type historyPackage struct {
+	PackageKey PackageKey
+	Samples    []Sample
+}
+
+// LoadHistory returns the history saved in file. If file does not exist,
+// the history is empty.
+func LoadHistory(file string) (*History, error) {
+	h := &History{File: file, packages: make(map[PackageKey][]Sample)}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved []historyPackage
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	for _, p := range saved {
+		k := historyKey(p.PackageKey)
+		h.packages[k] = append(h.packages[k], p.Samples...)
+	}
+	return h, nil
+}
+
+// Save writes the history to its File, replacing it.
+func (h *History) Save() error {
+	h.mu.Lock()
+	saved := make([]historyPackage, 0, len(h.packages))
+	for k, samples := range h.packages {
+		saved = append(saved, historyPackage{PackageKey: k, Samples: samples})
+	}
+	h.mu.Unlock()
+	slices.SortFunc(saved, func(a, b historyPackage) int {
+		return cmp.Or(cmp.Compare(a.PackageKey.System, b.PackageKey.System), cmp.Compare(a.PackageKey.Name, b.PackageKey.Name))
+	})
+	data, err := json.MarshalIndent(saved, "", "\t")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(h.File); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	// Write to a temporary file and rename it, so that an interrupted
+	// save does not lose the history.
+	tmp := h.File + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.File)
+}
+
+// historyKey returns k in the form the history keeps it.
+func historyKey(k PackageKey) PackageKey {
+	k.System = strings.ToUpper(k.System)
+	k.Name = CanonicalName(k.System, k.Name)
+	return k
+}
+
+// Record adds a sample of the package k to the history. The history keeps
+// a sample a day: a sample taken on the same day, in UTC, as the last one
+// replaces it.
+func (h *History) Record(k PackageKey, s Sample) {
+	k = historyKey(k)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.packages == nil {
+		h.packages = make(map[PackageKey][]Sample)
+	}
+	samples := h.packages[k]
+	if n := len(samples); n > 0 && sameDay(samples[n-1].Time, s.Time) {
+		samples[n-1] = s
+		return
+	}
+	h.packages[k] = append(samples, s)
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// Packages returns the packages with samples in the history, sorted.
+func (h *History) Packages() []PackageKey {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]PackageKey, 0, len(h.packages))
+	for k := range h.packages {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b PackageKey) int {
+		return cmp.Or(cmp.Compare(a.System, b.System), cmp.Compare(a.Name, b.Name))
+	})
+	return keys
+}
+
+// Samples returns the samples of the package k, oldest first.
+func (h *History) Samples(k PackageKey) []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.packages[historyKey(k)])
+}
+
+// Direction is the direction in which the adoption of a package is going.
+type Direction int
+
+const (
+	// Steady adoption changed by less than trendThreshold.
+	Steady Direction = iota
+
+	// Growing adoption.
+	Growing
+
+	// Declining adoption.
+	Declining
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Steady:
+		return "steady"
+	case Growing:
+		return "growing"
+	case Declining:
+		return "declining"
+	}
+	return "unknown direction"
+}
+
+// trendThreshold is the smallest relative change in adoption that is not
+// Steady.
+const trendThreshold = 0.05
+
+// Trend is the change in the adoption of a package over a period.
+type Trend struct {
+	PackageKey PackageKey
+
+	// The first and last samples of the period.
+	First, Last Sample
+
+	// The change in the number of dependents, of stars, and of the
+	// Scorecard score over the period.
+	Dependents int
+	Stars      int
+	Scorecard  float64
+
+	// The direction of the change in dependents, or, for packages with no
+	// dependents, in stars.
+	Direction Direction
+}
+
+// Trend returns the trend of the adoption of the package k since the given
+// time, reporting whether there are at least two samples since then to
+// tell it from.
+func (h *History) Trend(k PackageKey, since time.Time) (*Trend, bool) {
+	samples := h.Samples(k)
+	i, _ := slices.BinarySearchFunc(samples, since, func(s Sample, t time.Time) int {
+		return s.Time.Compare(t)
+	})
+	samples = samples[i:]
+	if len(samples) < 2 {
+		return nil, false
+	}
+	t := &Trend{
+		PackageKey: historyKey(k),
+		First:      samples[0],
+		Last:       samples[len(samples)-1],
+	}
+	t.Dependents = t.Last.Dependents - t.First.Dependents
+	t.Stars = t.Last.Stars - t.First.Stars
+	t.Scorecard = t.Last.Scorecard - t.First.Scorecard
+	if t.First.Dependents > 0 || t.Last.Dependents > 0 {
+		t.Direction = direction(t.First.Dependents, t.Last.Dependents)
+	} else {
+		t.Direction = direction(t.First.Stars, t.Last.Stars)
+	}
+	return t, true
+}
+
+// direction returns the direction of a change from a to b.
+func direction(a, b int) Direction {
+	switch {
+	case a == 0 && b > 0:
+		return Growing
+	case a == 0:
+		return Steady
+	}
+	switch change := float64(b-a) / float64(a); {
+	case change >= trendThreshold:
+		return Growing
+	case change <= -trendThreshold:
+		return Declining
+	}
+	return Steady
+}
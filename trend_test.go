@@ -0,0 +1,107 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSampleAdoption(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/npm/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versions":[{"versionKey":{"system":"NPM","name":"foo","version":"1.0.0"}},{"versionKey":{"system":"NPM","name":"foo","version":"2.0.0"},"isDefault":true}]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/foo/versions/2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"foo","version":"2.0.0"},"relatedProjects":[{"projectKey":{"id":"github.com/example/foo"},"relationType":"SOURCE_REPO"}]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/foo/versions/2.0.0:dependents", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"dependentCount":120,"directDependentCount":30}`)
+	})
+	mux.HandleFunc("/projects/github.com%2Fexample%2Ffoo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"starsCount":42,"scorecard":{"overallScore":6.5}}`)
+	})
+
+	got, err := client.SampleAdoption(context.Background(), "npm", "foo")
+	if err != nil {
+		t.Fatalf("SampleAdoption failed: %v", err)
+	}
+	want := &Sample{Version: "2.0.0", Dependents: 120, DirectDependents: 30, Stars: 42, Scorecard: 6.5}
+	if diff := cmp.Diff(want, got, cmp.FilterPath(func(p cmp.Path) bool { return p.String() == "Time" }, cmp.Ignore())); diff != "" {
+		t.Errorf("SampleAdoption mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHistoryTrend(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history.json")
+	h, err := LoadHistory(file)
+	if err != nil {
+		t.Fatalf("LoadHistory of a missing file failed: %v", err)
+	}
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	foo := PackageKey{System: "npm", Name: "foo"}
+	bar := PackageKey{System: "PYPI", Name: "Bar_Baz"}
+	h.Record(foo, Sample{Time: day(1), Dependents: 100, Stars: 10, Scorecard: 5})
+	h.Record(foo, Sample{Time: day(2), Dependents: 105})
+	// Replaces the sample of the same day.
+	h.Record(foo, Sample{Time: day(2).Add(time.Hour), Dependents: 110, Stars: 12, Scorecard: 5.5})
+	h.Record(bar, Sample{Time: day(1), Dependents: 50})
+	h.Record(bar, Sample{Time: day(3), Dependents: 49})
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	h, err = LoadHistory(file)
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	wantKeys := []PackageKey{{System: "NPM", Name: "foo"}, {System: "PYPI", Name: "bar-baz"}}
+	if diff := cmp.Diff(wantKeys, h.Packages()); diff != "" {
+		t.Errorf("Packages mismatch (-want +got):\n%s", diff)
+	}
+	if n := len(h.Samples(foo)); n != 2 {
+		t.Errorf("foo has %d samples; want 2", n)
+	}
+
+	tr, ok := h.Trend(foo, time.Time{})
+	if !ok {
+		t.Fatal("Trend of foo not known")
+	}
+	if tr.Dependents != 10 || tr.Stars != 2 || tr.Scorecard != 0.5 || tr.Direction != Growing {
+		t.Errorf("Trend of foo = %+v; want 10 more dependents, 2 more stars, a score 0.5 higher, growing", tr)
+	}
+	tr, ok = h.Trend(PackageKey{System: "pypi", Name: "bar.baz"}, time.Time{})
+	if !ok || tr.Direction != Steady || tr.Dependents != -1 {
+		t.Errorf("Trend of bar = %+v, %t; want steady", tr, ok)
+	}
+	if _, ok := h.Trend(foo, day(2)); ok {
+		t.Error("Trend of foo since its last sample is known; want not")
+	}
+}
+
+func TestDirection(t *testing.T) {
+	tests := []struct {
+		a, b int
+		want Direction
+	}{
+		{0, 0, Steady},
+		{0, 3, Growing},
+		{100, 104, Steady},
+		{100, 105, Growing},
+		{100, 90, Declining},
+		{100, 0, Declining},
+	}
+	for _, tt := range tests {
+		if got := direction(tt.a, tt.b); got != tt.want {
+			t.Errorf("direction(%d, %d) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
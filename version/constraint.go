@@ -0,0 +1,621 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a version requirement, such as "^1.2.3" for npm or
+// "[1.0,2.0)" for Maven, parsed following the rules of its system:
+//
+//   - npm: node-semver ranges, including "||", hyphen, caret, tilde, and
+//     X-ranges.
+//   - Cargo: comma-separated comparators, where a bare version is a caret
+//     requirement.
+//   - Go: a module query: a version, a version prefix such as "v1.2", a
+//     single comparison such as "<v1.3.0", or "latest".
+//   - PyPI: comma-separated PEP 440 version specifiers.
+//   - Maven and NuGet: version ranges such as "[1.0,2.0)" or "(,1.0],[1.2,)".
+//     A bare version is a soft requirement for that version in Maven and a
+//     minimum version in NuGet.
+//   - RubyGems: comma-separated Gem::Requirement comparisons, such as
+//     "~> 2.2, >= 2.2.1", where a bare version is an exact requirement.
+//
+// Pre-releases only satisfy a constraint that mentions a pre-release: for
+// npm, Cargo, and Go, one of the same release, as node-semver does; for the
+// other systems, any.
+type Constraint struct {
+	// The package management system of the constraint, in upper case.
+	System string
+
+	raw string
+
+	// The versions matched are those matched by all the terms of any of the
+	// sets.
+	sets [][]term
+
+	// The release numbers of the pre-release versions mentioned by each set,
+	// for systems that only match pre-releases of those releases.
+	pre [][][]int
+
+	// Whether pre-releases are matched, for systems where mentioning any
+	// pre-release allows all of them.
+	allowPre bool
+
+	// Whether Resolve prefers the lowest matching version.
+	lowest bool
+
+	// An exact version Resolve prefers, if any.
+	soft *Version
+}
+
+// term reports whether a version satisfies part of a constraint.
+type term func(v *Version) bool
+
+// ParseConstraint parses the version requirement s for a package of the
+// given system.
+func ParseConstraint(system, s string) (*Constraint, error) {
+	system = strings.ToUpper(system)
+	c := &Constraint{System: system, raw: s}
+	p := &constraintParser{system: system, c: c}
+	var err error
+	switch system {
+	case "NPM":
+		err = p.parseNPM(s)
+	case "CARGO":
+		err = p.parseCargo(s)
+	case "GO":
+		err = p.parseGo(s)
+	case "PYPI":
+		err = p.parsePEP440(s)
+	case "MAVEN", "NUGET":
+		err = p.parseRanges(s)
+	case "RUBYGEMS":
+		err = p.parseRubyGems(s)
+	default:
+		err = fmt.Errorf("unsupported system")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s version requirement %q: %v", system, s, err)
+	}
+	return c, nil
+}
+
+// String returns the constraint as it was given to ParseConstraint.
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Match reports whether v satisfies c.
+func (c *Constraint) Match(v *Version) bool {
+	for i, set := range c.sets {
+		if v.Prerelease() && !c.allowPre && !containsRelease(c.pre[i], v.release) {
+			continue
+		}
+		ok := true
+		for _, t := range set {
+			if !t(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRelease(releases [][]int, r []int) bool {
+	for _, rr := range releases {
+		if cmpRelease(rr, r) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func cmpRelease(a, b []int) int {
+	va, vb := &Version{release: a}, &Version{release: b}
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if c := cmpInt(va.component(i), vb.component(i)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Resolve returns the version among versions that the package manager would
+// choose to satisfy c: the greatest matching version for most systems, the
+// lowest for NuGet, and the requested version for a Maven soft requirement
+// if available. Versions that cannot be parsed are ignored. It returns nil
+// if no version matches.
+func (c *Constraint) Resolve(versions []*Version) *Version {
+	var best *Version
+	for _, v := range versions {
+		if c.soft != nil && v.Compare(c.soft) == 0 {
+			return v
+		}
+		if !c.Match(v) {
+			continue
+		}
+		if best == nil || c.lowest && v.Compare(best) < 0 || !c.lowest && v.Compare(best) > 0 {
+			best = v
+		}
+	}
+	return best
+}
+
+// Resolve parses requirement and versions for a package of the given system
+// and returns the version that satisfies requirement as Constraint.Resolve
+// does.
+func Resolve(system, requirement string, versions []string) (string, error) {
+	c, err := ParseConstraint(system, requirement)
+	if err != nil {
+		return "", err
+	}
+	var vs []*Version
+	for _, s := range versions {
+		if v, err := Parse(system, s); err == nil {
+			vs = append(vs, v)
+		}
+	}
+	v := c.Resolve(vs)
+	if v == nil {
+		return "", fmt.Errorf("no version satisfies %q", requirement)
+	}
+	return v.String(), nil
+}
+
+func greater(w *Version) term      { return func(v *Version) bool { return v.Compare(w) > 0 } }
+func greaterEqual(w *Version) term { return func(v *Version) bool { return v.Compare(w) >= 0 } }
+func less(w *Version) term         { return func(v *Version) bool { return v.Compare(w) < 0 } }
+func lessEqual(w *Version) term    { return func(v *Version) bool { return v.Compare(w) <= 0 } }
+func equal(w *Version) term        { return func(v *Version) bool { return v.Compare(w) == 0 } }
+
+// floor returns the lowest version with the given release number, lower
+// than any of its pre-releases.
+func floor(release ...int) *Version {
+	return &Version{release: release, pre: []string{}}
+}
+
+// bump returns release with its last component incremented.
+func bump(release []int) []int {
+	r := append([]int(nil), release...)
+	r[len(r)-1]++
+	return r
+}
+
+// partial is a possibly incomplete version, such as "1.2", "1.x", or "*".
+type partial struct {
+	release []int    // the components given
+	v       *Version // the version, if complete
+}
+
+type constraintParser struct {
+	system string
+	c      *Constraint
+	set    []term
+	pre    [][]int
+}
+
+func (p *constraintParser) add(t ...term) {
+	p.set = append(p.set, t...)
+}
+
+// endSet adds the terms added so far as a set of the constraint.
+func (p *constraintParser) endSet() {
+	if p.set == nil {
+		// An empty set matches any version.
+		p.set = []term{}
+	}
+	p.c.sets = append(p.c.sets, p.set)
+	p.c.pre = append(p.c.pre, p.pre)
+	p.set, p.pre = nil, nil
+}
+
+// parsePartial parses a semantic version that may lack components or have
+// wildcards in their place.
+func (p *constraintParser) parsePartial(s string) (partial, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var pt partial
+	core := s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		core = s[:i]
+	}
+	if core == "" {
+		return pt, fmt.Errorf("missing version")
+	}
+	fields := strings.Split(core, ".")
+	for i, f := range fields {
+		if f == "*" || f == "x" || f == "X" {
+			if i < len(fields)-1 && !isWildcard(fields[i+1:]) {
+				return pt, fmt.Errorf("invalid version %q", s)
+			}
+			return pt, nil
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return pt, fmt.Errorf("invalid version %q", s)
+		}
+		pt.release = append(pt.release, n)
+	}
+	if len(pt.release) < 3 {
+		if core != s {
+			return pt, fmt.Errorf("invalid version %q", s)
+		}
+		return pt, nil
+	}
+	v, err := Parse(p.system, s)
+	if err != nil {
+		return pt, err
+	}
+	pt.v = v
+	if v.Prerelease() {
+		p.pre = append(p.pre, v.release)
+	}
+	return pt, nil
+}
+
+func isWildcard(fields []string) bool {
+	for _, f := range fields {
+		if f != "*" && f != "x" && f != "X" {
+			return false
+		}
+	}
+	return true
+}
+
+// comparator adds the terms of a node-semver comparator, such as ">=1.2" or
+// "^1.2.3", where caret is the meaning of a bare complete version.
+func (p *constraintParser) comparator(s string, bare string) error {
+	op := ""
+	for _, o := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, o) {
+			op, s = o, s[len(o):]
+			break
+		}
+	}
+	if op == "~" {
+		s = strings.TrimPrefix(s, ">") // "~>" is a synonym of "~".
+	}
+	pt, err := p.parsePartial(s)
+	if err != nil {
+		return err
+	}
+	if op == "" {
+		op = bare
+	}
+	r := pt.release
+	switch op {
+	case "=":
+		switch {
+		case pt.v != nil:
+			p.add(equal(pt.v))
+		case len(r) > 0:
+			p.add(greaterEqual(floor(r...)), less(floor(bump(r)...)))
+		}
+	case "^":
+		// Changes that do not modify the left-most non-zero component.
+		if len(r) == 0 {
+			break
+		}
+		lo := floor(r...)
+		if pt.v != nil {
+			lo = pt.v
+		}
+		i := 0
+		for i < len(r)-1 && r[i] == 0 {
+			i++
+		}
+		p.add(greaterEqual(lo), less(floor(bump(r[:i+1])...)))
+	case "~":
+		// Patch-level changes if a minor version is given, minor-level
+		// changes if not.
+		if len(r) == 0 {
+			break
+		}
+		lo := floor(r...)
+		if pt.v != nil {
+			lo = pt.v
+		}
+		n := 2
+		if len(r) == 1 {
+			n = 1
+		}
+		p.add(greaterEqual(lo), less(floor(bump(r[:n])...)))
+	case ">":
+		switch {
+		case pt.v != nil:
+			p.add(greater(pt.v))
+		case len(r) > 0:
+			p.add(greaterEqual(floor(bump(r)...)))
+		default:
+			p.add(func(*Version) bool { return false })
+		}
+	case ">=":
+		switch {
+		case pt.v != nil:
+			p.add(greaterEqual(pt.v))
+		case len(r) > 0:
+			p.add(greaterEqual(floor(r...)))
+		}
+	case "<":
+		switch {
+		case pt.v != nil:
+			p.add(less(pt.v))
+		case len(r) > 0:
+			p.add(less(floor(r...)))
+		default:
+			p.add(func(*Version) bool { return false })
+		}
+	case "<=":
+		switch {
+		case pt.v != nil:
+			p.add(lessEqual(pt.v))
+		case len(r) > 0:
+			p.add(less(floor(bump(r)...)))
+		}
+	}
+	return nil
+}
+
+// parseNPM parses a node-semver range.
+func (p *constraintParser) parseNPM(s string) error {
+	for _, set := range strings.Split(s, "||") {
+		if lo, hi, ok := strings.Cut(set, " - "); ok {
+			if err := p.comparator(">="+strings.TrimSpace(lo), "="); err != nil {
+				return err
+			}
+			if err := p.comparator("<="+strings.TrimSpace(hi), "="); err != nil {
+				return err
+			}
+			p.endSet()
+			continue
+		}
+		fields := strings.Fields(set)
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+			// Allow space between an operator and its version.
+			if strings.Trim(f, "<>=^~") == "" && i+1 < len(fields) {
+				i++
+				f += fields[i]
+			}
+			if err := p.comparator(f, "="); err != nil {
+				return err
+			}
+		}
+		p.endSet()
+	}
+	return nil
+}
+
+// parseCargo parses a Cargo version requirement.
+func (p *constraintParser) parseCargo(s string) error {
+	for _, f := range strings.Split(s, ",") {
+		f = strings.Join(strings.Fields(f), "")
+		if f == "" {
+			return fmt.Errorf("empty comparator")
+		}
+		if err := p.comparator(f, "^"); err != nil {
+			return err
+		}
+	}
+	p.endSet()
+	return nil
+}
+
+// parseGo parses a Go module query.
+func (p *constraintParser) parseGo(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "latest" || s == "upgrade" {
+		p.endSet()
+		return nil
+	}
+	if !strings.HasPrefix(strings.TrimLeft(s, "<>="), "v") {
+		return fmt.Errorf("version must start with v")
+	}
+	if err := p.comparator(s, "="); err != nil {
+		return err
+	}
+	p.endSet()
+	return nil
+}
+
+// parsePEP440 parses PEP 440 version specifiers.
+func (p *constraintParser) parsePEP440(s string) error {
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		op := ""
+		for _, o := range []string{"===", "~=", "==", "!=", "<=", ">=", "<", ">"} {
+			if strings.HasPrefix(f, o) {
+				op, f = o, strings.TrimSpace(f[len(o):])
+				break
+			}
+		}
+		if op == "" {
+			return fmt.Errorf("missing operator in %q", f)
+		}
+		if op == "===" {
+			p.add(func(v *Version) bool { return v.raw == f })
+			continue
+		}
+		prefix := strings.HasSuffix(f, ".*")
+		if prefix && op != "==" && op != "!=" {
+			return fmt.Errorf("wildcard not allowed with %s", op)
+		}
+		w, err := Parse("PYPI", strings.TrimSuffix(f, ".*"))
+		if err != nil {
+			return err
+		}
+		if w.Prerelease() {
+			p.c.allowPre = true
+		}
+		switch op {
+		case "~=":
+			if len(w.release) < 2 {
+				return fmt.Errorf("~= requires at least two release components")
+			}
+			r := w.release[:len(w.release)-1]
+			p.add(greaterEqual(w), less(floor(bump(r)...)))
+		case "==", "!=":
+			t := equal(w)
+			if prefix {
+				lo, hi := floor(w.release...), floor(bump(w.release)...)
+				lo.epoch, hi.epoch = w.epoch, w.epoch
+				t = func(v *Version) bool { return v.Compare(lo) >= 0 && v.Compare(hi) < 0 }
+			}
+			if op == "!=" {
+				eq := t
+				t = func(v *Version) bool { return !eq(v) }
+			}
+			p.add(t)
+		case "<=":
+			p.add(lessEqual(w))
+		case ">=":
+			p.add(greaterEqual(w))
+		case "<":
+			// Pre-releases of the given version are not lower than it.
+			lo := floor(w.release...)
+			lo.epoch = w.epoch
+			if w.Prerelease() {
+				lo = w
+			}
+			p.add(less(lo))
+		case ">":
+			p.add(greater(w))
+		}
+	}
+	p.endSet()
+	return nil
+}
+
+// parseRubyGems parses a RubyGems requirement.
+func (p *constraintParser) parseRubyGems(s string) error {
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		op := "="
+		for _, o := range []string{"~>", ">=", "<=", "!=", "=", ">", "<"} {
+			if strings.HasPrefix(f, o) {
+				op, f = o, strings.TrimSpace(f[len(o):])
+				break
+			}
+		}
+		w, err := Parse("RUBYGEMS", f)
+		if err != nil {
+			return err
+		}
+		if w.Prerelease() {
+			p.c.allowPre = true
+		}
+		switch op {
+		case "~>":
+			// The last release component may increase: "~> 2.2" allows
+			// 2.2 up to 3.0, excluded.
+			r := w.release
+			if len(r) > 1 {
+				r = r[:len(r)-1]
+			}
+			p.add(greaterEqual(w), less(floor(bump(r)...)))
+		case "=":
+			p.add(equal(w))
+		case "!=":
+			p.add(func(v *Version) bool { return v.Compare(w) != 0 })
+		case ">=":
+			p.add(greaterEqual(w))
+		case "<=":
+			p.add(lessEqual(w))
+		case ">":
+			p.add(greater(w))
+		case "<":
+			p.add(less(w))
+		}
+	}
+	p.endSet()
+	return nil
+}
+
+// parseRanges parses Maven or NuGet version ranges, such as "[1.0,2.0)",
+// "(,1.0],[1.2,)", or "[1.5]", or a bare version.
+func (p *constraintParser) parseRanges(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return fmt.Errorf("empty requirement")
+	}
+	if s[0] != '[' && s[0] != '(' {
+		v, err := Parse(p.system, s)
+		if err != nil {
+			return err
+		}
+		if p.system == "MAVEN" {
+			// A soft requirement: the given version if available, or
+			// else any other.
+			p.c.soft = v
+		} else {
+			p.add(greaterEqual(v))
+			p.c.lowest = true
+		}
+		p.c.allowPre = v.Prerelease()
+		p.endSet()
+		return nil
+	}
+	if p.system == "NUGET" {
+		p.c.lowest = true
+	}
+	for s != "" {
+		end := strings.IndexAny(s, "])")
+		if end < 0 || s[0] != '[' && s[0] != '(' {
+			return fmt.Errorf("malformed range")
+		}
+		r := s[1:end]
+		lo, hi, isRange := strings.Cut(r, ",")
+		if !isRange {
+			if s[0] != '[' || s[end] != ']' {
+				return fmt.Errorf("malformed range")
+			}
+			v, err := Parse(p.system, strings.TrimSpace(lo))
+			if err != nil {
+				return err
+			}
+			p.c.allowPre = p.c.allowPre || v.Prerelease()
+			p.add(equal(v))
+		}
+		if isRange {
+			if lo = strings.TrimSpace(lo); lo != "" {
+				v, err := Parse(p.system, lo)
+				if err != nil {
+					return err
+				}
+				p.c.allowPre = p.c.allowPre || v.Prerelease()
+				if s[0] == '[' {
+					p.add(greaterEqual(v))
+				} else {
+					p.add(greater(v))
+				}
+			}
+			if hi = strings.TrimSpace(hi); hi != "" {
+				v, err := Parse(p.system, hi)
+				if err != nil {
+					return err
+				}
+				p.c.allowPre = p.c.allowPre || v.Prerelease()
+				if s[end] == ']' {
+					p.add(lessEqual(v))
+				} else {
+					p.add(less(v))
+				}
+			}
+		}
+		p.endSet()
+		s = strings.TrimLeft(s[end+1:], " ,")
+	}
+	return nil
+}
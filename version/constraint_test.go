@@ -0,0 +1,108 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	npm := []string{"0.0.3", "0.0.4", "0.1.0", "0.2.5", "1.0.0", "1.2.0", "1.2.5", "1.3.0-beta.1", "1.3.0", "2.0.0-rc.1", "2.0.0", "2.1.0"}
+	pypi := []string{"1.0", "1.4.5", "1.4.9", "1.5", "2.0rc1", "2.0", "2.2", "2.3.1", "3.0a1"}
+	maven := []string{"1.0", "1.5", "2.0-SNAPSHOT", "2.0", "2.1", "3.0"}
+	golang := []string{"v1.0.0", "v1.2.0", "v1.2.3", "v1.3.0-pre", "v1.3.0"}
+	gems := []string{"2.1.0", "2.2.0", "2.2.5", "2.3.0.rc1", "2.3.0", "3.0.0"}
+
+	testCases := []struct {
+		system, requirement string
+		versions            []string
+		want                string // empty if none matches
+	}{
+		{"NPM", "^1.2.0", npm, "1.3.0"},
+		{"NPM", "~1.2.0", npm, "1.2.5"},
+		{"NPM", "^0.1.0", npm, "0.1.0"},
+		{"NPM", "^0.0.3", npm, "0.0.3"},
+		{"NPM", "1.x", npm, "1.3.0"},
+		{"NPM", "*", npm, "2.1.0"},
+		{"NPM", "", npm, "2.1.0"},
+		{"NPM", ">=1.0.0 <1.3.0", npm, "1.2.5"},
+		{"NPM", ">= 1.0.0 < 1.3", npm, "1.2.5"},
+		{"NPM", "1.0.0 - 1.2", npm, "1.2.5"},
+		{"NPM", "<1.0.0 || ^2.0.0", npm, "2.1.0"},
+		{"NPM", "<1.0.0", npm, "0.2.5"},
+		{"NPM", "1.3.0-beta.1", npm, "1.3.0-beta.1"},
+		{"NPM", ">=1.3.0-beta.0 <1.3.0", npm, "1.3.0-beta.1"},
+		{"NPM", ">1.2.5 <2.0.0-rc.2", npm, "2.0.0-rc.1"},
+		{"NPM", ">2.1", npm, ""},
+		{"CARGO", "1.2", npm, "1.3.0"},
+		{"CARGO", "=1.2.0", npm, "1.2.0"},
+		{"CARGO", ">=1.0, <1.2.5", npm, "1.2.0"},
+		{"CARGO", "~1", npm, "1.3.0"},
+		{"GO", "v1.2", golang, "v1.2.3"},
+		{"GO", "latest", golang, "v1.3.0"},
+		{"GO", "<v1.3.0", golang, "v1.2.3"},
+		{"GO", "v1.0.0", golang, "v1.0.0"},
+		{"PYPI", "~=1.4.5", pypi, "1.4.9"},
+		{"PYPI", "~=1.4", pypi, "1.5"},
+		{"PYPI", "==1.4.*", pypi, "1.4.9"},
+		{"PYPI", ">=1.0,!=2.3.1", pypi, "2.2"},
+		{"PYPI", "<2.0", pypi, "1.5"},
+		{"PYPI", ">=2.0rc1,<2.1", pypi, "2.0"},
+		{"PYPI", ">=3.0a1", pypi, "3.0a1"},
+		{"PYPI", "===1.0", pypi, "1.0"},
+		{"MAVEN", "[1.0,2.0)", maven, "1.5"},
+		{"MAVEN", "[1.0,2.0]", maven, "2.0"},
+		{"MAVEN", "(,1.0],[2.1,)", maven, "3.0"},
+		{"MAVEN", "[1.5]", maven, "1.5"},
+		{"MAVEN", "2.0", maven, "2.0"},
+		{"MAVEN", "2.5", maven, "3.0"},
+		{"NUGET", "1.5", maven, "1.5"},
+		{"NUGET", "1.6", maven, "2.0"},
+		{"NUGET", "[1.1,3.0)", maven, "1.5"},
+		{"RUBYGEMS", "~> 2.2", gems, "2.3.0"},
+		{"RUBYGEMS", "~> 2.2.0", gems, "2.2.5"},
+		{"RUBYGEMS", "~> 2", gems, "2.3.0"},
+		{"RUBYGEMS", ">= 2.2, < 2.3", gems, "2.2.5"},
+		{"RUBYGEMS", "2.2.0", gems, "2.2.0"},
+		{"RUBYGEMS", "!= 3.0.0", gems, "2.3.0"},
+		{"RUBYGEMS", ">= 2.3.0.rc1, < 2.3.0", gems, "2.3.0.rc1"},
+		{"RUBYGEMS", "> 3.0", gems, ""},
+	}
+	for _, c := range testCases {
+		got, err := Resolve(c.system, c.requirement, c.versions)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("Resolve(%q, %q) = %q; want error", c.system, c.requirement, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%q, %q) failed: %v", c.system, c.requirement, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Resolve(%q, %q) = %q; want %q", c.system, c.requirement, got, c.want)
+		}
+	}
+}
+
+func TestParseConstraintError(t *testing.T) {
+	testCases := [][2]string{
+		{"NPM", "^1.x.2"},
+		{"NPM", ">=a.b"},
+		{"CARGO", "1.0,"},
+		{"GO", "1.2.3"},
+		{"PYPI", "1.0"},
+		{"PYPI", ">=1.*"},
+		{"PYPI", "~=1"},
+		{"MAVEN", "[1.0,2.0"},
+		{"MAVEN", "(1.0)"},
+		{"RUBYGEMS", "=> 1.0"},
+		{"CONDA", "1.0"},
+	}
+	for _, c := range testCases {
+		if _, err := ParseConstraint(c[0], c[1]); err == nil {
+			t.Errorf("ParseConstraint(%q, %q) succeeded", c[0], c[1])
+		}
+	}
+}
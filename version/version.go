@@ -0,0 +1,402 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package version parses and orders the versions of packages following the
+// rules of each package management system known to deps.dev.
+//
+// Go, npm, Cargo, and NuGet versions follow Semantic Versioning. PyPI
+// versions follow PEP 440, Maven versions the ordering of Maven's
+// ComparableVersion, and RubyGems versions that of Gem::Version, all
+// approximately: uncommon forms are accepted but may not be ordered exactly
+// as the package manager would.
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed package version.
+type Version struct {
+	// The package management system the version belongs to, in upper case.
+	System string
+
+	epoch   int
+	release []int
+	pre     []string // pre-release identifiers; nil for a release
+	post    int      // PyPI post-release or Maven service pack number
+	extra   string   // anything else, compared lexically
+	raw     string
+}
+
+// Parse parses the version v of a package of the given system.
+func Parse(system, v string) (*Version, error) {
+	system = strings.ToUpper(system)
+	var (
+		ver *Version
+		ok  bool
+	)
+	switch system {
+	case "PYPI":
+		ver, ok = parsePEP440(v)
+	case "MAVEN":
+		ver, ok = parseMaven(v)
+	case "RUBYGEMS":
+		ver, ok = parseRubyGems(v)
+	default:
+		ver, ok = parseSemver(system, v)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid %s version %q", system, v)
+	}
+	ver.System = system
+	ver.raw = v
+	return ver, nil
+}
+
+// String returns the version as it was given to Parse.
+func (v *Version) String() string {
+	return v.raw
+}
+
+// Major returns the first component of the release number, or 0 if absent.
+func (v *Version) Major() int { return v.component(0) }
+
+// Minor returns the second component of the release number, or 0 if absent.
+func (v *Version) Minor() int { return v.component(1) }
+
+// Patch returns the third component of the release number, or 0 if absent.
+func (v *Version) Patch() int { return v.component(2) }
+
+func (v *Version) component(i int) int {
+	if i < len(v.release) {
+		return v.release[i]
+	}
+	return 0
+}
+
+// Prerelease reports whether v is a pre-release, such as an alpha, beta,
+// release candidate, development, or snapshot version. Go pseudo-versions
+// are pre-releases too.
+func (v *Version) Prerelease() bool {
+	return v.pre != nil
+}
+
+// Compare returns -1, 0, or +1 depending on whether v is lower than, equal
+// to, or greater than w. Versions of different systems are compared as if
+// they were of the same one.
+func (v *Version) Compare(w *Version) int {
+	if c := cmpInt(v.epoch, w.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(v.release) || i < len(w.release); i++ {
+		if c := cmpInt(v.component(i), w.component(i)); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case v.pre == nil && w.pre != nil:
+		return 1
+	case v.pre != nil && w.pre == nil:
+		return -1
+	}
+	if c := cmpIdentifiers(v.pre, w.pre); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.post, w.post); c != 0 {
+		return c
+	}
+	return strings.Compare(v.extra, w.extra)
+}
+
+// Compare parses the versions a and b of a package of the given system and
+// compares them as Version.Compare does. Versions that cannot be parsed are
+// lower than any that can, and are compared lexically among themselves.
+func Compare(system, a, b string) int {
+	va, errA := Parse(system, a)
+	vb, errB := Parse(system, b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return va.Compare(vb)
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// cmpIdentifiers compares pre-release identifiers as Semantic Versioning
+// does: numeric identifiers are compared numerically and are lower than
+// alphanumeric ones, which are compared lexically, and a shorter list is
+// lower than a longer one it is a prefix of.
+func cmpIdentifiers(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmpInt(na, nb)
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmpInt(len(a), len(b))
+}
+
+// parseRelease parses a dot-separated list of numbers.
+func parseRelease(s string) ([]int, bool) {
+	if s == "" {
+		return nil, false
+	}
+	var release []int
+	for _, f := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || f[0] == '+' {
+			return nil, false
+		}
+		release = append(release, n)
+	}
+	return release, true
+}
+
+// parseSemver parses a Semantic Versioning version. A leading "v" is
+// allowed, build metadata is ignored, and NuGet versions may have a fourth
+// release component.
+func parseSemver(system, v string) (*Version, bool) {
+	s := strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var pre string
+	hasPre := false
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, pre, hasPre = s[:i], s[i+1:], true
+	}
+	release, ok := parseRelease(s)
+	components := 3
+	if system == "NUGET" {
+		components = 4
+	}
+	if !ok || len(release) > components {
+		return nil, false
+	}
+	ver := &Version{release: release}
+	if hasPre {
+		if pre == "" {
+			return nil, false
+		}
+		ver.pre = strings.Split(pre, ".")
+	}
+	return ver, true
+}
+
+// PEP 440 pre-release phases, as numeric identifiers ordered as the phases.
+var pep440Phases = map[string]string{
+	"dev":     "0",
+	"a":       "1",
+	"alpha":   "1",
+	"b":       "2",
+	"beta":    "2",
+	"c":       "3",
+	"rc":      "3",
+	"pre":     "3",
+	"preview": "3",
+}
+
+// parsePEP440 parses a PyPI version such as 1!2.0.0rc1.post2.dev3. Local
+// version labels are ignored.
+func parsePEP440(v string) (*Version, bool) {
+	s := strings.ToLower(strings.TrimSpace(v))
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	ver := new(Version)
+	if i := strings.IndexByte(s, '!'); i >= 0 {
+		epoch, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return nil, false
+		}
+		ver.epoch, s = epoch, s[i+1:]
+	}
+
+	// The release is the leading run of digits and dots.
+	i := 0
+	for i < len(s) && (s[i] == '.' || '0' <= s[i] && s[i] <= '9') {
+		i++
+	}
+	release, ok := parseRelease(strings.TrimSuffix(s[:i], "."))
+	if !ok {
+		return nil, false
+	}
+	ver.release = release
+	s = s[i:]
+
+	// What follows is a sequence of phases, each optionally separated from
+	// the previous one by ".", "-", or "_" and optionally numbered.
+	for s != "" {
+		s = strings.TrimLeft(s, ".-_")
+		j := 0
+		for j < len(s) && 'a' <= s[j] && s[j] <= 'z' {
+			j++
+		}
+		phase := s[:j]
+		s = strings.TrimLeft(s[j:], ".-_")
+		k := 0
+		for k < len(s) && '0' <= s[k] && s[k] <= '9' {
+			k++
+		}
+		n := 0
+		if k > 0 {
+			n, _ = strconv.Atoi(s[:k])
+		}
+		s = s[k:]
+		switch {
+		case phase == "post" || phase == "rev" || phase == "r" || phase == "" && k > 0:
+			ver.post = n + 1
+		case phase == "dev" && len(ver.pre) == 4:
+			// A development release of a pre-release precedes it.
+			ver.pre[2], ver.pre[3] = "0", strconv.Itoa(n)
+		case phase == "dev" && ver.post > 0:
+			// A development release of a post-release follows the release
+			// it is a post-release of but precedes the post-release.
+			ver.post--
+			ver.extra = "dev" + strconv.Itoa(n)
+		case phase == "dev":
+			ver.pre = []string{pep440Phases[phase], strconv.Itoa(n)}
+		case pep440Phases[phase] != "":
+			// The last two identifiers order development releases of the
+			// pre-release before it.
+			ver.pre = []string{pep440Phases[phase], strconv.Itoa(n), "1", "0"}
+		default:
+			return nil, false
+		}
+	}
+	return ver, true
+}
+
+// Maven qualifiers ordered before a release, as numeric identifiers ordered
+// as the qualifiers.
+var mavenPrerelease = map[string]string{
+	"alpha":     "1",
+	"a":         "1",
+	"beta":      "2",
+	"b":         "2",
+	"milestone": "3",
+	"m":         "3",
+	"rc":        "4",
+	"cr":        "4",
+	"snapshot":  "5",
+}
+
+// Maven qualifiers that denote a release.
+var mavenRelease = map[string]bool{
+	"":        true,
+	"ga":      true,
+	"final":   true,
+	"release": true,
+}
+
+// parseMaven parses a Maven version such as 1.2.3-RC1 or 2.0.0.Final.
+func parseMaven(v string) (*Version, bool) {
+	s := strings.ToLower(strings.TrimSpace(v))
+	i := 0
+	for i < len(s) && (s[i] == '.' || '0' <= s[i] && s[i] <= '9') {
+		i++
+	}
+	release, ok := parseRelease(strings.TrimSuffix(s[:i], "."))
+	if !ok {
+		return nil, false
+	}
+	ver := &Version{release: release}
+
+	// The qualifier is a word optionally followed by a number, as in
+	// "alpha-1", "RC2", or "sp1".
+	q := strings.TrimLeft(s[i:], ".-_")
+	j := 0
+	for j < len(q) && !('0' <= q[j] && q[j] <= '9') {
+		j++
+	}
+	word := strings.TrimRight(q[:j], ".-_")
+	n, err := strconv.Atoi(strings.TrimLeft(q[j:], ".-_"))
+	if err != nil && q[j:] != "" {
+		// Not a word and a number: keep the whole qualifier.
+		word, n = q, 0
+	}
+	switch {
+	case mavenRelease[word] && n == 0:
+	case mavenPrerelease[word] != "":
+		ver.pre = []string{mavenPrerelease[word], strconv.Itoa(n)}
+	case word == "sp":
+		ver.post = n + 1
+	default:
+		// Unknown qualifiers follow the release and are compared lexically.
+		ver.post = 1 << 30
+		ver.extra = q
+	}
+	return ver, true
+}
+
+// parseRubyGems parses a RubyGems version such as 7.1.3 or 2.0.0.rc1. Its
+// segments are separated by dots and by changes between digits and letters;
+// the numeric segments up to the first letter are the release and the rest,
+// if any, make it a pre-release. As in Gem::Version, a "-" stands for
+// ".pre.".
+func parseRubyGems(v string) (*Version, bool) {
+	s := strings.ReplaceAll(strings.TrimSpace(v), "-", ".pre.")
+	if s == "" || strings.Trim(s, ".0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return nil, false
+	}
+	var segments []string
+	for _, f := range strings.Split(s, ".") {
+		if f == "" {
+			return nil, false
+		}
+		start := 0
+		for i := 1; i < len(f); i++ {
+			if isDigit(f[i]) != isDigit(f[i-1]) {
+				segments = append(segments, f[start:i])
+				start = i
+			}
+		}
+		segments = append(segments, f[start:])
+	}
+	ver := new(Version)
+	for i, seg := range segments {
+		n, err := strconv.Atoi(seg)
+		if err != nil {
+			if i == 0 {
+				return nil, false
+			}
+			ver.pre = segments[i:]
+			break
+		}
+		ver.release = append(ver.release, n)
+	}
+	return ver, true
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
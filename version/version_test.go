@@ -0,0 +1,89 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package version
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	// Each list is in increasing order.
+	testCases := []struct {
+		system   string
+		versions []string
+	}{
+		{"NPM", []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "1.10.0", "2.0.0"}},
+		{"GO", []string{"v0.0.0-20190101000000-abcdef123456", "v0.1.0", "v1.5.2", "v2.0.0+incompatible", "v2.1.0"}},
+		{"NUGET", []string{"1.0.0", "1.0.0.1", "1.0.1"}},
+		{"PYPI", []string{"1.0.dev1", "1.0a1.dev1", "1.0a1", "1.0b2", "1.0rc1", "1.0", "1.0.post0.dev1", "1.0.post1", "1.0.1", "1.1", "1!0.1"}},
+		{"MAVEN", []string{"1.0-alpha-1", "1.0-beta2", "1.0-RC1", "1.0-SNAPSHOT", "1.0", "1.0-sp1", "1.0.1", "2.0.0.Final"}},
+		{"RUBYGEMS", []string{"1.0.a", "1.0.b1", "1.0.b2", "1.0.rc1", "1.0", "1.0.1", "1.9", "1.10", "2.0.0-beta"}},
+	}
+	for _, c := range testCases {
+		for i := range c.versions {
+			for j := range c.versions {
+				want := cmpInt(i, j)
+				if got := Compare(c.system, c.versions[i], c.versions[j]); got != want {
+					t.Errorf("Compare(%q, %q, %q) = %d; want %d", c.system, c.versions[i], c.versions[j], got, want)
+				}
+			}
+		}
+	}
+
+	// Equivalent spellings.
+	equal := [][3]string{
+		{"PYPI", "1.0", "1.0.0"},
+		{"PYPI", "1.0-RC.1", "1.0rc1"},
+		{"MAVEN", "1.0", "1.0.0.GA"},
+		{"RUBYGEMS", "1.0", "1.0.0"},
+		{"RUBYGEMS", "1.0.rc1", "1.0rc1"},
+		{"NPM", "1.0.0", "v1.0.0"},
+	}
+	for _, e := range equal {
+		if got := Compare(e[0], e[1], e[2]); got != 0 {
+			t.Errorf("Compare(%q, %q, %q) = %d; want 0", e[0], e[1], e[2], got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		system, v           string
+		major, minor, patch int
+		prerelease          bool
+	}{
+		{"npm", "4.17.21", 4, 17, 21, false},
+		{"GO", "v0.0.0-20190101000000-abcdef123456", 0, 0, 0, true},
+		{"PYPI", "2.31", 2, 31, 0, false},
+		{"PYPI", "3.0.0b1", 3, 0, 0, true},
+		{"MAVEN", "5.3.31", 5, 3, 31, false},
+		{"MAVEN", "6.0.0-M1", 6, 0, 0, true},
+		{"RUBYGEMS", "7.1.3.4", 7, 1, 3, false},
+		{"RUBYGEMS", "2.0.0.rc1", 2, 0, 0, true},
+	}
+	for _, c := range testCases {
+		v, err := Parse(c.system, c.v)
+		if err != nil {
+			t.Errorf("Parse(%q, %q) failed: %v", c.system, c.v, err)
+			continue
+		}
+		if v.Major() != c.major || v.Minor() != c.minor || v.Patch() != c.patch || v.Prerelease() != c.prerelease {
+			t.Errorf("Parse(%q, %q) = %d.%d.%d prerelease %t; want %d.%d.%d prerelease %t", c.system, c.v,
+				v.Major(), v.Minor(), v.Patch(), v.Prerelease(), c.major, c.minor, c.patch, c.prerelease)
+		}
+		if v.String() != c.v {
+			t.Errorf("String() = %q; want %q", v.String(), c.v)
+		}
+	}
+
+	for _, v := range []string{"", "1.x", "1.0.0-", "1.2.3.4", "latest"} {
+		if _, err := Parse("NPM", v); err == nil {
+			t.Errorf("Parse(NPM, %q) succeeded", v)
+		}
+	}
+	for _, v := range []string{"", "one", "1.0.dev1.dev2x!"} {
+		if _, err := Parse("PYPI", v); err == nil {
+			t.Errorf("Parse(PYPI, %q) succeeded", v)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+)
+
+// EventKind identifies the kind of change reported by a Watcher.
+type EventKind int
+
+const (
+	// NewVersion reports that a new version of a package was published.
+	NewVersion EventKind = iota
+
+	// NewAdvisory reports that a new security advisory affects the default
+	// version of a package.
+	NewAdvisory
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case NewVersion:
+		return "new version"
+	case NewAdvisory:
+		return "new advisory"
+	}
+	return "unknown event"
+}
+
+// Event is a change observed by a Watcher.
+type Event struct {
+	Kind EventKind
+
+	// The version that was published, or the version affected by the
+	// advisory.
+	VersionKey VersionKey
+
+	// The advisory. Only set for NewAdvisory events.
+	AdvisoryKey AdvisoryKey
+}
+
+// A Watcher polls deps.dev for new versions of a set of packages and for new
+// security advisories affecting their default versions.
+type Watcher struct {
+	// History, if not nil, records a Sample of the adoption of each
+	// package every time it is polled. It is not saved by the Watcher.
+	History *History
+
+	client   *Client
+	packages []PackageKey
+
+	// State recorded by the previous poll, per package. A package is absent
+	// until it has been polled successfully once.
+	versions   map[PackageKey]map[string]bool
+	advisories map[PackageKey]map[string]bool
+}
+
+// NewWatcher returns a Watcher for the given packages that uses c to talk to
+// deps.dev.
+func NewWatcher(c *Client, packages []PackageKey) *Watcher {
+	return &Watcher{
+		client:     c,
+		packages:   packages,
+		versions:   make(map[PackageKey]map[string]bool),
+		advisories: make(map[PackageKey]map[string]bool),
+	}
+}
+
+// Poll fetches the current state of the watched packages and returns the
+// changes observed since the previous call. The first successful poll of a
+// package only records its state and reports no events for it.
+//
+// A failure to poll one package does not prevent the others from being
+// polled; the returned error is a *BatchError with the errors of all
+// failed packages.
+func (w *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	var events []Event
+	var batch Batch
+	for _, k := range w.packages {
+		ev, err := w.poll(ctx, k)
+		if err != nil {
+			batch.Fail(k, err)
+			continue
+		}
+		events = append(events, ev...)
+	}
+	return events, batch.Err()
+}
+
+func (w *Watcher) poll(ctx context.Context, k PackageKey) ([]Event, error) {
+	p, _, err := w.client.GetPackage(ctx, k.System, k.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var def *Version
+	versions := make(map[string]bool)
+	for i, v := range p.Versions {
+		versions[v.VersionKey.Version] = true
+		if v.IsDefault {
+			def = &p.Versions[i]
+		}
+	}
+	var advisoryKeys []AdvisoryKey
+	advisories := make(map[string]bool)
+	if def != nil {
+		dk := def.VersionKey
+		v, _, err := w.client.GetVersion(ctx, dk.System, dk.Name, dk.Version)
+		if err != nil {
+			return nil, err
+		}
+		advisoryKeys = v.AdvisoryKeys
+		for _, a := range advisoryKeys {
+			advisories[a.ID] = true
+		}
+		if w.History != nil {
+			s, err := w.client.sampleVersion(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			w.History.Record(k, *s)
+		}
+	}
+
+	prevVersions, ok := w.versions[k]
+	prevAdvisories := w.advisories[k]
+	w.versions[k] = versions
+	w.advisories[k] = advisories
+	if !ok {
+		return nil, nil
+	}
+
+	var events []Event
+	for _, v := range p.Versions {
+		if !prevVersions[v.VersionKey.Version] {
+			events = append(events, Event{Kind: NewVersion, VersionKey: v.VersionKey})
+		}
+	}
+	for _, a := range advisoryKeys {
+		if !prevAdvisories[a.ID] {
+			events = append(events, Event{Kind: NewAdvisory, VersionKey: def.VersionKey, AdvisoryKey: a})
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	client, mux := setup(t)
+
+	// The package gains a version and an advisory after the first poll.
+	poll := 0
+	mux.HandleFunc("/systems/npm/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		if poll == 0 {
+			fmt.Fprint(w, `{"packageKey":{"system":"NPM","name":"foo"},"versions":[{"versionKey":{"system":"NPM","name":"foo","version":"1.0.0"},"isDefault":true}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"packageKey":{"system":"NPM","name":"foo"},"versions":[{"versionKey":{"system":"NPM","name":"foo","version":"1.0.0"},"isDefault":true},{"versionKey":{"system":"NPM","name":"foo","version":"1.1.0-beta"}}]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/foo/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		if poll == 0 {
+			fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"foo","version":"1.0.0"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"foo","version":"1.0.0"},"advisoryKeys":[{"id":"GHSA-xxxx"}]}`)
+	})
+
+	w := NewWatcher(client, []PackageKey{{System: "npm", Name: "foo"}})
+	events, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("first Poll returned %+v; want no events", events)
+	}
+
+	poll++
+	events, err = w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	want := []Event{
+		{Kind: NewVersion, VersionKey: VersionKey{System: "NPM", Name: "foo", Version: "1.1.0-beta"}},
+		{Kind: NewAdvisory, VersionKey: VersionKey{System: "NPM", Name: "foo", Version: "1.0.0"}, AdvisoryKey: AdvisoryKey{ID: "GHSA-xxxx"}},
+	}
+	if !cmp.Equal(events, want) {
+		t.Errorf("Poll returned %+v; want %+v", events, want)
+	}
+
+	events, err = w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("third Poll returned %+v; want no events", events)
+	}
+}
+
+func TestWatcherPollError(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/npm/packages/foo", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "package not found", http.StatusNotFound)
+	})
+
+	w := NewWatcher(client, []PackageKey{{System: "npm", Name: "foo"}})
+	if _, err := w.Poll(context.Background()); err == nil {
+		t.Errorf("Poll expected error")
+	}
+}
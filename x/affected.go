@@ -0,0 +1,49 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doAffected prints, for each version of the package name, whether it is
+// affected by the advisory with the given ID, and the first version that
+// fixed it.
+func doAffected(ctx context.Context, c *insights.Client, system, name, id string) error {
+	adv, _, err := c.GetAdvisory(ctx, id)
+	if err != nil {
+		return err
+	}
+	pkg, _, err := c.GetPackage(ctx, system, name)
+	if err != nil {
+		return err
+	}
+	statuses, err := analysis.AffectedVersions(ctx, &analysis.OSVClient{HTTPClient: httpClient, Retry: retryPolicy}, adv, pkg)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"version", "status"}
+	var rows [][]string
+	for _, s := range statuses {
+		rows = append(rows, []string{s.Version, string(s.Status)})
+	}
+	return printList(statuses, header, rows, func() {
+		for _, s := range statuses {
+			color := green
+			if s.Status == analysis.Affected {
+				color = red
+			}
+			fmt.Printf("%s %s\n", s.Version, colorize(color, string(s.Status)))
+		}
+		if v := analysis.FirstFixed(system, statuses, ""); v != "" {
+			fmt.Printf("first fixed in %s\n", v)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/criticality"
+)
+
+// doAudit scans the project at path and prints the known vulnerabilities of
+// its dependencies, and those that are deprecated. If checkRegistries is
+// set, the registries are asked about deprecated and yanked versions too.
+// If scoresFile is not empty, the criticality scores in it are shown and
+// the most critical vulnerable packages are listed first. It reports
+// whether any vulnerabilities were found.
+func doAudit(ctx context.Context, c *insights.Client, path string, checkRegistries bool, scoresFile string) (bool, error) {
+	deps, err := scanPath(path)
+	if err != nil {
+		return false, err
+	}
+	opts := &audit.Options{Concurrency: concurrency}
+	if checkRegistries {
+		opts.Registry = newRegistryClient()
+	}
+	if scoresFile != "" {
+		if opts.Criticality, err = criticality.LoadWithClient(ctx, httpClient, scoresFile); err != nil {
+			return false, err
+		}
+	}
+	r, err := audit.Run(ctx, c, deps, opts)
+	if err != nil && !partial(err) {
+		return false, err
+	}
+
+	vulnerable := r.Vulnerable()
+	deprecated := r.Deprecated()
+	// Findings in the packages most critical to the ecosystem first.
+	slices.SortStableFunc(vulnerable, func(a, b audit.Package) int {
+		return cmp.Compare(b.Criticality, a.Criticality)
+	})
+
+	header := []string{"system", "name", "version", "direct", "licenses", "advisory", "severity", "cvss3", "title", "criticality"}
+	var rows [][]string
+	for _, p := range vulnerable {
+		k := p.Dependency.VersionKey
+		for _, a := range p.Advisories {
+			rating, _ := severity(a.CVSS3Score)
+			rows = append(rows, []string{k.System, k.Name, k.Version, strconv.FormatBool(p.Dependency.Direct),
+				strings.Join(p.Licenses, " "), a.AdvisoryKey.ID, rating, fmt.Sprintf("%.1f", a.CVSS3Score), a.Title, criticalityString(p)})
+		}
+	}
+	if outputFormat == "github" {
+		return len(vulnerable) > 0, cmp.Or(printAuditGitHub(path, r, vulnerable, deprecated), err)
+	}
+	perr := printList(r, header, rows, func() {
+		for _, p := range vulnerable {
+			k := p.Dependency.VersionKey
+			direct := "indirect"
+			if p.Dependency.Direct {
+				direct = "direct"
+			}
+			if c := criticalityString(p); c != "" {
+				direct += ", criticality " + c
+			}
+			fmt.Printf("%s %s@%s (%s)\n", k.System, k.Name, k.Version, direct)
+			for _, a := range p.Advisories {
+				rating, color := severity(a.CVSS3Score)
+				fmt.Printf("  %s %s %s\n", a.AdvisoryKey.ID, colorize(color, fmt.Sprintf("[%s %.1f]", rating, a.CVSS3Score)), a.Title)
+			}
+		}
+		if len(deprecated) > 0 {
+			if len(vulnerable) > 0 {
+				fmt.Println()
+			}
+			fmt.Println(colorize(yellow, "Deprecated:"))
+			for _, p := range deprecated {
+				k := p.Dependency.VersionKey
+				fmt.Printf("  %s %s@%s %s\n", k.System, k.Name, k.Version, deprecation(p))
+			}
+		}
+		if len(vulnerable) == 0 {
+			fmt.Printf("%s: no known vulnerabilities in %d dependencies\n", path, len(r.Packages))
+			return
+		}
+		sum := r.Summary()
+		fmt.Printf("\n%s\n", colorize(red, fmt.Sprintf("%d advisories affect %d of %d dependencies", sum.Findings, sum.Vulnerable, len(r.Packages))))
+		fmt.Printf("%d critical, %d high, %d medium, %d low, %d unrated; %d in direct dependencies\n",
+			sum.Critical, sum.High, sum.Medium, sum.Low, sum.Unknown, sum.Direct)
+	})
+	return len(vulnerable) > 0, cmp.Or(perr, err)
+}
+
+// criticalityString formats the criticality score of p, if known.
+func criticalityString(p audit.Package) string {
+	if p.Criticality == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", p.Criticality)
+}
+
+// deprecation describes why p is listed as deprecated.
+func deprecation(p audit.Package) string {
+	what := "(deprecated)"
+	if p.Yanked {
+		what = "(yanked)"
+	}
+	if p.DeprecatedReason != "" {
+		what += " " + p.DeprecatedReason
+	}
+	return what
+}
+
+// printAuditGitHub prints the vulnerabilities and deprecated dependencies as
+// GitHub workflow annotations on the files declaring them and writes a
+// summary of the audit.
+func printAuditGitHub(path string, r *audit.Result, vulnerable, deprecated []audit.Package) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Dependency audit of %s\n\n", path)
+	for _, p := range deprecated {
+		k := p.Dependency.VersionKey
+		fmt.Println(annotation{
+			level: "warning",
+			file:  p.Dependency.File,
+			title: fmt.Sprintf("%s@%s is deprecated", k.Name, k.Version),
+			msg:   deprecation(p),
+		})
+	}
+	if len(deprecated) > 0 {
+		fmt.Fprintf(&sb, "%d dependencies are deprecated or yanked.\n\n", len(deprecated))
+	}
+	if len(vulnerable) == 0 {
+		fmt.Fprintf(&sb, "No known vulnerabilities in %d dependencies.\n", len(r.Packages))
+		return writeStepSummary(sb.String())
+	}
+	fmt.Fprintf(&sb, "%d of %d dependencies are affected by known vulnerabilities.\n\n", len(vulnerable), len(r.Packages))
+	sb.WriteString("| Package | Version | Advisory | Severity | Title |\n| --- | --- | --- | --- | --- |\n")
+	for _, p := range vulnerable {
+		k := p.Dependency.VersionKey
+		for _, a := range p.Advisories {
+			rating, _ := severity(a.CVSS3Score)
+			fmt.Println(annotation{
+				level: annotationLevel(a.CVSS3Score),
+				file:  p.Dependency.File,
+				title: fmt.Sprintf("%s %s@%s", a.AdvisoryKey.ID, k.Name, k.Version),
+				msg:   fmt.Sprintf("%s (%s %.1f)", a.Title, rating, a.CVSS3Score),
+			})
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s %.1f | %s |\n", markdownCell(k.Name), markdownCell(k.Version), a.AdvisoryKey.ID, rating, a.CVSS3Score, markdownCell(a.Title))
+		}
+	}
+	return writeStepSummary(sb.String())
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/scan"
+)
+
+// Badge colors.
+const (
+	badgeGreen  = "#4c1"
+	badgeYellow = "#dfb317"
+	badgeRed    = "#e05d44"
+	badgeGrey   = "#9f9f9f"
+)
+
+// badge is a status badge in the style of shields.io.
+type badge struct {
+	Label, Message, Color string
+}
+
+// textWidth approximates the width in pixels of s in 11px Verdana.
+func textWidth(s string) int {
+	return len(s)*7 + 10
+}
+
+// writeSVG writes b as an SVG image to w.
+func (b *badge) writeSVG(w io.Writer) error {
+	lw, mw := textWidth(b.Label), textWidth(b.Message)
+	label, msg := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<title>%[4]s: %[5]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[7]d" y="14">%[4]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text><text x="%[8]d" y="14">%[5]s</text>
+</g>
+</svg>
+`, lw+mw, lw, mw, label, msg, b.Color, lw/2, lw+mw/2)
+	return err
+}
+
+// vulnerabilitiesBadge returns a badge with the number of vulnerable
+// packages among pkgs. If some could not be audited, the number is only a
+// lower bound, and none found is shown as unknown.
+func vulnerabilitiesBadge(pkgs []audit.Package) *badge {
+	n, unaudited := 0, 0
+	for _, p := range pkgs {
+		switch {
+		case p.Error != "":
+			unaudited++
+		case len(p.Advisories) > 0:
+			n++
+		}
+	}
+	switch {
+	case n > 0 && unaudited > 0:
+		return &badge{"vulnerabilities", fmt.Sprintf("%d+", n), badgeRed}
+	case n > 0:
+		return &badge{"vulnerabilities", fmt.Sprint(n), badgeRed}
+	case unaudited > 0:
+		return &badge{"vulnerabilities", "unknown", badgeGrey}
+	}
+	return &badge{"vulnerabilities", "none", badgeGreen}
+}
+
+// scorecardBadge returns a badge with the mean OpenSSF Scorecard score of
+// the source repositories of pkgs.
+func scorecardBadge(pkgs []audit.Package) *badge {
+	var sum float64
+	n := 0
+	for _, p := range pkgs {
+		if p.Scorecard != nil {
+			sum += p.Scorecard.OverallScore
+			n++
+		}
+	}
+	if n == 0 {
+		return &badge{"scorecard", "unknown", badgeGrey}
+	}
+	score := sum / float64(n)
+	color := badgeRed
+	switch {
+	case score >= 7:
+		color = badgeGreen
+	case score >= 4:
+		color = badgeYellow
+	}
+	return &badge{"scorecard", fmt.Sprintf("%.1f/10", score), color}
+}
+
+// freshnessBadge returns a badge with the share of deps that are at their
+// latest release.
+func freshnessBadge(ctx context.Context, c *insights.Client, deps []scan.Dependency) (*badge, error) {
+	current := 0
+	for _, d := range deps {
+		k := d.VersionKey
+		p, _, err := c.GetPackage(ctx, k.System, k.Name)
+		if err != nil {
+			return nil, err
+		}
+		o, err := updates(k, p)
+		if err != nil {
+			return nil, err
+		}
+		if o.Patch == "" && o.Minor == "" && o.Major == "" {
+			current++
+		}
+	}
+	if len(deps) == 1 {
+		if current == 1 {
+			return &badge{"freshness", "up to date", badgeGreen}, nil
+		}
+		return &badge{"freshness", "outdated", badgeYellow}, nil
+	}
+	pct := 100
+	if len(deps) > 0 {
+		pct = current * 100 / len(deps)
+	}
+	color := badgeRed
+	switch {
+	case pct == 100:
+		color = badgeGreen
+	case pct >= 75:
+		color = badgeYellow
+	}
+	return &badge{"freshness", fmt.Sprintf("%d%% up to date", pct), color}, nil
+}
+
+// doBadge writes to out, or to standard output if out is empty, an SVG
+// badge showing metric for the project at path or, if args names a system
+// and a package, for the default or given version of that package. For a
+// project, the freshness metric only considers its direct dependencies.
+func doBadge(ctx context.Context, c *insights.Client, metric, out string, args []string) error {
+	var deps []scan.Dependency
+	switch len(args) {
+	case 0, 1:
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+		var err error
+		if deps, err = scanPath(path); err != nil {
+			return err
+		}
+	default:
+		system, name := args[0], args[1]
+		var v string
+		if len(args) > 2 {
+			v = args[2]
+		} else {
+			var err error
+			if v, err = defaultVersion(ctx, c, system, name); err != nil {
+				return err
+			}
+		}
+		deps = []scan.Dependency{{VersionKey: insights.VersionKey{System: system, Name: name, Version: v}, Direct: true}}
+	}
+
+	var b *badge
+	switch metric {
+	case "vulnerabilities", "scorecard":
+		r, err := audit.Run(ctx, c, deps, &audit.Options{Concurrency: concurrency})
+		if err != nil && !partial(err) {
+			return err
+		}
+		// The badge tells that packages could not be audited; the errors
+		// are only logged, so that it is still written.
+		if err != nil {
+			log.Print(err)
+		}
+		if metric == "vulnerabilities" {
+			b = vulnerabilitiesBadge(r.Packages)
+		} else {
+			b = scorecardBadge(r.Packages)
+		}
+	case "freshness":
+		var direct []scan.Dependency
+		for _, d := range deps {
+			if d.Direct {
+				direct = append(direct, d)
+			}
+		}
+		var err error
+		if b, err = freshnessBadge(ctx, c, direct); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown badge metric %q", metric)
+	}
+
+	if out == "" {
+		return b.writeSVG(os.Stdout)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := b.writeSVG(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVulnerabilitiesBadge(t *testing.T) {
+	clean := audit.Package{Licenses: []string{"MIT"}}
+	vulnerable := audit.Package{Advisories: []*insights.Advisory{{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-1"}}}}
+	failed := audit.Package{Error: "500 internal error\n"}
+	tests := []struct {
+		name string
+		pkgs []audit.Package
+		want *badge
+	}{
+		{"none", []audit.Package{clean, clean}, &badge{"vulnerabilities", "none", badgeGreen}},
+		{"vulnerable", []audit.Package{clean, vulnerable, vulnerable}, &badge{"vulnerabilities", "2", badgeRed}},
+		{"unaudited", []audit.Package{clean, failed}, &badge{"vulnerabilities", "unknown", badgeGrey}},
+		{"all unaudited", []audit.Package{failed}, &badge{"vulnerabilities", "unknown", badgeGrey}},
+		{"vulnerable and unaudited", []audit.Package{vulnerable, failed}, &badge{"vulnerabilities", "1+", badgeRed}},
+	}
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.want, vulnerabilitiesBadge(tt.pkgs)); diff != "" {
+			t.Errorf("%s: vulnerabilitiesBadge mismatch (-want +got):\n%s", tt.name, diff)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/policy"
+	"github.com/franoliveto/insights/scan"
+)
+
+// errUnaudited is the error of check when dependencies could not be
+// audited.
+var errUnaudited = errors.New("dependencies could not be audited")
+
+// defaultPolicyFile is the policy file check reads when none is given on
+// the command line or in the configuration file.
+const defaultPolicyFile = "policy.yaml"
+
+// violation is a policy violation as printed by check.
+type violation struct {
+	System  string `json:"system"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+
+	// How the dependency is reached: the chain of package versions from the
+	// checked package, or, for a project, its manifest and whether the
+	// dependency is direct.
+	Path string `json:"path"`
+
+	file string
+}
+
+// doCheck checks against the policy in policyFile the dependencies of the
+// project at path or, if args names a system and a package, of the default
+// or given version of that package. It reports whether the policy was
+// violated. If any dependency could not be audited, the error wraps
+// errUnaudited.
+func doCheck(ctx context.Context, c *insights.Client, policyFile string, args []string) (violated bool, err error) {
+	p, err := policy.Load(policyFile)
+	if err != nil {
+		return false, err
+	}
+
+	deps, graph, what, err := dependenciesOf(ctx, c, args)
+	if err != nil {
+		return false, err
+	}
+
+	// The lookups that failed are reported below as unaudited dependencies
+	// or, for advisories, as violations for lack of a score.
+	r, err := audit.Run(ctx, c, deps, &audit.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return false, err
+	}
+	// The policy cannot be checked for dependencies that could not be
+	// audited, so they fail the check rather than pass it.
+	var unaudited []string
+	for _, pkg := range r.Packages {
+		if pkg.Error != "" {
+			k := pkg.Dependency.VersionKey
+			unaudited = append(unaudited, fmt.Sprintf("%s@%s: %s", k.Name, k.Version, strings.TrimSpace(pkg.Error)))
+		}
+	}
+	if len(unaudited) > 0 {
+		defer func() {
+			if err == nil {
+				err = fmt.Errorf("%w: %d of %d, so the policy was not checked for them:\n  %s",
+					errUnaudited, len(unaudited), len(deps), strings.Join(unaudited, "\n  "))
+			}
+		}()
+	}
+
+	var out []violation
+	for _, v := range p.Check(r) {
+		d := v.Dependency
+		k := d.VersionKey
+		out = append(out, violation{
+			System: k.System, Name: k.Name, Version: k.Version,
+			Rule: v.Rule, Message: v.Message,
+			Path: dependencyPath(graph, d),
+			file: d.File,
+		})
+	}
+
+	if outputFormat == "github" {
+		return len(out) > 0, printCheckGitHub(what, len(deps), len(unaudited), out)
+	}
+	header := []string{"system", "name", "version", "rule", "message", "path"}
+	var rows [][]string
+	for _, v := range out {
+		rows = append(rows, []string{v.System, v.Name, v.Version, v.Rule, v.Message, v.Path})
+	}
+	err = printList(out, header, rows, func() {
+		for _, v := range out {
+			fmt.Printf("%s@%s: %s: %s\n", v.Name, v.Version, v.Rule, v.Message)
+			fmt.Printf("  %s\n", v.Path)
+		}
+		if len(out) == 0 {
+			if len(unaudited) == 0 {
+				fmt.Printf("%s: %d dependencies comply with %s\n", what, len(deps), policyFile)
+			}
+			return
+		}
+		fmt.Printf("\n%s\n", colorize(red, fmt.Sprintf("%d policy violations", len(out))))
+	})
+	return len(out) > 0, err
+}
+
+// dependencyPath describes how d is reached: by its shortest chain in
+// graph, if the dependencies come from a graph, and otherwise by the file
+// declaring it.
+func dependencyPath(graph *insights.Dependencies, d scan.Dependency) string {
+	k := d.VersionKey
+	if graph != nil {
+		for _, chain := range graph.Why(k.Name) {
+			if chain[len(chain)-1].VersionKey != k {
+				continue
+			}
+			var names []string
+			for _, n := range chain {
+				names = append(names, n.VersionKey.Name+"@"+n.VersionKey.Version)
+			}
+			return strings.Join(names, " > ")
+		}
+		return k.Name + "@" + k.Version
+	}
+	via := "indirect"
+	if d.Direct {
+		via = "direct"
+	}
+	return fmt.Sprintf("%s (%s)", d.File, via)
+}
+
+// printCheckGitHub prints the violations as GitHub workflow annotations on
+// the files declaring the dependencies and writes a summary of the check of
+// n dependencies, of which unaudited could not be audited.
+func printCheckGitHub(what string, n, unaudited int, out []violation) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Policy check of %s\n\n", what)
+	if unaudited > 0 {
+		fmt.Fprintf(&sb, "%d of %d dependencies could not be audited; the policy was not checked for them.\n\n", unaudited, n)
+	}
+	if len(out) == 0 {
+		if unaudited == 0 {
+			fmt.Fprintf(&sb, "All %d dependencies comply with the policy.\n", n)
+		}
+		return writeStepSummary(sb.String())
+	}
+	fmt.Fprintf(&sb, "%d policy violations.\n\n", len(out))
+	sb.WriteString("| Package | Version | Rule | Violation | Path |\n| --- | --- | --- | --- | --- |\n")
+	for _, v := range out {
+		fmt.Println(annotation{
+			level: "error",
+			file:  v.file,
+			title: fmt.Sprintf("%s %s@%s", v.Rule, v.Name, v.Version),
+			msg:   v.Message,
+		})
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", markdownCell(v.Name), markdownCell(v.Version), v.Rule, markdownCell(v.Message), markdownCell(v.Path))
+	}
+	return writeStepSummary(sb.String())
+}
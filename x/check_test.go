@@ -0,0 +1,47 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckUnaudited(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/NPM/packages/a/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"},"licenses":["MIT"]}`)
+	})
+	mux.HandleFunc("/systems/NPM/packages/b/versions/2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	})
+
+	dir := t.TempDir()
+	lock := `{"lockfileVersion": 3, "packages": {"": {"dependencies": {"a": "^1.0.0"}}, "node_modules/a": {"version": "1.0.0"}, "node_modules/b": {"version": "2.0.0"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(lock), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policyFile := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(policyFile, []byte("allow_licenses: [MIT]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	violated, err := doCheck(context.Background(), client, policyFile, []string{dir})
+	if violated {
+		t.Error("doCheck reported a violation")
+	}
+	if !errors.Is(err, errUnaudited) || !strings.Contains(err.Error(), "b@2.0.0") {
+		t.Fatalf("doCheck returned error %v; want one naming b@2.0.0 and wrapping errUnaudited", err)
+	}
+	if got := exitCode(err); got != exitAPI {
+		t.Errorf("exitCode = %d; want %d", got, exitAPI)
+	}
+}
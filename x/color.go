@@ -0,0 +1,56 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+
+	"github.com/franoliveto/insights/audit"
+)
+
+// useColor reports whether human-readable output is colorized. Color is
+// disabled when standard output is not a terminal, when the NO_COLOR
+// environment variable is set (https://no-color.org), and for dumb terminals.
+var useColor = func() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}()
+
+// ANSI color escape sequences.
+const (
+	red     = "\x1b[31m"
+	green   = "\x1b[32m"
+	yellow  = "\x1b[33m"
+	magenta = "\x1b[35m"
+	bold    = "\x1b[1m"
+	reset   = "\x1b[0m"
+)
+
+// colorize returns s in the given color if color is enabled.
+func colorize(color, s string) string {
+	if !useColor || color == "" {
+		return s
+	}
+	return color + s + reset
+}
+
+// severity returns the qualitative severity rating of a CVSS v3 score and
+// the color used to display it.
+func severity(score float32) (rating, color string) {
+	switch rating = audit.Severity(score); rating {
+	case "CRITICAL":
+		return rating, bold + magenta
+	case "HIGH":
+		return rating, red
+	case "MEDIUM":
+		return rating, yellow
+	case "LOW":
+		return rating, green
+	}
+	return rating, ""
+}
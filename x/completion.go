@@ -0,0 +1,143 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// completionData is the data rendered by the completion scripts.
+type completionData struct {
+	Commands []completionCommand
+
+	// The names of all the subcommands, and of those whose first argument
+	// is a package management system.
+	Names      []string
+	WithSystem []string
+	Systems    []string
+}
+
+type completionCommand struct {
+	Name    string
+	Summary string
+	Flags   []string
+}
+
+var completionFuncs = map[string]any{
+	"join": strings.Join,
+	"dashed": func(flags []string) string {
+		var s []string
+		for _, f := range flags {
+			s = append(s, "-"+f)
+		}
+		return strings.Join(s, " ")
+	},
+}
+
+// The scripts complete subcommands, their flags, and, for the subcommands
+// taking one, the package management system as first argument.
+var completionScripts = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Funcs(completionFuncs).Parse(`# bash completion for x
+_x() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	if [ "$COMP_CWORD" -eq 1 ]; then
+		COMPREPLY=( $(compgen -W "{{join .Names " "}}" -- "$cur") )
+		return
+	fi
+	local flags=""
+	case "${COMP_WORDS[1]}" in
+{{- range .Commands}}{{if .Flags}}
+	{{.Name}}) flags="{{dashed .Flags}}" ;;
+{{- end}}{{end}}
+	esac
+	if [[ "$cur" == -* ]]; then
+		COMPREPLY=( $(compgen -W "$flags" -- "$cur") )
+		return
+	fi
+	case "${COMP_WORDS[1]}" in
+	{{join .WithSystem "|"}})
+		# Complete the system if no other argument was given yet.
+		local i
+		for (( i=2; i < COMP_CWORD; i++ )); do
+			[[ "${COMP_WORDS[i]}" != -* ]] && return
+		done
+		COMPREPLY=( $(compgen -W "{{join .Systems " "}}" -- "$cur") )
+		;;
+	*)
+		COMPREPLY=( $(compgen -f -- "$cur") )
+		;;
+	esac
+}
+complete -F _x x
+`)),
+	"zsh": template.Must(template.New("zsh").Funcs(completionFuncs).Parse(`#compdef x
+# zsh completion for x
+_x() {
+	local -a commands
+	commands=(
+{{- range .Commands}}
+		'{{.Name}}:{{.Summary}}'
+{{- end}}
+	)
+	if (( CURRENT == 2 )); then
+		_describe 'command' commands
+		return
+	fi
+	local -a flags
+	case $words[2] in
+{{- range .Commands}}{{if .Flags}}
+	{{.Name}}) flags=({{dashed .Flags}}) ;;
+{{- end}}{{end}}
+	esac
+	if [[ $PREFIX == -* ]]; then
+		compadd -a flags
+		return
+	fi
+	case $words[2] in
+	{{join .WithSystem "|"}})
+		local i
+		for (( i=3; i < CURRENT; i++ )); do
+			[[ $words[i] != -* ]] && return
+		done
+		compadd {{join .Systems " "}}
+		;;
+	*)
+		_files
+		;;
+	esac
+}
+compdef _x x
+`)),
+	"fish": template.Must(template.New("fish").Funcs(completionFuncs).Parse(`# fish completion for x
+complete -c x -f
+{{- range .Commands}}
+complete -c x -n __fish_use_subcommand -a {{.Name}} -d '{{.Summary}}'
+{{- $name := .Name}}{{range .Flags}}
+complete -c x -n '__fish_seen_subcommand_from {{$name}}' -o {{.}}
+{{- end}}{{end}}
+complete -c x -n '__fish_seen_subcommand_from {{join .WithSystem " "}}' -a '{{join .Systems " "}}'
+complete -c x -n '__fish_seen_subcommand_from identify report' -F
+`)),
+}
+
+// doCompletion writes the completion script for the named shell to w.
+func doCompletion(w io.Writer, shell string) error {
+	t, ok := completionScripts[shell]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+	data := completionData{Systems: systems}
+	for _, c := range commands {
+		data.Commands = append(data.Commands, completionCommand{c.name, c.summary, c.flags})
+		data.Names = append(data.Names, c.name)
+		if c.system {
+			data.WithSystem = append(data.WithSystem, c.name)
+		}
+	}
+	return t.Execute(w, data)
+}
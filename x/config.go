@@ -0,0 +1,94 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config holds the defaults read from the configuration file. Command line
+// flags take precedence over them.
+//
+//	base_url: https://api.deps.dev/v3/
+//	format: json
+//	cache_dir: /var/cache/insight
+//	max_age: 12h
+//	concurrency: 8
+//	system: npm
+//	policy: .github/policy.yaml
+//	tls_cert: /etc/insight/client.pem
+//	tls_key: /etc/insight/client.key
+//	tls_ca: /etc/ssl/corporate-ca.pem
+//	registry_fallback: true
+type config struct {
+	// The base URL of the deps.dev API.
+	BaseURL string `yaml:"base_url"`
+
+	// The default output format.
+	Format string `yaml:"format"`
+
+	// The directory in which API responses are cached.
+	CacheDir string `yaml:"cache_dir"`
+
+	// The maximum age of cached API responses, as a time.Duration string.
+	MaxAge string `yaml:"max_age"`
+
+	// The maximum number of concurrent API requests.
+	Concurrency int `yaml:"concurrency"`
+
+	// The package management system assumed when a command's system
+	// argument is omitted.
+	System string `yaml:"system"`
+
+	// The policy file read by the check command.
+	Policy string `yaml:"policy"`
+
+	// PEM files of the client certificate and its key, presented to
+	// servers requiring mutual TLS, and of further certificate authorities
+	// to trust.
+	TLSCert string `yaml:"tls_cert"`
+	TLSKey  string `yaml:"tls_key"`
+	TLSCA   string `yaml:"tls_ca"`
+
+	// Whether to ask the package registries for what deps.dev lacks.
+	RegistryFallback bool `yaml:"registry_fallback"`
+}
+
+// defaultConfigFile returns the path of the configuration file used when
+// none is given: $XDG_CONFIG_HOME/insight/config.yaml or the equivalent for
+// the operating system.
+func defaultConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "insight", "config.yaml")
+}
+
+// loadConfig reads the configuration file. A missing file is not an error
+// unless it was given explicitly.
+func loadConfig(file string, explicit bool) (*config, error) {
+	cfg := new(config)
+	if file == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return cfg, nil
+}
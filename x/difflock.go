@@ -0,0 +1,261 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/scan"
+)
+
+// lockChange is a change of the resolved version of a package between two
+// lockfiles. Old is empty for an added package and New for a removed one.
+type lockChange struct {
+	System string `json:"system"`
+	Name   string `json:"name"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+	Direct bool   `json:"direct"`
+
+	// The licenses of the old and new versions, if they differ.
+	OldLicenses []string `json:"oldLicenses,omitempty"`
+	NewLicenses []string `json:"newLicenses,omitempty"`
+
+	// The advisories affecting the new version but not the old one, and
+	// those affecting the old version but no longer the new one.
+	NewAdvisories   []*insights.Advisory `json:"newAdvisories,omitempty"`
+	FixedAdvisories []string             `json:"fixedAdvisories,omitempty"`
+
+	// Describes why information about a version could not be obtained.
+	Error string `json:"error,omitempty"`
+}
+
+// readLockfile returns the dependencies declared in file, parsed as the kind
+// of lockfile named kind.
+func readLockfile(file, kind string) ([]scan.Dependency, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := scan.Parse(kind, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return deps, nil
+}
+
+// diffLockfiles returns the changes between the dependencies of an old and a
+// new lockfile, sorted by package. When a package is resolved to exactly one
+// version in each, a change of version is a single change; otherwise every
+// version added or removed is a change of its own.
+func diffLockfiles(oldDeps, newDeps []scan.Dependency) []*lockChange {
+	type pkg struct{ system, name string }
+	versions := func(deps []scan.Dependency) map[pkg]map[string]bool {
+		m := make(map[pkg]map[string]bool)
+		for _, d := range deps {
+			p := pkg{d.VersionKey.System, d.VersionKey.Name}
+			if m[p] == nil {
+				m[p] = make(map[string]bool)
+			}
+			m[p][d.VersionKey.Version] = m[p][d.VersionKey.Version] || d.Direct
+		}
+		return m
+	}
+	oldVersions, newVersions := versions(oldDeps), versions(newDeps)
+
+	var changes []*lockChange
+	for p, nv := range newVersions {
+		ov := oldVersions[p]
+		var added, removed []string
+		for v := range nv {
+			if _, ok := ov[v]; !ok {
+				added = append(added, v)
+			}
+		}
+		for v := range ov {
+			if _, ok := nv[v]; !ok {
+				removed = append(removed, v)
+			}
+		}
+		if len(added) == 1 && len(removed) == 1 {
+			changes = append(changes, &lockChange{System: p.system, Name: p.name, Old: removed[0], New: added[0], Direct: nv[added[0]]})
+			continue
+		}
+		for _, v := range added {
+			changes = append(changes, &lockChange{System: p.system, Name: p.name, New: v, Direct: nv[v]})
+		}
+		for _, v := range removed {
+			changes = append(changes, &lockChange{System: p.system, Name: p.name, Old: v, Direct: ov[v]})
+		}
+	}
+	for p, ov := range oldVersions {
+		if _, ok := newVersions[p]; ok {
+			continue
+		}
+		for v, direct := range ov {
+			changes = append(changes, &lockChange{System: p.system, Name: p.name, Old: v, Direct: direct})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.System != b.System {
+			return a.System < b.System
+		}
+		if a.Old != b.Old {
+			return a.Old < b.Old
+		}
+		return a.New < b.New
+	})
+	return changes
+}
+
+// annotate audits the versions involved in changes and records the license
+// and advisory differences between them.
+func annotate(ctx context.Context, c *insights.Client, changes []*lockChange) error {
+	var deps []scan.Dependency
+	for _, ch := range changes {
+		for _, v := range []string{ch.Old, ch.New} {
+			if v != "" {
+				deps = append(deps, scan.Dependency{VersionKey: insights.VersionKey{System: ch.System, Name: ch.Name, Version: v}})
+			}
+		}
+	}
+	r, err := audit.Run(ctx, c, deps, &audit.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+	pkgs := make(map[insights.VersionKey]audit.Package)
+	for _, p := range r.Packages {
+		pkgs[p.Dependency.VersionKey] = p
+	}
+
+	for _, ch := range changes {
+		if ch.New == "" {
+			continue
+		}
+		np := pkgs[insights.VersionKey{System: ch.System, Name: ch.Name, Version: ch.New}]
+		var op audit.Package
+		if ch.Old != "" {
+			op = pkgs[insights.VersionKey{System: ch.System, Name: ch.Name, Version: ch.Old}]
+		}
+		var errs []string
+		for _, e := range []string{np.Error, op.Error} {
+			if e != "" {
+				errs = append(errs, strings.TrimSpace(e))
+			}
+		}
+		ch.Error = strings.Join(errs, "; ")
+		if ch.Old != "" && !slices.Equal(op.Licenses, np.Licenses) {
+			ch.OldLicenses, ch.NewLicenses = op.Licenses, np.Licenses
+		}
+		ch.NewAdvisories, ch.FixedAdvisories = splitAdvisories(op.Advisories, np.Advisories)
+	}
+	return err
+}
+
+// splitAdvisories returns the advisories of the new version of a package
+// that do not affect the old one, and the IDs of those of the old version
+// that no longer affect the new one, in the order given.
+func splitAdvisories(oldAdvs, newAdvs []*insights.Advisory) (added []*insights.Advisory, fixed []string) {
+	isFixed := make(map[string]bool)
+	for _, a := range oldAdvs {
+		isFixed[a.AdvisoryKey.ID] = true
+	}
+	for _, a := range newAdvs {
+		if !isFixed[a.AdvisoryKey.ID] {
+			added = append(added, a)
+		}
+		delete(isFixed, a.AdvisoryKey.ID)
+	}
+	for _, a := range oldAdvs {
+		if isFixed[a.AdvisoryKey.ID] {
+			fixed = append(fixed, a.AdvisoryKey.ID)
+		}
+	}
+	return added, fixed
+}
+
+// doDiffLockfile prints the changes between two revisions of a lockfile,
+// annotated with the advisories and licenses they bring in. If the name of
+// the old file is not that of a supported lockfile, such as when it was
+// extracted from version control, it is parsed as the same kind as the new
+// one. It reports whether the changes introduce any advisories.
+func doDiffLockfile(ctx context.Context, c *insights.Client, oldFile, newFile string) (vulnerable bool, err error) {
+	kind := oldFile
+	if !scan.Supported(oldFile) {
+		kind = newFile
+	}
+	oldDeps, err := readLockfile(oldFile, kind)
+	if err != nil {
+		return false, err
+	}
+	newDeps, err := readLockfile(newFile, newFile)
+	if err != nil {
+		return false, err
+	}
+	changes := diffLockfiles(oldDeps, newDeps)
+	err = annotate(ctx, c, changes)
+	if err != nil && !partial(err) {
+		return false, err
+	}
+	for _, ch := range changes {
+		if len(ch.NewAdvisories) > 0 {
+			vulnerable = true
+		}
+	}
+
+	perr := printResult(changes, func() {
+		if len(changes) == 0 {
+			fmt.Println("no changes")
+			return
+		}
+		for _, ch := range changes {
+			direct := ""
+			if ch.Direct {
+				direct = " (direct)"
+			}
+			switch {
+			case ch.Old == "":
+				fmt.Printf("%s %s %s %s%s\n", colorize(green, "+"), ch.System, ch.Name, ch.New, direct)
+			case ch.New == "":
+				fmt.Printf("%s %s %s %s%s\n", colorize(red, "-"), ch.System, ch.Name, ch.Old, direct)
+			default:
+				fmt.Printf("%s %s %s %s -> %s%s\n", colorize(yellow, "~"), ch.System, ch.Name, ch.Old, ch.New, direct)
+			}
+			if ch.OldLicenses != nil || ch.NewLicenses != nil {
+				fmt.Printf("    license: %s -> %s\n", licenseList(ch.OldLicenses), licenseList(ch.NewLicenses))
+			}
+			for _, a := range ch.NewAdvisories {
+				rating, color := severity(a.CVSS3Score)
+				fmt.Printf("    %s %s %s\n", a.AdvisoryKey.ID, colorize(color, fmt.Sprintf("[%s %.1f]", rating, a.CVSS3Score)), a.Title)
+			}
+			for _, id := range ch.FixedAdvisories {
+				fmt.Printf("    %s fixed\n", id)
+			}
+			if ch.Error != "" {
+				fmt.Printf("    error: %s\n", ch.Error)
+			}
+		}
+	})
+	return vulnerable, cmp.Or(perr, err)
+}
+
+func licenseList(licenses []string) string {
+	if len(licenses) == 0 {
+		return "none"
+	}
+	return strings.Join(licenses, ", ")
+}
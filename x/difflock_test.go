@@ -0,0 +1,124 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/scan"
+	"github.com/google/go-cmp/cmp"
+)
+
+func dep(name, version string, direct bool) scan.Dependency {
+	return scan.Dependency{VersionKey: insights.VersionKey{System: "NPM", Name: name, Version: version}, Direct: direct}
+}
+
+func TestDiffLockfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new []scan.Dependency
+		want     []*lockChange
+	}{
+		{
+			name: "unchanged",
+			old:  []scan.Dependency{dep("a", "1.0.0", true)},
+			new:  []scan.Dependency{dep("a", "1.0.0", true)},
+		},
+		{
+			name: "single version changed",
+			old:  []scan.Dependency{dep("a", "1.0.0", true), dep("b", "2.0.0", false)},
+			new:  []scan.Dependency{dep("a", "1.1.0", true), dep("b", "2.0.0", false)},
+			want: []*lockChange{{System: "NPM", Name: "a", Old: "1.0.0", New: "1.1.0", Direct: true}},
+		},
+		{
+			name: "multiple versions",
+			old:  []scan.Dependency{dep("a", "1.0.0", false), dep("a", "2.0.0", false)},
+			new:  []scan.Dependency{dep("a", "1.0.1", false), dep("a", "2.0.1", true)},
+			want: []*lockChange{
+				{System: "NPM", Name: "a", New: "1.0.1"},
+				{System: "NPM", Name: "a", New: "2.0.1", Direct: true},
+				{System: "NPM", Name: "a", Old: "1.0.0"},
+				{System: "NPM", Name: "a", Old: "2.0.0"},
+			},
+		},
+		{
+			name: "version added next to a kept one",
+			old:  []scan.Dependency{dep("a", "1.0.0", false)},
+			new:  []scan.Dependency{dep("a", "1.0.0", false), dep("a", "2.0.0", false)},
+			want: []*lockChange{{System: "NPM", Name: "a", New: "2.0.0"}},
+		},
+		{
+			name: "added only",
+			new:  []scan.Dependency{dep("b", "1.0.0", false), dep("a", "1.0.0", true)},
+			want: []*lockChange{
+				{System: "NPM", Name: "a", New: "1.0.0", Direct: true},
+				{System: "NPM", Name: "b", New: "1.0.0"},
+			},
+		},
+		{
+			name: "removed only",
+			old:  []scan.Dependency{dep("a", "1.0.0", true), dep("a", "1.0.0", false)},
+			want: []*lockChange{{System: "NPM", Name: "a", Old: "1.0.0", Direct: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffLockfiles(tt.old, tt.new)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("diffLockfiles mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitAdvisories(t *testing.T) {
+	adv := func(id string) *insights.Advisory {
+		return &insights.Advisory{AdvisoryKey: insights.AdvisoryKey{ID: id}}
+	}
+	tests := []struct {
+		name      string
+		old, new  []*insights.Advisory
+		wantAdded []*insights.Advisory
+		wantFixed []string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "kept",
+			old:  []*insights.Advisory{adv("GHSA-1")},
+			new:  []*insights.Advisory{adv("GHSA-1")},
+		},
+		{
+			name:      "added and fixed",
+			old:       []*insights.Advisory{adv("GHSA-1"), adv("GHSA-2")},
+			new:       []*insights.Advisory{adv("GHSA-2"), adv("GHSA-3")},
+			wantAdded: []*insights.Advisory{adv("GHSA-3")},
+			wantFixed: []string{"GHSA-1"},
+		},
+		{
+			name:      "added package",
+			new:       []*insights.Advisory{adv("GHSA-1")},
+			wantAdded: []*insights.Advisory{adv("GHSA-1")},
+		},
+		{
+			name:      "all fixed",
+			old:       []*insights.Advisory{adv("GHSA-1"), adv("GHSA-2")},
+			wantFixed: []string{"GHSA-1", "GHSA-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, fixed := splitAdvisories(tt.old, tt.new)
+			if diff := cmp.Diff(tt.wantAdded, added); diff != "" {
+				t.Errorf("added advisories mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantFixed, fixed); diff != "" {
+				t.Errorf("fixed advisories mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
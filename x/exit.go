@@ -0,0 +1,74 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/registry"
+)
+
+// Exit codes. They are part of the interface of the command, so that scripts
+// and CI pipelines can act on the outcome without parsing the output.
+const (
+	exitOK         = 0 // success
+	exitError      = 1 // any error not covered below
+	exitUsage      = 2 // invalid command line
+	exitNotFound   = 3 // the package, version, project, or advisory does not exist
+	exitAPI        = 4 // the deps.dev API could not be reached or failed
+	exitVulnerable = 5 // vulnerabilities were found
+	exitPolicy     = 6 // a policy was violated
+)
+
+const exitCodesHelp = `Exit status:
+  0  success
+  1  error
+  2  invalid command line
+  3  package, version, project, or advisory not found
+  4  network or deps.dev API error
+  5  vulnerabilities found
+  6  policy violation
+`
+
+// exitCode returns the exit code for err.
+func exitCode(err error) int {
+	if errors.Is(err, insights.ErrNotFound) {
+		return exitNotFound
+	}
+	var apiErr *insights.APIError
+	if errors.As(err, &apiErr) || errors.Is(err, errUnaudited) {
+		return exitAPI
+	}
+	var statusErr *registry.StatusError
+	if errors.As(err, &statusErr) {
+		return exitAPI
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, insights.ErrOffline) {
+		return exitAPI
+	}
+	return exitError
+}
+
+// partial reports whether err is a *insights.BatchError, which comes with
+// the results of the keys that did not fail. Commands print such results
+// before returning the error.
+func partial(err error) bool {
+	var be *insights.BatchError
+	return errors.As(err, &be)
+}
+
+// fatal prints err and exits with the corresponding exit code. The error is
+// printed even in quiet mode.
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(exitCode(err))
+}
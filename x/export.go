@@ -0,0 +1,214 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates the tables of an SQLite export, replacing those of a
+// previous one.
+const sqliteSchema = `DROP TABLE IF EXISTS packages;
+CREATE TABLE packages (system TEXT, name TEXT, default_version TEXT, PRIMARY KEY (system, name));
+DROP TABLE IF EXISTS versions;
+CREATE TABLE versions (system TEXT, name TEXT, version TEXT, published_at TEXT, is_default INTEGER, is_deprecated INTEGER, PRIMARY KEY (system, name, version));
+DROP TABLE IF EXISTS dependencies;
+CREATE TABLE dependencies (system TEXT, name TEXT, version TEXT, direct INTEGER, file TEXT, licenses TEXT, source_repository TEXT, deprecated INTEGER, yanked INTEGER, error TEXT);
+DROP TABLE IF EXISTS advisories;
+CREATE TABLE advisories (id TEXT PRIMARY KEY, url TEXT, title TEXT, aliases TEXT, cvss3_score REAL, cvss3_vector TEXT);
+DROP TABLE IF EXISTS dependency_advisories;
+CREATE TABLE dependency_advisories (system TEXT, name TEXT, version TEXT, advisory_id TEXT);
+DROP TABLE IF EXISTS graph_nodes;
+CREATE TABLE graph_nodes (node INTEGER PRIMARY KEY, system TEXT, name TEXT, version TEXT, relation TEXT, bundled INTEGER, errors TEXT);
+DROP TABLE IF EXISTS graph_edges;
+CREATE TABLE graph_edges (from_node INTEGER, to_node INTEGER, requirement TEXT);
+DROP TABLE IF EXISTS projects;
+CREATE TABLE projects (id TEXT PRIMARY KEY, stars INTEGER, forks INTEGER, open_issues INTEGER, license TEXT, description TEXT, homepage TEXT, scorecard_date TEXT, scorecard_score REAL);
+`
+
+// sqlWriter writes SQL statements, remembering the first error.
+type sqlWriter struct {
+	w   io.Writer
+	err error
+}
+
+// insert writes a statement inserting a row of values into table. Values
+// are strings, integers, floats, or booleans, which are stored as 0 or 1;
+// values of other types are an error.
+func (sw *sqlWriter) insert(table string, values ...any) {
+	if sw.err != nil {
+		return
+	}
+	lits := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			lits[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		case bool:
+			lits[i] = "0"
+			if v {
+				lits[i] = "1"
+			}
+		case int:
+			lits[i] = strconv.Itoa(v)
+		case float32:
+			lits[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+		case float64:
+			lits[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		default:
+			sw.err = fmt.Errorf("%s: unexpected SQL value of type %T", table, v)
+			return
+		}
+	}
+	_, sw.err = fmt.Fprintf(sw.w, "INSERT OR REPLACE INTO %s VALUES (%s);\n", table, strings.Join(lits, ", "))
+}
+
+// writeSQLite writes the SQL statements that create the tables of an
+// SQLite export of the audited dependencies, their packages and projects,
+// and graph, if not nil.
+func writeSQLite(ctx context.Context, c *insights.Client, w io.Writer, r *audit.Result, graph *insights.Dependencies) error {
+	sw := &sqlWriter{w: w}
+	_, sw.err = io.WriteString(w, "BEGIN TRANSACTION;\n"+sqliteSchema)
+
+	// Packages and projects in the order of the dependencies.
+	var (
+		packages []insights.PackageKey
+		projects []string
+		seen     = make(map[string]bool)
+	)
+	for _, p := range r.Packages {
+		k := p.Dependency.VersionKey
+		sw.insert("dependencies", k.System, k.Name, k.Version, p.Dependency.Direct, p.Dependency.File,
+			strings.Join(p.Licenses, " "), p.SourceRepository, p.Deprecated, p.Yanked, strings.TrimSpace(p.Error))
+		for _, a := range p.Advisories {
+			sw.insert("dependency_advisories", k.System, k.Name, k.Version, a.AdvisoryKey.ID)
+			if !seen["advisory "+a.AdvisoryKey.ID] {
+				seen["advisory "+a.AdvisoryKey.ID] = true
+				sw.insert("advisories", a.AdvisoryKey.ID, a.URL, a.Title, strings.Join(a.Aliases, " "), a.CVSS3Score, a.CVSS3Vector)
+			}
+		}
+		if p.SourceRepository != "" && !seen["project "+p.SourceRepository] {
+			seen["project "+p.SourceRepository] = true
+			projects = append(projects, p.SourceRepository)
+		}
+		if pk := "package " + k.System + " " + k.Name; !seen[pk] {
+			seen[pk] = true
+			packages = append(packages, insights.PackageKey{System: k.System, Name: k.Name})
+		}
+	}
+
+	// Packages and projects deps.dev cannot tell about are left out.
+	for _, k := range packages {
+		p, _, err := c.GetPackage(ctx, k.System, k.Name)
+		if err != nil {
+			log.Printf("%s %s: %v", k.System, k.Name, err)
+			continue
+		}
+		var def string
+		for _, v := range p.Versions {
+			vk := v.VersionKey
+			if v.IsDefault {
+				def = vk.Version
+			}
+			sw.insert("versions", vk.System, vk.Name, vk.Version, v.PublishedAt, v.IsDefault, v.IsDeprecated)
+		}
+		sw.insert("packages", p.PackageKey.System, p.PackageKey.Name, def)
+	}
+	for _, id := range projects {
+		p, _, err := c.GetProject(ctx, id)
+		if err != nil {
+			log.Printf("%s: %v", id, err)
+			continue
+		}
+		sw.insert("projects", p.ProjectKey.ID, p.StarsCount, p.ForksCount, p.OpenIssuesCount, p.License,
+			p.Description, p.Homepage, p.Scorecard.Date, p.Scorecard.OverallScore)
+	}
+
+	if graph != nil {
+		for i, n := range graph.Nodes {
+			k := n.VersionKey
+			sw.insert("graph_nodes", i, k.System, k.Name, k.Version, n.Relation, n.Bundled, strings.Join(n.Errors, "; "))
+		}
+		for _, e := range graph.Edges {
+			sw.insert("graph_edges", e.FromNode, e.ToNode, e.Requirement)
+		}
+	}
+	if sw.err == nil {
+		_, sw.err = io.WriteString(w, "COMMIT;\n")
+	}
+	return sw.err
+}
+
+// doExportSQLite exports what deps.dev knows about the dependencies of the
+// project at args[0], or of the package version args[0] args[1] args[2],
+// to the SQLite database out, for querying with SQL. The package version
+// defaults to the default version, and the project to the current
+// directory. If out ends in .sql, or is empty, the SQL statements that
+// create the database are written to it, or to the standard output,
+// instead.
+func doExportSQLite(ctx context.Context, c *insights.Client, out string, args []string) error {
+	deps, graph, _, err := dependenciesOf(ctx, c, args)
+	if err != nil {
+		return err
+	}
+	// The packages that could not be audited are exported with their
+	// errors, and the failed lookups reported once the database is written.
+	r, err := audit.Run(ctx, c, deps, &audit.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+	return cmp.Or(exportSQLite(ctx, c, out, r, graph), err)
+}
+
+// exportSQLite writes the SQLite database of r and graph as doExportSQLite
+// does.
+func exportSQLite(ctx context.Context, c *insights.Client, out string, r *audit.Result, graph *insights.Dependencies) error {
+	if out == "" {
+		return writeSQLite(ctx, c, os.Stdout, r, graph)
+	}
+	if filepath.Ext(out) == ".sql" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		if err := writeSQLite(ctx, c, f, r, graph); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	var script strings.Builder
+	if err := writeSQLite(ctx, c, &script, r, graph); err != nil {
+		return err
+	}
+	return execSQLite(ctx, out, script.String())
+}
+
+// execSQLite runs the SQL statements of script on the SQLite database at
+// path, creating it if needed.
+func execSQLite(ctx context.Context, path, script string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, script); err != nil {
+		db.Close()
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return db.Close()
+}
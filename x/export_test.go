@@ -0,0 +1,185 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/scan"
+	"github.com/google/go-cmp/cmp"
+)
+
+// setup returns a client talking to a test server whose API handlers are
+// registered on mux.
+func setup(t *testing.T) (*insights.Client, *http.ServeMux) {
+	t.Helper()
+	mux := http.NewServeMux()
+	apiMux := http.NewServeMux()
+	apiMux.Handle("/v3/", http.StripPrefix("/v3", mux))
+	server := httptest.NewServer(apiMux)
+	t.Cleanup(server.Close)
+
+	client := insights.NewClient()
+	client.BaseURL, _ = url.Parse(server.URL + "/v3/")
+	return client, mux
+}
+
+func TestSQLWriterInsert(t *testing.T) {
+	var sb strings.Builder
+	sw := &sqlWriter{w: &sb}
+	sw.insert("t", "it's", "", true, false, 42, float32(7.5), 9.8)
+	want := "INSERT OR REPLACE INTO t VALUES ('it''s', '', 1, 0, 42, 7.5, 9.8);\n"
+	if sw.err != nil || sb.String() != want {
+		t.Errorf("insert wrote %q, %v; want %q", sb.String(), sw.err, want)
+	}
+
+	sb.Reset()
+	sw.insert("t", []string{"a"})
+	if sw.err == nil {
+		t.Errorf("insert of a slice did not fail")
+	}
+	sw.insert("t", "a")
+	if sb.Len() != 0 {
+		t.Errorf("insert wrote %q after an error", sb.String())
+	}
+}
+
+func TestWriteSQLite(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/NPM/packages/a", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packageKey":{"system":"NPM","name":"a"},"versions":[{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"},"isDefault":true}]}`)
+	})
+	// The project is unknown to deps.dev, and left out.
+
+	dep := scan.Dependency{VersionKey: insights.VersionKey{System: "NPM", Name: "a", Version: "1.0.0"}, Direct: true}
+	r := &audit.Result{Packages: []audit.Package{{
+		Dependency:       dep,
+		Licenses:         []string{"MIT", "ISC"},
+		SourceRepository: "github.com/x/a",
+		Advisories: []*insights.Advisory{
+			{AdvisoryKey: insights.AdvisoryKey{ID: "GHSA-1"}, Title: "don't; DROP TABLE advisories", CVSS3Score: 9.8},
+		},
+	}}}
+	graph := &insights.Dependencies{
+		Nodes: []insights.Node{{VersionKey: dep.VersionKey, Relation: "SELF"}},
+	}
+
+	var script strings.Builder
+	if err := writeSQLite(context.Background(), client, &script, r, graph); err != nil {
+		t.Fatalf("writeSQLite failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "deps.db")
+	// A second export replaces the tables of the first.
+	for range 2 {
+		if err := execSQLite(context.Background(), path, script.String()); err != nil {
+			t.Fatalf("execSQLite failed: %v", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	queries := []struct {
+		query string
+		want  []string
+	}{
+		{"SELECT name || ' ' || version || ' ' || direct || ' ' || licenses FROM dependencies", []string{"a 1.0.0 1 MIT ISC"}},
+		{"SELECT id || ' ' || title || ' ' || cvss3_score FROM advisories", []string{"GHSA-1 don't; DROP TABLE advisories 9.8"}},
+		{"SELECT advisory_id FROM dependency_advisories", []string{"GHSA-1"}},
+		{"SELECT name || ' ' || default_version FROM packages", []string{"a 1.0.0"}},
+		{"SELECT version || ' ' || is_default FROM versions", []string{"1.0.0 1"}},
+		{"SELECT id FROM projects", nil},
+		{"SELECT node || ' ' || name || ' ' || relation FROM graph_nodes", []string{"0 a SELF"}},
+		{"SELECT from_node FROM graph_edges", nil},
+	}
+	for _, q := range queries {
+		rows, err := db.Query(q.query)
+		if err != nil {
+			t.Errorf("%s: %v", q.query, err)
+			continue
+		}
+		var got []string
+		for rows.Next() {
+			var s string
+			if err := rows.Scan(&s); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, s)
+		}
+		rows.Close()
+		if diff := cmp.Diff(q.want, got); diff != "" {
+			t.Errorf("%s mismatch (-want +got):\n%s", q.query, diff)
+		}
+	}
+}
+
+func TestDependenciesOf(t *testing.T) {
+	client, mux := setup(t)
+	mux.HandleFunc("/systems/npm/packages/a/versions/1.0.0:dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"nodes":[
+				{"versionKey":{"system":"NPM","name":"a","version":"1.0.0"},"relation":"SELF"},
+				{"versionKey":{"system":"NPM","name":"b","version":"2.0.0"},"relation":"DIRECT"},
+				{"versionKey":{"system":"NPM","name":"c","version":"3.0.0"},"relation":"INDIRECT"}
+			],
+			"edges":[{"fromNode":0,"toNode":1},{"fromNode":1,"toNode":2}]
+		}`)
+	})
+	dir := t.TempDir()
+	lock := `{"lockfileVersion": 3, "packages": {"": {"dependencies": {"b": "^2.0.0"}}, "node_modules/b": {"version": "2.0.0"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(lock), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Arguments are given as the commands give them, with the default
+	// system set: a path is not taken for a package name.
+	old := defaultSystem
+	defaultSystem = "npm"
+	t.Cleanup(func() { defaultSystem = old })
+
+	tests := []struct {
+		args     []string
+		want     []string
+		wantWhat string
+		graph    bool
+	}{
+		{[]string{dir}, []string{"b@2.0.0 direct"}, dir, false},
+		{[]string{"npm:a@1.0.0"}, []string{"a@1.0.0 direct", "b@2.0.0 direct", "c@3.0.0 indirect"}, "a@1.0.0", true},
+		{[]string{"npm", "a", "1.0.0"}, []string{"a@1.0.0 direct", "b@2.0.0 direct", "c@3.0.0 indirect"}, "a@1.0.0", true},
+	}
+	for _, tt := range tests {
+		deps, graph, what, err := dependenciesOf(context.Background(), client, keyArgs(tt.args))
+		if err != nil {
+			t.Errorf("dependenciesOf(%q) failed: %v", tt.args, err)
+			continue
+		}
+		var got []string
+		for _, d := range deps {
+			via := "indirect"
+			if d.Direct {
+				via = "direct"
+			}
+			got = append(got, d.VersionKey.Name+"@"+d.VersionKey.Version+" "+via)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("dependenciesOf(%q) mismatch (-want +got):\n%s", tt.args, diff)
+		}
+		if what != tt.wantWhat || (graph != nil) != tt.graph {
+			t.Errorf("dependenciesOf(%q) = %q, graph %v; want %q, graph %v", tt.args, what, graph != nil, tt.wantWhat, tt.graph)
+		}
+	}
+}
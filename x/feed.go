@@ -0,0 +1,81 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/feed"
+)
+
+// doFeed writes an Atom feed of the new versions and advisories of the
+// packages in the watchlist file, published or first seen in the period
+// since, to out, or to the standard output if out is empty. id identifies
+// the feed; if empty, the watchlist file is used. If stateFile is not
+// empty, the times advisories were first seen are kept in it, so that they
+// keep their dates across runs.
+func doFeed(ctx context.Context, c *insights.Client, file, out, stateFile, id string, since time.Duration) error {
+	wl, err := readWatchlist(file)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]time.Time)
+	if stateFile != "" {
+		data, err := os.ReadFile(stateFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &seen); err != nil {
+				return fmt.Errorf("%s: %v", stateFile, err)
+			}
+		}
+	}
+
+	entries, err := feed.Entries(ctx, c, wl.packageKeys(), &feed.Options{Since: since, Seen: seen})
+	if err != nil {
+		// The feed of the other packages is still of use.
+		log.Print(err)
+	}
+	if stateFile != "" {
+		data, err := json.MarshalIndent(seen, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(stateFile, append(data, '\n'), 0o666); err != nil {
+			return err
+		}
+	}
+
+	if id == "" {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+		id = "file://" + filepath.ToSlash(abs)
+	}
+	f := &feed.Feed{Title: "Watched packages: " + filepath.Base(file), ID: id, Entries: entries}
+	if out == "" {
+		return f.WriteAtom(os.Stdout)
+	}
+	w, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := f.WriteAtom(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
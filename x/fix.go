@@ -0,0 +1,71 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doFix prints the upgrades of the direct dependencies of the project at
+// path that clear the given advisories, or all of them if none are given,
+// and what they would change. The plan is printed even if lookups failed,
+// as it may still be of use, and their errors are returned after it.
+func doFix(ctx context.Context, c *insights.Client, path string, advisories []string) error {
+	deps, err := scanPath(path)
+	if err != nil {
+		return err
+	}
+	var direct []insights.VersionKey
+	for _, d := range deps {
+		if d.Direct {
+			direct = append(direct, d.VersionKey)
+		}
+	}
+	plan, err := analysis.SuggestFixes(ctx, c, direct, &analysis.FixOptions{
+		Options:    analysis.Options{Concurrency: concurrency},
+		Advisories: advisories,
+	})
+	var be *insights.BatchError
+	if err != nil && !errors.As(err, &be) {
+		return err
+	}
+
+	perr := printResult(plan, func() {
+		if len(plan.Findings) == 0 && be == nil {
+			fmt.Printf("%s: no advisories to fix\n", path)
+			return
+		}
+		for _, b := range plan.Bumps {
+			fmt.Printf("upgrade %s %s -> %s (fixes %s)\n", b.From.Name, b.From.Version, b.To.Version, strings.Join(b.Fixes, ", "))
+		}
+		for _, r := range plan.Refreshes {
+			fmt.Printf("refresh %s %s -> %s (fixes %s)\n", r.From.Name, r.From.Version, r.To.Version, strings.Join(r.Fixes, ", "))
+		}
+		for _, f := range plan.Unfixed {
+			fmt.Printf("%s %s %s@%s via %s\n", colorize(red, "unfixed"), f.Advisory, f.Package.Name, f.Package.Version, f.Via.Name)
+		}
+		if len(plan.Changes) > 0 {
+			fmt.Println("\nchanges:")
+		}
+		for _, ch := range plan.Changes {
+			switch {
+			case len(ch.Before) == 0:
+				fmt.Printf("%s %s %s\n", colorize(green, "+"), ch.Name, strings.Join(ch.After, ", "))
+			case len(ch.After) == 0:
+				fmt.Printf("%s %s %s\n", colorize(red, "-"), ch.Name, strings.Join(ch.Before, ", "))
+			default:
+				fmt.Printf("%s %s %s -> %s\n", colorize(yellow, "~"), ch.Name, strings.Join(ch.Before, ", "), strings.Join(ch.After, ", "))
+			}
+		}
+	})
+	return cmp.Or(perr, err)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GitHub Actions output: findings are printed as workflow commands, which
+// GitHub shows as annotations, and a Markdown summary is appended to the file
+// named by $GITHUB_STEP_SUMMARY, shown on the summary page of the run.
+// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions.
+
+// annotation is a GitHub workflow annotation.
+type annotation struct {
+	level string // "error", "warning", or "notice"
+	file  string
+	title string
+	msg   string
+}
+
+var (
+	ghDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	ghPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// String returns a as a workflow command.
+func (a annotation) String() string {
+	var props []string
+	if a.file != "" {
+		props = append(props, "file="+ghPropertyEscaper.Replace(a.file))
+	}
+	if a.title != "" {
+		props = append(props, "title="+ghPropertyEscaper.Replace(a.title))
+	}
+	cmd := "::" + a.level
+	if len(props) > 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+	return cmd + "::" + ghDataEscaper.Replace(a.msg)
+}
+
+// annotationLevel returns the annotation level for an advisory with the
+// given CVSS v3 score: error for high and critical severity, warning
+// otherwise.
+func annotationLevel(score float32) string {
+	if score >= 7 {
+		return "error"
+	}
+	return "warning"
+}
+
+// writeStepSummary appends md to the step summary of the GitHub Actions job,
+// if running in one.
+func writeStepSummary(md string) error {
+	file := os.Getenv("GITHUB_STEP_SUMMARY")
+	if file == "" {
+		return nil
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(f, md); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// markdownCell escapes s for use in a cell of a Markdown table.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
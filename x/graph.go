@@ -0,0 +1,56 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/render"
+)
+
+// graphWriters write dependency graphs in the format of a file extension.
+var graphWriters = map[string]func(io.Writer, *insights.Dependencies) error{
+	".svg": render.WriteSVG,
+	".png": render.WritePNG,
+	".dot": render.WriteDOT,
+	".gv":  render.WriteDOT,
+}
+
+// doGraph draws the resolved dependency graph of the given package version
+// to out, in the format of its extension: SVG, PNG, or DOT. If out is
+// empty, the graph is written to the standard output in DOT. The graph is
+// pruned as prune specifies first.
+func doGraph(ctx context.Context, c *insights.Client, system, name, version, out string, prune *insights.PruneOptions) error {
+	write := render.WriteDOT
+	if out != "" {
+		var ok bool
+		if write, ok = graphWriters[strings.ToLower(filepath.Ext(out))]; !ok {
+			return fmt.Errorf("%s: unknown image format; use .svg, .png, or .dot", out)
+		}
+	}
+	g, _, err := c.GetDependencies(ctx, system, name, version)
+	if err != nil {
+		return err
+	}
+	g = g.Prune(prune)
+	if out == "" {
+		return write(os.Stdout, g)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := write(f, g); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
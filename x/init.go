@@ -0,0 +1,121 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights/scan"
+)
+
+// permissiveLicenses are the licenses the starter policy allows for every
+// system.
+var permissiveLicenses = []string{"MIT", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "ISC", "0BSD"}
+
+// systemLicenses are further permissive licenses common in some systems.
+var systemLicenses = map[string][]string{
+	"CARGO":    {"Unicode-3.0", "Unicode-DFS-2016", "Zlib"},
+	"PYPI":     {"PSF-2.0"},
+	"RUBYGEMS": {"Ruby"},
+}
+
+// doInit writes a starter configuration file, unless one exists, and a
+// starter policy in the directory dir, suggesting rules for the package
+// management systems of the project in dir. The configuration file is
+// shared by every project, so nothing in it is derived from the project.
+// Existing files are only overwritten if force is set.
+func doInit(configFile, dir string, force bool) error {
+	deps, err := scan.Tree(dir)
+	if err != nil {
+		return err
+	}
+	var systems []string
+	for _, d := range deps {
+		if s := strings.ToUpper(d.VersionKey.System); !slices.Contains(systems, s) {
+			systems = append(systems, s)
+		}
+	}
+	slices.Sort(systems)
+	if len(systems) == 0 {
+		fmt.Printf("%s: no supported manifests or lockfiles found; writing generic defaults\n", dir)
+	} else {
+		fmt.Printf("%s: found %d dependencies in %s\n", dir, len(deps), strings.ToLower(strings.Join(systems, ", ")))
+	}
+
+	if configFile != "" {
+		if err := writeStarter(configFile, starterConfig(), force); err != nil {
+			return err
+		}
+	}
+	policyFile := filepath.Join(dir, defaultPolicyFile)
+	if err := writeStarter(policyFile, starterPolicy(systems), force); err != nil {
+		return err
+	}
+	fmt.Printf("\nRun \"x check\" in %s to check the project against the policy.\n", dir)
+	return nil
+}
+
+// writeStarter writes content to file, creating its directory, unless file
+// exists and force is not set.
+func writeStarter(file, content string, force bool) error {
+	if _, err := os.Stat(file); err == nil && !force {
+		fmt.Printf("%s exists; not overwritten (use -f to replace it)\n", file)
+		return nil
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", file)
+	return nil
+}
+
+// starterConfig returns a configuration file with the defaults of the x
+// command.
+func starterConfig() string {
+	var sb strings.Builder
+	sb.WriteString("# Defaults for the x command. Command line flags and INSIGHT_*\n# environment variables take precedence.\n\n")
+	sb.WriteString("# format: json\n")
+	if dir, err := os.UserCacheDir(); err == nil {
+		fmt.Fprintf(&sb, "cache_dir: %s\n", filepath.Join(dir, "insight"))
+	} else {
+		sb.WriteString("# cache_dir: /var/cache/insight\n")
+	}
+	sb.WriteString("max_age: 24h\n")
+	fmt.Fprintf(&sb, "concurrency: %d\n", concurrency)
+	sb.WriteString("# The system of package names given without one.\n# system: npm\n")
+	return sb.String()
+}
+
+// starterPolicy returns a policy for a project using the given systems.
+func starterPolicy(systems []string) string {
+	licenses := slices.Clone(permissiveLicenses)
+	for _, s := range systems {
+		licenses = append(licenses, systemLicenses[s]...)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Policy checked by \"x check\". See the documentation of the policy\n# package for all the rules.\n\n")
+	fmt.Fprintf(&sb, "allow_licenses: [%s]\n", strings.Join(licenses, ", "))
+	sb.WriteString("deny_licenses: [AGPL-3.0, SSPL-1.0]\n\n")
+	sb.WriteString("# Fail on high and critical advisories; list accepted ones below.\nmax_cvss3: 6.9\nignore_advisories: []\n\n")
+	sb.WriteString("# Fail on dependencies whose repositories score poorly on the OpenSSF Scorecard.\nmin_scorecard: 3\n\n")
+	// Only some registries publish provenance attestations.
+	if slices.Contains(systems, "NPM") || slices.Contains(systems, "PYPI") {
+		sb.WriteString("# npm and PyPI packages may have provenance attestations.\n# require_provenance: true\n\n")
+	}
+	sb.WriteString("deny_packages: []\n")
+	return sb.String()
+}
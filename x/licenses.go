@@ -0,0 +1,118 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doLicenseWorklist prints the dependencies of the project at path whose
+// license is unknown or non-standard, with where to look for it.
+func doLicenseWorklist(ctx context.Context, c *insights.Client, path string) error {
+	deps, err := scanPath(path)
+	if err != nil {
+		return err
+	}
+	keys := make([]insights.VersionKey, len(deps))
+	for i, d := range deps {
+		keys[i] = d.VersionKey
+	}
+	items, err := analysis.LicenseWorklist(ctx, c, keys, &analysis.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+
+	header := []string{"system", "name", "version", "licenses", "source_repository", "project_license", "links", "error"}
+	var rows [][]string
+	for _, it := range items {
+		var links []string
+		for _, l := range it.Links {
+			links = append(links, l.URL)
+		}
+		k := it.Package
+		rows = append(rows, []string{k.System, k.Name, k.Version, strings.Join(it.Licenses, " "), it.SourceRepository, it.ProjectLicense, strings.Join(links, " "), it.Error})
+	}
+	perr := printList(items, header, rows, func() {
+		if len(items) == 0 && err == nil {
+			fmt.Printf("%s: the licenses of all %d dependencies are known\n", path, len(deps))
+			return
+		}
+		for i, it := range items {
+			if i > 0 {
+				fmt.Println()
+			}
+			k := it.Package
+			fmt.Printf("%s %s %s\n", k.System, k.Name, k.Version)
+			if it.Error != "" {
+				fmt.Printf("    error: %s\n", strings.TrimSpace(it.Error))
+				continue
+			}
+			fmt.Printf("    license: %s\n", licenseList(it.Licenses))
+			if it.SourceRepository != "" {
+				license := it.ProjectLicense
+				if license == "" {
+					license = "unknown"
+				}
+				fmt.Printf("    repository: %s (license %s)\n", it.SourceRepository, license)
+			}
+			for _, l := range it.Links {
+				fmt.Printf("    %s: %s\n", strings.ToLower(l.Label), l.URL)
+			}
+		}
+	})
+	return cmp.Or(perr, err)
+}
+
+// doLicenseReport prints the licenses of the dependencies of the project at
+// path, with the packages under each of them, flagging those that leave the
+// actual license unknown.
+func doLicenseReport(ctx context.Context, c *insights.Client, path string) error {
+	deps, err := scanPath(path)
+	if err != nil {
+		return err
+	}
+	keys := make([]insights.VersionKey, len(deps))
+	for i, d := range deps {
+		keys[i] = d.VersionKey
+	}
+	inv, err := analysis.Licenses(ctx, c, keys, &analysis.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+
+	header := []string{"license", "unclear", "count", "system", "name", "version"}
+	var rows [][]string
+	for _, g := range inv.Groups {
+		for _, k := range g.Packages {
+			rows = append(rows, []string{g.License, strconv.FormatBool(g.Unclear), strconv.Itoa(len(g.Packages)), k.System, k.Name, k.Version})
+		}
+	}
+	perr := printList(inv, header, rows, func() {
+		for i, g := range inv.Groups {
+			if i > 0 {
+				fmt.Println()
+			}
+			license := cmp.Or(g.License, "unknown")
+			if g.Unclear {
+				license = colorize(yellow, license+" (needs review)")
+			}
+			fmt.Printf("%s: %d packages\n", license, len(g.Packages))
+			for _, k := range g.Packages {
+				fmt.Printf("    %s %s %s\n", k.System, k.Name, k.Version)
+			}
+		}
+		if len(inv.Failed) > 0 {
+			fmt.Printf("\n%d packages could not be looked up\n", len(inv.Failed))
+		}
+	})
+	return cmp.Or(perr, err)
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doLookup prints the package with the given name in system or, if system
+// is empty, in every system that has one, side by side.
+func doLookup(ctx context.Context, c *insights.Client, system, name string) error {
+	var pkgs []analysis.SystemPackage
+	var err error
+	if system == "" {
+		pkgs, err = analysis.LookupAll(ctx, c, name, &analysis.Options{Concurrency: concurrency})
+		if err != nil && !partial(err) {
+			return err
+		}
+	} else if p := analysis.Lookup(ctx, c, strings.ToUpper(system), name); p != nil {
+		pkgs = append(pkgs, *p)
+	}
+
+	header := []string{"system", "name", "versions", "default", "dependents", "error"}
+	var rows [][]string
+	for _, p := range pkgs {
+		rows = append(rows, []string{p.System, p.Name, strconv.Itoa(p.Versions), p.DefaultVersion, strconv.Itoa(p.Dependents), strings.TrimSpace(p.Error)})
+	}
+	perr := printList(pkgs, header, rows, func() {
+		if len(pkgs) == 0 && err == nil {
+			fmt.Printf("no package named %s\n", name)
+			return
+		}
+		printTable(header, rows)
+	})
+	return cmp.Or(perr, err)
+}
@@ -5,90 +5,1298 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"hash"
+	"io"
 	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+	"github.com/franoliveto/insights/marker"
+	"github.com/franoliveto/insights/registry"
 )
 
-func doVersion(c *insights.Client, system, name, version string) error {
+// command describes a subcommand, for the usage message and shell completion.
+type command struct {
+	name    string
+	args    string
+	summary string
+
+	// Flags accepted by the subcommand, without the leading dash.
+	flags []string
+
+	// Whether the first argument is a package management system.
+	system bool
+}
+
+var commands = []command{
+	{name: "package", args: "system name", summary: "show a package and its versions", system: true},
+	{name: "version", args: "system name version", summary: "show a package version", system: true},
+	{name: "dependencies", args: "system name version", summary: "show the resolved dependency graph of a version", system: true},
+	{name: "graph", args: "[-o file] [-depth n] [-relation list] [-no-bundled] system name version", summary: "draw the dependency graph of a version as an SVG or PNG image, or in DOT", flags: []string{"o", "depth", "relation", "no-bundled"}, system: true},
+	{name: "dependents", args: "system name version", summary: "show how many packages depend on a version", system: true},
+	{name: "obscure", args: "[-min-depth n] [-min-dependents n] [-min-stars n] system name version", summary: "list the deep, little used dependencies of a version", flags: []string{"min-depth", "min-dependents", "min-stars"}, system: true},
+	{name: "stats", args: "system name version", summary: "summarize the dependency graph of a version", system: true},
+	{name: "size", args: "system name version", summary: "estimate the installed size of the dependency graph of a version", system: true},
+	{name: "why", args: "system name version target-package", summary: "explain why a package is in a dependency graph", system: true},
+	{name: "lookup", args: "[-all-systems] [system] name", summary: "look up a package name in one system, or in every system", flags: []string{"all-systems"}},
+	{name: "similar", args: "system name", summary: "list packages with similar names", system: true},
+	{name: "affected", args: "system name advisory", summary: "show which versions of a package an advisory affects", system: true},
+	{name: "verify", args: "[-local] system name version", summary: "check the provenance of a version, as verified by deps.dev or locally", flags: []string{"local"}, system: true},
+	{name: "download-url", args: "system name version", summary: "print the URL of the artifact of a version in its registry", system: true},
+	{name: "identify", args: "file", summary: "find the package versions matching a local file"},
+	{name: "open", args: "[-print] system name [version]", summary: "open the homepage of a package", flags: []string{"print"}, system: true},
+	{name: "watch", args: "[-f file] [-interval d] [-exec command] [-history file]", summary: "watch packages for new versions and advisories", flags: []string{"f", "interval", "exec", "history"}},
+	{name: "feed", args: "[-f file] [-o file] [-state file] [-id id] [-since d]", summary: "write an Atom feed of the new versions and advisories of watched packages", flags: []string{"f", "o", "state", "id", "since"}},
+	{name: "trend", args: "[-f file] [-history file] [-since d] [-record]", summary: "show whether the adoption of watched packages is growing or declining", flags: []string{"f", "history", "since", "record"}},
+	{name: "audit", args: "[-registry] [-criticality file] [path]", summary: "list the known vulnerabilities and deprecated dependencies of a project", flags: []string{"registry", "criticality"}},
+	{name: "search", args: "[-n count] system term", summary: "search the registry of a system for packages", flags: []string{"n"}, system: true},
+	{name: "namespace", args: "[-n count] system namespace", summary: "list the packages under an npm scope or Maven group ID with their deps.dev data", flags: []string{"n"}, system: true},
+	{name: "resolve", args: "system name requirement", summary: "show the version of a package a version requirement resolves to", system: true},
+	{name: "outdated", args: "[path]", summary: "list the direct dependencies of a project that have newer versions"},
+	{name: "license-worklist", args: "[path]", summary: "list the dependencies of a project whose license needs investigating"},
+	{name: "license-report", args: "[path]", summary: "list the licenses of the dependencies of a project with the packages under each"},
+	{name: "fix", args: "[-advisory ids] [path]", summary: "suggest the upgrades that clear the advisories affecting a project", flags: []string{"advisory"}},
+	{name: "what-if", args: "[-path dir] system name version", summary: "estimate what upgrading a direct dependency of a project would change", flags: []string{"path"}, system: true},
+	{name: "diff-lockfile", args: "old new", summary: "show the packages changed between two lockfiles and the advisories they bring in"},
+	{name: "badge", args: "[-metric vulnerabilities|scorecard|freshness] [-o file] [path | system name [version]]", summary: "write an SVG badge for a project or package", flags: []string{"metric", "o"}},
+	{name: "init", args: "[-f] [dir]", summary: "write a starter configuration file and policy for a project", flags: []string{"f"}},
+	{name: "check", args: "[-policy file] [path | system name [version]]", summary: "check the dependencies of a project or package against a policy", flags: []string{"policy"}},
+	{name: "report", args: "[-format md|html|json] [-out file] [-r] [path]", summary: "write a report about the dependencies of a project or monorepo", flags: []string{"format", "out", "r"}},
+	{name: "export", args: "sqlite [-out file] [path | system name [version]]", summary: "export the dependencies of a project or package, with their packages, advisories, and projects, to an SQLite database", flags: []string{"out"}},
+	{name: "project", args: "id", summary: "show a project"},
+	{name: "scorecard-history", args: "id [commit...]", summary: "show how the OpenSSF Scorecard score of a project changed across commits"},
+	{name: "project-packages", args: "id", summary: "list the package versions built from a project"},
+	{name: "project-systems", args: "[-n count] id", summary: "compare side by side the packages a project publishes to each system", flags: []string{"n"}},
+	{name: "schema", args: "[-dir dir] [output]", summary: "print the JSON Schema of the JSON output of a command or API result, or list them", flags: []string{"dir"}},
+	{name: "prune-cache", args: "", summary: "remove the cached API responses older than -max-age"},
+	{name: "completion", args: "bash|zsh|fish", summary: "print a shell completion script"},
+}
+
+// lookupCommand returns the command with the given name, or nil.
+func lookupCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// outputFormat is the format in which API results are printed: text, json,
+// or, for lists, csv. Commands that check projects also support github, for
+// GitHub Actions.
+var outputFormat = "text"
+
+// concurrency is the maximum number of API requests that commands looking up
+// many packages, such as audit, issue at the same time.
+var concurrency = 4
+
+// pythonEnv is the Python environment scanned requirements are evaluated
+// in, if one is given; requirements whose environment markers do not hold
+// in it are left out.
+var pythonEnv *marker.Environment
+
+// httpClient sends the requests to deps.dev, registries, and other
+// services, and retryPolicy tells how they are retried, if at all.
+var (
+	httpClient  = http.DefaultClient
+	retryPolicy *insights.RetryPolicy
+)
+
+// newRegistryClient returns a client of the package registries that sends
+// its requests as those to deps.dev are.
+func newRegistryClient() *registry.Client {
+	return &registry.Client{HTTPClient: httpClient, Retry: retryPolicy}
+}
+
+// printResult prints v, an API result, in the output format, using text to
+// print it as text.
+func printResult(v any, text func()) error {
+	switch outputFormat {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "text":
+		text()
+		return nil
+	case "csv":
+		return fmt.Errorf("output format csv is only supported for lists")
+	case "github":
+		return fmt.Errorf("output format github is not supported by this command")
+	}
+	return fmt.Errorf("unknown output format %q", outputFormat)
+}
+
+// printList prints v, a list of results, in the output format. As CSV, it
+// is printed as header followed by rows, one record per element of v.
+func printList(v any, header []string, rows [][]string, text func()) error {
+	if outputFormat != "csv" {
+		return printResult(v, text)
+	}
+	w := csv.NewWriter(os.Stdout)
+	w.Write(header)
+	w.WriteAll(rows)
+	return w.Error()
+}
+
+// systems are the package management systems known to deps.dev.
+var systems = []string{"go", "rubygems", "npm", "cargo", "maven", "pypi", "nuget"}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: x [flags] command [args]\n\nCommands:\n")
+	w := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s %s\t%s\n", c.name, c.args, c.summary)
+	}
+	w.Flush()
+	fmt.Fprintf(os.Stderr, "\nThe system, name, and version arguments may be given as one, as a purl such as\npkg:npm/react@18.2.0 or as system:name@version, such as npm:react@18.2.0. Maven\npackages may also be given as coordinates, group:artifact[:version].\n")
+	fmt.Fprintf(os.Stderr, "\nFlags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\n%s", exitCodesHelp)
+	os.Exit(exitUsage)
+}
+
+func doVersion(ctx context.Context, c *insights.Client, system, name, version string) error {
 	var v *insights.Version
-	v, err := c.GetVersion(system, name, version)
+	v, _, err := c.GetVersion(ctx, system, name, version)
 	if err != nil {
 		return err
 	}
-	fmt.Println(*v)
-	return nil
+	return printResult(v, func() { fmt.Println(*v) })
 }
 
-func doPackage(c *insights.Client, system, name string) error {
+func doPackage(ctx context.Context, c *insights.Client, system, name string) error {
 	var p *insights.Package
-	p, err := c.GetPackage(system, name)
+	p, _, err := c.GetPackage(ctx, system, name)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, v := range p.Versions {
+		rows = append(rows, []string{v.VersionKey.Version, v.PublishedAt, strconv.FormatBool(v.IsDefault)})
+	}
+	return printList(p, []string{"version", "published_at", "is_default"}, rows, func() { fmt.Println(*p) })
+}
+
+func doDependents(ctx context.Context, c *insights.Client, system, name, version string) error {
+	d, _, err := c.GetDependents(ctx, system, name, version)
+	if err != nil {
+		return err
+	}
+	return printResult(d, func() {
+		fmt.Printf("dependents: %d\n", d.DependentCount)
+		fmt.Printf("direct:     %d\n", d.DirectDependentCount)
+		fmt.Printf("indirect:   %d\n", d.IndirectDependentCount)
+	})
+}
+
+func doWhy(ctx context.Context, c *insights.Client, system, name, version, target string) error {
+	d, _, err := c.GetDependencies(ctx, system, name, version)
 	if err != nil {
 		return err
 	}
-	fmt.Println(*p)
+	chains := d.Why(target)
+	if len(chains) == 0 {
+		fmt.Printf("(%s@%s does not need %s)\n", name, version, target)
+		return nil
+	}
+	for i, chain := range chains {
+		if i > 0 {
+			fmt.Println()
+		}
+		last := chain[len(chain)-1].VersionKey
+		fmt.Printf("# %s@%s\n", last.Name, last.Version)
+		for _, n := range chain {
+			fmt.Printf("%s@%s\n", n.VersionKey.Name, n.VersionKey.Version)
+		}
+	}
 	return nil
 }
 
+// popularity returns the default version of the package along with its number
+// of dependents and the stars of its source repository. Signals that could not
+// be obtained are reported as -1.
+func popularity(ctx context.Context, c *insights.Client, system, name string) (version string, dependents, stars int) {
+	dependents, stars = -1, -1
+	p, _, err := c.GetPackage(ctx, system, name)
+	if err != nil {
+		return "", dependents, stars
+	}
+	for _, v := range p.Versions {
+		if v.IsDefault {
+			version = v.VersionKey.Version
+			break
+		}
+	}
+	if version == "" {
+		return "", dependents, stars
+	}
+	if d, _, err := c.GetDependents(ctx, system, name, version); err == nil {
+		dependents = d.DependentCount
+	}
+	v, _, err := c.GetVersion(ctx, system, name, version)
+	if err != nil {
+		return version, dependents, stars
+	}
+	for _, rp := range v.RelatedProjects {
+		if rp.RelationType != "SOURCE_REPO" {
+			continue
+		}
+		if pr, _, err := c.GetProject(ctx, rp.ProjectKey.ID); err == nil {
+			stars = pr.StarsCount
+		}
+		break
+	}
+	return version, dependents, stars
+}
+
+func doSimilar(ctx context.Context, c *insights.Client, system, name string) error {
+	s, _, err := c.GetSimilarlyNamedPackages(ctx, system, name)
+	if err != nil {
+		return err
+	}
+	if len(s.Packages) == 0 {
+		fmt.Printf("no packages with names similar to %s\n", name)
+		return nil
+	}
+	count := func(n int) string {
+		if n < 0 {
+			return "-"
+		}
+		return strconv.Itoa(n)
+	}
+	type row struct {
+		version           string
+		dependents, stars int
+	}
+	rows := make([]row, len(s.Packages))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, p := range s.Packages {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := &rows[i]
+			r.version, r.dependents, r.stars = popularity(ctx, c, p.PackageKey.System, p.PackageKey.Name)
+			<-sem
+		}()
+	}
+	wg.Wait()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDEFAULT VERSION\tDEPENDENTS\tSTARS")
+	for i, p := range s.Packages {
+		r := rows[i]
+		if r.version == "" {
+			r.version = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.PackageKey.Name, r.version, count(r.dependents), count(r.stars))
+	}
+	return w.Flush()
+}
+
+func doProjectPackages(ctx context.Context, c *insights.Client, id string) error {
+	pv, _, err := c.GetProjectPackageVersions(ctx, id)
+	if err != nil {
+		return err
+	}
+	header := []string{"system", "name", "version", "relation", "provenance", "attestations"}
+	var rows [][]string
+	for _, v := range pv.Versions {
+		// An attestation is reported as verified only if every attestation
+		// linking the version to the project was verified.
+		attested := "none"
+		if n := len(v.Attestations) + len(v.SLSAProvenances); n > 0 {
+			attested = "verified"
+			for _, a := range v.Attestations {
+				if !a.Verified {
+					attested = "unverified"
+				}
+			}
+			for _, p := range v.SLSAProvenances {
+				if !p.Verified {
+					attested = "unverified"
+				}
+			}
+		}
+		k := v.VersionKey
+		rows = append(rows, []string{k.System, k.Name, k.Version, v.RelationType, v.RelationProvenance, attested})
+	}
+	return printList(pv, header, rows, func() {
+		if len(rows) == 0 {
+			fmt.Printf("no package versions known to be built from %s\n", id)
+			return
+		}
+		printTable(header, rows)
+	})
+}
+
+// printTable prints header, in upper case, and rows as aligned columns.
+func printTable(header []string, rows [][]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(header, "\t")))
+	for _, r := range rows {
+		fmt.Fprintln(w, strings.Join(r, "\t"))
+	}
+	w.Flush()
+}
+
+// repoID reduces a source repository URL such as
+// "git+https://github.com/user/repo.git" to a project identifier of the form
+// "github.com/user/repo".
+func repoID(s string) string {
+	s = strings.TrimPrefix(s, "git+")
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+len("://"):]
+	}
+	s = strings.TrimPrefix(s, "git@")
+	s = strings.Replace(s, ":", "/", 1)
+	s = strings.TrimSuffix(s, "/")
+	s = strings.TrimSuffix(s, ".git")
+	return strings.ToLower(s)
+}
+
+// doVerify prints the provenance attestations of the given package version
+// and whether deps.dev verified them, and checks that they name the source
+// repository the version is linked to. It fails if any attestation is not
+// verified or names another repository. If local is set, the provenance is
+// also verified locally, with verifyLocally.
+func doVerify(ctx context.Context, c *insights.Client, system, name, version string, local bool) error {
+	v, _, err := c.GetVersion(ctx, system, name, version)
+	if err != nil {
+		return err
+	}
+
+	// The source repository the version claims to be built from.
+	var source string
+	for _, rp := range v.RelatedProjects {
+		if rp.RelationType == "SOURCE_REPO" {
+			source = strings.ToLower(rp.ProjectKey.ID)
+			break
+		}
+	}
+
+	type claim struct {
+		kind, repo, commit string
+		verified           bool
+	}
+	var claims []claim
+	for _, a := range v.Attestations {
+		claims = append(claims, claim{a.Type, a.SourceRepository, a.Commit, a.Verified})
+	}
+	if len(v.Attestations) == 0 {
+		// SLSA provenances are also listed as attestations; only fall back
+		// to them when no attestations are reported.
+		for _, p := range v.SLSAProvenances {
+			claims = append(claims, claim{"SLSA provenance", p.SourceRepository, p.Commit, p.Verified})
+		}
+	}
+	if len(claims) == 0 {
+		return fmt.Errorf("%s@%s has no attestations", name, version)
+	}
+
+	ok := true
+	for _, cl := range claims {
+		status := colorize(green, "verified")
+		if !cl.verified {
+			status = colorize(red, "NOT verified")
+			ok = false
+		}
+		fmt.Printf("%s: %s\n", cl.kind, status)
+		if cl.repo == "" {
+			continue
+		}
+		fmt.Printf("  built from %s", cl.repo)
+		if cl.commit != "" {
+			fmt.Printf(" at %s", cl.commit)
+		}
+		fmt.Println()
+		if source != "" && repoID(cl.repo) != source {
+			fmt.Printf("  %s\n", colorize(red, "source repository does NOT match "+source))
+			ok = false
+		}
+	}
+	if !ok {
+		return fmt.Errorf("%s@%s: provenance verification failed", name, version)
+	}
+	if local {
+		repo := source
+		for _, cl := range claims {
+			if repo != "" {
+				break
+			}
+			repo = repoID(cl.repo)
+		}
+		if repo == "" {
+			return fmt.Errorf("%s@%s: no source repository to verify the provenance locally against", name, version)
+		}
+		if err := verifyLocally(ctx, system, name, version, repo); err != nil {
+			return err
+		}
+	}
+	if source == "" {
+		fmt.Printf("%s@%s: %s\n", name, version, colorize(yellow, "attestations verified, but the source repository could not be checked: the version is not linked to one"))
+		return nil
+	}
+	fmt.Printf("%s@%s: %s\n", name, version, colorize(green, "provenance verified"))
+	return nil
+}
+
+// sigverifyCommand is the command verifyLocally runs.
+var sigverifyCommand = "insight-sigverify"
+
+// verifyLocally verifies the SLSA provenance of an npm package version
+// locally, checking its Sigstore bundles rather than relying on deps.dev,
+// and that it was built from repo. It runs insight-sigverify, a command of
+// its own so that x does not depend on sigstore-go.
+func verifyLocally(ctx context.Context, system, name, version, repo string) error {
+	if !strings.EqualFold(system, "npm") {
+		return fmt.Errorf("%s: only the provenance of npm packages can be verified locally", system)
+	}
+	path, err := exec.LookPath(sigverifyCommand)
+	if err != nil {
+		return fmt.Errorf("verifying locally needs %s, installed with go install github.com/franoliveto/insights/cmd/insight-sigverify@latest: %w", sigverifyCommand, err)
+	}
+	cmd := exec.CommandContext(ctx, path, name, version, repo)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s@%s: local provenance verification failed: %w", name, version, err)
+	}
+	return nil
+}
+
+// hashFile returns the base64-encoded digests of the named file for each of
+// the hash types supported by the query endpoint.
+func hashFile(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hashes := map[string]hash.Hash{
+		"MD5":    md5.New(),
+		"SHA1":   sha1.New(),
+		"SHA256": sha256.New(),
+		"SHA512": sha512.New(),
+	}
+	writers := make([]io.Writer, 0, len(hashes))
+	for _, h := range hashes {
+		writers = append(writers, h)
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return nil, err
+	}
+	sums := make(map[string]string)
+	for typ, h := range hashes {
+		sums[typ] = base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
+
+func doIdentify(ctx context.Context, c *insights.Client, file string) error {
+	sums, err := hashFile(file)
+	if err != nil {
+		return err
+	}
+	// Strongest hashes first; registries record different hash types.
+	var hashes []insights.Hash
+	for _, typ := range []string{"SHA512", "SHA256", "SHA1", "MD5"} {
+		hashes = append(hashes, insights.Hash{Type: typ, Value: sums[typ]})
+	}
+	matches, err := c.Identify(ctx, hashes, &insights.IdentifyOptions{Concurrency: concurrency})
+	if err != nil {
+		return err
+	}
+	seen := make(map[insights.VersionKey]bool)
+	var found []insights.VersionKey
+	for _, h := range hashes {
+		for _, k := range matches[h] {
+			if !seen[k] {
+				seen[k] = true
+				found = append(found, k)
+			}
+		}
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("%s: no matching package versions", file)
+	}
+	for _, k := range found {
+		fmt.Printf("%s %s %s\n", k.System, k.Name, k.Version)
+	}
+	return nil
+}
+
+// defaultVersion returns the default version of the package.
+func defaultVersion(ctx context.Context, c *insights.Client, system, name string) (string, error) {
+	p, _, err := c.GetPackage(ctx, system, name)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range p.Versions {
+		if v.IsDefault {
+			return v.VersionKey.Version, nil
+		}
+	}
+	return "", fmt.Errorf("%s has no default version", name)
+}
+
+// browse opens the web page at link in the default web browser. Links
+// come from package metadata, which publishers control, so only http and
+// https links are opened: others could start any local handler.
+func browse(link string) error {
+	u, err := url.Parse(link)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s: not opening a link that is not http or https", link)
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	return cmd.Start()
+}
+
+// homepage returns the link to the homepage of v or, if it has none, to
+// its source repository; it returns "" if there is neither.
+func homepage(v *insights.Version) string {
+	links := make(map[string]string)
+	for _, l := range v.Links {
+		if _, ok := links[l.Label]; !ok {
+			links[l.Label] = l.URL
+		}
+	}
+	for _, label := range []string{"HOMEPAGE", "SOURCE_REPO"} {
+		if u, ok := links[label]; ok {
+			return u
+		}
+	}
+	for _, rp := range v.RelatedProjects {
+		if rp.RelationType == "SOURCE_REPO" {
+			return "https://" + rp.ProjectKey.ID
+		}
+	}
+	return ""
+}
+
+func doOpen(ctx context.Context, c *insights.Client, system, name, version string, printURL bool) error {
+	if version == "" {
+		var err error
+		if version, err = defaultVersion(ctx, c, system, name); err != nil {
+			return err
+		}
+	}
+	v, _, err := c.GetVersion(ctx, system, name, version)
+	if err != nil {
+		return err
+	}
+	link := homepage(v)
+	if link == "" {
+		return fmt.Errorf("%s@%s has no homepage or source repository", name, version)
+	}
+	if printURL {
+		fmt.Println(link)
+		return nil
+	}
+	return browse(link)
+}
+
 func main() {
 	log.SetFlags(0)
+	configFile := flag.String("config", defaultConfigFile(), "read defaults from the configuration `file`")
+	baseURL := flag.String("base-url", "", "base `URL` of the deps.dev API; overrides $INSIGHT_BASE_URL")
+	flag.StringVar(&outputFormat, "o", outputFormat, "output `format`: text, json, csv for lists, or github for audit and check; overrides $INSIGHT_FORMAT")
+	cacheDir := flag.String("cache-dir", "", "cache API responses in `dir`; overrides $INSIGHT_CACHE_DIR")
+	noCache := flag.Bool("no-cache", false, "do not use cached API responses; overrides $INSIGHT_NO_CACHE")
+	maxAge := flag.Duration("max-age", insights.DefaultCacheMaxAge, "maximum `age` of cached API responses")
+	offline := flag.Bool("offline", false, "use only cached API responses, never the network")
+	timeout := flag.Duration("timeout", 30*time.Second, "`timeout` of each attempt at an API request; 0 means none")
+	retries := flag.Int("retries", 2, "number of `times` to retry failed API requests")
+	tlsCert := flag.String("tls-cert", "", "present the client certificate in PEM `file` to servers requiring mutual TLS")
+	tlsKey := flag.String("tls-key", "", "private key of the client certificate, in PEM `file`")
+	tlsCA := flag.String("tls-ca", "", "also trust the certificate authorities in PEM `file`")
+	registryFallback := flag.Bool("registry-fallback", false, "ask the npm, PyPI, crates.io, and Maven Central registries for packages and metadata deps.dev lacks")
+	quiet := flag.Bool("q", false, "quiet: print only results, no diagnostics")
+	verbose := flag.Bool("v", false, "verbose: log API requests")
+	veryVerbose := flag.Bool("vv", false, "very verbose: also log cache hits and retries")
+	flag.StringVar(&defaultSystem, "system", "", "package management `system` assumed when a command's system argument is omitted; overrides $INSIGHT_SYSTEM")
+	flag.IntVar(&concurrency, "concurrency", concurrency, "maximum `number` of concurrent API requests")
+	python := flag.String("python", "", "leave out Python requirements whose environment markers do not hold for this Python `version`")
+	pythonPlatform := flag.String("python-platform", "linux", "sys.platform `value` of the Python environment given by -python")
+	flag.Usage = usage
 	flag.Parse()
 
 	if flag.NArg() == 0 {
-		fmt.Fprintln(os.Stderr, "Usage: x command [args]")
-		os.Exit(1)
+		usage()
+	}
+
+	// Flags given on the command line override the configuration file.
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	// The configuration file init is to write need not exist.
+	cfg, err := loadConfig(*configFile, set["config"] && flag.Arg(0) != "init")
+	if err != nil {
+		fatal(err)
+	}
+	// Environment variables in turn override the configuration file.
+	// INSIGHT_BASE_URL is honored by insights.NewClient.
+	if !set["base-url"] && os.Getenv("INSIGHT_BASE_URL") == "" {
+		*baseURL = cfg.BaseURL
+	}
+	if !set["o"] {
+		if v := os.Getenv("INSIGHT_FORMAT"); v != "" {
+			outputFormat = v
+		} else if cfg.Format != "" {
+			outputFormat = cfg.Format
+		}
 	}
 
+	if !set["cache-dir"] {
+		if *cacheDir = os.Getenv("INSIGHT_CACHE_DIR"); *cacheDir == "" {
+			*cacheDir = cfg.CacheDir
+		}
+	}
+	if !set["no-cache"] && os.Getenv("INSIGHT_NO_CACHE") != "" {
+		*noCache = true
+	}
+	if !set["max-age"] && cfg.MaxAge != "" {
+		if *maxAge, err = time.ParseDuration(cfg.MaxAge); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid max_age in configuration: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+	if !set["system"] {
+		if defaultSystem = os.Getenv("INSIGHT_SYSTEM"); defaultSystem == "" {
+			defaultSystem = cfg.System
+		}
+	}
+	if defaultSystem != "" {
+		s, ok := canonicalSystem(defaultSystem)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown package management system %q\n", defaultSystem)
+			os.Exit(exitUsage)
+		}
+		defaultSystem = s
+	}
+	if !set["concurrency"] && cfg.Concurrency > 0 {
+		concurrency = cfg.Concurrency
+	}
+	if *python != "" {
+		pythonEnv = marker.NewEnvironment(*python, *pythonPlatform)
+	}
+	if concurrency < 1 {
+		fmt.Fprintln(os.Stderr, "-concurrency must be at least 1")
+		os.Exit(exitUsage)
+	}
+	if *offline && (*noCache || *cacheDir == "") {
+		fmt.Fprintln(os.Stderr, "-offline requires a cache directory")
+		os.Exit(exitUsage)
+	}
+
+	if *quiet && (*verbose || *veryVerbose) {
+		fmt.Fprintln(os.Stderr, "-q cannot be combined with -v or -vv")
+		os.Exit(exitUsage)
+	}
+	var logger *slog.Logger
+	switch {
+	case *quiet:
+		log.SetOutput(io.Discard)
+	case *veryVerbose:
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	case *verbose:
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	}
+
+	// Up to concurrency requests are in flight at once, so as many
+	// connections are kept open.
+	topts := &insights.TransportOptions{MaxIdleConnsPerHost: concurrency}
+	if !set["tls-cert"] {
+		*tlsCert = cfg.TLSCert
+	}
+	if !set["tls-key"] {
+		*tlsKey = cfg.TLSKey
+	}
+	if !set["tls-ca"] {
+		*tlsCA = cfg.TLSCA
+	}
+	if *tlsCert != "" {
+		// The key may be in the certificate file.
+		cert, err := insights.LoadClientCertificate(*tlsCert, cmp.Or(*tlsKey, *tlsCert))
+		if err != nil {
+			fatal(err)
+		}
+		topts.Certificates = []tls.Certificate{cert}
+	}
+	if *tlsCA != "" {
+		if topts.RootCAs, err = insights.LoadCertPool(*tlsCA); err != nil {
+			fatal(err)
+		}
+	}
+	httpClient = &http.Client{Timeout: *timeout, Transport: insights.NewTransport(topts)}
+	if *retries > 0 {
+		retryPolicy = &insights.RetryPolicy{MaxRetries: *retries}
+	}
+
+	ctx := context.Background()
 	client := insights.NewClient()
+	// The cache NewClient sets up from the environment is replaced, as the
+	// flags and the configuration file take part in choosing it too.
+	client.Cache = nil
+	if *cacheDir != "" && !*noCache {
+		cache := &insights.DiskCache{Dir: *cacheDir, MaxAge: *maxAge}
+		if *offline {
+			// Anything cached is better than nothing.
+			cache.MaxAge = 0
+		}
+		client.Cache = cache
+	}
+	client.Offline = *offline
+	client.Logger = logger
+	client.HTTPClient = httpClient
+	client.Retry = retryPolicy
+	if (*registryFallback || (cfg.RegistryFallback && !set["registry-fallback"])) && !*offline {
+		client.Fallback = newRegistryClient()
+	}
+	if *baseURL != "" {
+		u, err := url.Parse(*baseURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid base URL: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		// The API paths are resolved relative to the base URL.
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		client.BaseURL = u
+	}
 
-	switch cmd := flag.Arg(0); cmd {
+	args := flag.Args()
+	if c := lookupCommand(args[0]); c != nil && c.system && len(c.flags) == 0 {
+		args = append(args[:1:1], systemArgs(args[1:])...)
+	}
+
+	switch cmd := args[0]; cmd {
 	case "package":
-		if flag.NArg() < 3 {
+		if len(args) < 3 {
 			fmt.Fprintln(os.Stderr, "usage: x package system name")
-			os.Exit(1)
+			os.Exit(exitUsage)
 		}
-		system := flag.Arg(1)
-		name := flag.Arg(2)
-		if err := doPackage(client, system, name); err != nil {
-			log.Fatal(err)
+		system := args[1]
+		name := args[2]
+		if err := doPackage(ctx, client, system, name); err != nil {
+			fatal(err)
 		}
 	case "version":
-		if flag.NArg() < 4 {
+		if len(args) < 4 {
 			fmt.Fprintln(os.Stderr, "usage: x version system name version")
-			os.Exit(1)
+			os.Exit(exitUsage)
 		}
-		system := flag.Arg(1)
-		name := flag.Arg(2)
-		version := flag.Arg(3)
-		if err := doVersion(client, system, name, version); err != nil {
-			log.Fatal(err)
+		system := args[1]
+		name := args[2]
+		version := args[3]
+		if err := doVersion(ctx, client, system, name, version); err != nil {
+			fatal(err)
 		}
 	case "dependencies":
-		if flag.NArg() < 4 {
+		if len(args) < 4 {
 			fmt.Fprintln(os.Stderr, "usage: x dependencies system name version")
-			os.Exit(1)
+			os.Exit(exitUsage)
+		}
+		system := args[1]
+		name := args[2]
+		version := args[3]
+		d, _, err := client.GetDependencies(ctx, system, name, version)
+		if err != nil {
+			fatal(err)
+		}
+		var rows [][]string
+		for _, n := range d.Nodes {
+			k := n.VersionKey
+			rows = append(rows, []string{k.System, k.Name, k.Version, n.Relation, strconv.FormatBool(n.Bundled), strings.Join(n.Errors, "; ")})
+		}
+		header := []string{"system", "name", "version", "relation", "bundled", "errors"}
+		if err := printList(d, header, rows, func() { fmt.Println(*d) }); err != nil {
+			fatal(err)
+		}
+	case "dependents":
+		if len(args) < 4 {
+			fmt.Fprintln(os.Stderr, "usage: x dependents system name version")
+			os.Exit(exitUsage)
+		}
+		system := args[1]
+		name := args[2]
+		version := args[3]
+		if err := doDependents(ctx, client, system, name, version); err != nil {
+			fatal(err)
+		}
+	case "stats":
+		if len(args) < 4 {
+			fmt.Fprintln(os.Stderr, "usage: x stats system name version")
+			os.Exit(exitUsage)
+		}
+		if err := doStats(ctx, client, args[1], args[2], args[3]); err != nil {
+			fatal(err)
+		}
+	case "size":
+		if len(args) < 4 {
+			fmt.Fprintln(os.Stderr, "usage: x size system name version")
+			os.Exit(exitUsage)
+		}
+		if err := doSize(ctx, client, args[1], args[2], args[3]); err != nil {
+			fatal(err)
+		}
+	case "why":
+		if len(args) < 5 {
+			fmt.Fprintln(os.Stderr, "usage: x why system name version target-package")
+			os.Exit(exitUsage)
+		}
+		system := args[1]
+		name := args[2]
+		version := args[3]
+		target := args[4]
+		if err := doWhy(ctx, client, system, name, version, target); err != nil {
+			fatal(err)
 		}
-		system := flag.Arg(1)
-		name := flag.Arg(2)
-		version := flag.Arg(3)
-		d, err := client.GetDependencies(insights.VersionKey{System: system, Name: name, Version: version})
+	case "lookup":
+		fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+		all := fs.Bool("all-systems", false, "look up the name in every system")
+		fs.Parse(args[1:])
+		fargs := fs.Args()
+		if !*all {
+			fargs = systemArgs(fargs)
+		}
+		// Without a system, the name is looked up in all of them.
+		var system, name string
+		switch {
+		case len(fargs) == 1:
+			name = fargs[0]
+		case len(fargs) == 2 && !*all:
+			system, name = fargs[0], fargs[1]
+		default:
+			fmt.Fprintln(os.Stderr, "usage: x lookup [-all-systems] [system] name")
+			os.Exit(exitUsage)
+		}
+		if err := doLookup(ctx, client, system, name); err != nil {
+			fatal(err)
+		}
+	case "similar":
+		if len(args) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: x similar system name")
+			os.Exit(exitUsage)
+		}
+		system := args[1]
+		name := args[2]
+		if err := doSimilar(ctx, client, system, name); err != nil {
+			fatal(err)
+		}
+	case "affected":
+		if len(args) < 4 {
+			fmt.Fprintln(os.Stderr, "usage: x affected system name advisory")
+			os.Exit(exitUsage)
+		}
+		if err := doAffected(ctx, client, args[1], args[2], args[3]); err != nil {
+			fatal(err)
+		}
+	case "verify":
+		fs := flag.NewFlagSet("verify", flag.ExitOnError)
+		local := fs.Bool("local", false, "also verify the Sigstore bundles locally, with insight-sigverify (npm only)")
+		fs.Parse(args[1:])
+		fargs := systemArgs(fs.Args())
+		if len(fargs) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: x verify [-local] system name version")
+			os.Exit(exitUsage)
+		}
+		if err := doVerify(ctx, client, fargs[0], fargs[1], fargs[2], *local); err != nil {
+			fatal(err)
+		}
+	case "download-url":
+		if len(args) < 4 {
+			fmt.Fprintln(os.Stderr, "usage: x download-url system name version")
+			os.Exit(exitUsage)
+		}
+		u, err := registry.DownloadURL(args[1], args[2], args[3], "")
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(u)
+	case "identify":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: x identify file")
+			os.Exit(exitUsage)
+		}
+		if err := doIdentify(ctx, client, args[1]); err != nil {
+			fatal(err)
+		}
+	case "open":
+		fs := flag.NewFlagSet("open", flag.ExitOnError)
+		printURL := fs.Bool("print", false, "print the URL instead of opening it")
+		fs.Parse(args[1:])
+		fargs := systemArgs(fs.Args())
+		if len(fargs) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: x open [-print] system name [version]")
+			os.Exit(exitUsage)
+		}
+		system := fargs[0]
+		name := fargs[1]
+		var version string
+		if len(fargs) > 2 {
+			version = fargs[2]
+		}
+		if err := doOpen(ctx, client, system, name, version, *printURL); err != nil {
+			fatal(err)
+		}
+	case "watch":
+		fs := flag.NewFlagSet("watch", flag.ExitOnError)
+		file := fs.String("f", "watchlist.yaml", "watchlist `file`")
+		interval := fs.Duration("interval", 0, "polling interval (overrides the watchlist)")
+		command := fs.String("exec", "", "`command` to run for each change")
+		history := fs.String("history", "", "record the adoption of the packages in `file` (overrides the watchlist)")
+		fs.Parse(args[1:])
+		// Polling needs fresh responses.
+		client.Cache = nil
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		if err := doWatch(ctx, client, *file, *interval, *command, *history); err != nil {
+			fatal(err)
+		}
+	case "feed":
+		fs := flag.NewFlagSet("feed", flag.ExitOnError)
+		file := fs.String("f", "watchlist.yaml", "watchlist `file`")
+		out := fs.String("o", "", "write the feed to `file` instead of the standard output")
+		state := fs.String("state", "", "keep when advisories were first seen in `file`, to date them across runs")
+		id := fs.String("id", "", "the `URL` the feed is published at, identifying it (default the watchlist file)")
+		since := fs.Duration("since", 30*24*time.Hour, "include the changes of the last `period`")
+		fs.Parse(args[1:])
+		if err := doFeed(ctx, client, *file, *out, *state, *id, *since); err != nil {
+			fatal(err)
+		}
+	case "trend":
+		fs := flag.NewFlagSet("trend", flag.ExitOnError)
+		file := fs.String("f", "watchlist.yaml", "watchlist `file`")
+		history := fs.String("history", "", "read the adoption of the packages from `file` (overrides the watchlist)")
+		since := fs.Duration("since", 0, "consider only the samples of the last `period`; 0 means all")
+		record := fs.Bool("record", false, "record the current adoption of the watched packages first")
+		fs.Parse(args[1:])
+		if *record {
+			// Samples need fresh responses.
+			client.Cache = nil
+		}
+		if err := doTrend(ctx, client, *file, *history, *since, *record); err != nil {
+			fatal(err)
+		}
+	case "audit":
+		fs := flag.NewFlagSet("audit", flag.ExitOnError)
+		checkRegistries := fs.Bool("registry", false, "ask the package registries whether versions are deprecated or yanked")
+		scoresFile := fs.String("criticality", "", "weight findings by the OpenSSF criticality scores in CSV `file` or URL")
+		fs.Parse(args[1:])
+		path := "."
+		if fs.NArg() > 0 {
+			path = fs.Arg(0)
+		}
+		vulnerable, err := doAudit(ctx, client, path, *checkRegistries, *scoresFile)
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
+		}
+		if vulnerable {
+			os.Exit(exitVulnerable)
+		}
+	case "search":
+		fs := flag.NewFlagSet("search", flag.ExitOnError)
+		n := fs.Int("n", 10, "maximum `number` of packages to list")
+		fs.Parse(args[1:])
+		fargs := systemArgs(fs.Args())
+		if len(fargs) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: x search [-n count] system term")
+			os.Exit(exitUsage)
+		}
+		if *n < 1 {
+			fmt.Fprintln(os.Stderr, "-n must be at least 1")
+			os.Exit(exitUsage)
+		}
+		if err := doSearch(ctx, fargs[0], fargs[1], *n); err != nil {
+			fatal(err)
+		}
+	case "namespace":
+		fs := flag.NewFlagSet("namespace", flag.ExitOnError)
+		n := fs.Int("n", 0, "maximum `number` of packages to list; 0 means all")
+		fs.Parse(args[1:])
+		fargs := systemArgs(fs.Args())
+		if len(fargs) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: x namespace [-n count] system namespace")
+			os.Exit(exitUsage)
+		}
+		if err := doNamespace(ctx, client, fargs[0], fargs[1], *n); err != nil {
+			fatal(err)
+		}
+	case "resolve":
+		if len(args) < 4 {
+			fmt.Fprintln(os.Stderr, "usage: x resolve system name requirement")
+			os.Exit(exitUsage)
+		}
+		if err := doResolve(ctx, client, args[1], args[2], args[3]); err != nil {
+			fatal(err)
+		}
+	case "outdated":
+		path := "."
+		if len(args) > 1 {
+			path = args[1]
+		}
+		if err := doOutdated(ctx, client, path); err != nil {
+			fatal(err)
+		}
+	case "license-report":
+		path := "."
+		if len(args) > 1 {
+			path = args[1]
+		}
+		if err := doLicenseReport(ctx, client, path); err != nil {
+			fatal(err)
+		}
+	case "license-worklist":
+		path := "."
+		if len(args) > 1 {
+			path = args[1]
+		}
+		if err := doLicenseWorklist(ctx, client, path); err != nil {
+			fatal(err)
+		}
+	case "fix":
+		fs := flag.NewFlagSet("fix", flag.ExitOnError)
+		ids := fs.String("advisory", "", "comma-separated `ids` of the advisories to clear (default all)")
+		fs.Parse(args[1:])
+		path := "."
+		if fs.NArg() > 0 {
+			path = fs.Arg(0)
+		}
+		var advisories []string
+		if *ids != "" {
+			advisories = strings.Split(*ids, ",")
+		}
+		if err := doFix(ctx, client, path, advisories); err != nil {
+			fatal(err)
+		}
+	case "what-if":
+		fs := flag.NewFlagSet("what-if", flag.ExitOnError)
+		path := fs.String("path", ".", "the project `dir`ectory or manifest")
+		fs.Parse(args[1:])
+		fargs := systemArgs(fs.Args())
+		if len(fargs) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: x what-if [-path dir] system name version")
+			os.Exit(exitUsage)
+		}
+		if err := doWhatIf(ctx, client, *path, fargs[0], fargs[1], fargs[2]); err != nil {
+			fatal(err)
+		}
+	case "graph":
+		fs := flag.NewFlagSet("graph", flag.ExitOnError)
+		out := fs.String("o", "", "write the graph to `file`, in the format of its extension: .svg, .png, or .dot")
+		var prune insights.PruneOptions
+		fs.IntVar(&prune.MaxDepth, "depth", 0, "draw only the dependencies at most `n` levels deep; 0 means all")
+		relations := fs.String("relation", "", "draw only the dependencies of the comma-separated `list` of relations, such as direct or indirect")
+		fs.BoolVar(&prune.ExcludeBundled, "no-bundled", false, "leave out bundled dependencies")
+		fs.Parse(args[1:])
+		fargs := systemArgs(fs.Args())
+		if len(fargs) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: x graph [-o file] [-depth n] [-relation list] [-no-bundled] system name version")
+			os.Exit(exitUsage)
+		}
+		if *relations != "" {
+			for _, r := range strings.Split(*relations, ",") {
+				prune.Relations = append(prune.Relations, strings.ToUpper(strings.TrimSpace(r)))
+			}
+		}
+		if err := doGraph(ctx, client, fargs[0], fargs[1], fargs[2], *out, &prune); err != nil {
+			fatal(err)
+		}
+	case "obscure":
+		fs := flag.NewFlagSet("obscure", flag.ExitOnError)
+		var opts analysis.ObscureOptions
+		fs.IntVar(&opts.MinDepth, "min-depth", 2, "consider dependencies at least `n` levels deep")
+		fs.IntVar(&opts.MinDependents, "min-dependents", 10, "flag dependencies with fewer than `n` dependents per level of depth")
+		fs.IntVar(&opts.MinStars, "min-stars", 10, "flag dependencies whose repository has fewer than `n` stars")
+		fs.Parse(args[1:])
+		fargs := systemArgs(fs.Args())
+		if len(fargs) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: x obscure [-min-depth n] [-min-dependents n] [-min-stars n] system name version")
+			os.Exit(exitUsage)
+		}
+		if err := doObscure(ctx, client, fargs[0], fargs[1], fargs[2], &opts); err != nil {
+			fatal(err)
+		}
+	case "diff-lockfile":
+		if len(args) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: x diff-lockfile old new")
+			os.Exit(exitUsage)
+		}
+		vulnerable, err := doDiffLockfile(ctx, client, args[1], args[2])
+		if err != nil {
+			fatal(err)
+		}
+		if vulnerable {
+			os.Exit(exitVulnerable)
+		}
+	case "badge":
+		fs := flag.NewFlagSet("badge", flag.ExitOnError)
+		metric := fs.String("metric", "vulnerabilities", "`metric` to show: vulnerabilities, scorecard, or freshness")
+		out := fs.String("o", "", "write the badge to `file`")
+		fs.Parse(args[1:])
+		if err := doBadge(ctx, client, *metric, *out, keyArgs(fs.Args())); err != nil {
+			fatal(err)
+		}
+	case "export":
+		if len(args) < 2 || args[1] != "sqlite" {
+			fmt.Fprintln(os.Stderr, "usage: x export sqlite [-out file] [path | system name [version]]")
+			os.Exit(exitUsage)
+		}
+		fs := flag.NewFlagSet("export sqlite", flag.ExitOnError)
+		out := fs.String("out", "", "write the database to `file`, or SQL statements if it ends in .sql (default the standard output, as SQL)")
+		fs.Parse(args[2:])
+		if err := doExportSQLite(ctx, client, *out, keyArgs(fs.Args())); err != nil {
+			fatal(err)
+		}
+	case "init":
+		fs := flag.NewFlagSet("init", flag.ExitOnError)
+		force := fs.Bool("f", false, "overwrite existing files")
+		fs.Parse(args[1:])
+		dir := "."
+		if fs.NArg() > 0 {
+			dir = fs.Arg(0)
+		}
+		if err := doInit(*configFile, dir, *force); err != nil {
+			fatal(err)
+		}
+	case "check":
+		fs := flag.NewFlagSet("check", flag.ExitOnError)
+		policyFile := fs.String("policy", cmp.Or(cfg.Policy, defaultPolicyFile), "read the policy from `file`")
+		fs.Parse(args[1:])
+		violated, err := doCheck(ctx, client, *policyFile, keyArgs(fs.Args()))
+		if err != nil {
+			fatal(err)
+		}
+		if violated {
+			os.Exit(exitPolicy)
+		}
+	case "report":
+		fs := flag.NewFlagSet("report", flag.ExitOnError)
+		format := fs.String("format", "md", "report `format`: md, html, or json")
+		out := fs.String("out", "", "write the report to `file`")
+		tree := fs.Bool("r", false, "scan the subdirectories of path as the subprojects of a monorepo")
+		fs.Parse(args[1:])
+		path := "."
+		if fs.NArg() > 0 {
+			path = fs.Arg(0)
+		}
+		if err := doReport(ctx, client, path, *format, *out, *tree); err != nil {
+			fatal(err)
 		}
-		fmt.Println(*d)
 	case "project":
-		if flag.NArg() < 2 {
+		if len(args) < 2 {
 			fmt.Fprintln(os.Stderr, "usage: x project id")
-			os.Exit(1)
+			os.Exit(exitUsage)
+		}
+		p, _, err := client.GetProject(ctx, args[1])
+		if err != nil {
+			fatal(err)
+		}
+		if err := printResult(p, func() { fmt.Println(*p) }); err != nil {
+			fatal(err)
+		}
+	case "project-packages":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: x project-packages id")
+			os.Exit(exitUsage)
+		}
+		if err := doProjectPackages(ctx, client, args[1]); err != nil {
+			fatal(err)
+		}
+	case "scorecard-history":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: x scorecard-history id [commit...]")
+			os.Exit(exitUsage)
 		}
-		p, err := client.GetProject(flag.Arg(1))
+		if err := doScorecardHistory(ctx, args[1], args[2:]); err != nil {
+			fatal(err)
+		}
+	case "project-systems":
+		fs := flag.NewFlagSet("project-systems", flag.ExitOnError)
+		n := fs.Int("n", 5, "maximum `number` of versions to show for each package")
+		fs.Parse(args[1:])
+		if fs.NArg() < 1 {
+			fmt.Fprintln(os.Stderr, "usage: x project-systems [-n count] id")
+			os.Exit(exitUsage)
+		}
+		if err := doProjectSystems(ctx, client, fs.Arg(0), *n); err != nil {
+			fatal(err)
+		}
+	case "schema":
+		fs := flag.NewFlagSet("schema", flag.ExitOnError)
+		dir := fs.String("dir", "", "write the schemas of all outputs to files in `dir`")
+		fs.Parse(args[1:])
+		if fs.NArg() > 1 || fs.NArg() == 1 && *dir != "" {
+			fmt.Fprintln(os.Stderr, "usage: x schema [-dir dir] [output]")
+			os.Exit(exitUsage)
+		}
+		if err := doSchema(fs.Arg(0), *dir); err != nil {
+			fatal(err)
+		}
+	case "prune-cache":
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "usage: x prune-cache")
+			os.Exit(exitUsage)
+		}
+		if *cacheDir == "" {
+			fmt.Fprintln(os.Stderr, "x prune-cache: no cache directory; set -cache-dir or $INSIGHT_CACHE_DIR")
+			os.Exit(exitUsage)
+		}
+		n, err := (&insights.DiskCache{Dir: *cacheDir, MaxAge: *maxAge}).Prune()
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
+		}
+		log.Printf("removed %d cached responses", n)
+	case "completion":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "usage: x completion bash|zsh|fish")
+			os.Exit(exitUsage)
+		}
+		if err := doCompletion(os.Stdout, args[1]); err != nil {
+			fatal(err)
 		}
-		fmt.Println(*p)
+	default:
+		fmt.Fprintf(os.Stderr, "x: unknown command %q\n", cmd)
+		usage()
 	}
 
 }
@@ -0,0 +1,169 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/franoliveto/insights"
+)
+
+func TestHomepage(t *testing.T) {
+	tests := []struct {
+		name, version, want string
+	}{
+		{
+			name:    "homepage first",
+			version: `{"links":[{"label":"SOURCE_REPO","url":"https://github.com/x/a"},{"label":"HOMEPAGE","url":"https://a.dev"},{"label":"HOMEPAGE","url":"https://b.dev"}]}`,
+			want:    "https://a.dev",
+		},
+		{
+			name:    "source repository link",
+			version: `{"links":[{"label":"ISSUE_TRACKER","url":"https://github.com/x/a/issues"},{"label":"SOURCE_REPO","url":"https://github.com/x/a"}]}`,
+			want:    "https://github.com/x/a",
+		},
+		{
+			name:    "source repository project",
+			version: `{"relatedProjects":[{"projectKey":{"id":"github.com/x/b"},"relationType":"ISSUE_TRACKER"},{"projectKey":{"id":"github.com/x/a"},"relationType":"SOURCE_REPO"}]}`,
+			want:    "https://github.com/x/a",
+		},
+		{
+			name:    "none",
+			version: `{"links":[{"label":"DOCUMENTATION","url":"https://docs.a.dev"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		var v insights.Version
+		if err := json.Unmarshal([]byte(tt.version), &v); err != nil {
+			t.Fatal(err)
+		}
+		if got := homepage(&v); got != tt.want {
+			t.Errorf("%s: homepage = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBrowseScheme(t *testing.T) {
+	// Only links that are refused are tried, so that no browser is started.
+	for _, link := range []string{
+		"file:///etc/passwd",
+		"javascript:alert(1)",
+		"vscode://file/tmp/x",
+		"smb://host/share",
+		"//example.com/a",
+		"example.com",
+		"http://[::1",
+	} {
+		if err := browse(link); err == nil {
+			t.Errorf("browse(%q) succeeded; want an error", link)
+		}
+	}
+}
+
+func TestRepoID(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"https://github.com/User/Repo", "github.com/user/repo"},
+		{"git+https://github.com/user/repo.git", "github.com/user/repo"},
+		{"git://github.com/user/repo.git", "github.com/user/repo"},
+		{"git+ssh://git@github.com/user/repo.git", "github.com/user/repo"},
+		{"git@github.com:user/repo.git", "github.com/user/repo"},
+		{"https://gitlab.com/group/project/", "gitlab.com/group/project"},
+		{"github.com/user/repo", "github.com/user/repo"},
+	}
+	for _, tt := range tests {
+		if got := repoID(tt.url); got != tt.want {
+			t.Errorf("repoID(%q) = %q; want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestDoVerify(t *testing.T) {
+	client, mux := setup(t)
+	handle := func(name, version string) {
+		mux.HandleFunc("/systems/npm/packages/"+name+"/versions/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, version)
+		})
+	}
+	const linked = `"relatedProjects":[{"projectKey":{"id":"github.com/x/a"},"relationType":"SOURCE_REPO"}]`
+	handle("verified", `{"attestations":[{"type":"https://slsa.dev/provenance/v1","verified":true,"sourceRepository":"https://github.com/X/a","commit":"abc"}],`+linked+`}`)
+	handle("unverified", `{"attestations":[{"type":"https://slsa.dev/provenance/v1","verified":false,"sourceRepository":"https://github.com/x/a"}],`+linked+`}`)
+	handle("other-repo", `{"attestations":[{"type":"https://slsa.dev/provenance/v1","verified":true,"sourceRepository":"https://github.com/y/a"}],`+linked+`}`)
+	handle("unlinked", `{"attestations":[{"type":"https://slsa.dev/provenance/v1","verified":true,"sourceRepository":"https://github.com/x/a"}]}`)
+	handle("slsa", `{"slsaProvenances":[{"sourceRepository":"https://github.com/x/a","verified":true}],`+linked+`}`)
+	handle("none", `{`+linked+`}`)
+
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"verified", false},
+		{"unverified", true},
+		{"other-repo", true},
+		{"unlinked", false},
+		{"slsa", false},
+		{"none", true},
+	}
+	for _, tt := range tests {
+		err := doVerify(context.Background(), client, "npm", tt.name, "1.0.0", false)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("doVerify(%s) returned error %v; want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Local verification runs sigverifyCommand with the package version and
+	// the repository it is linked to, or else the one it claims.
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake insight-sigverify is a shell script")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "args")
+	script := filepath.Join(dir, "insight-sigverify")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> "+out+"\n[ \"$1\" != unlinked ]\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := sigverifyCommand
+	sigverifyCommand = script
+	t.Cleanup(func() { sigverifyCommand = old })
+
+	if err := doVerify(context.Background(), client, "npm", "verified", "1.0.0", true); err != nil {
+		t.Errorf("doVerify -local failed: %v", err)
+	}
+	if err := doVerify(context.Background(), client, "npm", "unlinked", "1.0.0", true); err == nil {
+		t.Error("doVerify -local succeeded despite insight-sigverify failing")
+	}
+	if err := doVerify(context.Background(), client, "npm", "unverified", "1.0.0", true); err == nil {
+		t.Error("doVerify -local of an unverified attestation succeeded")
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "verified 1.0.0 github.com/x/a\nunlinked 1.0.0 github.com/x/a\n"
+	if string(got) != want {
+		t.Errorf("insight-sigverify run with %q; want %q", got, want)
+	}
+}
+
+func TestVerifyLocallyUnsupported(t *testing.T) {
+	if err := verifyLocally(context.Background(), "pypi", "a", "1.0.0", "github.com/x/a"); err == nil {
+		t.Error("verifyLocally of a PyPI package succeeded")
+	}
+	old := sigverifyCommand
+	sigverifyCommand = filepath.Join(t.TempDir(), "insight-sigverify")
+	t.Cleanup(func() { sigverifyCommand = old })
+	if err := verifyLocally(context.Background(), "npm", "a", "1.0.0", "github.com/x/a"); err == nil || !strings.Contains(err.Error(), "go install") {
+		t.Errorf("verifyLocally without insight-sigverify returned error %v; want one telling how to install it", err)
+	}
+}
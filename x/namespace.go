@@ -0,0 +1,58 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/scan"
+)
+
+// doNamespace prints up to n packages published under namespace, an npm
+// scope or a Maven group ID, with what deps.dev knows about their latest
+// versions, so that an organization can audit everything it publishes.
+func doNamespace(ctx context.Context, c *insights.Client, system, namespace string, n int) error {
+	pkgs, err := newRegistryClient().Namespace(ctx, system, namespace, n)
+	if err != nil {
+		return err
+	}
+	var deps []scan.Dependency
+	for _, p := range pkgs {
+		deps = append(deps, scan.Dependency{VersionKey: insights.VersionKey{System: p.System, Name: p.Name, Version: p.Version}, Direct: true})
+	}
+	r, err := audit.Run(ctx, c, deps, &audit.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+
+	header := []string{"name", "version", "licenses", "advisories", "scorecard", "attested"}
+	var rows [][]string
+	for _, p := range r.Packages {
+		k := p.Dependency.VersionKey
+		var ids []string
+		for _, a := range p.Advisories {
+			ids = append(ids, a.AdvisoryKey.ID)
+		}
+		score := ""
+		if p.Scorecard != nil {
+			score = fmt.Sprintf("%.1f", p.Scorecard.OverallScore)
+		}
+		rows = append(rows, []string{k.Name, k.Version, strings.Join(p.Licenses, " "), strings.Join(ids, " "), score, strconv.FormatBool(p.Provenance.Attested)})
+	}
+	perr := printList(r.Packages, header, rows, func() {
+		if len(rows) == 0 {
+			fmt.Printf("no packages found under %s\n", namespace)
+			return
+		}
+		printTable(header, rows)
+	})
+	return cmp.Or(perr, err)
+}
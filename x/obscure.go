@@ -0,0 +1,48 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doObscure prints the deep, little used dependencies of the given package
+// version.
+func doObscure(ctx context.Context, c *insights.Client, system, name, version string, opts *analysis.ObscureOptions) error {
+	g, _, err := c.GetDependencies(ctx, system, name, version)
+	if err != nil {
+		return err
+	}
+	opts.Concurrency = concurrency
+	deps, err := analysis.Obscure(ctx, c, g, opts)
+	if err != nil && !partial(err) {
+		return err
+	}
+
+	header := []string{"system", "name", "version", "depth", "dependents", "repository", "stars"}
+	var rows [][]string
+	for _, d := range deps {
+		k := d.Package
+		rows = append(rows, []string{k.System, k.Name, k.Version, strconv.Itoa(d.Depth), strconv.Itoa(d.Dependents), d.SourceRepository, strconv.Itoa(d.Stars)})
+	}
+	perr := printList(deps, header, rows, func() {
+		if len(deps) == 0 && err == nil {
+			fmt.Printf("%s@%s: no obscure dependencies\n", name, version)
+			return
+		}
+		table := make([][]string, len(rows))
+		for i, r := range rows {
+			table[i] = r[1:]
+		}
+		printTable(header[1:], table)
+	})
+	return cmp.Or(perr, err)
+}
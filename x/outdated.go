@@ -0,0 +1,137 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/scan"
+	"github.com/franoliveto/insights/version"
+)
+
+// outdated describes the newer versions available for a dependency: the
+// greatest release with the same major and minor version, the greatest with
+// the same major version, and the greatest with a greater major version.
+// A field is empty if there is no such version.
+type outdated struct {
+	System  string `json:"system"`
+	Name    string `json:"name"`
+	Current string `json:"current"`
+	Patch   string `json:"patch,omitempty"`
+	Minor   string `json:"minor,omitempty"`
+	Major   string `json:"major,omitempty"`
+}
+
+// updates returns the newer versions of the package version k available
+// among the versions of p. Pre-releases are ignored.
+func updates(k insights.VersionKey, p *insights.Package) (*outdated, error) {
+	cur, err := version.Parse(k.System, k.Version)
+	if err != nil {
+		return nil, err
+	}
+	o := &outdated{System: k.System, Name: k.Name, Current: k.Version}
+	var patch, minor, major *version.Version
+	for _, pv := range p.Versions {
+		v, err := version.Parse(k.System, pv.VersionKey.Version)
+		if err != nil || v.Prerelease() || v.Compare(cur) <= 0 {
+			continue
+		}
+		switch {
+		case v.Major() != cur.Major():
+			if major == nil || v.Compare(major) > 0 {
+				major = v
+			}
+		case v.Minor() != cur.Minor():
+			if minor == nil || v.Compare(minor) > 0 {
+				minor = v
+			}
+		default:
+			if patch == nil || v.Compare(patch) > 0 {
+				patch = v
+			}
+		}
+	}
+	for _, u := range []struct {
+		v   *version.Version
+		dst *string
+	}{{patch, &o.Patch}, {minor, &o.Minor}, {major, &o.Major}} {
+		if u.v != nil {
+			*u.dst = u.v.String()
+		}
+	}
+	return o, nil
+}
+
+// doOutdated prints the direct dependencies of the project at path for
+// which newer versions are available.
+func doOutdated(ctx context.Context, c *insights.Client, path string) error {
+	deps, err := scanPath(path)
+	if err != nil {
+		return err
+	}
+	var direct []scan.Dependency
+	for _, d := range deps {
+		if d.Direct {
+			direct = append(direct, d)
+		}
+	}
+
+	results := make([]*outdated, len(direct))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, d := range direct {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			k := d.VersionKey
+			p, _, err := c.GetPackage(ctx, k.System, k.Name)
+			if err == nil {
+				results[i], err = updates(k, p)
+			}
+			if err != nil {
+				log.Printf("%s@%s: %v", k.Name, k.Version, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var out []*outdated
+	for _, o := range results {
+		if o != nil && (o.Patch != "" || o.Minor != "" || o.Major != "") {
+			out = append(out, o)
+		}
+	}
+	header := []string{"system", "name", "current", "patch", "minor", "major"}
+	var rows [][]string
+	for _, o := range out {
+		rows = append(rows, []string{o.System, o.Name, o.Current, o.Patch, o.Minor, o.Major})
+	}
+	return printList(out, header, rows, func() {
+		if len(out) == 0 {
+			fmt.Printf("%s: all %d direct dependencies are up to date\n", path, len(direct))
+			return
+		}
+		dash := func(s string) string {
+			if s == "" {
+				return "-"
+			}
+			return s
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCURRENT\tPATCH\tMINOR\tMAJOR")
+		for _, o := range out {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", o.Name, o.Current, dash(o.Patch), dash(o.Minor), dash(o.Major))
+		}
+		w.Flush()
+	})
+}
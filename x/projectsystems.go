@@ -0,0 +1,74 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doProjectSystems prints side by side the latest n versions of each
+// package published from the project id, with their advisories.
+func doProjectSystems(ctx context.Context, c *insights.Client, id string, n int) error {
+	pkgs, err := analysis.ProjectPackages(ctx, c, id, n, &analysis.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+
+	header := []string{"system", "name", "version", "advisories", "error"}
+	var rows [][]string
+	for _, p := range pkgs {
+		for _, v := range p.Versions {
+			rows = append(rows, []string{p.System, p.Name, v.Version, strings.Join(v.Advisories, " "), v.Error})
+		}
+	}
+	perr := printList(pkgs, header, rows, func() {
+		if len(pkgs) == 0 && err == nil {
+			fmt.Printf("no packages known to be built from %s\n", id)
+			return
+		}
+		// One column per package, its versions from newest to oldest.
+		var cols []string
+		depth := 0
+		for _, p := range pkgs {
+			cols = append(cols, p.System+" "+p.Name)
+			depth = max(depth, len(p.Versions))
+		}
+		var table [][]string
+		for i := 0; i < depth; i++ {
+			row := make([]string, len(pkgs))
+			for j, p := range pkgs {
+				if i >= len(p.Versions) {
+					continue
+				}
+				v := p.Versions[i]
+				switch {
+				case v.Error != "":
+					row[j] = v.Version + " (unknown)"
+				case len(v.Advisories) > 0:
+					row[j] = v.Version + " (" + strings.Join(v.Advisories, ", ") + ")"
+				default:
+					row[j] = v.Version
+				}
+			}
+			table = append(table, row)
+		}
+		// Not printTable, which would upper-case the package names.
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(cols, "\t"))
+		for _, row := range table {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		w.Flush()
+	})
+	return cmp.Or(perr, err)
+}
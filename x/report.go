@@ -0,0 +1,410 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/audit"
+	"github.com/franoliveto/insights/scan"
+)
+
+// scanPath returns the dependencies declared in path, which may be a
+// manifest or lockfile, or a directory containing them.
+func scanPath(path string) ([]scan.Dependency, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var deps []scan.Dependency
+	if fi.IsDir() {
+		deps, err = scan.Dir(path)
+	} else {
+		deps, err = scan.File(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pythonEnv != nil {
+		deps = scan.Select(deps, pythonEnv)
+	}
+	if fi.IsDir() && len(deps) == 0 {
+		return nil, fmt.Errorf("%s: no dependencies found", path)
+	}
+	return deps, nil
+}
+
+// dependenciesOf returns the dependencies of the project at args[0], or of
+// the package version args[0] args[1] args[2], with what names them. The
+// package version defaults to the default version, and the project to the
+// current directory. The dependency graph of a package version is also
+// returned; it is nil for a project.
+func dependenciesOf(ctx context.Context, c *insights.Client, args []string) (deps []scan.Dependency, graph *insights.Dependencies, what string, err error) {
+	if len(args) < 2 {
+		what = "."
+		if len(args) == 1 {
+			what = args[0]
+		}
+		deps, err = scanPath(what)
+		return deps, nil, what, err
+	}
+	system, name := args[0], args[1]
+	var v string
+	if len(args) > 2 {
+		v = args[2]
+	} else if v, err = defaultVersion(ctx, c, system, name); err != nil {
+		return nil, nil, "", err
+	}
+	if graph, _, err = c.GetDependencies(ctx, system, name, v); err != nil {
+		return nil, nil, "", err
+	}
+	seen := make(map[insights.VersionKey]bool)
+	for _, n := range graph.Nodes {
+		if !seen[n.VersionKey] {
+			seen[n.VersionKey] = true
+			deps = append(deps, scan.Dependency{VersionKey: n.VersionKey, Direct: n.Relation != "INDIRECT"})
+		}
+	}
+	return deps, graph, name + "@" + v, nil
+}
+
+// report is the data rendered by the report templates.
+type report struct {
+	Path      string
+	Generated time.Time
+	Direct    int
+	Result    *audit.Result
+	Summary   *audit.Summary
+
+	Vulnerabilities []vulnerability
+	Licenses        []licenseCount
+	Errors          []audit.Package
+
+	// The breakdown by subproject of a monorepo, if scanned as one.
+	Subprojects []subproject
+}
+
+// subproject summarizes the dependencies of one subproject of a monorepo.
+type subproject struct {
+	Dir          string
+	Dependencies int
+	Direct       int
+	Vulnerable   int
+	Advisories   []string
+}
+
+// subprojects returns the breakdown by subproject of deps, as found by
+// scan.Tree, given the audit r of their package versions.
+func subprojects(deps []scan.Dependency, r *audit.Result) []subproject {
+	audited := make(map[insights.VersionKey]*audit.Package)
+	for i, p := range r.Packages {
+		audited[p.Dependency.VersionKey] = &r.Packages[i]
+	}
+	var subs []subproject
+	index := make(map[string]int)
+	advisories := make(map[string]map[string]bool)
+	for _, d := range deps {
+		i, ok := index[d.Subproject]
+		if !ok {
+			i = len(subs)
+			index[d.Subproject] = i
+			subs = append(subs, subproject{Dir: d.Subproject})
+			advisories[d.Subproject] = make(map[string]bool)
+		}
+		s := &subs[i]
+		s.Dependencies++
+		if d.Direct {
+			s.Direct++
+		}
+		p := audited[d.VersionKey]
+		if p == nil || len(p.Advisories) == 0 {
+			continue
+		}
+		s.Vulnerable++
+		for _, a := range p.Advisories {
+			advisories[d.Subproject][a.AdvisoryKey.ID] = true
+		}
+	}
+	for i := range subs {
+		for id := range advisories[subs[i].Dir] {
+			subs[i].Advisories = append(subs[i].Advisories, id)
+		}
+		sort.Strings(subs[i].Advisories)
+	}
+	return subs
+}
+
+// mergeDependencies returns deps without repeated package versions, keeping
+// the first of each, which is direct if any of the repeated ones is.
+func mergeDependencies(deps []scan.Dependency) []scan.Dependency {
+	index := make(map[insights.VersionKey]int)
+	var out []scan.Dependency
+	for _, d := range deps {
+		if i, ok := index[d.VersionKey]; ok {
+			out[i].Direct = out[i].Direct || d.Direct
+			continue
+		}
+		index[d.VersionKey] = len(out)
+		out = append(out, d)
+	}
+	return out
+}
+
+type vulnerability struct {
+	Package  audit.Package
+	Advisory *insights.Advisory
+}
+
+// generated returns the time a report is generated at: the time given by
+// the SOURCE_DATE_EPOCH environment variable, if set, so that reports can be
+// reproduced byte for byte, or else the current time.
+func generated() time.Time {
+	if s := os.Getenv("SOURCE_DATE_EPOCH"); s != "" {
+		if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return time.Unix(sec, 0).UTC()
+		}
+	}
+	return time.Now().UTC()
+}
+
+type licenseCount struct {
+	License  string
+	Packages []string
+}
+
+func newReport(path string, r *audit.Result) *report {
+	rep := &report{Path: path, Generated: generated(), Result: r, Summary: r.Summary()}
+	licenses := make(map[string][]string)
+	for _, p := range r.Packages {
+		if p.Dependency.Direct {
+			rep.Direct++
+		}
+		if p.Error != "" {
+			rep.Errors = append(rep.Errors, p)
+			continue
+		}
+		for _, a := range p.Advisories {
+			rep.Vulnerabilities = append(rep.Vulnerabilities, vulnerability{p, a})
+		}
+		k := p.Dependency.VersionKey
+		name := k.Name + "@" + k.Version
+		if len(p.Licenses) == 0 {
+			licenses["unknown"] = append(licenses["unknown"], name)
+		}
+		for _, l := range p.Licenses {
+			licenses[l] = append(licenses[l], name)
+		}
+	}
+	// Most severe first.
+	sort.SliceStable(rep.Vulnerabilities, func(i, j int) bool {
+		a, b := rep.Vulnerabilities[i].Advisory, rep.Vulnerabilities[j].Advisory
+		if a.CVSS3Score != b.CVSS3Score {
+			return a.CVSS3Score > b.CVSS3Score
+		}
+		return a.AdvisoryKey.ID < b.AdvisoryKey.ID
+	})
+	for l, pkgs := range licenses {
+		rep.Licenses = append(rep.Licenses, licenseCount{l, pkgs})
+	}
+	sort.Slice(rep.Licenses, func(i, j int) bool {
+		a, b := rep.Licenses[i], rep.Licenses[j]
+		if len(a.Packages) != len(b.Packages) {
+			return len(a.Packages) > len(b.Packages)
+		}
+		return a.License < b.License
+	})
+	return rep
+}
+
+var reportFuncs = map[string]any{
+	"join": strings.Join,
+	"date": func(t time.Time) string { return t.Format(time.RFC1123) },
+}
+
+var markdownReport = template.Must(template.New("md").Funcs(reportFuncs).Parse(`# Dependency report for {{.Path}}
+
+Generated {{date .Generated}}. {{len .Result.Packages}} dependencies, {{.Direct}} direct.
+{{if .Subprojects}}
+## Subprojects
+
+| Subproject | Dependencies | Direct | Vulnerable | Advisories |
+| --- | --- | --- | --- | --- |
+{{range .Subprojects}}| {{.Dir}} | {{.Dependencies}} | {{.Direct}} | {{.Vulnerable}} | {{join .Advisories ", "}} |
+{{end}}{{end}}
+## Vulnerabilities
+{{if .Vulnerabilities}}
+{{with .Summary}}{{.Critical}} critical, {{.High}} high, {{.Medium}} medium, {{.Low}} low, and {{.Unknown}} unrated findings; {{.Direct}} in direct dependencies.{{end}}
+
+| Package | Version | Advisory | CVSS | Title |
+| --- | --- | --- | --- | --- |
+{{range .Vulnerabilities}}| {{.Package.Dependency.VersionKey.Name}} | {{.Package.Dependency.VersionKey.Version}} | [{{.Advisory.AdvisoryKey.ID}}]({{.Advisory.URL}}) | {{.Advisory.CVSS3Score}} | {{.Advisory.Title}} |
+{{end}}{{else}}
+No known vulnerabilities.
+{{end}}
+## Licenses
+
+| License | Count | Packages |
+| --- | --- | --- |
+{{range .Licenses}}| {{.License}} | {{len .Packages}} | {{join .Packages ", "}} |
+{{end}}
+## Scorecards
+
+| Package | Version | Repository | Score |
+| --- | --- | --- | --- |
+{{range .Result.Packages}}{{if .Scorecard}}| {{.Dependency.VersionKey.Name}} | {{.Dependency.VersionKey.Version}} | {{.SourceRepository}} | {{.Scorecard.OverallScore}} |
+{{end}}{{end}}
+## Provenance
+
+| Package | Version | Attested | Verified | Built from |
+| --- | --- | --- | --- | --- |
+{{range .Result.Packages}}{{if not .Error}}| {{.Dependency.VersionKey.Name}} | {{.Dependency.VersionKey.Version}} | {{.Provenance.Attested}} | {{.Provenance.Verified}} | {{.Provenance.SourceRepository}} {{.Provenance.Commit}} |
+{{end}}{{end}}{{if .Errors}}
+## Errors
+
+{{range .Errors}}- {{.Dependency.VersionKey.Name}}@{{.Dependency.VersionKey.Version}}: {{.Error}}
+{{end}}{{end}}`))
+
+var htmlReport = htmltemplate.Must(htmltemplate.New("html").Funcs(reportFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Dependency report for {{.Path}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Dependency report for {{.Path}}</h1>
+<p>Generated {{date .Generated}}. {{len .Result.Packages}} dependencies, {{.Direct}} direct.</p>
+{{if .Subprojects}}
+<h2>Subprojects</h2>
+<table>
+<tr><th>Subproject</th><th>Dependencies</th><th>Direct</th><th>Vulnerable</th><th>Advisories</th></tr>
+{{range .Subprojects}}<tr><td>{{.Dir}}</td><td>{{.Dependencies}}</td><td>{{.Direct}}</td><td>{{.Vulnerable}}</td><td>{{join .Advisories ", "}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Vulnerabilities</h2>
+{{if .Vulnerabilities}}{{with .Summary}}<p>{{.Critical}} critical, {{.High}} high, {{.Medium}} medium, {{.Low}} low, and {{.Unknown}} unrated findings; {{.Direct}} in direct dependencies.</p>{{end}}
+<table>
+<tr><th>Package</th><th>Version</th><th>Advisory</th><th>CVSS</th><th>Title</th></tr>
+{{range .Vulnerabilities}}<tr><td>{{.Package.Dependency.VersionKey.Name}}</td><td>{{.Package.Dependency.VersionKey.Version}}</td><td><a href="{{.Advisory.URL}}">{{.Advisory.AdvisoryKey.ID}}</a></td><td>{{.Advisory.CVSS3Score}}</td><td>{{.Advisory.Title}}</td></tr>
+{{end}}</table>
+{{else}}<p>No known vulnerabilities.</p>
+{{end}}
+<h2>Licenses</h2>
+<table>
+<tr><th>License</th><th>Count</th><th>Packages</th></tr>
+{{range .Licenses}}<tr><td>{{.License}}</td><td>{{len .Packages}}</td><td>{{join .Packages ", "}}</td></tr>
+{{end}}</table>
+
+<h2>Scorecards</h2>
+<table>
+<tr><th>Package</th><th>Version</th><th>Repository</th><th>Score</th></tr>
+{{range .Result.Packages}}{{if .Scorecard}}<tr><td>{{.Dependency.VersionKey.Name}}</td><td>{{.Dependency.VersionKey.Version}}</td><td>{{.SourceRepository}}</td><td>{{.Scorecard.OverallScore}}</td></tr>
+{{end}}{{end}}</table>
+
+<h2>Provenance</h2>
+<table>
+<tr><th>Package</th><th>Version</th><th>Attested</th><th>Verified</th><th>Built from</th></tr>
+{{range .Result.Packages}}{{if not .Error}}<tr><td>{{.Dependency.VersionKey.Name}}</td><td>{{.Dependency.VersionKey.Version}}</td><td>{{.Provenance.Attested}}</td><td>{{.Provenance.Verified}}</td><td>{{.Provenance.SourceRepository}} {{.Provenance.Commit}}</td></tr>
+{{end}}{{end}}</table>
+{{if .Errors}}
+<h2>Errors</h2>
+<ul>
+{{range .Errors}}<li>{{.Dependency.VersionKey.Name}}@{{.Dependency.VersionKey.Version}}: {{.Error}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+// jsonReport is a report in the json format.
+type jsonReport struct {
+	Path        string
+	Generated   time.Time
+	Packages    []audit.Package
+	Subprojects []subproject `json:",omitempty"`
+}
+
+func writeReport(w io.Writer, rep *report, format string) error {
+	switch format {
+	case "md":
+		return markdownReport.Execute(w, rep)
+	case "html":
+		return htmlReport.Execute(w, rep)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(jsonReport{rep.Path, rep.Generated, rep.Result.Packages, rep.Subprojects})
+	}
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+// doReport scans the project at path and writes a report about its
+// dependencies in the given format to out, or to standard output if out is
+// empty. If tree is set, path is scanned as a monorepo: the dependencies of
+// all its subprojects are audited together and the report breaks them down
+// by subproject.
+func doReport(ctx context.Context, c *insights.Client, path, format, out string, tree bool) error {
+	switch format {
+	case "md", "html", "json":
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+	var deps []scan.Dependency
+	var err error
+	if tree {
+		deps, err = scan.Tree(path)
+		if err == nil && len(deps) == 0 {
+			err = fmt.Errorf("%s: no dependencies found", path)
+		}
+	} else {
+		deps, err = scanPath(path)
+	}
+	if err != nil {
+		return err
+	}
+	// The packages that could not be audited are in the report with their
+	// errors, and the failed lookups reported once it is written.
+	r, err := audit.Run(ctx, c, mergeDependencies(deps), &audit.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+	rep := newReport(path, r)
+	if tree {
+		rep.Subprojects = subprojects(deps, r)
+	}
+	return cmp.Or(createReport(out, rep, format), err)
+}
+
+// createReport writes rep in the given format to the file out, or to
+// standard output if out is empty.
+func createReport(out string, rep *report, format string) error {
+	if out == "" {
+		return writeReport(os.Stdout, rep, format)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := writeReport(f, rep, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
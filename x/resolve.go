@@ -0,0 +1,41 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/version"
+)
+
+// doResolve prints the version of a package that requirement resolves to
+// among the versions known to deps.dev.
+func doResolve(ctx context.Context, c *insights.Client, system, name, requirement string) error {
+	constraint, err := version.ParseConstraint(system, requirement)
+	if err != nil {
+		return err
+	}
+	p, _, err := c.GetPackage(ctx, system, name)
+	if err != nil {
+		return err
+	}
+	var versions []*version.Version
+	for _, v := range p.Versions {
+		if pv, err := version.Parse(system, v.VersionKey.Version); err == nil {
+			versions = append(versions, pv)
+		}
+	}
+	v := constraint.Resolve(versions)
+	if v == nil {
+		return fmt.Errorf("no version of %s satisfies %q", name, requirement)
+	}
+	result := struct {
+		Requirement string `json:"requirement"`
+		Version     string `json:"version"`
+	}{requirement, v.String()}
+	return printResult(result, func() { fmt.Println(v) })
+}
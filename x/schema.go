@@ -0,0 +1,229 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+	"github.com/franoliveto/insights/audit"
+)
+
+// schemaTypes maps the names of the outputs that JSON Schemas are generated
+// for to the types printed as them: the API results and the JSON output
+// of the commands, named after them.
+var schemaTypes = map[string]reflect.Type{
+	"package":          reflect.TypeFor[insights.Package](),
+	"version":          reflect.TypeFor[insights.Version](),
+	"dependencies":     reflect.TypeFor[insights.Dependencies](),
+	"dependents":       reflect.TypeFor[insights.Dependents](),
+	"requirements":     reflect.TypeFor[insights.Requirements](),
+	"project":          reflect.TypeFor[insights.Project](),
+	"project-packages": reflect.TypeFor[insights.ProjectPackageVersions](),
+	"advisory":         reflect.TypeFor[insights.Advisory](),
+	"audit":            reflect.TypeFor[audit.Result](),
+	"report":           reflect.TypeFor[jsonReport](),
+	"check":            reflect.TypeFor[[]violation](),
+	"outdated":         reflect.TypeFor[[]*outdated](),
+	"diff-lockfile":    reflect.TypeFor[[]*lockChange](),
+	"license-worklist": reflect.TypeFor[[]analysis.LicenseItem](),
+	"license-report":   reflect.TypeFor[analysis.LicenseInventory](),
+	"stats":            reflect.TypeFor[analysis.GraphStats](),
+	"size":             reflect.TypeFor[analysis.InstallSize](),
+	"what-if":          reflect.TypeFor[analysis.Impact](),
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schemaGenerator generates a JSON Schema for the encoding of a Go type
+// by encoding/json, defining each named struct type once so that
+// recursive types can be described.
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// jsonSchema returns the JSON Schema, draft 2020-12, of the JSON encoding
+// of values of type t, titled name.
+func jsonSchema(name string, t reflect.Type) map[string]any {
+	g := &schemaGenerator{defs: make(map[string]any)}
+	s := g.schema(t)
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = name
+	if len(g.defs) > 0 {
+		s["$defs"] = g.defs
+	}
+	return s
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Anything goes.
+		return map[string]any{}
+	case t.Kind() != reflect.Pointer && (t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)):
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		return nullable(s)
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": []string{"string", "null"}, "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": []string{"array", "null"}, "items": g.schema(t.Elem())}
+	case reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		// Defined once, and referred to where used. The types of the
+		// command are named without their package.
+		name := strings.TrimPrefix(t.String(), "main.")
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // to stop recursion
+			g.defs[name] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	// Interfaces and others: anything goes.
+	return map[string]any{}
+}
+
+// nullable returns the schema s allowing null as well.
+func nullable(s map[string]any) map[string]any {
+	switch typ := s["type"].(type) {
+	case string:
+		s["type"] = []string{typ, "null"}
+		return s
+	case []string:
+		if !slices.Contains(typ, "null") {
+			s["type"] = append(typ, "null")
+		}
+		return s
+	case nil:
+		if len(s) == 0 {
+			return s
+		}
+	}
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
+
+// object returns the schema of the JSON object a struct of type t is
+// encoded as. Fields of embedded structs are promoted as encoding/json
+// does, and fields not omitted when empty are required.
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		for i := range t.NumField() {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			ft := f.Type
+			if f.Anonymous && name == "" {
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					add(ft)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			s := g.schema(ft)
+			options := strings.Split(opts, ",")
+			if slices.Contains(options, "string") {
+				s = map[string]any{"type": "string"}
+			}
+			props[name] = s
+			if !slices.Contains(options, "omitempty") && !slices.Contains(options, "omitzero") {
+				required = append(required, name)
+			}
+		}
+	}
+	add(t)
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// doSchema prints the JSON Schema of the named output, or, if dir is not
+// empty, writes the schemas of all outputs to files in dir named after
+// them. Without a name or dir, it lists the outputs.
+func doSchema(name, dir string) error {
+	names := make([]string, 0, len(schemaTypes))
+	for n := range schemaTypes {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		for _, n := range names {
+			data, err := json.MarshalIndent(jsonSchema(n, schemaTypes[n]), "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, n+".schema.json"), append(data, '\n'), 0o644); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if name == "" {
+		for _, n := range names {
+			fmt.Println(n)
+		}
+		return nil
+	}
+	t, ok := schemaTypes[name]
+	if !ok {
+		return fmt.Errorf("no schema for %q; run x schema to list them", name)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonSchema(name, t))
+}
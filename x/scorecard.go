@@ -0,0 +1,64 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/franoliveto/insights/scorecard"
+)
+
+// checkScore formats a Scorecard check score, where negative scores are
+// of checks that did not run or are missing.
+func checkScore(score int) string {
+	if score < 0 {
+		return "-"
+	}
+	return fmt.Sprint(score)
+}
+
+// doScorecardHistory prints the OpenSSF Scorecard results of the project
+// id for the given commits and its latest result, oldest first, and the
+// checks whose scores changed between the oldest and the latest.
+func doScorecardHistory(ctx context.Context, id string, commits []string) error {
+	c := &scorecard.Client{HTTPClient: httpClient, Retry: retryPolicy}
+	results, err := c.History(ctx, id, commits)
+	if err != nil {
+		// The history of the other commits is still of use.
+		log.Print(err)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("%s: no Scorecard results", id)
+	}
+
+	header := []string{"date", "commit", "score", "change"}
+	var rows [][]string
+	for i, s := range results {
+		change := ""
+		if i > 0 {
+			change = fmt.Sprintf("%+.1f", s.OverallScore-results[i-1].OverallScore)
+		}
+		commit := s.Repository.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		rows = append(rows, []string{s.Date, commit, fmt.Sprintf("%.1f", s.OverallScore), change})
+	}
+	changes := scorecard.Compare(results[0], results[len(results)-1])
+	return printList(results, header, rows, func() {
+		printTable(header, rows)
+		if len(changes) == 0 {
+			return
+		}
+		var crows [][]string
+		for _, ch := range changes {
+			crows = append(crows, []string{ch.Check, checkScore(ch.From), checkScore(ch.To)})
+		}
+		fmt.Println()
+		printTable([]string{"check", "from", "to"}, crows)
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// doSearch prints up to n packages of the given system whose registry
+// matches term.
+func doSearch(ctx context.Context, system, term string, n int) error {
+	pkgs, err := newRegistryClient().Search(ctx, system, term, n)
+	if err != nil {
+		return err
+	}
+	header := []string{"name", "version", "description"}
+	var rows [][]string
+	for _, p := range pkgs {
+		rows = append(rows, []string{p.Name, p.Version, p.Description})
+	}
+	return printList(pkgs, header, rows, func() {
+		if len(pkgs) == 0 {
+			fmt.Printf("no packages match %q\n", term)
+			return
+		}
+		printTable(header, rows)
+	})
+}
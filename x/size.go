@@ -0,0 +1,72 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// byteSize formats n bytes with a binary unit.
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// doSize prints the estimated installed size of the dependency graph of the
+// given package version, and that of each of its nodes with everything it
+// pulls in, largest first.
+func doSize(ctx context.Context, c *insights.Client, system, name, version string) error {
+	g, _, err := c.GetDependencies(ctx, system, name, version)
+	if err != nil {
+		return err
+	}
+	is, err := analysis.EstimateInstallSize(ctx, newRegistryClient(), g, &analysis.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+
+	nodes := slices.Clone(is.Nodes)
+	slices.SortStableFunc(nodes, func(a, b analysis.NodeSize) int { return cmp.Compare(b.Total, a.Total) })
+	header := []string{"system", "name", "version", "size", "total", "error"}
+	var rows [][]string
+	for _, n := range nodes {
+		k := n.VersionKey
+		rows = append(rows, []string{k.System, k.Name, k.Version, strconv.FormatInt(n.Size, 10), strconv.FormatInt(n.Total, 10), n.Error})
+	}
+	perr := printList(is, header, rows, func() {
+		fmt.Printf("%s %s: about %s installed", name, version, byteSize(is.Total))
+		if is.Unknown > 0 {
+			fmt.Printf(" (%d package versions of unknown size left out)", is.Unknown)
+		}
+		fmt.Println()
+		var rows [][]string
+		for _, n := range nodes {
+			if n.Node == 0 || n.Total == 0 {
+				continue
+			}
+			rows = append(rows, []string{n.VersionKey.Name, n.VersionKey.Version, byteSize(n.Size), byteSize(n.Total)})
+		}
+		if len(rows) > 0 {
+			fmt.Println()
+			printTable([]string{"name", "version", "size", "with dependencies"}, rows)
+		}
+	})
+	return cmp.Or(perr, err)
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doStats prints statistics about the dependency graph of the given package
+// version.
+func doStats(ctx context.Context, c *insights.Client, system, name, version string) error {
+	g, _, err := c.GetDependencies(ctx, system, name, version)
+	if err != nil {
+		return err
+	}
+	s, err := analysis.Stats(ctx, c, g, &analysis.Options{Concurrency: concurrency})
+	if err != nil && !partial(err) {
+		return err
+	}
+	perr := printResult(s, func() {
+		fmt.Printf("nodes:      %d\n", s.Nodes)
+		fmt.Printf("edges:      %d\n", s.Edges)
+		fmt.Printf("depth:      %d\n", s.Depth)
+		fmt.Printf("direct:     %d\n", s.Direct)
+		fmt.Printf("indirect:   %d\n", s.Indirect)
+		fmt.Printf("duplicates: %d\n", len(s.Duplicates))
+		fmt.Printf("advisories: %d\n", s.Advisories)
+		fmt.Printf("vulnerable: %d\n", s.Vulnerable)
+		if s.Errors > 0 {
+			fmt.Printf("errors:     %d\n", s.Errors)
+		}
+
+		if len(s.Licenses) > 0 {
+			// Most common licenses first.
+			licenses := make([]string, 0, len(s.Licenses))
+			for l := range s.Licenses {
+				licenses = append(licenses, l)
+			}
+			slices.SortFunc(licenses, func(a, b string) int {
+				return cmp.Or(cmp.Compare(s.Licenses[b], s.Licenses[a]), cmp.Compare(a, b))
+			})
+			var rows [][]string
+			for _, l := range licenses {
+				rows = append(rows, []string{l, fmt.Sprint(s.Licenses[l])})
+			}
+			fmt.Println()
+			printTable([]string{"license", "versions"}, rows)
+		}
+		if len(s.Duplicates) > 0 {
+			var rows [][]string
+			for _, d := range s.Duplicates {
+				rows = append(rows, []string{d.Name, strings.Join(d.Versions, ", ")})
+			}
+			fmt.Println()
+			printTable([]string{"duplicate", "versions"}, rows)
+		}
+	})
+	return cmp.Or(perr, err)
+}
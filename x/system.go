@@ -0,0 +1,118 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/franoliveto/insights"
+)
+
+// defaultSystem is the package management system assumed when a command's
+// system argument is omitted. If empty, the system must always be given.
+var defaultSystem string
+
+// systemAliases maps common alternative names of package management systems
+// to the names known to deps.dev.
+var systemAliases = map[string]string{
+	"golang":    "go",
+	"node":      "npm",
+	"nodejs":    "npm",
+	"js":        "npm",
+	"rust":      "cargo",
+	"crates":    "cargo",
+	"python":    "pypi",
+	"pip":       "pypi",
+	"java":      "maven",
+	"mvn":       "maven",
+	"dotnet":    "nuget",
+	"csharp":    "nuget",
+	"crates.io": "cargo",
+	"ruby":      "rubygems",
+	"gem":       "rubygems",
+	"gems":      "rubygems",
+}
+
+// canonicalSystem returns the deps.dev name of the package management system
+// named s, which may be an alias, and reports whether it is known.
+func canonicalSystem(s string) (string, bool) {
+	s = strings.ToLower(s)
+	if a, ok := systemAliases[s]; ok {
+		return a, true
+	}
+	return s, slices.Contains(systems, s)
+}
+
+// systemArgs returns the arguments of a command whose first argument is a
+// package management system. If it is one, any alias is replaced by the
+// deps.dev name; otherwise, the default system, if any, is prepended. Thus a
+// package named after a system must be preceded by the system explicitly.
+//
+// Packages may also be given as one argument, as expanded by keyArgs. Maven
+// packages may be given as coordinates, as in
+// "org.slf4j:slf4j-api:2.0.9", which stand for the name and version
+// arguments. As only Maven package names contain colons, and not slashes
+// as paths do, the system may be omitted before them.
+func systemArgs(args []string) []string {
+	args = keyArgs(args)
+	if len(args) > 0 {
+		if s, ok := canonicalSystem(args[0]); ok {
+			return mavenArgs(append([]string{s}, args[1:]...))
+		}
+		if strings.Contains(args[0], ":") && !strings.ContainsAny(args[0], `/\`) {
+			return mavenArgs(append([]string{"maven"}, args...))
+		}
+	}
+	if defaultSystem != "" {
+		return mavenArgs(append([]string{defaultSystem}, args...))
+	}
+	return args
+}
+
+// mavenArgs replaces the Maven coordinate following the system in args, if
+// any, with the package name and version it stands for.
+func mavenArgs(args []string) []string {
+	if len(args) < 2 || args[0] != "maven" {
+		return args
+	}
+	c, err := insights.ParseMavenCoordinate(args[1])
+	if err != nil {
+		return args
+	}
+	out := []string{args[0], c.Name()}
+	if c.Version != "" {
+		out = append(out, c.Version)
+	}
+	return append(out, args[2:]...)
+}
+
+// keyArgs expands a package version given as the first argument, as a purl
+// such as "pkg:npm/react@18.2.0" or in the shorthand form
+// "npm:react@18.2.0", into the system, name, and, if given, version
+// arguments it stands for. The system of the shorthand may be an alias, as
+// in "python:requests@2.31.0". Other arguments are returned unchanged.
+func keyArgs(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	s := args[0]
+	if system, rest, ok := strings.Cut(s, ":"); ok && system != "pkg" {
+		system, ok = canonicalSystem(system)
+		if !ok {
+			return args
+		}
+		s = system + ":" + rest
+	}
+	k, err := insights.ParseVersionKey(s)
+	if err != nil {
+		return args
+	}
+	out := []string{strings.ToLower(k.System), k.Name}
+	if k.Version != "" {
+		out = append(out, k.Version)
+	}
+	return append(out, args[1:]...)
+}
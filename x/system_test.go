@@ -0,0 +1,148 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSystemArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultSystem string
+		args          []string
+		want          []string
+	}{
+		{
+			name: "system",
+			args: []string{"npm", "react", "18.2.0"},
+			want: []string{"npm", "react", "18.2.0"},
+		},
+		{
+			name: "alias",
+			args: []string{"Python", "requests"},
+			want: []string{"pypi", "requests"},
+		},
+		{
+			name: "shorthand with a scoped name",
+			args: []string{"npm:@babel/core@7.0.0", "extra"},
+			want: []string{"npm", "@babel/core", "7.0.0", "extra"},
+		},
+		{
+			name: "shorthand with an alias",
+			args: []string{"python:requests@2.31.0"},
+			want: []string{"pypi", "requests", "2.31.0"},
+		},
+		{
+			name: "shorthand without a version",
+			args: []string{"npm:react"},
+			want: []string{"npm", "react"},
+		},
+		{
+			name: "purl",
+			args: []string{"pkg:npm/%40babel/core@7.0.0"},
+			want: []string{"npm", "@babel/core", "7.0.0"},
+		},
+		{
+			name: "purl of a Maven package",
+			args: []string{"pkg:maven/org.slf4j/slf4j-api@2.0.9"},
+			want: []string{"maven", "org.slf4j:slf4j-api", "2.0.9"},
+		},
+		{
+			name: "Maven coordinates",
+			args: []string{"org.slf4j:slf4j-api:2.0.9"},
+			want: []string{"maven", "org.slf4j:slf4j-api", "2.0.9"},
+		},
+		{
+			name: "Maven coordinates after the system",
+			args: []string{"mvn", "org.slf4j:slf4j-api:2.0.9"},
+			want: []string{"maven", "org.slf4j:slf4j-api", "2.0.9"},
+		},
+		{
+			name: "Maven coordinates without a version",
+			args: []string{"org.slf4j:slf4j-api"},
+			want: []string{"maven", "org.slf4j:slf4j-api"},
+		},
+		{
+			name:          "Maven coordinates with another default system",
+			defaultSystem: "npm",
+			args:          []string{"org.slf4j:slf4j-api:2.0.9"},
+			want:          []string{"maven", "org.slf4j:slf4j-api", "2.0.9"},
+		},
+		{
+			name: "path",
+			args: []string{`C:\src\project`},
+			want: []string{`C:\src\project`},
+		},
+		{
+			name:          "default system",
+			defaultSystem: "npm",
+			args:          []string{"react", "18.2.0"},
+			want:          []string{"npm", "react", "18.2.0"},
+		},
+		{
+			name:          "system given despite a default",
+			defaultSystem: "npm",
+			args:          []string{"cargo", "serde"},
+			want:          []string{"cargo", "serde"},
+		},
+		{
+			name:          "package named like a system",
+			defaultSystem: "npm",
+			args:          []string{"npm", "go", "1.0.0"},
+			want:          []string{"npm", "go", "1.0.0"},
+		},
+		{
+			name:          "package named like a system without its system",
+			defaultSystem: "npm",
+			args:          []string{"go", "1.0.0"},
+			want:          []string{"go", "1.0.0"},
+		},
+		{
+			name: "no default system",
+			args: []string{"react", "18.2.0"},
+			want: []string{"react", "18.2.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := defaultSystem
+			defaultSystem = tt.defaultSystem
+			t.Cleanup(func() { defaultSystem = old })
+
+			got := systemArgs(tt.args)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("systemArgs(%q) mismatch (-want +got):\n%s", tt.args, diff)
+			}
+		})
+	}
+}
+
+func TestKeyArgs(t *testing.T) {
+	// The default system is not applied: commands that take a path use
+	// keyArgs so that the path is not taken for a package name.
+	old := defaultSystem
+	defaultSystem = "npm"
+	t.Cleanup(func() { defaultSystem = old })
+
+	tests := []struct {
+		args, want []string
+	}{
+		{nil, nil},
+		{[]string{"."}, []string{"."}},
+		{[]string{"./dir/package-lock.json"}, []string{"./dir/package-lock.json"}},
+		{[]string{"npm:@babel/core@7.0.0"}, []string{"npm", "@babel/core", "7.0.0"}},
+		{[]string{"python:requests"}, []string{"pypi", "requests"}},
+		{[]string{"pkg:cargo/serde@1.0.0", "x"}, []string{"cargo", "serde", "1.0.0", "x"}},
+		{[]string{"react", "18.2.0"}, []string{"react", "18.2.0"}},
+	}
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.want, keyArgs(tt.args)); diff != "" {
+			t.Errorf("keyArgs(%q) mismatch (-want +got):\n%s", tt.args, diff)
+		}
+	}
+}
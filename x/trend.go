@@ -0,0 +1,96 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/franoliveto/insights"
+)
+
+// doTrend prints the trends in the adoption of the packages recorded in
+// historyFile, or else in the history of the watchlist file, over the
+// period since. If since is zero, the whole history is considered. If
+// record is set, a sample of the adoption of the packages in the watchlist
+// is recorded first, as when run periodically instead of watch.
+func doTrend(ctx context.Context, c *insights.Client, file, historyFile string, since time.Duration, record bool) error {
+	var wl *watchlist
+	if record || historyFile == "" {
+		var err error
+		if wl, err = readWatchlist(file); err != nil {
+			return err
+		}
+		historyFile = cmp.Or(historyFile, wl.History)
+	}
+	if historyFile == "" {
+		return fmt.Errorf("%s: no history file; set history or use -history", file)
+	}
+	h, err := insights.LoadHistory(historyFile)
+	if err != nil {
+		return err
+	}
+
+	keys := h.Packages()
+	if wl != nil {
+		keys = wl.packageKeys()
+	}
+	if record {
+		var batch insights.Batch
+		for _, k := range keys {
+			s, err := c.SampleAdoption(ctx, k.System, k.Name)
+			if err != nil {
+				batch.Fail(k, err)
+				continue
+			}
+			h.Record(k, *s)
+		}
+		if err := h.Save(); err != nil {
+			return err
+		}
+		// The trends of the packages that were sampled are still of use.
+		if err := batch.Err(); err != nil {
+			log.Print(err)
+		}
+	}
+
+	var start time.Time
+	if since > 0 {
+		start = time.Now().Add(-since)
+	}
+	var trends []*insights.Trend
+	var unknown []insights.PackageKey
+	for _, k := range keys {
+		if t, ok := h.Trend(k, start); ok {
+			trends = append(trends, t)
+		} else {
+			unknown = append(unknown, k)
+		}
+	}
+
+	header := []string{"system", "name", "version", "dependents", "change", "stars", "change", "scorecard", "change", "trend", "since"}
+	var rows [][]string
+	for _, t := range trends {
+		k, l := t.PackageKey, t.Last
+		rows = append(rows, []string{
+			k.System, k.Name, l.Version,
+			fmt.Sprint(l.Dependents), fmt.Sprintf("%+d", t.Dependents),
+			fmt.Sprint(l.Stars), fmt.Sprintf("%+d", t.Stars),
+			fmt.Sprintf("%.1f", l.Scorecard), fmt.Sprintf("%+.1f", t.Scorecard),
+			t.Direction.String(), t.First.Time.Format(time.DateOnly),
+		})
+	}
+	return printList(trends, header, rows, func() {
+		if len(rows) > 0 {
+			printTable(header, rows)
+		}
+		for _, k := range unknown {
+			fmt.Printf("%s %s: not enough samples to tell a trend\n", k.System, k.Name)
+		}
+	})
+}
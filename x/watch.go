@@ -0,0 +1,150 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/franoliveto/insights"
+	"gopkg.in/yaml.v3"
+)
+
+// watchlist is the configuration read by the watch command.
+//
+//	interval: 30m
+//	history: history.json
+//	packages:
+//	  - system: npm
+//	    name: react
+type watchlist struct {
+	// How often to poll deps.dev, as a time.Duration string.
+	Interval string `yaml:"interval"`
+
+	// The file in which to record the adoption of the packages, if any.
+	History string `yaml:"history"`
+
+	// The packages to watch.
+	Packages []struct {
+		System string `yaml:"system"`
+		Name   string `yaml:"name"`
+	} `yaml:"packages"`
+}
+
+func readWatchlist(file string) (*watchlist, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	wl := new(watchlist)
+	if err := yaml.Unmarshal(data, wl); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if len(wl.Packages) == 0 {
+		return nil, fmt.Errorf("%s: no packages to watch", file)
+	}
+	return wl, nil
+}
+
+// describe returns a description of the event. For advisories, it includes
+// their severity and title if they can be fetched.
+func describe(ctx context.Context, c *insights.Client, e insights.Event) (desc, color string) {
+	k := e.VersionKey
+	switch e.Kind {
+	case insights.NewAdvisory:
+		desc = fmt.Sprintf("%s: %s affects %s %s@%s", e.Kind, e.AdvisoryKey.ID, k.System, k.Name, k.Version)
+		if a, _, err := c.GetAdvisory(ctx, e.AdvisoryKey.ID); err == nil {
+			var rating string
+			rating, color = severity(a.CVSS3Score)
+			desc += fmt.Sprintf(" [%s] %s", rating, a.Title)
+		}
+		return desc, color
+	default:
+		return fmt.Sprintf("%s: %s %s@%s", e.Kind, k.System, k.Name, k.Version), ""
+	}
+}
+
+// packageKeys returns the keys of the packages in the watchlist.
+func (wl *watchlist) packageKeys() []insights.PackageKey {
+	var keys []insights.PackageKey
+	for _, p := range wl.Packages {
+		keys = append(keys, insights.PackageKey{System: p.System, Name: p.Name})
+	}
+	return keys
+}
+
+// doWatch polls the packages in the watchlist file until ctx is done,
+// printing every change. If command is not empty, it is run for each change
+// with the description of the change as its last argument. If historyFile,
+// or else the history of the watchlist, is not empty, the adoption of the
+// packages is recorded in it.
+func doWatch(ctx context.Context, c *insights.Client, file string, interval time.Duration, command, historyFile string) error {
+	wl, err := readWatchlist(file)
+	if err != nil {
+		return err
+	}
+	var history *insights.History
+	if historyFile = cmp.Or(historyFile, wl.History); historyFile != "" {
+		if history, err = insights.LoadHistory(historyFile); err != nil {
+			return err
+		}
+	}
+	if interval == 0 {
+		interval = time.Hour
+		if wl.Interval != "" {
+			if interval, err = time.ParseDuration(wl.Interval); err != nil {
+				return fmt.Errorf("%s: invalid interval: %v", file, err)
+			}
+		}
+	}
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval %v: must be positive", interval)
+	}
+	keys := wl.packageKeys()
+
+	w := insights.NewWatcher(c, keys)
+	w.History = history
+	log.Printf("watching %d packages every %v", len(keys), interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil {
+			// Keep watching; the failed packages are retried next time.
+			log.Print(err)
+		}
+		if history != nil {
+			if err := history.Save(); err != nil {
+				log.Print(err)
+			}
+		}
+		for _, e := range events {
+			msg, color := describe(ctx, c, e)
+			fmt.Printf("%s %s\n", time.Now().Format(time.RFC3339), colorize(color, msg))
+			if command == "" {
+				continue
+			}
+			args := strings.Fields(command)
+			cmd := exec.CommandContext(ctx, args[0], append(args[1:], msg)...)
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			if err := cmd.Run(); err != nil {
+				log.Printf("%s: %v", command, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Francisco Oliveto. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/franoliveto/insights"
+	"github.com/franoliveto/insights/analysis"
+)
+
+// doWhatIf prints what upgrading the direct dependency name of the project
+// at path to the given version would change. The impact is printed even
+// if advisories could not be looked up, and their errors are returned
+// after it.
+func doWhatIf(ctx context.Context, c *insights.Client, path, system, name, version string) error {
+	deps, err := scanPath(path)
+	if err != nil {
+		return err
+	}
+	var direct []insights.VersionKey
+	for _, d := range deps {
+		if d.Direct {
+			direct = append(direct, d.VersionKey)
+		}
+	}
+	upgrade := insights.VersionKey{System: system, Name: name, Version: version}
+	im, err := analysis.WhatIf(ctx, c, direct, upgrade, &analysis.Options{Concurrency: concurrency})
+	var be *insights.BatchError
+	if err != nil && !errors.As(err, &be) {
+		return err
+	}
+
+	perr := printResult(im, func() {
+		fmt.Printf("%s %s -> %s\n", im.From.Name, im.From.Version, im.To.Version)
+		if len(im.Changes) == 0 {
+			fmt.Println("no changes to the dependencies")
+		}
+		for _, ch := range im.Changes {
+			switch {
+			case len(ch.Before) == 0:
+				fmt.Printf("%s %s %s\n", colorize(green, "+"), ch.Name, strings.Join(ch.After, ", "))
+			case len(ch.After) == 0:
+				fmt.Printf("%s %s %s\n", colorize(red, "-"), ch.Name, strings.Join(ch.Before, ", "))
+			default:
+				fmt.Printf("%s %s %s -> %s\n", colorize(yellow, "~"), ch.Name, strings.Join(ch.Before, ", "), strings.Join(ch.After, ", "))
+			}
+		}
+		for _, a := range im.Fixed {
+			fmt.Printf("fixes %s %s\n", a.AdvisoryKey.ID, a.Title)
+		}
+		for _, a := range im.Introduced {
+			rating, color := severity(a.CVSS3Score)
+			fmt.Printf("introduces %s %s %s\n", a.AdvisoryKey.ID, colorize(color, fmt.Sprintf("[%s %.1f]", rating, a.CVSS3Score)), a.Title)
+		}
+	})
+	return cmp.Or(perr, err)
+}